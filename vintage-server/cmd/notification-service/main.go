@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+
+	"vintage-server/internal/service/notification"
+	"vintage-server/pkg/auth"
+	"vintage-server/pkg/config"
+	"vintage-server/pkg/middleware"
+)
+
+func main() {
+	// 1. Muat Konfigurasi
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("could not load config: %v", err)
+	}
+
+	// 2. Koneksi Database menggunakan config
+	db, err := sqlx.Connect("postgres", cfg.DSN())
+	if err != nil {
+		log.Fatalf("Failed to connect to DB: %v", err)
+	}
+
+	// 3. Merakit semua lapisan (Wiring)
+	jwtService := auth.NewJWTService(cfg.JWTSecretKey)
+	notificationRepo := notification.NewRepository(db)
+	notificationService := notification.NewService(notificationRepo, notification.PriceAlertConfig{
+		ThresholdPercent: cfg.PriceDropThresholdPercent,
+		Cooldown:         cfg.PriceAlertCooldown,
+	})
+	notificationHandler := notification.NewHandler(notificationService)
+
+	// 4. Background job: alert penurunan harga produk di wishlist
+	go notification.StartPriceAlertJob(context.Background(), notificationService, cfg.PriceAlertInterval)
+
+	// 5. Setup Router Gin
+	router := gin.Default()
+
+	api := router.Group("/api/v1", middleware.RequireAuth(jwtService))
+	{
+		notifications := api.Group("/me/notifications")
+		{
+			notifications.GET("", notificationHandler.ListNotifications)
+			notifications.POST("/read", notificationHandler.MarkAllRead)
+			notifications.POST("/:id/read", notificationHandler.MarkRead)
+		}
+	}
+
+	// 6. Jalankan server
+	log.Println("Notification Service running on port :8085")
+	router.Run(":8085")
+}
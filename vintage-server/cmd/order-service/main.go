@@ -1,10 +1,124 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+
+	"vintage-server/internal/service/auction"
+	"vintage-server/internal/service/inventory"
+	"vintage-server/internal/service/offer"
+	"vintage-server/internal/service/order"
+	"vintage-server/internal/service/shipment"
+	"vintage-server/pkg/auth"
+	"vintage-server/pkg/config"
+	"vintage-server/pkg/middleware"
 )
 
 func main() {
-	log.Printf("Service order sedang berjalan...\n")
-	// TODO: Inisialisasi server, dependency injection, dll.
+	// 1. Muat Konfigurasi
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("could not load config: %v", err)
+	}
+
+	// 2. Koneksi Database menggunakan config
+	db, err := sqlx.Connect("postgres", cfg.DSN())
+	if err != nil {
+		log.Fatalf("Failed to connect to DB: %v", err)
+	}
+
+	// 3. Merakit semua lapisan (Wiring)
+	jwtService := auth.NewJWTService(cfg.JWTSecretKey)
+	inventoryRepo := inventory.NewRepository()
+	orderRepo := order.NewRepository(db, inventoryRepo, shipment.NewRepository(db))
+	serviceRates, err := shipment.ParseServiceRates(cfg.ShippingServiceRates)
+	if err != nil {
+		log.Fatalf("invalid SHIPPING_SERVICE_RATES: %v", err)
+	}
+	shippingRates := shipment.ConfigRates{
+		Default: shipment.ZoneRates{
+			SameRegency:   cfg.ShippingRateSameRegency,
+			SameProvince:  cfg.ShippingRateSameProvince,
+			OtherProvince: cfg.ShippingRateOtherProvince,
+		},
+		Services: serviceRates,
+	}
+	orderService := order.NewService(orderRepo, cfg.CheckoutHoldTTL, shippingRates)
+	orderHandler := order.NewHandler(orderService)
+	offerRepo := offer.NewRepository(db, inventoryRepo)
+	offerService := offer.NewService(offerRepo, offer.OfferPolicy{
+		TTL:           cfg.OfferTTL,
+		AcceptedTTL:   cfg.OfferAcceptedTTL,
+		MaxPerProduct: cfg.OfferMaxPerProduct,
+	})
+	offerHandler := offer.NewHandler(offerService)
+	auctionRepo := auction.NewRepository(db, inventoryRepo)
+	auctionService := auction.NewService(auctionRepo, auction.AuctionPolicy{
+		SnipeWindow: cfg.AuctionSnipeWindow,
+		PaymentTTL:  cfg.AuctionPaymentTTL,
+	})
+	auctionHandler := auction.NewHandler(auctionService)
+
+	// 4. Background job: lepas hold yang kedaluwarsa, tutup offer yang kedaluwarsa & lelang yang berakhir
+	go order.StartHoldSweeper(context.Background(), orderService, time.Minute)
+	go offer.StartExpiryJob(context.Background(), offerService, time.Minute)
+	go auction.StartCloseJob(context.Background(), auctionService, 30*time.Second)
+
+	// 5. Setup Router Gin
+	router := gin.Default()
+
+	api := router.Group("/api/v1", middleware.RequireAuth(jwtService))
+	{
+		cart := api.Group("/cart")
+		{
+			cart.GET("", orderHandler.GetCart)
+			cart.POST("/items", orderHandler.AddCartItem)
+			cart.DELETE("/items/:product_id", orderHandler.RemoveCartItem)
+			cart.GET("/shipping-options", orderHandler.GetShippingOptions)
+		}
+
+		orders := api.Group("/orders")
+		{
+			orders.GET("", orderHandler.GetOrders)
+			orders.GET("/:id", orderHandler.GetOrder)
+			orders.POST("/checkout", orderHandler.Checkout)
+			orders.POST("/:id/cancel", orderHandler.CancelOrder)
+			orders.GET("/:id/shipping-options", orderHandler.GetOrderShippingOptions)
+			orders.POST("/:id/shipping", orderHandler.ChooseOrderShipping)
+		}
+
+		offers := api.Group("/offers")
+		{
+			offers.POST("", offerHandler.CreateOffer)
+			offers.GET("", offerHandler.GetBuyerOffers)
+			offers.GET("/:id", offerHandler.GetOffer)
+			offers.POST("/:id/respond", offerHandler.RespondOffer)
+		}
+
+		auctions := api.Group("/auctions")
+		{
+			auctions.GET("/:id", auctionHandler.GetAuction)
+			auctions.GET("/:id/bids", auctionHandler.GetBids)
+			auctions.POST("/:id/bids", auctionHandler.PlaceBid)
+		}
+		api.GET("/products/:id/auction", auctionHandler.GetProductAuction)
+
+		seller := api.Group("/seller")
+		{
+			seller.GET("/orders", orderHandler.GetShopOrders)
+			seller.GET("/orders/:id", orderHandler.GetShopOrder)
+			seller.GET("/offers", offerHandler.GetSellerOffers)
+			seller.POST("/auctions", auctionHandler.CreateAuction)
+			seller.POST("/auctions/:id/cancel", auctionHandler.CancelAuction)
+		}
+	}
+
+	// 6. Jalankan server
+	log.Println("Order Service running on port :8083")
+	router.Run(":8083")
 }
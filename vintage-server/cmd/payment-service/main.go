@@ -2,9 +2,47 @@ package main
 
 import (
 	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+
+	"vintage-server/internal/service/inventory"
+	"vintage-server/internal/service/payment"
+	"vintage-server/pkg/config"
 )
 
 func main() {
-	log.Printf("Service payment sedang berjalan...\n")
-	// TODO: Inisialisasi server, dependency injection, dll.
+	// 1. Muat Konfigurasi
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("could not load config: %v", err)
+	}
+
+	// 2. Koneksi Database menggunakan config
+	db, err := sqlx.Connect("postgres", cfg.DSN())
+	if err != nil {
+		log.Fatalf("Failed to connect to DB: %v", err)
+	}
+
+	// 3. Merakit semua lapisan (Wiring)
+	inventoryRepo := inventory.NewRepository()
+	paymentRepo := payment.NewRepository(db, inventoryRepo)
+	paymentService := payment.NewService(paymentRepo, cfg.MidtransServerKey, cfg.OrderShipWindow)
+	paymentHandler := payment.NewHandler(paymentService)
+
+	// 4. Setup Router Gin
+	router := gin.Default()
+
+	api := router.Group("/api/v1")
+	{
+		payments := api.Group("/payments")
+		{
+			payments.POST("/midtrans/notification", paymentHandler.MidtransNotification)
+		}
+	}
+
+	// 5. Jalankan server
+	log.Println("Payment Service running on port :8084")
+	router.Run(":8084")
 }
@@ -1,10 +1,280 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+
+	"vintage-server/internal/service/bulk"
+	"vintage-server/internal/service/feed"
+	"vintage-server/internal/service/inventory"
+	"vintage-server/internal/service/product"
+	"vintage-server/internal/service/recentlyviewed"
+	"vintage-server/internal/service/recommendation"
+	"vintage-server/internal/service/retention"
+	"vintage-server/internal/service/syndication"
+	"vintage-server/internal/service/trending"
+	"vintage-server/pkg/auth"
+	"vintage-server/pkg/cache"
+	"vintage-server/pkg/config"
+	"vintage-server/pkg/middleware"
+	"vintage-server/pkg/redis"
+	"vintage-server/pkg/storage"
 )
 
 func main() {
-	log.Printf("Service product sedang berjalan...\n")
-	// TODO: Inisialisasi server, dependency injection, dll.
+	// 1. Muat Konfigurasi
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("could not load config: %v", err)
+	}
+
+	// 2. Koneksi Database menggunakan config
+	db, err := sqlx.Connect("postgres", cfg.DSN())
+	if err != nil {
+		log.Fatalf("Failed to connect to DB: %v", err)
+	}
+
+	// 3. Merakit semua lapisan (Wiring)
+	jwtService := auth.NewJWTService(cfg.JWTSecretKey)
+	productRepo := product.NewRepository(db, inventory.NewRepository())
+	fileStorage := storage.NewLocalStorage(cfg.StorageDir, cfg.StorageBaseURL)
+	referenceCache := cache.New(5 * time.Minute)
+	listingPolicy := product.ListingPolicy{
+		ReviewEnabled:     cfg.ListingReviewEnabled,
+		TrustedAfter:      cfg.ListingReviewTrustedAfter,
+		VerifiedPriceFrom: cfg.KYCHighValueListingPrice,
+	}
+	productService := product.NewService(productRepo, fileStorage, referenceCache, listingPolicy)
+	productHandler := product.NewHandler(productService)
+	feedService := feed.NewService(feed.NewRepository(db), fileStorage, cache.New(time.Minute))
+	feedHandler := feed.NewHandler(feedService)
+	recommendationService := recommendation.NewService(recommendation.NewRepository(db))
+	recommendationHandler := recommendation.NewHandler(recommendationService)
+	bulkService := bulk.NewService(bulk.NewRepository(db), fileStorage)
+	bulkHandler := bulk.NewHandler(bulkService)
+	syndicationService := syndication.NewService(syndication.NewRepository(db), syndication.SiteConfig{
+		SiteBaseURL:  cfg.SiteBaseURL,
+		AssetBaseURL: cfg.AssetBaseURL,
+	})
+	syndicationHandler := syndication.NewHandler(syndicationService)
+	trendingService := trending.NewService(trending.NewRepository(db))
+	trendingHandler := trending.NewHandler(trendingService)
+	retentionService := retention.NewService(retention.NewRepository(db), cfg.RetentionPeriod)
+	retentionHandler := retention.NewHandler(retentionService)
+	recentlyViewedStore := recentlyviewed.NewPostgresStore(db)
+	if cfg.RecentlyViewedStore == "redis" {
+		redisClient := redis.New(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB)
+		recentlyViewedStore = recentlyviewed.NewRedisStore(redisClient, recentlyviewed.SessionTTL)
+	}
+	recentlyViewedService := recentlyviewed.NewService(recentlyViewedStore, recentlyviewed.NewRepository(db))
+	recentlyViewedHandler := recentlyviewed.NewHandler(recentlyViewedService)
+
+	// Hitung ulang daftar rekomendasi dan proses import listing di background
+	go recommendation.StartNeighborJob(context.Background(), recommendationService, cfg.RecommendationInterval)
+	go bulk.StartImportWorker(context.Background(), bulkService, 5*time.Second)
+	go syndication.StartArtifactJob(context.Background(), syndicationService, cfg.SyndicationInterval)
+	// View dikumpulkan di memori lalu ditulis per batch, bukan satu UPDATE per view
+	go trending.StartViewFlusher(context.Background(), trendingService, 30*time.Second)
+	go trending.StartTrendingJob(context.Background(), trendingService, cfg.TrendingInterval)
+	// Produk, shop dan akun yang di-soft-delete dihapus permanen setelah masa retensi
+	go retention.StartPurgeJob(context.Background(), retentionService, cfg.RetentionInterval)
+	// Listing yang baru tayang dicocokkan ke saved search pembeli secara inkremental
+	go product.StartSavedSearchAlertJob(context.Background(), productService, cfg.SavedSearchAlertInterval)
+	go feed.StartShopDropAlertJob(context.Background(), feedService, cfg.ShopDropAlertInterval)
+	go recentlyviewed.StartPurgeJob(context.Background(), recentlyViewedService, time.Hour)
+
+	// 4. Setup Router Gin
+	router := gin.Default()
+	router.Static(cfg.StorageBaseURL, cfg.StorageDir)
+
+	// Sitemap & product feed disajikan di root supaya bisa didaftarkan ke crawler / Merchant Center
+	router.GET("/sitemap.xml", syndicationHandler.ServeArtifact)
+	router.GET("/sitemaps/:name", syndicationHandler.ServeArtifact)
+	router.GET("/feeds/products.xml", syndicationHandler.ServeArtifact)
+
+	api := router.Group("/api/v1")
+	{
+		products := api.Group("/products")
+		{
+			products.GET("", middleware.OptionalAuth(jwtService), productHandler.SearchProducts)
+			products.GET("/trending", trendingHandler.GetTrending)
+			products.GET("/:id", productHandler.GetProduct)
+			products.GET("/:id/breadcrumb", productHandler.GetProductBreadcrumb)
+			products.GET("/:id/price-history", productHandler.GetPriceHistory)
+			products.GET("/:id/measurements", middleware.OptionalAuth(jwtService), productHandler.GetProductMeasurements)
+			products.GET("/:id/attributes", productHandler.GetProductAttributes)
+			products.GET("/:id/recommendations", recommendationHandler.GetRecommendations)
+			// Satu view mengisi riwayat recently viewed lalu dihitung untuk trending
+			products.POST("/:id/views", middleware.OptionalAuth(jwtService), recentlyViewedHandler.TrackView, trendingHandler.RecordView)
+		}
+
+		feeds := api.Group("/feed")
+		{
+			feeds.GET("/home", middleware.OptionalAuth(jwtService), feedHandler.GetHomeFeed)
+			feeds.GET("/new-arrivals", feedHandler.GetNewArrivals)
+		}
+
+		api.GET("/shops/:slug", middleware.OptionalAuth(jwtService), productHandler.GetShop)
+
+		// Riwayat milik akun yang login, atau sesi anonim lewat header X-Session-ID
+		api.GET("/recently-viewed", middleware.OptionalAuth(jwtService), recentlyViewedHandler.GetRecentlyViewed)
+		api.DELETE("/recently-viewed", middleware.OptionalAuth(jwtService), recentlyViewedHandler.ClearRecentlyViewed)
+
+		collections := api.Group("/collections")
+		{
+			collections.GET("", feedHandler.GetCollections)
+			collections.GET("/:slug", feedHandler.GetCollection)
+		}
+
+		categories := api.Group("/categories")
+		{
+			categories.GET("", productHandler.GetCategories)
+			categories.GET("/tree", productHandler.GetCategoryTree)
+			categories.GET("/:id/measurement-fields", productHandler.GetCategoryMeasurementSchema)
+		}
+		api.GET("/measurement-fields", productHandler.GetMeasurementFields)
+		api.GET("/label-types", productHandler.GetLabelTypes)
+		api.GET("/materials", productHandler.GetMaterials)
+		api.GET("/tags", productHandler.BrowseTags)
+		api.GET("/brands", productHandler.GetBrands)
+		api.GET("/conditions", productHandler.GetConditions)
+		api.GET("/sizes", productHandler.GetSizes)
+
+		me := api.Group("/me", middleware.RequireAuth(jwtService))
+		{
+			me.GET("/measurements", productHandler.GetMyMeasurements)
+			me.PUT("/measurements", productHandler.SetMyMeasurements)
+			me.GET("/wishlist", productHandler.GetWishlist)
+			me.POST("/wishlist", productHandler.AddToWishlist)
+			me.DELETE("/wishlist/:product_id", productHandler.RemoveFromWishlist)
+			me.GET("/saved-searches", productHandler.GetSavedSearches)
+			me.POST("/saved-searches", productHandler.SaveSearch)
+			me.PATCH("/saved-searches/:id", productHandler.UpdateSavedSearch)
+			me.DELETE("/saved-searches/:id", productHandler.DeleteSavedSearch)
+			me.GET("/saved-searches/:id/products", productHandler.RunSavedSearch)
+			me.POST("/recently-viewed/merge", recentlyViewedHandler.MergeSession)
+			me.GET("/feed/just-dropped", feedHandler.GetJustDropped)
+			me.GET("/feed/following", feedHandler.GetFollowingFeed)
+			me.GET("/followed-shops", feedHandler.GetFollowedShops)
+			me.PUT("/followed-shops/:id", feedHandler.FollowShop)
+			me.DELETE("/followed-shops/:id", feedHandler.UnfollowShop)
+		}
+
+		seller := api.Group("/seller", middleware.RequireAuth(jwtService))
+		{
+			sellerProducts := seller.Group("/products")
+			{
+				sellerProducts.GET("", productHandler.GetSellerProducts)
+				sellerProducts.GET("/export", bulkHandler.ExportListings)
+				sellerProducts.GET("/views", trendingHandler.GetListingViewStats)
+				sellerProducts.POST("", productHandler.CreateProduct)
+				sellerProducts.GET("/:id", productHandler.GetSellerProduct)
+				sellerProducts.PATCH("/:id", productHandler.UpdateProduct)
+				sellerProducts.DELETE("/:id", productHandler.DeleteProduct)
+				sellerProducts.POST("/:id/status", productHandler.ChangeListingStatus)
+				sellerProducts.PUT("/:id/measurements", productHandler.SetProductMeasurements)
+				sellerProducts.PUT("/:id/attributes", productHandler.SetProductAttributes)
+			}
+
+			sellerImports := seller.Group("/imports")
+			{
+				sellerImports.POST("", bulkHandler.CreateImport)
+				sellerImports.GET("", bulkHandler.GetImports)
+				sellerImports.GET("/:id", bulkHandler.GetImport)
+			}
+		}
+
+		admin := api.Group("/admin", middleware.RequireAuth(jwtService), middleware.RequireRole("admin"))
+		{
+			adminCategories := admin.Group("/categories")
+			{
+				adminCategories.POST("", productHandler.CreateCategory)
+				adminCategories.PATCH("/:id", productHandler.RenameCategory)
+				adminCategories.POST("/:id/move", productHandler.MoveCategory)
+				adminCategories.PUT("/:id/measurement-fields", productHandler.SetCategoryMeasurementSchema)
+				adminCategories.POST("/:id/merge", productHandler.MergeReference(product.ReferenceCategory))
+				adminCategories.DELETE("/:id", productHandler.DeleteReference(product.ReferenceCategory))
+			}
+
+			adminBrands := admin.Group("/brands")
+			{
+				adminBrands.POST("", productHandler.CreateBrand)
+				adminBrands.PATCH("/:id", productHandler.UpdateBrand)
+				adminBrands.POST("/:id/logo", productHandler.UploadBrandLogo)
+				adminBrands.POST("/:id/merge", productHandler.MergeReference(product.ReferenceBrand))
+				adminBrands.DELETE("/:id", productHandler.DeleteReference(product.ReferenceBrand))
+			}
+
+			adminListings := admin.Group("/listings")
+			{
+				adminListings.GET("/pending", productHandler.GetModerationQueue)
+				adminListings.POST("/:id/approve", productHandler.ApproveListing)
+				adminListings.POST("/:id/reject", productHandler.RejectListing)
+			}
+
+			adminProducts := admin.Group("/products")
+			{
+				adminProducts.GET("/deleted", retentionHandler.GetDeleted(retention.KindProduct))
+				adminProducts.DELETE("/:id", retentionHandler.Delete(retention.KindProduct))
+				adminProducts.POST("/:id/restore", retentionHandler.Restore(retention.KindProduct))
+			}
+
+			adminShops := admin.Group("/shops")
+			{
+				adminShops.GET("/deleted", retentionHandler.GetDeleted(retention.KindShop))
+				adminShops.DELETE("/:id", retentionHandler.Delete(retention.KindShop))
+				adminShops.POST("/:id/restore", retentionHandler.Restore(retention.KindShop))
+			}
+
+			adminAccounts := admin.Group("/accounts")
+			{
+				adminAccounts.GET("/deleted", retentionHandler.GetDeleted(retention.KindAccount))
+				adminAccounts.DELETE("/:id", retentionHandler.Delete(retention.KindAccount))
+				adminAccounts.POST("/:id/restore", retentionHandler.Restore(retention.KindAccount))
+			}
+
+			adminCollections := admin.Group("/collections")
+			{
+				adminCollections.GET("", feedHandler.GetAllCollections)
+				adminCollections.POST("", feedHandler.CreateCollection)
+				adminCollections.GET("/:id", feedHandler.GetCollectionForAdmin)
+				adminCollections.PATCH("/:id", feedHandler.UpdateCollection)
+				adminCollections.DELETE("/:id", feedHandler.DeleteCollection)
+				adminCollections.POST("/:id/cover", feedHandler.UploadCollectionCover)
+				adminCollections.PUT("/:id/items", feedHandler.SetCollectionItems)
+			}
+
+			adminTags := admin.Group("/tags")
+			{
+				adminTags.POST("/:id/merge", productHandler.MergeTag)
+				adminTags.POST("/:id/synonyms", productHandler.AddTagSynonym)
+				adminTags.DELETE("/synonyms/:alias", productHandler.DeleteTagSynonym)
+			}
+
+			adminConditions := admin.Group("/conditions")
+			{
+				adminConditions.POST("", productHandler.CreateCondition)
+				adminConditions.PATCH("/:id", productHandler.UpdateCondition)
+				adminConditions.POST("/:id/merge", productHandler.MergeReference(product.ReferenceCondition))
+				adminConditions.DELETE("/:id", productHandler.DeleteReference(product.ReferenceCondition))
+			}
+
+			adminSizes := admin.Group("/sizes")
+			{
+				adminSizes.POST("", productHandler.CreateSize)
+				adminSizes.PATCH("/:id", productHandler.UpdateSize)
+				adminSizes.POST("/:id/merge", productHandler.MergeReference(product.ReferenceSize))
+				adminSizes.DELETE("/:id", productHandler.DeleteReference(product.ReferenceSize))
+			}
+		}
+	}
+
+	// 5. Jalankan server
+	log.Println("Product Service running on port :8082")
+	router.Run(":8082")
 }
@@ -2,9 +2,49 @@ package main
 
 import (
 	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+
+	"vintage-server/internal/service/shipment"
+	"vintage-server/pkg/auth"
+	"vintage-server/pkg/config"
+	"vintage-server/pkg/middleware"
 )
 
 func main() {
-	log.Printf("Service shipment sedang berjalan...\n")
-	// TODO: Inisialisasi server, dependency injection, dll.
+	// 1. Muat Konfigurasi
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("could not load config: %v", err)
+	}
+
+	// 2. Koneksi Database menggunakan config
+	db, err := sqlx.Connect("postgres", cfg.DSN())
+	if err != nil {
+		log.Fatalf("Failed to connect to DB: %v", err)
+	}
+
+	// 3. Merakit semua lapisan (Wiring)
+	jwtService := auth.NewJWTService(cfg.JWTSecretKey)
+	shipmentRepo := shipment.NewRepository(db)
+	shipmentService := shipment.NewService(shipmentRepo)
+	shipmentHandler := shipment.NewHandler(shipmentService)
+
+	// 4. Setup Router Gin
+	router := gin.Default()
+
+	api := router.Group("/api/v1", middleware.RequireAuth(jwtService))
+	{
+		seller := api.Group("/seller/shipments")
+		{
+			seller.POST("/:id/ship", shipmentHandler.ShipShipment)
+			seller.PUT("/:id/tracking", shipmentHandler.UpdateTrackingNumber)
+		}
+	}
+
+	// 5. Jalankan server
+	log.Println("Shipment Service running on port :8087")
+	router.Run(":8087")
 }
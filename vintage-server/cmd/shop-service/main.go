@@ -1,10 +1,112 @@
 package main
 
 import (
+	"context"
 	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+
+	"vintage-server/internal/service/kyc"
+	"vintage-server/internal/service/shop"
+	"vintage-server/pkg/auth"
+	"vintage-server/pkg/config"
+	"vintage-server/pkg/middleware"
+	"vintage-server/pkg/secret"
+	"vintage-server/pkg/storage"
 )
 
 func main() {
-	log.Printf("Service shop sedang berjalan...\n")
-	// TODO: Inisialisasi server, dependency injection, dll.
+	// 1. Muat Konfigurasi
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("could not load config: %v", err)
+	}
+
+	// 2. Koneksi Database menggunakan config
+	db, err := sqlx.Connect("postgres", cfg.DSN())
+	if err != nil {
+		log.Fatalf("Failed to connect to DB: %v", err)
+	}
+
+	// 3. Merakit semua lapisan (Wiring)
+	jwtService := auth.NewJWTService(cfg.JWTSecretKey)
+	fileStorage := storage.NewLocalStorage(cfg.StorageDir, cfg.StorageBaseURL)
+	shopService := shop.NewService(shop.NewRepository(db), fileStorage)
+	shopHandler := shop.NewHandler(shopService)
+	// Dokumen KYC tidak boleh ikut dilayani router.Static
+	kycCipher, err := secret.NewCipher(cfg.KYCEncryptionKey)
+	if err != nil {
+		log.Fatalf("invalid KYC_ENCRYPTION_KEY: %v", err)
+	}
+	privateStorage := storage.NewLocalPrivateStorage(cfg.PrivateStorageDir)
+	kycService := kyc.NewService(kyc.NewRepository(db), privateStorage, kycCipher)
+	kycHandler := kyc.NewHandler(kycService)
+
+	go shop.StartStatsJob(context.Background(), shopService, cfg.ShopStatsInterval)
+	go shop.StartVacationJob(context.Background(), shopService, cfg.ShopVacationInterval)
+
+	// 4. Setup Router Gin
+	router := gin.Default()
+	router.Static(cfg.StorageBaseURL, cfg.StorageDir)
+
+	api := router.Group("/api/v1")
+	{
+		sellerShop := api.Group("/seller/shop", middleware.RequireAuth(jwtService))
+		{
+			sellerShop.POST("", shopHandler.CreateShop)
+			sellerShop.GET("", shopHandler.GetMyShop)
+			sellerShop.PATCH("", shopHandler.UpdateShop)
+			sellerShop.POST("/logo", shopHandler.UploadImage(shop.ImageLogo))
+			sellerShop.POST("/banner", shopHandler.UploadImage(shop.ImageBanner))
+			sellerShop.PUT("/vacation", shopHandler.SetVacation)
+			sellerShop.DELETE("/vacation", shopHandler.EndVacation)
+			sellerShop.GET("/shipping", shopHandler.GetShipping)
+			sellerShop.PUT("/shipping", shopHandler.UpdateShipping)
+			sellerShop.GET("/members", shopHandler.GetMembers)
+			sellerShop.PATCH("/members/:id", shopHandler.UpdateMemberRole)
+			sellerShop.DELETE("/members/:id", shopHandler.RemoveMember)
+			sellerShop.GET("/invitations", shopHandler.GetInvitations)
+			sellerShop.POST("/invitations", shopHandler.InviteMember)
+			sellerShop.DELETE("/invitations/:id", shopHandler.RevokeInvitation)
+			sellerShop.GET("/kyc", kycHandler.GetMyKYC)
+			sellerShop.POST("/kyc", kycHandler.SubmitKYC)
+		}
+
+		api.GET("/couriers", shopHandler.GetCouriers)
+
+		// Undangan staff untuk akun yang login, dicocokkan dengan email akunnya
+		myInvitations := api.Group("/me/shop-invitations", middleware.RequireAuth(jwtService))
+		{
+			myInvitations.GET("", shopHandler.GetMyInvitations)
+			myInvitations.POST("/:id/accept", shopHandler.AcceptInvitation)
+			myInvitations.POST("/:id/decline", shopHandler.DeclineInvitation)
+		}
+
+		adminShops := api.Group("/admin/shops", middleware.RequireAuth(jwtService), middleware.RequireRole("admin"))
+		{
+			adminShops.GET("", shopHandler.GetShops)
+			adminShops.GET("/:id", shopHandler.GetShop)
+			adminShops.POST("/:id/approve", shopHandler.ChangeStatus(shop.ActionApprove))
+			adminShops.POST("/:id/activate", shopHandler.ChangeStatus(shop.ActionActivate))
+			adminShops.POST("/:id/suspend", shopHandler.ChangeStatus(shop.ActionSuspend))
+			adminShops.POST("/:id/reinstate", shopHandler.ChangeStatus(shop.ActionReinstate))
+		}
+
+		adminKYC := api.Group("/admin/kyc", middleware.RequireAuth(jwtService), middleware.RequireRole("admin"))
+		{
+			adminKYC.GET("", kycHandler.GetQueue)
+			adminKYC.GET("/:id", kycHandler.GetSubmission)
+			adminKYC.GET("/:id/ktp-number", kycHandler.RevealKTPNumber)
+			adminKYC.GET("/:id/documents/:kind", kycHandler.GetDocument)
+			adminKYC.POST("/:id/approve", kycHandler.Review(kyc.ActionApprove))
+			adminKYC.POST("/:id/reject", kycHandler.Review(kyc.ActionReject))
+			adminKYC.POST("/:id/request-info", kycHandler.Review(kyc.ActionRequestInfo))
+		}
+	}
+
+	// 5. Jalankan server
+	log.Println("Shop Service running on port :8086")
+	router.Run(":8086")
 }
@@ -24,6 +24,7 @@ type Account struct {
 	Active    bool      `json:"active" db:"active"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 type Roles struct {
@@ -51,8 +52,8 @@ type Address struct {
 // Wishlist merepresentasikan tabel 'wishlist'
 type Wishlist struct {
 	ID        int64     `json:"id" db:"id"`
-	AccountID int64     `json:"account_id" db:"account_id"`
-	ProductID int64     `json:"product_id" db:"product_id"`
+	AccountID uuid.UUID `json:"account_id" db:"account_id"`
+	ProductID uuid.UUID `json:"product_id" db:"product_id"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -60,7 +61,7 @@ type Wishlist struct {
 // AdminLog merepresentasikan tabel 'admin_logs'
 type AdminLog struct {
 	ID          int64     `json:"id" db:"id"`
-	AdminID     int64     `json:"admin_id" db:"admin_id"`
+	AdminID     uuid.UUID `json:"admin_id" db:"admin_id"`
 	Action      string    `json:"action" db:"action"`
 	Description *string   `json:"description" db:"description"`
 	IPAddress   string    `json:"ip_address" db:"ip_address"`
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status lelang (kolom 'auctions.status')
+const (
+	AuctionStatusActive    = "active"
+	AuctionStatusSold      = "sold"   // order pemenang sudah dibuat
+	AuctionStatusUnsold    = "unsold" // tanpa bid, reserve tidak tercapai, atau stok tidak bisa di-hold
+	AuctionStatusCancelled = "cancelled"
+)
+
+// Auction merepresentasikan tabel 'auctions'.
+// LeaderMax adalah bid maksimum (proxy) pemimpin lelang dan tidak pernah dikirim ke client.
+type Auction struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	ProductID      uuid.UUID  `json:"product_id" db:"product_id"`
+	ShopID         uuid.UUID  `json:"shop_id" db:"shop_id"`
+	Status         string     `json:"status" db:"status"`
+	StartPrice     int64      `json:"start_price" db:"start_price"`
+	ReservePrice   *int64     `json:"reserve_price,omitempty" db:"reserve_price"`
+	BidIncrement   int64      `json:"bid_increment" db:"bid_increment"`
+	StartsAt       time.Time  `json:"starts_at" db:"starts_at"`
+	EndsAt         time.Time  `json:"ends_at" db:"ends_at"`
+	OriginalEndsAt time.Time  `json:"original_ends_at" db:"original_ends_at"`
+	ExtensionCount int        `json:"extension_count" db:"extension_count"`
+	CurrentPrice   *int64     `json:"current_price" db:"current_price"`
+	LeaderID       *uuid.UUID `json:"leader_id" db:"leader_id"`
+	LeaderMax      *int64     `json:"-" db:"leader_max"`
+	BidCount       int        `json:"bid_count" db:"bid_count"`
+	OrderID        *uuid.UUID `json:"order_id" db:"order_id"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// AuctionBid merepresentasikan tabel 'auction_bids'.
+// IsAuto menandai bid yang dipasang sistem atas nama proxy bidder; MaxAmount dirahasiakan.
+type AuctionBid struct {
+	ID        int64      `json:"id" db:"id"`
+	AuctionID uuid.UUID  `json:"auction_id" db:"auction_id"`
+	BidderID  *uuid.UUID `json:"bidder_id" db:"bidder_id"`
+	Amount    int64      `json:"amount" db:"amount"`
+	MaxAmount int64      `json:"-" db:"max_amount"`
+	IsAuto    bool       `json:"is_auto" db:"is_auto"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
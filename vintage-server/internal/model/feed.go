@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShopFollower merepresentasikan tabel 'shop_followers'
+type ShopFollower struct {
+	AccountID uuid.UUID `json:"account_id" db:"account_id"`
+	ShopID    uuid.UUID `json:"shop_id" db:"shop_id"`
+	// Notify: follower ingin dinotifikasi saat shop menayangkan listing baru
+	Notify    bool      `json:"notify" db:"notify"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Collection merepresentasikan tabel 'collections' (koleksi editorial kurasi admin)
+type Collection struct {
+	ID            int        `json:"id" db:"id"`
+	Slug          string     `json:"slug" db:"slug"`
+	Title         string     `json:"title" db:"title"`
+	Description   *string    `json:"description" db:"description"`
+	CoverImageURL *string    `json:"cover_image_url" db:"cover_image_url"`
+	Position      int        `json:"position" db:"position"`
+	StartsAt      *time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt        *time.Time `json:"ends_at" db:"ends_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IsLive mengembalikan true jika koleksi sedang berada di dalam jendela tayangnya.
+func (c Collection) IsLive(now time.Time) bool {
+	if c.StartsAt != nil && now.Before(*c.StartsAt) {
+		return false
+	}
+	if c.EndsAt != nil && !now.Before(*c.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// CollectionItem merepresentasikan tabel 'collection_items'
+type CollectionItem struct {
+	CollectionID int       `json:"collection_id" db:"collection_id"`
+	ProductID    uuid.UUID `json:"product_id" db:"product_id"`
+	Position     int       `json:"position" db:"position"`
+}
+
+// GeneratedArtifact merepresentasikan tabel 'generated_artifacts' (sitemap & product feed)
+type GeneratedArtifact struct {
+	Name        string    `json:"name" db:"name"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Content     []byte    `json:"-" db:"content"`
+	ETag        string    `json:"etag" db:"etag"`
+	Fingerprint string    `json:"fingerprint" db:"fingerprint"`
+	GeneratedAt time.Time `json:"generated_at" db:"generated_at"`
+}
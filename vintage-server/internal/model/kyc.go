@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status verifikasi shop (kolom 'shop.kyc_status')
+const (
+	ShopKYCUnverified = "unverified"
+	ShopKYCPending    = "pending"
+	ShopKYCNeedsInfo  = "needs_info"
+	ShopKYCVerified   = "verified"
+	ShopKYCRejected   = "rejected"
+)
+
+// Status pengajuan KYC (kolom 'kyc_submissions.status')
+const (
+	KYCStatusPending   = "pending"
+	KYCStatusNeedsInfo = "needs_info"
+	KYCStatusApproved  = "approved"
+	KYCStatusRejected  = "rejected"
+)
+
+// KYCSubmission merepresentasikan tabel 'kyc_submissions'.
+// Nomor KTP dan key dokumen tidak pernah ikut di-serialize ke JSON.
+type KYCSubmission struct {
+	ID                 uuid.UUID  `json:"id" db:"id"`
+	ShopID             uuid.UUID  `json:"shop_id" db:"shop_id"`
+	SubmittedBy        *uuid.UUID `json:"submitted_by" db:"submitted_by"`
+	Status             string     `json:"status" db:"status"`
+	KTPNumberEncrypted []byte     `json:"-" db:"ktp_number_encrypted"`
+	KTPNumberHash      string     `json:"-" db:"ktp_number_hash"`
+	KTPLast4           string     `json:"ktp_last4" db:"ktp_last4"`
+	KTPPhotoKey        string     `json:"-" db:"ktp_photo_key"`
+	SelfieKey          string     `json:"-" db:"selfie_key"`
+	BankAccountHolder  string     `json:"bank_account_holder" db:"bank_account_holder"`
+	ReviewNote         *string    `json:"review_note" db:"review_note"`
+	ReviewedBy         *uuid.UUID `json:"reviewed_by" db:"reviewed_by"`
+	ReviewedAt         *time.Time `json:"reviewed_at" db:"reviewed_at"`
+	SubmittedAt        time.Time  `json:"submitted_at" db:"submitted_at"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx/types"
+)
+
+// Status job import listing
+const (
+	ImportStatusQueued    = "queued"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+)
+
+// ListingImport merepresentasikan tabel 'listing_imports'
+type ListingImport struct {
+	ID           uuid.UUID      `json:"id" db:"id"`
+	ShopID       uuid.UUID      `json:"shop_id" db:"shop_id"`
+	AccountID    uuid.UUID      `json:"account_id" db:"account_id"`
+	Format       string         `json:"format" db:"format"`
+	FileName     string         `json:"file_name" db:"file_name"`
+	Source       []byte         `json:"-" db:"source"`
+	Images       []byte         `json:"-" db:"images"`
+	Mapping      types.JSONText `json:"mapping" db:"mapping"`
+	Status       string         `json:"status" db:"status"`
+	TotalRows    int            `json:"total_rows" db:"total_rows"`
+	NextRow      int            `json:"processed_rows" db:"next_row"`
+	CreatedCount int            `json:"created_count" db:"created_count"`
+	FailedCount  int            `json:"failed_count" db:"failed_count"`
+	Errors       types.JSONText `json:"errors" db:"errors"`
+	LastError    *string        `json:"last_error" db:"last_error"`
+	Attempts     int            `json:"-" db:"attempts"`
+	LockedUntil  *time.Time     `json:"-" db:"locked_until"`
+	CreatedAt    time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at" db:"updated_at"`
+	FinishedAt   *time.Time     `json:"finished_at" db:"finished_at"`
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MeasurementField merepresentasikan tabel 'measurement_fields'
+type MeasurementField struct {
+	Key            string  `json:"key" db:"key"`
+	Label          string  `json:"label" db:"label"`
+	MinCM          float64 `json:"min_cm" db:"min_cm"`
+	MaxCM          float64 `json:"max_cm" db:"max_cm"`
+	FitToleranceCM float64 `json:"fit_tolerance_cm" db:"fit_tolerance_cm"`
+}
+
+// CategoryMeasurementField merepresentasikan tabel 'category_measurement_fields'
+type CategoryMeasurementField struct {
+	CategoryID int    `json:"category_id" db:"category_id"`
+	FieldKey   string `json:"field_key" db:"field_key"`
+	Required   bool   `json:"required" db:"required"`
+	Position   int16  `json:"position" db:"position"`
+}
+
+// ProductMeasurement merepresentasikan tabel 'product_measurements'
+type ProductMeasurement struct {
+	ProductID uuid.UUID `json:"product_id" db:"product_id"`
+	FieldKey  string    `json:"field_key" db:"field_key"`
+	ValueCM   float64   `json:"value_cm" db:"value_cm"`
+}
+
+// AccountMeasurement merepresentasikan tabel 'account_measurements'
+type AccountMeasurement struct {
+	AccountID uuid.UUID `json:"account_id" db:"account_id"`
+	FieldKey  string    `json:"field_key" db:"field_key"`
+	ValueCM   float64   `json:"value_cm" db:"value_cm"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx/types"
+)
+
+// Jenis notifikasi (kolom 'notifications.type')
+const (
+	NotificationTypePriceDrop   = "price_drop"
+	NotificationTypeOffer       = "offer"
+	NotificationTypeAuction     = "auction"
+	NotificationTypeSavedSearch = "saved_search"
+	NotificationTypeShop        = "shop"
+	NotificationTypeShopDrop    = "shop_drop"
+	NotificationTypeOrder       = "order"
+)
+
+// Notification merepresentasikan tabel 'notifications'
+type Notification struct {
+	ID        uuid.UUID      `json:"id" db:"id"`
+	AccountID uuid.UUID      `json:"account_id" db:"account_id"`
+	Type      string         `json:"type" db:"type"`
+	Title     string         `json:"title" db:"title"`
+	Body      string         `json:"body" db:"body"`
+	Data      types.JSONText `json:"data" db:"data"`
+	ReadAt    *time.Time     `json:"read_at" db:"read_at"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
+
+// ProductPriceHistory merepresentasikan tabel 'product_price_history'
+type ProductPriceHistory struct {
+	ID        int64     `json:"id" db:"id"`
+	ProductID uuid.UUID `json:"product_id" db:"product_id"`
+	OldPrice  *int64    `json:"old_price" db:"old_price"`
+	NewPrice  int64     `json:"new_price" db:"new_price"`
+	ChangedAt time.Time `json:"changed_at" db:"changed_at"`
+}
+
+// PendingPriceAlert merepresentasikan tabel 'pending_price_alerts'
+type PendingPriceAlert struct {
+	AccountID uuid.UUID `json:"account_id" db:"account_id"`
+	ProductID uuid.UUID `json:"product_id" db:"product_id"`
+	OldPrice  int64     `json:"old_price" db:"old_price"`
+	NewPrice  int64     `json:"new_price" db:"new_price"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status offer (kolom 'offers.status')
+const (
+	OfferStatusPending   = "pending"   // menunggu jawaban seller
+	OfferStatusCountered = "countered" // menunggu jawaban pembeli
+	OfferStatusAccepted  = "accepted"  // harga khusus pembeli sedang di-hold
+	OfferStatusDeclined  = "declined"
+	OfferStatusWithdrawn = "withdrawn"
+	OfferStatusExpired   = "expired"
+	OfferStatusPurchased = "purchased"
+)
+
+// Aksi di riwayat negosiasi (kolom 'offer_events.action')
+const (
+	OfferActionOffer    = "offer"
+	OfferActionCounter  = "counter"
+	OfferActionAccept   = "accept"
+	OfferActionDecline  = "decline"
+	OfferActionWithdraw = "withdraw"
+	OfferActionExpire   = "expire"
+	OfferActionPurchase = "purchase"
+)
+
+// Offer merepresentasikan tabel 'offers'
+type Offer struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	ProductID uuid.UUID  `json:"product_id" db:"product_id"`
+	ShopID    uuid.UUID  `json:"shop_id" db:"shop_id"`
+	BuyerID   uuid.UUID  `json:"buyer_id" db:"buyer_id"`
+	Status    string     `json:"status" db:"status"`
+	Amount    int64      `json:"amount" db:"amount"`
+	Quantity  int        `json:"quantity" db:"quantity"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	HoldID    *uuid.UUID `json:"hold_id" db:"hold_id"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// OfferEvent merepresentasikan tabel 'offer_events'
+type OfferEvent struct {
+	ID        int64      `json:"id" db:"id"`
+	OfferID   uuid.UUID  `json:"offer_id" db:"offer_id"`
+	Action    string     `json:"action" db:"action"`
+	Amount    *int64     `json:"amount" db:"amount"`
+	ActorID   *uuid.UUID `json:"actor_id" db:"actor_id"`
+	Note      *string    `json:"note" db:"note"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
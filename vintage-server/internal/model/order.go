@@ -6,21 +6,33 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	OrderStatusPendingPayment = iota + 1
+	OrderStatusPaid
+	OrderStatusShipped
+	OrderStatusCompleted
+	OrderStatusCancelled
+)
+
 // Order merepresentasikan tabel 'orders'
 type Order struct {
-	ID         uuid.UUID `json:"id" db:"id"`
-	AccountID  int64     `json:"account_id" db:"account_id"`
-	TotalPrice int64     `json:"total_price" db:"total_price"`
-	Status     int16     `json:"status" db:"status"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	ID        uuid.UUID `json:"id" db:"id"`
+	AccountID uuid.UUID `json:"account_id" db:"account_id"`
+	// TotalPrice sudah termasuk ShippingCost (total ongkir semua shipment order)
+	TotalPrice   int64 `json:"total_price" db:"total_price"`
+	ShippingCost int64 `json:"shipping_cost" db:"shipping_cost"`
+	Status       int16 `json:"status" db:"status"`
+	// ShipBy adalah batas seller mengirim order, diisi saat order dibayar
+	ShipBy    *time.Time `json:"ship_by" db:"ship_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 // OrderItem merepresentasikan tabel 'order_items'
 type OrderItem struct {
 	ID              int64     `json:"id" db:"id"`
-	OrderID         int64     `json:"order_id" db:"order_id"`
-	ProductID       int64     `json:"product_id" db:"product_id"`
+	OrderID         uuid.UUID `json:"order_id" db:"order_id"`
+	ProductID       uuid.UUID `json:"product_id" db:"product_id"`
 	Quantity        int       `json:"quantity" db:"quantity"`
 	PriceAtPurchase int64     `json:"price_at_purchase" db:"price_at_purchase"`
 	CreatedAt       time.Time `json:"created_at" db:"created_at"`
@@ -28,19 +40,19 @@ type OrderItem struct {
 
 // OrderStatusLog merepresentasikan tabel 'order_status_logs'
 type OrderStatusLog struct {
-	ID        int64     `json:"id" db:"id"`
-	OrderID   int64     `json:"order_id" db:"order_id"`
-	OldStatus *int16    `json:"old_status" db:"old_status"`
-	NewStatus int16     `json:"new_status" db:"new_status"`
-	Note      *string   `json:"note" db:"note"`
-	CreatedBy *int64    `json:"created_by" db:"created_by"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID        int64      `json:"id" db:"id"`
+	OrderID   uuid.UUID  `json:"order_id" db:"order_id"`
+	OldStatus *int16     `json:"old_status" db:"old_status"`
+	NewStatus int16      `json:"new_status" db:"new_status"`
+	Note      *string    `json:"note" db:"note"`
+	CreatedBy *uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
 }
 
 // Payment merepresentasikan tabel 'payments'
 type Payment struct {
 	ID                    uuid.UUID `json:"id" db:"id"`
-	OrderID               int64     `json:"order_id" db:"order_id"`
+	OrderID               uuid.UUID `json:"order_id" db:"order_id"`
 	PaymentStatus         string    `json:"payment_status" db:"payment_status"`
 	MidtransOrderID       string    `json:"midtrans_order_id" db:"midtrans_order_id"`
 	MidtransTransactionID *string   `json:"midtrans_transaction_id" db:"midtrans_transaction_id"`
@@ -49,11 +61,19 @@ type Payment struct {
 	UpdatedAt             time.Time `json:"updated_at" db:"updated_at"`
 }
 
-// Shipment merepresentasikan tabel 'shipments'
+const (
+	ShipmentStatusPending = iota + 1
+	ShipmentStatusShipped
+	ShipmentStatusDelivered
+)
+
+// Shipment merepresentasikan tabel 'shipments' (satu per shop di dalam order)
 type Shipment struct {
-	ID             uuid.UUID `json:"id" db:"id"`
-	OrderID        int64     `json:"order_id" db:"order_id"`
-	AddressID      int64     `json:"address_id" db:"address_id"`
+	ID      uuid.UUID `json:"id" db:"id"`
+	OrderID uuid.UUID `json:"order_id" db:"order_id"`
+	ShopID  uuid.UUID `json:"shop_id" db:"shop_id"`
+	// AddressID kosong untuk pesanan yang diambil sendiri di alamat asal shop
+	AddressID      *int64    `json:"address_id" db:"address_id"`
 	Courier        string    `json:"courier" db:"courier"`
 	Service        string    `json:"service" db:"service"`
 	ShippingCost   int64     `json:"shipping_cost" db:"shipping_cost"`
@@ -66,7 +86,7 @@ type Shipment struct {
 // Cart merepresentasikan tabel 'cart'
 type Cart struct {
 	ID        uuid.UUID `json:"id" db:"id"`
-	AccountID int64     `json:"account_id" db:"account_id"`
+	AccountID uuid.UUID `json:"account_id" db:"account_id"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -74,9 +94,34 @@ type Cart struct {
 // CartItem merepresentasikan tabel 'cart_items'
 type CartItem struct {
 	ID        int64     `json:"id" db:"id"`
-	CartID    int64     `json:"cart_id" db:"cart_id"`
-	ProductID int64     `json:"product_id" db:"product_id"`
+	CartID    uuid.UUID `json:"cart_id" db:"cart_id"`
+	ProductID uuid.UUID `json:"product_id" db:"product_id"`
 	Quantity  int       `json:"quantity" db:"quantity"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
+
+const (
+	HoldStatusActive    = "active"
+	HoldStatusReleased  = "released"
+	HoldStatusExpired   = "expired"
+	HoldStatusConverted = "converted"
+)
+
+// InventoryHold merepresentasikan tabel 'inventory_holds'
+// Hold mengunci sebagian stok produk untuk satu pembeli selama checkout berlangsung.
+// UnitPrice hanya diisi untuk hold hasil offer yang diterima (harga khusus pembeli).
+type InventoryHold struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	ProductID uuid.UUID  `json:"product_id" db:"product_id"`
+	AccountID uuid.UUID  `json:"account_id" db:"account_id"`
+	OrderID   *uuid.UUID `json:"order_id" db:"order_id"`
+	Quantity  int        `json:"quantity" db:"quantity"`
+	UnitPrice *int64     `json:"unit_price" db:"unit_price"`
+	Status    string     `json:"status" db:"status"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	// OfferExpiresAt: masa berlaku asli hold offer selama hold itu dipakai sebuah order
+	OfferExpiresAt *time.Time `json:"offer_expires_at" db:"offer_expires_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
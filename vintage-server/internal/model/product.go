@@ -8,63 +8,133 @@ import (
 
 // ProductCondition merepresentasikan tabel 'product_conditions'
 type ProductCondition struct {
-	ID        int16     `json:"id" db:"id"`
-	Name      string    `json:"name" db:"name"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID   int16  `json:"id" db:"id"`
+	Name string `json:"name" db:"name"`
+	// GoogleCondition adalah padanan kondisi di product feed: new, refurbished, atau used
+	GoogleCondition string     `json:"google_condition" db:"google_condition"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt       *time.Time `json:"-" db:"deleted_at"`
 }
 
 // ProductCategory merepresentasikan tabel 'product_categories'
 type ProductCategory struct {
+	ID        int        `json:"id" db:"id"`
+	ParentID  *int       `json:"parent_id" db:"parent_id"`
+	Name      string     `json:"name" db:"name"`
+	Slug      string     `json:"slug" db:"slug"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt *time.Time `json:"-" db:"deleted_at"`
+}
+
+// Brand merepresentasikan tabel 'brands'
+type Brand struct {
+	ID        int        `json:"id" db:"id"`
+	Name      string     `json:"name" db:"name"`
+	LogoURL   *string    `json:"logo_url" db:"logo_url"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt *time.Time `json:"-" db:"deleted_at"`
+}
+
+// ProductSize merepresentasikan tabel 'product_size'
+type ProductSize struct {
+	ID        int        `json:"id" db:"id"`
+	SizeName  string     `json:"size_name" db:"size_name"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt *time.Time `json:"-" db:"deleted_at"`
+}
+
+// LabelType merepresentasikan tabel 'label_types'
+type LabelType struct {
 	ID        int       `json:"id" db:"id"`
 	Name      string    `json:"name" db:"name"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
-// Brand merepresentasikan tabel 'brands'
-type Brand struct {
+// Material merepresentasikan tabel 'materials'
+type Material struct {
 	ID        int       `json:"id" db:"id"`
 	Name      string    `json:"name" db:"name"`
-	LogoURL   *string   `json:"logo_url" db:"logo_url"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
-// ProductSize merepresentasikan tabel 'product_size'
-type ProductSize struct {
-	ID       int    `json:"id" db:"id"`
-	SizeName string `json:"size_name" db:"size_name"`
+// ProductMaterial merepresentasikan tabel 'product_materials'
+type ProductMaterial struct {
+	ProductID  uuid.UUID `json:"product_id" db:"product_id"`
+	MaterialID int       `json:"material_id" db:"material_id"`
+	Percentage *int16    `json:"percentage" db:"percentage"`
 }
 
-// Shop merepresentasikan tabel 'shop'
-type Shop struct {
-	ID          uuid.UUID `json:"id" db:"id"`
-	AccountID   int64     `json:"account_id" db:"account_id"`
-	Name        string    `json:"name" db:"name"`
-	Summary     *string   `json:"summary" db:"summary"`
-	Description *string   `json:"description" db:"description"`
-	Active      bool      `json:"active" db:"active"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+// Tag merepresentasikan tabel 'tags'
+type Tag struct {
+	ID        int       `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
 
-// Product merepresentasikan tabel 'products'
+// TagSynonym merepresentasikan tabel 'tag_synonyms'
+type TagSynonym struct {
+	Alias     string    `json:"alias" db:"alias"`
+	TagID     int       `json:"tag_id" db:"tag_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Status listing (kolom 'products.status')
+const (
+	ListingStatusDraft         = "draft"
+	ListingStatusPendingReview = "pending_review"
+	ListingStatusPublished     = "published"
+	ListingStatusSold          = "sold"
+	ListingStatusArchived      = "archived"
+)
+
+// Cara jual listing (kolom 'products.listing_mode')
+const (
+	ListingModeFixed   = "fixed"
+	ListingModeAuction = "auction"
+)
+
+// Product merepresentasikan tabel 'products'.
+// Kolom yang nullable hanya boleh kosong selama status masih draft.
 type Product struct {
-	ID          uuid.UUID `json:"id" db:"id"`
-	ShopID      int64     `json:"shop_id" db:"shop_id"`
-	ConditionID int16     `json:"condition_id" db:"condition_id"`
-	CategoryID  int       `json:"category_id" db:"category_id"`
-	BrandID     *int      `json:"brand_id" db:"brand_id"`
-	SizeID      *int      `json:"size_id" db:"size_id"`
-	Name        string    `json:"name" db:"name"`
-	Summary     *string   `json:"summary" db:"summary"`
-	Description *string   `json:"description" db:"description"`
-	Price       int64     `json:"price" db:"price"`
-	Stock       int       `json:"stock" db:"stock"`
-	IsLatest    bool      `json:"is_latest" db:"is_latest"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID              uuid.UUID  `json:"id" db:"id"`
+	ShopID          uuid.UUID  `json:"shop_id" db:"shop_id"`
+	ConditionID     *int16     `json:"condition_id" db:"condition_id"`
+	CategoryID      *int       `json:"category_id" db:"category_id"`
+	BrandID         *int       `json:"brand_id" db:"brand_id"`
+	SizeID          *int       `json:"size_id" db:"size_id"`
+	Name            string     `json:"name" db:"name"`
+	Slug            string     `json:"slug" db:"slug"`
+	Summary         *string    `json:"summary" db:"summary"`
+	Description     *string    `json:"description" db:"description"`
+	Price           *int64     `json:"price" db:"price"`
+	Stock           int        `json:"stock" db:"stock"`
+	EraDecade       *int16     `json:"era_decade" db:"era_decade"`
+	CountryOfOrigin *string    `json:"country_of_origin" db:"country_of_origin"`
+	LabelTypeID     *int       `json:"label_type_id" db:"label_type_id"`
+	Status          string     `json:"status" db:"status"`
+	ListingMode     string     `json:"listing_mode" db:"listing_mode"`
+	ModerationNote  *string    `json:"moderation_note" db:"moderation_note"`
+	SubmittedAt     *time.Time `json:"submitted_at" db:"submitted_at"`
+	PublishedAt     *time.Time `json:"published_at" db:"published_at"`
+	SoldAt          *time.Time `json:"sold_at" db:"sold_at"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// ProductStatusLog merepresentasikan tabel 'product_status_logs'
+type ProductStatusLog struct {
+	ID         int64      `json:"id" db:"id"`
+	ProductID  uuid.UUID  `json:"product_id" db:"product_id"`
+	FromStatus *string    `json:"from_status" db:"from_status"`
+	ToStatus   string     `json:"to_status" db:"to_status"`
+	Reason     *string    `json:"reason" db:"reason"`
+	CreatedBy  *uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
 }
 
 // ProductImage merepresentasikan tabel 'product_images'
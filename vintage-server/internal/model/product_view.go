@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductViewHourly merepresentasikan tabel 'product_view_hourly'
+type ProductViewHourly struct {
+	ProductID uuid.UUID `json:"product_id" db:"product_id"`
+	Hour      time.Time `json:"hour" db:"hour"`
+	Views     int       `json:"views" db:"views"`
+}
+
+// ProductTrending merepresentasikan tabel 'product_trending'
+type ProductTrending struct {
+	ProductID    uuid.UUID `json:"product_id" db:"product_id"`
+	CategoryID   int       `json:"category_id" db:"category_id"`
+	Score        float64   `json:"score" db:"score"`
+	Views        int       `json:"views" db:"views"`
+	WishlistAdds int       `json:"wishlist_adds" db:"wishlist_adds"`
+	Sales        int       `json:"sales" db:"sales"`
+	ComputedAt   time.Time `json:"computed_at" db:"computed_at"`
+}
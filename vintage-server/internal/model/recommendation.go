@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Jenis daftar tetangga di tabel product_neighbors
+const (
+	NeighborKindSimilar        = "similar"
+	NeighborKindAlsoWishlisted = "also_wishlisted"
+	NeighborKindBoughtTogether = "bought_together"
+)
+
+// ProductNeighbor merepresentasikan tabel 'product_neighbors'
+type ProductNeighbor struct {
+	ProductID  uuid.UUID `json:"product_id" db:"product_id"`
+	Kind       string    `json:"kind" db:"kind"`
+	NeighborID uuid.UUID `json:"neighbor_id" db:"neighbor_id"`
+	Score      float64   `json:"score" db:"score"`
+	Rank       int16     `json:"rank" db:"rank"`
+	ComputedAt time.Time `json:"computed_at" db:"computed_at"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx/types"
+)
+
+// Frekuensi alert saved search (kolom 'saved_searches.alert')
+const (
+	SavedSearchAlertInstant = "instant"
+	SavedSearchAlertDaily   = "daily" // digest sekali sehari
+	SavedSearchAlertOff     = "off"
+)
+
+// SavedSearch merepresentasikan tabel 'saved_searches'.
+// BrandID & SizeID adalah salinan dari Filter untuk keperluan pencocokan, tidak dikirim ke client.
+type SavedSearch struct {
+	ID            uuid.UUID      `json:"id" db:"id"`
+	AccountID     uuid.UUID      `json:"account_id" db:"account_id"`
+	Name          string         `json:"name" db:"name"`
+	Filter        types.JSONText `json:"filter" db:"filter"`
+	Alert         string         `json:"alert" db:"alert"`
+	BrandID       *int           `json:"-" db:"brand_id"`
+	SizeID        *int           `json:"-" db:"size_id"`
+	LastAlertedAt *time.Time     `json:"last_alerted_at" db:"last_alerted_at"`
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Shop merepresentasikan tabel 'shop'
+type Shop struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	AccountID   uuid.UUID `json:"account_id" db:"account_id"`
+	Name        string    `json:"name" db:"name"`
+	Slug        string    `json:"slug" db:"slug"`
+	Summary     *string   `json:"summary" db:"summary"`
+	Description *string   `json:"description" db:"description"`
+	LogoURL     *string   `json:"logo_url" db:"logo_url"`
+	BannerURL   *string   `json:"banner_url" db:"banner_url"`
+	// Kebijakan toko yang ditampilkan ke pembeli
+	ShippingPolicy *string `json:"shipping_policy" db:"shipping_policy"`
+	ReturnPolicy   *string `json:"return_policy" db:"return_policy"`
+	PaymentPolicy  *string `json:"payment_policy" db:"payment_policy"`
+	FollowerCount  int     `json:"follower_count" db:"follower_count"`
+	// Active selalu sama dengan Status == ShopStatusActive; produk shop yang tidak aktif disembunyikan dari katalog
+	Active       bool    `json:"active" db:"active"`
+	Status       string  `json:"status" db:"status"`
+	StatusReason *string `json:"status_reason" db:"status_reason"`
+	// Mode libur: listing tetap tayang tapi tidak bisa di-checkout selama VacationActive
+	VacationActive        bool       `json:"vacation_active" db:"vacation_active"`
+	VacationMessage       *string    `json:"vacation_message" db:"vacation_message"`
+	VacationStartsAt      *time.Time `json:"vacation_starts_at" db:"vacation_starts_at"`
+	VacationEndsAt        *time.Time `json:"vacation_ends_at" db:"vacation_ends_at"`
+	VacationPendingOrders string     `json:"vacation_pending_orders" db:"vacation_pending_orders"`
+	VacationBlockedAt     *time.Time `json:"vacation_blocked_at" db:"vacation_blocked_at"`
+	// Status verifikasi identitas seller, disalin dari pengajuan KYC terakhir
+	KYCStatus     string     `json:"kyc_status" db:"kyc_status"`
+	KYCVerifiedAt *time.Time `json:"kyc_verified_at" db:"kyc_verified_at"`
+	// Alamat asal pengiriman; kosong sampai seller mengisi pengaturan pengiriman
+	OriginProvinceID *string `json:"origin_province_id" db:"origin_province_id"`
+	OriginRegencyID  *string `json:"origin_regency_id" db:"origin_regency_id"`
+	OriginDistrictID *string `json:"origin_district_id" db:"origin_district_id"`
+	OriginStreet     *string `json:"origin_street" db:"origin_street"`
+	OriginPostalCode *string `json:"origin_postal_code" db:"origin_postal_code"`
+	// HandlingDays adalah lama proses sebelum dikirim; nil berarti batas kirim default platform
+	HandlingDays *int16     `json:"handling_days" db:"handling_days"`
+	SelfPickup   bool       `json:"self_pickup" db:"self_pickup"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// Status shop (kolom 'shop.status')
+const (
+	ShopStatusPending   = "pending"
+	ShopStatusApproved  = "approved"
+	ShopStatusActive    = "active"
+	ShopStatusSuspended = "suspended"
+)
+
+// Perlakuan order yang belum dikirim saat mode libur dimulai (kolom 'shop.vacation_pending_orders')
+const (
+	VacationPendingOrdersBlock  = "block"
+	VacationPendingOrdersExtend = "extend"
+)
+
+// ShopStatusLog merepresentasikan tabel 'shop_status_logs'
+type ShopStatusLog struct {
+	ID         int64      `json:"id" db:"id"`
+	ShopID     uuid.UUID  `json:"shop_id" db:"shop_id"`
+	FromStatus string     `json:"from_status" db:"from_status"`
+	ToStatus   string     `json:"to_status" db:"to_status"`
+	Reason     *string    `json:"reason" db:"reason"`
+	CreatedBy  *uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Role anggota shop (kolom 'shop_members.role'); hak aksesnya ada di package staff
+const (
+	ShopRoleOwner       = "owner"
+	ShopRoleManager     = "manager"
+	ShopRoleLister      = "lister"
+	ShopRoleFulfillment = "fulfillment"
+)
+
+// ShopMember merepresentasikan tabel 'shop_members'
+type ShopMember struct {
+	ShopID    uuid.UUID  `json:"shop_id" db:"shop_id"`
+	AccountID uuid.UUID  `json:"account_id" db:"account_id"`
+	Role      string     `json:"role" db:"role"`
+	InvitedBy *uuid.UUID `json:"invited_by" db:"invited_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// ShopInvitation merepresentasikan tabel 'shop_invitations'
+type ShopInvitation struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	ShopID     uuid.UUID  `json:"shop_id" db:"shop_id"`
+	Email      string     `json:"email" db:"email"`
+	Role       string     `json:"role" db:"role"`
+	InvitedBy  *uuid.UUID `json:"invited_by" db:"invited_by"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at" db:"accepted_at"`
+	DeclinedAt *time.Time `json:"declined_at" db:"declined_at"`
+	RevokedAt  *time.Time `json:"revoked_at" db:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// ShopCourier merepresentasikan tabel 'shop_couriers' (layanan kurir yang diaktifkan shop)
+type ShopCourier struct {
+	ShopID    uuid.UUID `json:"shop_id" db:"shop_id"`
+	Courier   string    `json:"courier" db:"courier"`
+	Service   string    `json:"service" db:"service"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
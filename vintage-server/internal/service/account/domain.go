@@ -50,6 +50,8 @@ type Repository interface {
 	FindAccountByEmailWithRole(ctx context.Context, email string, roleName string) (model.Account, error)
 	FindAccountByUsernameWithRole(ctx context.Context, username string, roleName string) (model.Account, error)
 
+	// FindAccountByUsername dan FindAccountByEmail ikut membaca akun yang sudah dihapus,
+	// supaya username / email-nya tidak bisa dipakai ulang selama masa retensi.
 	FindAccountByUsername(ctx context.Context, username string) (model.Account, error)
 	FindAccountByEmail(ctx context.Context, email string) (model.Account, error)
 
@@ -32,7 +32,7 @@ func (r *repository) IsUsernameUsed(ctx context.Context, username string) (bool,
 
 func (r *repository) FindAccountByID(ctx context.Context, id int64) (model.Account, error) {
 	var account model.Account
-	query := "SELECT * FROM accounts WHERE id = $1"
+	query := "SELECT * FROM accounts WHERE id = $1 AND deleted_at IS NULL"
 	err := r.db.GetContext(ctx, &account, query, id)
 	return account, err
 }
@@ -44,7 +44,7 @@ func (r *repository) FindAccountByEmailWithRole(ctx context.Context, email strin
 		FROM accounts a
 		JOIN account_roles ar ON a.id = ar.account_id
 		JOIN roles r ON ar.role_id = r.id
-		WHERE a.email = $1 AND r.name = $2
+		WHERE a.email = $1 AND r.name = $2 AND a.deleted_at IS NULL
 		LIMIT 1
 	`
 	err := r.db.GetContext(ctx, &account, query, email, roleName)
@@ -58,7 +58,7 @@ func (r *repository) FindAccountByUsernameWithRole(ctx context.Context, username
 		FROM accounts a
 		JOIN account_roles ar ON a.id = ar.account_id
 		JOIN roles r ON ar.role_id = r.id
-		WHERE a.username = $1 AND r.name = $2
+		WHERE a.username = $1 AND r.name = $2 AND a.deleted_at IS NULL
 		LIMIT 1
 	`
 	err := r.db.GetContext(ctx, &account, query, username, roleName)
@@ -278,7 +278,7 @@ func (r *repository) FindWishlistByAccountID(ctx context.Context, accountID int6
 			pi.url as product_image_url,
 			w.created_at
 		FROM wishlist w
-		JOIN products p ON w.product_id = p.id
+		JOIN products p ON w.product_id = p.id AND p.deleted_at IS NULL
 		LEFT JOIN product_images pi ON p.id = pi.product_id AND pi.image_index = 0
 		WHERE w.account_id = $1
 		ORDER BY w.created_at DESC`
@@ -0,0 +1,71 @@
+package auction
+
+// File: internal/service/auction/domain.go
+
+import (
+	"context"
+	"time"
+	"vintage-server/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// =================================================================================
+// KONTRAK UNTUK SERVICE (Logika Bisnis) 🧠
+// =================================================================================
+type Service interface {
+	// Usecase: SellerStart Auction (listing published berpindah ke mode lelang)
+	CreateAuction(ctx context.Context, sellerID uuid.UUID, req CreateAuctionRequest) (model.Auction, error)
+	// Usecase: SellerCancel Auction (hanya selama belum ada bid; listing kembali ke harga tetap)
+	CancelAuction(ctx context.Context, sellerID, auctionID uuid.UUID) error
+
+	// Usecase: CustomerView Auction
+	GetAuction(ctx context.Context, accountID, auctionID uuid.UUID) (AuctionView, error)
+	// GetProductAuction mengembalikan lelang terbaru sebuah produk (lelang berjalan diutamakan).
+	GetProductAuction(ctx context.Context, accountID, productID uuid.UUID) (AuctionView, error)
+	// Usecase: CustomerPlace Bid (proxy bidding; bid di menit terakhir memperpanjang lelang)
+	PlaceBid(ctx context.Context, bidderID, auctionID uuid.UUID, req PlaceBidRequest) (AuctionView, error)
+	// Usecase: CustomerView Bid History
+	GetBids(ctx context.Context, auctionID uuid.UUID) ([]model.AuctionBid, error)
+
+	// CloseEndedAuctions dipanggil job berkala: pemenang dibuatkan order dengan stok di-hold,
+	// lelang tanpa pemenang dikembalikan ke harga tetap.
+	CloseEndedAuctions(ctx context.Context) (int, error)
+}
+
+// =================================================================================
+// KONTRAK UNTUK REPOSITORY (Akses Database) 🚚
+// =================================================================================
+// Repository mendefinisikan semua interaksi ke database yang dibutuhkan oleh Service.
+// Setiap bid diproses dengan baris auctions dikunci (FOR UPDATE), jadi bid yang datang
+// bersamaan diproses satu per satu terhadap state terbaru.
+type Repository interface {
+	// FindShopMembership mengambil shop tempat akun menjadi anggota beserta role-nya.
+	FindShopMembership(ctx context.Context, accountID uuid.UUID) (model.ShopMember, error)
+	// FindShopProduct hanya menemukan produk (belum dihapus) milik shopID.
+	FindShopProduct(ctx context.Context, shopID, productID uuid.UUID) (AuctionProduct, error)
+	// TransactionCreateAuction mengunci produk, memastikan masih published dengan stok tersedia
+	// (ErrProductNotEligible) dan tanpa lelang berjalan (ErrAuctionExists), lalu menyimpan lelang
+	// dan memindah listing ke mode lelang.
+	TransactionCreateAuction(ctx context.Context, auction model.Auction) (model.Auction, error)
+	// TransactionCancelAuction mengembalikan ErrAuctionNotActive atau ErrAuctionHasBids jika lelang tidak bisa dibatalkan.
+	TransactionCancelAuction(ctx context.Context, shopID, auctionID uuid.UUID) error
+
+	FindAuction(ctx context.Context, auctionID uuid.UUID) (AuctionSummary, error)
+	FindProductAuction(ctx context.Context, productID uuid.UUID) (AuctionSummary, error)
+	FindBids(ctx context.Context, auctionID uuid.UUID) ([]model.AuctionBid, error)
+
+	// TransactionPlaceBid mengunci lelang, menerapkan apply terhadap state terkunci,
+	// lalu menyimpan state baru, baris bid, dan notifikasinya.
+	TransactionPlaceBid(ctx context.Context, auctionID uuid.UUID, apply BidApplier) (model.Auction, error)
+	// TransactionCloseAuctions menutup lelang yang sudah lewat ends_at. Pemenang yang memenuhi reserve
+	// dibuatkan order pending dengan hold berlaku sampai paymentExpiresAt; jika stok tidak bisa di-hold,
+	// lelang ditutup tanpa pemenang. Mengembalikan jumlah lelang yang ditutup.
+	TransactionCloseAuctions(ctx context.Context, now, paymentExpiresAt time.Time, notify ClosedAuctionNotifier) (int, error)
+}
+
+// BidApplier menghitung hasil sebuah bid dari state lelang yang sedang dikunci.
+type BidApplier func(auction model.Auction) (BidOutcome, error)
+
+// ClosedAuctionNotifier menyusun notifikasi untuk lelang yang ditutup job (status baru: sold / unsold).
+type ClosedAuctionNotifier func(auction AuctionSummary, status string) ([]model.Notification, error)
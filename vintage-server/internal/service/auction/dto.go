@@ -0,0 +1,79 @@
+package auction
+
+import (
+	"errors"
+	"time"
+	"vintage-server/internal/model"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrProductNotEligible dikembalikan jika produk tidak tayang, sudah dihapus, atau stoknya habis.
+	ErrProductNotEligible = errors.New("product cannot be auctioned")
+	// ErrAuctionExists dikembalikan jika produk masih punya lelang berjalan.
+	ErrAuctionExists = errors.New("product already has an active auction")
+	// ErrAuctionHasBids dikembalikan saat membatalkan lelang yang sudah menerima bid.
+	ErrAuctionHasBids = errors.New("auction already has bids")
+	// ErrAuctionNotActive dikembalikan jika lelang belum dimulai, sudah berakhir, atau dibatalkan.
+	ErrAuctionNotActive = errors.New("auction is not open for bidding")
+	// ErrBidTooLow dikembalikan jika bid di bawah minimum; pesan lengkapnya menyebut minimum tersebut.
+	ErrBidTooLow = errors.New("bid is too low")
+)
+
+// AuctionPolicy mengatur anti-sniping dan batas waktu pembayaran pemenang.
+type AuctionPolicy struct {
+	// SnipeWindow: bid yang masuk kurang dari SnipeWindow sebelum ends_at memundurkan ends_at
+	// menjadi SnipeWindow sejak bid tersebut.
+	SnipeWindow time.Duration
+	// PaymentTTL: lama stok di-hold untuk pemenang sampai order-nya dibayar.
+	PaymentTTL time.Duration
+}
+
+// AuctionProduct adalah data listing milik seller yang dibutuhkan untuk membuka lelang.
+type AuctionProduct struct {
+	ID     uuid.UUID `db:"id"`
+	ShopID uuid.UUID `db:"shop_id"`
+	Name   string    `db:"name"`
+	Status string    `db:"status"`
+}
+
+type CreateAuctionRequest struct {
+	ProductID    uuid.UUID `json:"product_id" binding:"required"`
+	StartPrice   int64     `json:"start_price" binding:"required,min=1"`
+	ReservePrice *int64    `json:"reserve_price" binding:"omitempty,min=1"`
+	BidIncrement int64     `json:"bid_increment" binding:"required,min=1"`
+	// StartsAt kosong berarti lelang langsung dimulai
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   time.Time  `json:"ends_at" binding:"required"`
+}
+
+// PlaceBidRequest adalah bid maksimum (proxy). Sistem hanya menaikkan harga seperlunya
+// untuk mengungguli bidder lain sampai batas MaxAmount.
+type PlaceBidRequest struct {
+	MaxAmount int64 `json:"max_amount" binding:"required,min=1"`
+}
+
+// AuctionSummary adalah lelang beserta info listing-nya.
+type AuctionSummary struct {
+	model.Auction
+	ProductName string    `json:"product_name" db:"product_name"`
+	ProductSlug string    `json:"product_slug" db:"product_slug"`
+	SellerID    uuid.UUID `json:"seller_id" db:"seller_id"`
+}
+
+// AuctionView adalah lelang dari sudut pandang satu akun. ReservePrice hanya terlihat oleh seller.
+type AuctionView struct {
+	AuctionSummary
+	MinNextBid int64 `json:"min_next_bid"`
+	ReserveMet bool  `json:"reserve_met"`
+	Leading    bool  `json:"leading"`
+}
+
+// BidOutcome adalah hasil penerapan satu bid terhadap lelang yang sedang dikunci:
+// state lelang yang baru, baris bid yang dicatat (termasuk bid otomatis proxy), dan notifikasi.
+type BidOutcome struct {
+	Auction       model.Auction
+	Bids          []model.AuctionBid
+	Notifications []model.Notification
+}
@@ -0,0 +1,135 @@
+package auction
+
+import (
+	"net/http"
+	"vintage-server/pkg/middleware"
+	"vintage-server/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Handler adalah struct yang memegang dependency ke Service
+type Handler struct {
+	svc Service
+}
+
+// NewHandler adalah constructor untuk handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// --- Seller ---
+
+// CreateAuction membuka lelang untuk listing milik seller yang sedang login
+func (h *Handler) CreateAuction(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var req CreateAuctionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	auction, err := h.svc.CreateAuction(c.Request.Context(), accountID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusCreated, auction)
+}
+
+// CancelAuction membatalkan lelang yang belum menerima bid
+func (h *Handler) CancelAuction(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	auctionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid auction id")
+		return
+	}
+
+	if err := h.svc.CancelAuction(c.Request.Context(), accountID, auctionID); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// --- Bidding ---
+
+// GetAuction mengembalikan detail lelang beserta bid minimum berikutnya
+func (h *Handler) GetAuction(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	auctionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid auction id")
+		return
+	}
+
+	auction, err := h.svc.GetAuction(c.Request.Context(), accountID, auctionID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, auction)
+}
+
+// GetProductAuction mengembalikan lelang terbaru sebuah produk
+func (h *Handler) GetProductAuction(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid product id")
+		return
+	}
+
+	auction, err := h.svc.GetProductAuction(c.Request.Context(), accountID, productID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, auction)
+}
+
+// PlaceBid memasang bid maksimum (proxy) untuk sebuah lelang
+func (h *Handler) PlaceBid(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	auctionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid auction id")
+		return
+	}
+
+	var req PlaceBidRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	auction, err := h.svc.PlaceBid(c.Request.Context(), accountID, auctionID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, auction)
+}
+
+// GetBids mengembalikan seluruh riwayat bid sebuah lelang, terbaru lebih dulu
+func (h *Handler) GetBids(c *gin.Context) {
+	auctionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid auction id")
+		return
+	}
+
+	bids, err := h.svc.GetBids(c.Request.Context(), auctionID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, bids)
+}
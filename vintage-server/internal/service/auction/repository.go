@@ -0,0 +1,319 @@
+package auction
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/inventory"
+	"vintage-server/internal/service/notification"
+	"vintage-server/internal/service/order"
+	"vintage-server/internal/service/staff"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// summaryColumns & summaryJoins membentuk AuctionSummary dari tabel auctions (alias a).
+const summaryColumns = `
+	a.*, p.name AS product_name, p.slug AS product_slug, s.account_id AS seller_id`
+
+const summaryJoins = `
+	JOIN products p ON p.id = a.product_id
+	JOIN shop s ON s.id = a.shop_id`
+
+// closeBatchSize membatasi jumlah lelang yang ditutup dalam satu transaksi job.
+const closeBatchSize = 100
+
+// repository adalah struct yang mengimplementasikan kontrak Repository dari domain.go
+type repository struct {
+	db        *sqlx.DB
+	inventory inventory.Repository
+}
+
+// NewRepository adalah constructor untuk implementasi repository.
+// Stok pemenang di-hold lewat inventory.Repository di dalam transaksi penutupan lelang.
+func NewRepository(db *sqlx.DB, inventoryRepo inventory.Repository) Repository {
+	return &repository{
+		db:        db,
+		inventory: inventoryRepo,
+	}
+}
+
+func (r *repository) FindShopMembership(ctx context.Context, accountID uuid.UUID) (model.ShopMember, error) {
+	return staff.FindMembership(ctx, r.db, accountID)
+}
+
+func (r *repository) FindShopProduct(ctx context.Context, shopID, productID uuid.UUID) (AuctionProduct, error) {
+	var product AuctionProduct
+	query := `
+		SELECT p.id, p.shop_id, p.name, p.status
+		FROM products p
+		WHERE p.id = $1 AND p.shop_id = $2 AND p.deleted_at IS NULL`
+	err := r.db.GetContext(ctx, &product, query, productID, shopID)
+	return product, err
+}
+
+func (r *repository) TransactionCreateAuction(ctx context.Context, auction model.Auction) (model.Auction, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return model.Auction{}, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Kunci produk lalu pastikan masih tayang dan ada stok yang bisa dimenangkan
+	if _, err := tx.ExecContext(ctx, "SELECT 1 FROM products WHERE id = $1 FOR UPDATE", auction.ProductID); err != nil {
+		return model.Auction{}, err
+	}
+	available, err := r.inventory.AvailableStock(ctx, tx, auction.ProductID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Auction{}, ErrProductNotEligible
+		}
+		return model.Auction{}, err
+	}
+	if available < 1 {
+		return model.Auction{}, ErrProductNotEligible
+	}
+
+	// Query 2: Simpan lelang; unique index menjaga satu lelang berjalan per produk
+	var saved model.Auction
+	query := `
+		INSERT INTO auctions (product_id, shop_id, status, start_price, reserve_price, bid_increment, starts_at, ends_at, original_ends_at)
+		VALUES (:product_id, :shop_id, :status, :start_price, :reserve_price, :bid_increment, :starts_at, :ends_at, :original_ends_at)
+		RETURNING *`
+	bound, args, err := tx.BindNamed(query, auction)
+	if err != nil {
+		return model.Auction{}, err
+	}
+	if err := tx.GetContext(ctx, &saved, bound, args...); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return model.Auction{}, ErrAuctionExists
+		}
+		return model.Auction{}, err
+	}
+
+	// Query 3: Listing hanya bisa dibeli lewat lelang
+	if err := setListingMode(ctx, tx, auction.ProductID, model.ListingModeAuction); err != nil {
+		return model.Auction{}, err
+	}
+	return saved, tx.Commit()
+}
+
+func (r *repository) TransactionCancelAuction(ctx context.Context, shopID, auctionID uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var auction model.Auction
+	queryLock := `
+		SELECT * FROM auctions
+		WHERE id = $1 AND shop_id = $2
+		FOR UPDATE`
+	if err := tx.GetContext(ctx, &auction, queryLock, auctionID, shopID); err != nil {
+		return err
+	}
+	if auction.Status != model.AuctionStatusActive {
+		return ErrAuctionNotActive
+	}
+	if auction.BidCount > 0 {
+		return ErrAuctionHasBids
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE auctions SET status = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1", auctionID, model.AuctionStatusCancelled); err != nil {
+		return err
+	}
+	if err := setListingMode(ctx, tx, auction.ProductID, model.ListingModeFixed); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *repository) FindAuction(ctx context.Context, auctionID uuid.UUID) (AuctionSummary, error) {
+	var auction AuctionSummary
+	query := `SELECT ` + summaryColumns + ` FROM auctions a` + summaryJoins + ` WHERE a.id = $1`
+	err := r.db.GetContext(ctx, &auction, query, auctionID)
+	return auction, err
+}
+
+func (r *repository) FindProductAuction(ctx context.Context, productID uuid.UUID) (AuctionSummary, error) {
+	var auction AuctionSummary
+	query := `
+		SELECT ` + summaryColumns + `
+		FROM auctions a` + summaryJoins + `
+		WHERE a.product_id = $1 AND a.status <> 'cancelled'
+		ORDER BY a.status = 'active' DESC, a.created_at DESC
+		LIMIT 1`
+	err := r.db.GetContext(ctx, &auction, query, productID)
+	return auction, err
+}
+
+func (r *repository) FindBids(ctx context.Context, auctionID uuid.UUID) ([]model.AuctionBid, error) {
+	bids := []model.AuctionBid{}
+	err := r.db.SelectContext(ctx, &bids, "SELECT * FROM auction_bids WHERE auction_id = $1 ORDER BY id DESC", auctionID)
+	return bids, err
+}
+
+func (r *repository) TransactionPlaceBid(ctx context.Context, auctionID uuid.UUID, apply BidApplier) (model.Auction, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return model.Auction{}, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Kunci lelang. Bid lain untuk lelang yang sama menunggu di sini sampai commit,
+	// jadi apply selalu melihat harga dan pemimpin terbaru.
+	var current model.Auction
+	if err := tx.GetContext(ctx, &current, "SELECT * FROM auctions WHERE id = $1 FOR UPDATE", auctionID); err != nil {
+		return model.Auction{}, err
+	}
+	outcome, err := apply(current)
+	if err != nil {
+		return model.Auction{}, err
+	}
+
+	// Query 2: Simpan state baru
+	var updated model.Auction
+	next := outcome.Auction
+	query := `
+		UPDATE auctions SET
+			current_price = $2, leader_id = $3, leader_max = $4, bid_count = $5,
+			ends_at = $6, extension_count = $7, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING *`
+	err = tx.GetContext(ctx, &updated, query, auctionID, next.CurrentPrice, next.LeaderID, next.LeaderMax, next.BidCount, next.EndsAt, next.ExtensionCount)
+	if err != nil {
+		return model.Auction{}, err
+	}
+
+	// Query 3: Riwayat bid (termasuk bid otomatis proxy) & notifikasi
+	queryBid := `
+		INSERT INTO auction_bids (auction_id, bidder_id, amount, max_amount, is_auto)
+		VALUES (:auction_id, :bidder_id, :amount, :max_amount, :is_auto)`
+	for _, bid := range outcome.Bids {
+		bid.AuctionID = auctionID
+		if _, err := tx.NamedExecContext(ctx, queryBid, bid); err != nil {
+			return model.Auction{}, err
+		}
+	}
+	for _, n := range outcome.Notifications {
+		if err := notification.SaveNotification(ctx, tx, n); err != nil {
+			return model.Auction{}, err
+		}
+	}
+	return updated, tx.Commit()
+}
+
+func (r *repository) TransactionCloseAuctions(ctx context.Context, now, paymentExpiresAt time.Time, notify ClosedAuctionNotifier) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Lelang yang sudah berakhir. SKIP LOCKED melewati lelang yang sedang menerima bid.
+	var ended []AuctionSummary
+	query := `
+		SELECT ` + summaryColumns + `
+		FROM auctions a` + summaryJoins + `
+		WHERE a.status = 'active' AND a.ends_at <= $1
+		ORDER BY a.ends_at
+		LIMIT $2
+		FOR UPDATE OF a SKIP LOCKED`
+	if err := tx.SelectContext(ctx, &ended, query, now, closeBatchSize); err != nil {
+		return 0, err
+	}
+
+	for _, auction := range ended {
+		var orderID *uuid.UUID
+		if auction.LeaderID != nil && reserveMet(auction.Auction) {
+			orderID, err = r.convertWinner(ctx, tx, auction.Auction, paymentExpiresAt)
+			if err != nil {
+				return 0, err
+			}
+		}
+		var listingMode string
+		auction.Status, listingMode = closedState(orderID)
+		auction.OrderID = orderID
+
+		// Query 2: Tutup lelang dan kembalikan listing ke harga tetap
+		queryClose := "UPDATE auctions SET status = $2, order_id = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $1"
+		if _, err := tx.ExecContext(ctx, queryClose, auction.ID, auction.Status, auction.OrderID); err != nil {
+			return 0, err
+		}
+		if err := setListingMode(ctx, tx, auction.ProductID, listingMode); err != nil {
+			return 0, err
+		}
+
+		// Query 3: Notifikasi untuk pemenang & seller
+		notifications, err := notify(auction, auction.Status)
+		if err != nil {
+			return 0, err
+		}
+		for _, n := range notifications {
+			if err := notification.SaveNotification(ctx, tx, n); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return len(ended), tx.Commit()
+}
+
+// convertWinner membuat order pending untuk pemenang dan meng-hold stoknya di harga akhir lelang.
+// Jika stok tidak bisa di-hold (produk diarsipkan, stok diambil offer), perubahan dibatalkan
+// lewat savepoint dan hasilnya nil, sehingga lelang ditutup tanpa pemenang.
+func (r *repository) convertWinner(ctx context.Context, tx *sqlx.Tx, auction model.Auction, paymentExpiresAt time.Time) (*uuid.UUID, error) {
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT convert_winner"); err != nil {
+		return nil, err
+	}
+
+	item := model.OrderItem{ProductID: auction.ProductID, Quantity: 1, PriceAtPurchase: *auction.CurrentPrice}
+	created, _, err := order.CreatePendingOrder(ctx, tx, *auction.LeaderID, []model.OrderItem{item}, 0, "auction won", nil)
+	if err != nil {
+		return nil, err
+	}
+	_, err = r.inventory.Hold(ctx, tx, inventory.HoldRequest{
+		ProductID: auction.ProductID,
+		AccountID: *auction.LeaderID,
+		OrderID:   &created.ID,
+		Quantity:  1,
+		ExpiresAt: paymentExpiresAt,
+	})
+	if errors.Is(err, inventory.ErrInsufficientStock) || errors.Is(err, inventory.ErrProductUnavailable) {
+		if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT convert_winner"); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT convert_winner"); err != nil {
+		return nil, err
+	}
+	return &created.ID, nil
+}
+
+// closedState menentukan status akhir lelang dan cara jual listing setelah lelang ditutup.
+// Listing selalu kembali ke harga tetap, juga saat terjual: unit pemenang sudah terlindungi hold
+// order-nya, sehingga sisa stok tetap bisa dibeli / ditawar, dan jika order pemenang batal atau
+// kedaluwarsa unit itu otomatis kembali dijual tanpa perlu tahu order berasal dari lelang.
+func closedState(orderID *uuid.UUID) (status, listingMode string) {
+	if orderID != nil {
+		return model.AuctionStatusSold, model.ListingModeFixed
+	}
+	return model.AuctionStatusUnsold, model.ListingModeFixed
+}
+
+// setListingMode memindah cara jual sebuah produk (harga tetap / lelang).
+func setListingMode(ctx context.Context, tx *sqlx.Tx, productID uuid.UUID, mode string) error {
+	_, err := tx.ExecContext(ctx, "UPDATE products SET listing_mode = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1", productID, mode)
+	return err
+}
@@ -0,0 +1,398 @@
+package auction
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/staff"
+	"vintage-server/pkg/apperror"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Rentang durasi lelang yang diizinkan
+	minAuctionDuration = time.Hour
+	maxAuctionDuration = 30 * 24 * time.Hour
+)
+
+// service adalah struct yang akan mengimplementasikan interface Service dari domain.go
+type service struct {
+	repo   Repository
+	policy AuctionPolicy
+}
+
+// NewService adalah constructor untuk service
+func NewService(repo Repository, policy AuctionPolicy) Service {
+	return &service{
+		repo:   repo,
+		policy: policy,
+	}
+}
+
+// --- Seller ---
+
+func (s *service) CreateAuction(ctx context.Context, sellerID uuid.UUID, req CreateAuctionRequest) (model.Auction, error) {
+	member, err := s.findShopMember(ctx, sellerID)
+	if err != nil {
+		return model.Auction{}, err
+	}
+	product, err := s.repo.FindShopProduct(ctx, member.ShopID, req.ProductID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Auction{}, apperror.New(apperror.ErrCodeNotFound, "product not found")
+		}
+		log.Printf("Error finding product for auction: %v", err)
+		return model.Auction{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if product.Status != model.ListingStatusPublished {
+		return model.Auction{}, apperror.New(apperror.ErrCodeConflict, "only published listings can be auctioned")
+	}
+
+	now := time.Now()
+	startsAt := now
+	if req.StartsAt != nil && req.StartsAt.After(now) {
+		startsAt = *req.StartsAt
+	}
+	duration := req.EndsAt.Sub(startsAt)
+	if duration < minAuctionDuration || duration > maxAuctionDuration {
+		return model.Auction{}, apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("auction must run between %s and %s", minAuctionDuration, maxAuctionDuration))
+	}
+	if req.ReservePrice != nil && *req.ReservePrice < req.StartPrice {
+		return model.Auction{}, apperror.New(apperror.ErrCodeValidation, "reserve price cannot be below the start price")
+	}
+
+	auction := model.Auction{
+		ProductID:      product.ID,
+		ShopID:         product.ShopID,
+		Status:         model.AuctionStatusActive,
+		StartPrice:     req.StartPrice,
+		ReservePrice:   req.ReservePrice,
+		BidIncrement:   req.BidIncrement,
+		StartsAt:       startsAt,
+		EndsAt:         req.EndsAt,
+		OriginalEndsAt: req.EndsAt,
+	}
+	saved, err := s.repo.TransactionCreateAuction(ctx, auction)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrProductNotEligible):
+			return model.Auction{}, apperror.New(apperror.ErrCodeConflict, "listing has no stock available to auction")
+		case errors.Is(err, ErrAuctionExists):
+			return model.Auction{}, apperror.New(apperror.ErrCodeConflict, "listing already has an active auction")
+		}
+		log.Printf("Error creating auction: %v", err)
+		return model.Auction{}, apperror.New(apperror.ErrCodeInternal, "failed to create auction")
+	}
+	return saved, nil
+}
+
+func (s *service) CancelAuction(ctx context.Context, sellerID, auctionID uuid.UUID) error {
+	member, err := s.findShopMember(ctx, sellerID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.TransactionCancelAuction(ctx, member.ShopID, auctionID); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return apperror.New(apperror.ErrCodeNotFound, "auction not found")
+		case errors.Is(err, ErrAuctionNotActive):
+			return apperror.New(apperror.ErrCodeConflict, "auction is no longer active")
+		case errors.Is(err, ErrAuctionHasBids):
+			return apperror.New(apperror.ErrCodeConflict, "auction with bids cannot be cancelled")
+		}
+		log.Printf("Error cancelling auction: %v", err)
+		return apperror.New(apperror.ErrCodeInternal, "failed to cancel auction")
+	}
+	return nil
+}
+
+// --- Bidding ---
+
+func (s *service) GetAuction(ctx context.Context, accountID, auctionID uuid.UUID) (AuctionView, error) {
+	auction, err := s.repo.FindAuction(ctx, auctionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return AuctionView{}, apperror.New(apperror.ErrCodeNotFound, "auction not found")
+		}
+		log.Printf("Error finding auction: %v", err)
+		return AuctionView{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return buildView(auction, accountID), nil
+}
+
+func (s *service) GetProductAuction(ctx context.Context, accountID, productID uuid.UUID) (AuctionView, error) {
+	auction, err := s.repo.FindProductAuction(ctx, productID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return AuctionView{}, apperror.New(apperror.ErrCodeNotFound, "auction not found")
+		}
+		log.Printf("Error finding product auction: %v", err)
+		return AuctionView{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return buildView(auction, accountID), nil
+}
+
+func (s *service) PlaceBid(ctx context.Context, bidderID, auctionID uuid.UUID, req PlaceBidRequest) (AuctionView, error) {
+	summary, err := s.repo.FindAuction(ctx, auctionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return AuctionView{}, apperror.New(apperror.ErrCodeNotFound, "auction not found")
+		}
+		log.Printf("Error finding auction: %v", err)
+		return AuctionView{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	// Owner maupun staff shop tidak boleh ikut menawar lelang shop-nya sendiri
+	member, err := s.repo.FindShopMembership(ctx, bidderID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Error finding shop membership: %v", err)
+		return AuctionView{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if err == nil && member.ShopID == summary.ShopID {
+		return AuctionView{}, apperror.New(apperror.ErrCodeForbidden, "cannot bid on your own auction")
+	}
+
+	// Harga & pemimpin dihitung ulang dari state yang dikunci repository, bukan dari summary di atas
+	apply := func(auction model.Auction) (BidOutcome, error) {
+		return applyBid(auction, summary.ProductName, bidderID, req.MaxAmount, time.Now(), s.policy.SnipeWindow)
+	}
+	updated, err := s.repo.TransactionPlaceBid(ctx, auctionID, apply)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrAuctionNotActive):
+			return AuctionView{}, apperror.New(apperror.ErrCodeConflict, "auction is not open for bidding")
+		case errors.Is(err, ErrBidTooLow):
+			return AuctionView{}, apperror.New(apperror.ErrCodeValidation, err.Error())
+		}
+		log.Printf("Error placing bid: %v", err)
+		return AuctionView{}, apperror.New(apperror.ErrCodeInternal, "failed to place bid")
+	}
+
+	summary.Auction = updated
+	return buildView(summary, bidderID), nil
+}
+
+func (s *service) GetBids(ctx context.Context, auctionID uuid.UUID) ([]model.AuctionBid, error) {
+	if _, err := s.repo.FindAuction(ctx, auctionID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.New(apperror.ErrCodeNotFound, "auction not found")
+		}
+		log.Printf("Error finding auction: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	bids, err := s.repo.FindBids(ctx, auctionID)
+	if err != nil {
+		log.Printf("Error finding auction bids: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return bids, nil
+}
+
+func (s *service) CloseEndedAuctions(ctx context.Context) (int, error) {
+	now := time.Now()
+	return s.repo.TransactionCloseAuctions(ctx, now, now.Add(s.policy.PaymentTTL), notifyClosedAuction)
+}
+
+// applyBid menerapkan bid maksimum bidderID terhadap lelang yang sedang dikunci (proxy bidding):
+//   - bidder pertama memimpin di harga awal;
+//   - penantang yang maksimumnya lebih tinggi mengambil alih di maksimum lawan + increment
+//     (dibatasi maksimumnya sendiri), dan pemimpin lama dinotifikasi;
+//   - jika tidak, proxy pemimpin otomatis membalas di bid penantang + increment
+//     (dibatasi maksimum pemimpin). Maksimum yang sama dimenangkan bidder yang lebih dulu;
+//   - pemimpin boleh menaikkan maksimumnya sendiri tanpa menaikkan harga.
+//
+// Harga naik ke reserve begitu maksimum pemimpin mencapainya. Bid yang masuk kurang dari
+// snipeWindow sebelum berakhir memperpanjang lelang menjadi snipeWindow sejak bid tersebut.
+func applyBid(auction model.Auction, productName string, bidderID uuid.UUID, maxAmount int64, now time.Time, snipeWindow time.Duration) (BidOutcome, error) {
+	if auction.Status != model.AuctionStatusActive || now.Before(auction.StartsAt) || !now.Before(auction.EndsAt) {
+		return BidOutcome{}, ErrAuctionNotActive
+	}
+
+	var outcome BidOutcome
+	var outbid *uuid.UUID
+	price := auction.StartPrice
+	bid := model.AuctionBid{BidderID: &bidderID, MaxAmount: maxAmount}
+
+	switch {
+	case auction.LeaderID == nil:
+		if maxAmount < auction.StartPrice {
+			return BidOutcome{}, fmt.Errorf("%w: minimum bid is %d", ErrBidTooLow, auction.StartPrice)
+		}
+		auction.LeaderID, auction.LeaderMax = &bidderID, &maxAmount
+
+	case *auction.LeaderID == bidderID:
+		if maxAmount <= *auction.LeaderMax {
+			return BidOutcome{}, fmt.Errorf("%w: new maximum must be above your current maximum of %d", ErrBidTooLow, *auction.LeaderMax)
+		}
+		price = *auction.CurrentPrice
+		auction.LeaderMax = &maxAmount
+
+	default:
+		minBid := *auction.CurrentPrice + auction.BidIncrement
+		if maxAmount < minBid {
+			return BidOutcome{}, fmt.Errorf("%w: minimum bid is %d", ErrBidTooLow, minBid)
+		}
+
+		leaderID, leaderMax := *auction.LeaderID, *auction.LeaderMax
+		if maxAmount > leaderMax {
+			// Penantang mengambil alih. Proxy pemimpin lama sudah naik sampai maksimumnya.
+			if leaderMax > *auction.CurrentPrice {
+				outcome.Bids = append(outcome.Bids, model.AuctionBid{BidderID: &leaderID, Amount: leaderMax, MaxAmount: leaderMax, IsAuto: true})
+			}
+			price = min(maxAmount, leaderMax+auction.BidIncrement)
+			auction.LeaderID, auction.LeaderMax = &bidderID, &maxAmount
+			outbid = &leaderID
+		} else {
+			// Proxy pemimpin membalas; bid penantang tercatat di maksimumnya
+			bid.Amount = maxAmount
+			outcome.Bids = append(outcome.Bids, bid)
+			price = min(leaderMax, maxAmount+auction.BidIncrement)
+			bid = model.AuctionBid{BidderID: &leaderID, MaxAmount: leaderMax, IsAuto: true}
+		}
+	}
+
+	if auction.ReservePrice != nil && *auction.LeaderMax >= *auction.ReservePrice && price < *auction.ReservePrice {
+		price = *auction.ReservePrice
+	}
+	bid.Amount = price
+	outcome.Bids = append(outcome.Bids, bid)
+
+	auction.CurrentPrice = &price
+	auction.BidCount += len(outcome.Bids)
+	if auction.EndsAt.Sub(now) < snipeWindow {
+		auction.EndsAt = now.Add(snipeWindow)
+		auction.ExtensionCount++
+	}
+	outcome.Auction = auction
+
+	if outbid != nil {
+		n, err := buildAuctionNotification(*outbid, auction, "You've been outbid",
+			fmt.Sprintf("%s is now at %d. Bid again before the auction ends.", productName, price))
+		if err != nil {
+			return BidOutcome{}, err
+		}
+		outcome.Notifications = append(outcome.Notifications, n)
+	}
+	return outcome, nil
+}
+
+// findShopMember mengambil keanggotaan shop seller, 403 jika akun belum punya shop atau
+// role-nya tidak boleh mengelola listing.
+func (s *service) findShopMember(ctx context.Context, sellerID uuid.UUID) (model.ShopMember, error) {
+	member, err := s.repo.FindShopMembership(ctx, sellerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ShopMember{}, apperror.New(apperror.ErrCodeForbidden, "you do not have a shop yet")
+		}
+		log.Printf("Error finding shop membership: %v", err)
+		return model.ShopMember{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if !staff.Can(member.Role, staff.PermissionListings) {
+		return model.ShopMember{}, apperror.New(apperror.ErrCodeForbidden, "your shop role does not allow managing auctions")
+	}
+	return member, nil
+}
+
+// buildView menyusun AuctionView untuk accountID. Reserve hanya terlihat oleh seller.
+func buildView(auction AuctionSummary, accountID uuid.UUID) AuctionView {
+	view := AuctionView{
+		AuctionSummary: auction,
+		MinNextBid:     auction.StartPrice,
+		ReserveMet:     reserveMet(auction.Auction),
+		Leading:        auction.LeaderID != nil && *auction.LeaderID == accountID,
+	}
+	if auction.CurrentPrice != nil {
+		view.MinNextBid = *auction.CurrentPrice + auction.BidIncrement
+	}
+	if auction.SellerID != accountID {
+		view.ReservePrice = nil
+	}
+	return view
+}
+
+// reserveMet bernilai true jika lelang tanpa reserve, atau harga saat ini sudah mencapai reserve.
+func reserveMet(auction model.Auction) bool {
+	if auction.ReservePrice == nil {
+		return true
+	}
+	return auction.CurrentPrice != nil && *auction.CurrentPrice >= *auction.ReservePrice
+}
+
+// notifyClosedAuction memberi tahu pemenang dan seller saat lelang ditutup job.
+func notifyClosedAuction(auction AuctionSummary, status string) ([]model.Notification, error) {
+	type message struct {
+		accountID   uuid.UUID
+		title, body string
+	}
+	var messages []message
+	if status == model.AuctionStatusSold {
+		messages = []message{
+			{*auction.LeaderID, "You won the auction", fmt.Sprintf("You won %s for %d. Choose shipping and complete payment to secure it.", auction.ProductName, *auction.CurrentPrice)},
+			{auction.SellerID, "Auction sold", fmt.Sprintf("%s sold at auction for %d.", auction.ProductName, *auction.CurrentPrice)},
+		}
+	} else {
+		messages = []message{
+			{auction.SellerID, "Auction ended without a sale", fmt.Sprintf("%s did not sell at auction and is back to fixed price.", auction.ProductName)},
+		}
+	}
+
+	var notifications []model.Notification
+	for _, m := range messages {
+		n, err := buildAuctionNotification(m.accountID, auction.Auction, m.title, m.body)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, nil
+}
+
+// buildAuctionNotification menyusun notifikasi lelang untuk satu penerima.
+func buildAuctionNotification(accountID uuid.UUID, auction model.Auction, title, body string) (model.Notification, error) {
+	data, err := json.Marshal(map[string]any{
+		"auction_id":    auction.ID,
+		"product_id":    auction.ProductID,
+		"status":        auction.Status,
+		"current_price": auction.CurrentPrice,
+		"ends_at":       auction.EndsAt,
+	})
+	if err != nil {
+		return model.Notification{}, err
+	}
+
+	return model.Notification{
+		AccountID: accountID,
+		Type:      model.NotificationTypeAuction,
+		Title:     title,
+		Body:      body,
+		Data:      data,
+	}, nil
+}
+
+// StartCloseJob menjalankan CloseEndedAuctions secara berkala sampai ctx dibatalkan.
+func StartCloseJob(ctx context.Context, svc Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			closed, err := svc.CloseEndedAuctions(ctx)
+			if err != nil {
+				log.Printf("Error closing ended auctions: %v", err)
+				continue
+			}
+			if closed > 0 {
+				log.Printf("Auction close job closed %d auction(s)", closed)
+			}
+		}
+	}
+}
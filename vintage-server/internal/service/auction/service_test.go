@@ -0,0 +1,308 @@
+package auction
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"vintage-server/internal/model"
+
+	"github.com/google/uuid"
+)
+
+func TestApplyBid(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	window := 5 * time.Minute
+	leader, challenger := uuid.New(), uuid.New()
+	ptr := func(v int64) *int64 { return &v }
+
+	// open membuat lelang aktif: harga awal 100, increment 10, berakhir 1 jam lagi
+	open := func() model.Auction {
+		return model.Auction{
+			Status:       model.AuctionStatusActive,
+			StartPrice:   100,
+			BidIncrement: 10,
+			StartsAt:     now.Add(-time.Hour),
+			EndsAt:       now.Add(time.Hour),
+		}
+	}
+	// led membuat lelang yang sudah dipimpin leader di harga current dengan maksimum max
+	led := func(current, max int64) model.Auction {
+		a := open()
+		a.LeaderID, a.LeaderMax, a.CurrentPrice = &leader, ptr(max), ptr(current)
+		a.BidCount = 1
+		return a
+	}
+
+	tests := []struct {
+		name      string
+		auction   model.Auction
+		bidder    uuid.UUID
+		max       int64
+		at        time.Time
+		wantErr   error
+		wantLead  uuid.UUID
+		wantMax   int64
+		wantPrice int64
+		wantBids  []model.AuctionBid
+		wantEnds  time.Time
+		wantExt   int
+		outbid    bool
+	}{
+		{
+			name:      "first bid leads at start price",
+			auction:   open(),
+			bidder:    leader,
+			max:       250,
+			wantLead:  leader,
+			wantMax:   250,
+			wantPrice: 100,
+			wantBids:  []model.AuctionBid{{BidderID: &leader, Amount: 100, MaxAmount: 250}},
+		},
+		{
+			name:    "first bid below start price",
+			auction: open(),
+			bidder:  leader,
+			max:     99,
+			wantErr: ErrBidTooLow,
+		},
+		{
+			name:      "leader raises own maximum without raising price",
+			auction:   led(150, 200),
+			bidder:    leader,
+			max:       400,
+			wantLead:  leader,
+			wantMax:   400,
+			wantPrice: 150,
+			wantBids:  []model.AuctionBid{{BidderID: &leader, Amount: 150, MaxAmount: 400}},
+		},
+		{
+			name:    "leader cannot lower own maximum",
+			auction: led(150, 200),
+			bidder:  leader,
+			max:     200,
+			wantErr: ErrBidTooLow,
+		},
+		{
+			name:    "challenger below minimum increment",
+			auction: led(150, 200),
+			bidder:  challenger,
+			max:     159,
+			wantErr: ErrBidTooLow,
+		},
+		{
+			name:      "challenger above leader maximum takes over",
+			auction:   led(150, 200),
+			bidder:    challenger,
+			max:       300,
+			wantLead:  challenger,
+			wantMax:   300,
+			wantPrice: 210,
+			wantBids: []model.AuctionBid{
+				{BidderID: &leader, Amount: 200, MaxAmount: 200, IsAuto: true},
+				{BidderID: &challenger, Amount: 210, MaxAmount: 300},
+			},
+			outbid: true,
+		},
+		{
+			name:      "challenger just above leader maximum pays own maximum",
+			auction:   led(150, 200),
+			bidder:    challenger,
+			max:       205,
+			wantLead:  challenger,
+			wantMax:   205,
+			wantPrice: 205,
+			wantBids: []model.AuctionBid{
+				{BidderID: &leader, Amount: 200, MaxAmount: 200, IsAuto: true},
+				{BidderID: &challenger, Amount: 205, MaxAmount: 205},
+			},
+			outbid: true,
+		},
+		{
+			name:      "challenger below leader maximum is answered by proxy",
+			auction:   led(150, 300),
+			bidder:    challenger,
+			max:       200,
+			wantLead:  leader,
+			wantMax:   300,
+			wantPrice: 210,
+			wantBids: []model.AuctionBid{
+				{BidderID: &challenger, Amount: 200, MaxAmount: 200},
+				{BidderID: &leader, Amount: 210, MaxAmount: 300, IsAuto: true},
+			},
+		},
+		{
+			name:      "tie is won by the earlier bidder",
+			auction:   led(150, 300),
+			bidder:    challenger,
+			max:       300,
+			wantLead:  leader,
+			wantMax:   300,
+			wantPrice: 300,
+			wantBids: []model.AuctionBid{
+				{BidderID: &challenger, Amount: 300, MaxAmount: 300},
+				{BidderID: &leader, Amount: 300, MaxAmount: 300, IsAuto: true},
+			},
+		},
+		{
+			name: "first bid reaching reserve jumps to reserve",
+			auction: func() model.Auction {
+				a := open()
+				a.ReservePrice = ptr(180)
+				return a
+			}(),
+			bidder:    leader,
+			max:       200,
+			wantLead:  leader,
+			wantMax:   200,
+			wantPrice: 180,
+			wantBids:  []model.AuctionBid{{BidderID: &leader, Amount: 180, MaxAmount: 200}},
+		},
+		{
+			name: "first bid under reserve stays at start price",
+			auction: func() model.Auction {
+				a := open()
+				a.ReservePrice = ptr(180)
+				return a
+			}(),
+			bidder:    leader,
+			max:       170,
+			wantLead:  leader,
+			wantMax:   170,
+			wantPrice: 100,
+			wantBids:  []model.AuctionBid{{BidderID: &leader, Amount: 100, MaxAmount: 170}},
+		},
+		{
+			name: "challenger crossing reserve takes over at reserve",
+			auction: func() model.Auction {
+				a := led(110, 120)
+				a.ReservePrice = ptr(250)
+				return a
+			}(),
+			bidder:    challenger,
+			max:       300,
+			wantLead:  challenger,
+			wantMax:   300,
+			wantPrice: 250,
+			wantBids: []model.AuctionBid{
+				{BidderID: &leader, Amount: 120, MaxAmount: 120, IsAuto: true},
+				{BidderID: &challenger, Amount: 250, MaxAmount: 300},
+			},
+			outbid: true,
+		},
+		{
+			name: "bid inside snipe window extends the auction",
+			auction: func() model.Auction {
+				a := open()
+				a.EndsAt = now.Add(2 * time.Minute)
+				return a
+			}(),
+			bidder:    leader,
+			max:       100,
+			wantLead:  leader,
+			wantMax:   100,
+			wantPrice: 100,
+			wantBids:  []model.AuctionBid{{BidderID: &leader, Amount: 100, MaxAmount: 100}},
+			wantEnds:  now.Add(window),
+			wantExt:   1,
+		},
+		{
+			name:    "auction already ended",
+			auction: open(),
+			bidder:  leader,
+			max:     500,
+			at:      now.Add(time.Hour),
+			wantErr: ErrAuctionNotActive,
+		},
+		{
+			name:    "auction not started yet",
+			auction: open(),
+			bidder:  leader,
+			max:     500,
+			at:      now.Add(-2 * time.Hour),
+			wantErr: ErrAuctionNotActive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at := tt.at
+			if at.IsZero() {
+				at = now
+			}
+			before := tt.auction
+			got, err := applyBid(tt.auction, "Levi's 501", tt.bidder, tt.max, at, window)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			a := got.Auction
+			if *a.LeaderID != tt.wantLead || *a.LeaderMax != tt.wantMax {
+				t.Errorf("leader = %v max %d, want %v max %d", *a.LeaderID, *a.LeaderMax, tt.wantLead, tt.wantMax)
+			}
+			if *a.CurrentPrice != tt.wantPrice {
+				t.Errorf("price = %d, want %d", *a.CurrentPrice, tt.wantPrice)
+			}
+			if a.BidCount != before.BidCount+len(tt.wantBids) {
+				t.Errorf("bid count = %d, want %d", a.BidCount, before.BidCount+len(tt.wantBids))
+			}
+
+			if len(got.Bids) != len(tt.wantBids) {
+				t.Fatalf("bids = %+v, want %+v", got.Bids, tt.wantBids)
+			}
+			for i, want := range tt.wantBids {
+				b := got.Bids[i]
+				if *b.BidderID != *want.BidderID || b.Amount != want.Amount || b.MaxAmount != want.MaxAmount || b.IsAuto != want.IsAuto {
+					t.Errorf("bid %d = {%v %d max %d auto %v}, want {%v %d max %d auto %v}", i,
+						*b.BidderID, b.Amount, b.MaxAmount, b.IsAuto, *want.BidderID, want.Amount, want.MaxAmount, want.IsAuto)
+				}
+			}
+
+			wantEnds := tt.wantEnds
+			if wantEnds.IsZero() {
+				wantEnds = before.EndsAt
+			}
+			if !a.EndsAt.Equal(wantEnds) || a.ExtensionCount != tt.wantExt {
+				t.Errorf("ends at %v (ext %d), want %v (ext %d)", a.EndsAt, a.ExtensionCount, wantEnds, tt.wantExt)
+			}
+
+			if tt.outbid {
+				if len(got.Notifications) != 1 || got.Notifications[0].AccountID != leader {
+					t.Errorf("notifications = %+v, want one outbid notification for the previous leader", got.Notifications)
+				}
+			} else if len(got.Notifications) != 0 {
+				t.Errorf("notifications = %+v, want none", got.Notifications)
+			}
+		})
+	}
+}
+
+func TestClosedState(t *testing.T) {
+	orderID := uuid.New()
+	tests := []struct {
+		name       string
+		orderID    *uuid.UUID
+		wantStatus string
+	}{
+		// Terjual: sisa stok & unit pemenang (jika ordernya batal / kedaluwarsa) dijual lagi dengan harga tetap
+		{"winner converted to an order", &orderID, model.AuctionStatusSold},
+		// Tanpa pemenang: tidak ada bid, reserve tidak tercapai, atau stok tidak bisa di-hold
+		{"no winner", nil, model.AuctionStatusUnsold},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, mode := closedState(tt.orderID)
+			if status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", status, tt.wantStatus)
+			}
+			if mode != model.ListingModeFixed {
+				t.Errorf("listing mode = %q, want %q", mode, model.ListingModeFixed)
+			}
+		})
+	}
+}
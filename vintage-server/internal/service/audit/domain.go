@@ -1 +1,28 @@
 package audit
+
+// File: internal/service/audit/domain.go
+
+import (
+	"time"
+	"vintage-server/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// Actor adalah admin yang melakukan sebuah perubahan beserta IP asal request-nya.
+// Service lain menerima Actor dari handler lalu mengubahnya menjadi baris admin_logs.
+type Actor struct {
+	AdminID   uuid.UUID
+	IPAddress string
+}
+
+// Entry membuat baris admin_logs untuk sebuah aksi, misal "brand.create".
+func (a Actor) Entry(action, description string) model.AdminLog {
+	return model.AdminLog{
+		AdminID:     a.AdminID,
+		Action:      action,
+		Description: &description,
+		IPAddress:   a.IPAddress,
+		CreatedAt:   time.Now(),
+	}
+}
@@ -1 +1,16 @@
 package audit
+
+import (
+	"vintage-server/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActorFromContext membangun Actor dari request admin yang sudah melewati middleware.RequireAuth.
+func ActorFromContext(c *gin.Context) Actor {
+	adminID, _ := middleware.GetAccountID(c)
+	return Actor{
+		AdminID:   adminID,
+		IPAddress: c.ClientIP(),
+	}
+}
@@ -1 +1,17 @@
 package audit
+
+import (
+	"context"
+	"vintage-server/internal/model"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SaveAdminLog menyimpan satu baris admin_logs.
+// exec bisa berupa *sqlx.DB atau *sqlx.Tx, sehingga log bisa ikut transaksi perubahan datanya.
+func SaveAdminLog(ctx context.Context, exec sqlx.ExtContext, entry model.AdminLog) error {
+	query := `INSERT INTO admin_logs (admin_id, action, description, ip_address, created_at)
+			  VALUES (:admin_id, :action, :description, :ip_address, :created_at)`
+	_, err := sqlx.NamedExecContext(ctx, exec, query, entry)
+	return err
+}
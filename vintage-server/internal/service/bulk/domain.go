@@ -0,0 +1,61 @@
+package bulk
+
+// File: internal/service/bulk/domain.go
+
+import (
+	"context"
+	"io"
+	"time"
+	"vintage-server/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// =================================================================================
+// KONTRAK UNTUK SERVICE (Logika Bisnis) 🧠
+// =================================================================================
+type Service interface {
+	// --- Import ---
+	// Usecase: SellerImport Listings (dry-run mengembalikan laporan validasi tanpa menyimpan apa pun)
+	CreateImport(ctx context.Context, sellerID uuid.UUID, req ImportRequest) (ImportResult, error)
+	// Usecase: SellerPoll Import Progress
+	GetImports(ctx context.Context, sellerID uuid.UUID) ([]model.ListingImport, error)
+	GetImport(ctx context.Context, sellerID, importID uuid.UUID) (model.ListingImport, error)
+
+	// ProcessImports dipanggil worker berkala: mengambil job yang antri / lease-nya habis
+	// lalu melanjutkannya dari baris terakhir yang tersimpan.
+	ProcessImports(ctx context.Context) (int, error)
+
+	// --- Export ---
+	// Usecase: SellerExport Listings (format kolom sama dengan import)
+	ExportListings(ctx context.Context, sellerID uuid.UUID, filter ExportFilter, w io.Writer) error
+}
+
+// =================================================================================
+// KONTRAK UNTUK REPOSITORY (Akses Database) 🚚
+// =================================================================================
+// Repository mendefinisikan semua interaksi ke database yang dibutuhkan oleh Service.
+type Repository interface {
+	// FindShopMembership mengambil shop tempat akun menjadi anggota beserta role-nya.
+	FindShopMembership(ctx context.Context, accountID uuid.UUID) (model.ShopMember, error)
+	// FindReferences memuat nama kategori, brand, ukuran, dan kondisi yang masih aktif.
+	FindReferences(ctx context.Context) (References, error)
+
+	// --- Import ---
+	SaveImport(ctx context.Context, job model.ListingImport) (model.ListingImport, error)
+	// FindImports & FindImportByID tidak memuat isi file (source / images).
+	FindImports(ctx context.Context, shopID uuid.UUID, limit int) ([]model.ListingImport, error)
+	FindImportByID(ctx context.Context, shopID, importID uuid.UUID) (model.ListingImport, error)
+	// ClaimImport mengambil satu job queued / running yang lease-nya sudah habis, menaikkan attempts,
+	// dan memperpanjang lease. Mengembalikan sql.ErrNoRows jika tidak ada job.
+	ClaimImport(ctx context.Context, leaseUntil time.Time) (model.ListingImport, error)
+	// TransactionImportBatch menyimpan listing satu batch, menambahkan error per baris, lalu
+	// memajukan next_row di transaksi yang sama sehingga batch tidak pernah tersimpan dua kali.
+	TransactionImportBatch(ctx context.Context, batch ImportBatch) error
+	// FindProductSlugs mengembalikan slug produk (aktif maupun lama) yang berawalan base.
+	FindProductSlugs(ctx context.Context, base string) ([]string, error)
+	FinishImport(ctx context.Context, importID uuid.UUID, status string, lastError *string) error
+
+	// --- Export ---
+	FindExportRows(ctx context.Context, shopID uuid.UUID, status string) ([]ExportRow, error)
+}
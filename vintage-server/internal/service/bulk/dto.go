@@ -0,0 +1,100 @@
+package bulk
+
+import (
+	"mime/multipart"
+	"time"
+	"vintage-server/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// ImportRequest dikirim sebagai multipart/form-data.
+type ImportRequest struct {
+	// File csv / xlsx, format ditentukan dari ekstensi nama file
+	File *multipart.FileHeader `form:"file" binding:"required"`
+	// Images (opsional) adalah zip berisi foto yang dirujuk nama filenya di kolom images
+	Images *multipart.FileHeader `form:"images"`
+	// Mapping (opsional) adalah JSON {"Nama Kolom": "field"} untuk header yang tidak dikenali otomatis.
+	// Field "" atau "-" berarti kolom diabaikan.
+	Mapping string `form:"mapping"`
+	DryRun  bool   `form:"dry_run"`
+}
+
+// RowError adalah kesalahan validasi pada satu sel. Row mengikuti nomor baris di spreadsheet
+// (header = baris 1).
+type RowError struct {
+	Row         int      `json:"row"`
+	Column      string   `json:"column,omitempty"`
+	Message     string   `json:"message"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// ValidationReport adalah hasil dry-run.
+type ValidationReport struct {
+	TotalRows int `json:"total_rows"`
+	ValidRows int `json:"valid_rows"`
+	// Columns adalah header -> field yang dipakai, IgnoredColumns adalah header yang tidak dipetakan
+	Columns        map[string]string `json:"columns"`
+	IgnoredColumns []string          `json:"ignored_columns"`
+	Errors         []RowError        `json:"errors"`
+	// Truncated bernilai true jika jumlah error melebihi batas laporan
+	Truncated bool `json:"truncated"`
+}
+
+type ImportResult struct {
+	DryRun bool                 `json:"dry_run"`
+	Report *ValidationReport    `json:"report,omitempty"`
+	Job    *model.ListingImport `json:"job,omitempty"`
+}
+
+type ExportFilter struct {
+	// Format csv (default) atau xlsx
+	Format string `form:"format"`
+	Status string `form:"status"`
+}
+
+// ReferenceName adalah id + nama data referensi untuk dicocokkan dengan isi spreadsheet.
+type ReferenceName struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+	// Slug hanya diisi untuk kategori
+	Slug string `db:"slug"`
+}
+
+type References struct {
+	Categories []ReferenceName
+	Brands     []ReferenceName
+	Sizes      []ReferenceName
+	Conditions []ReferenceName
+}
+
+// ImportedListing adalah satu baris valid yang siap disimpan sebagai draft.
+type ImportedListing struct {
+	Product   model.Product
+	ImageURLs []string
+}
+
+type ImportBatch struct {
+	ImportID   uuid.UUID
+	Listings   []ImportedListing
+	RowErrors  []RowError
+	NextRow    int
+	LeaseUntil time.Time
+}
+
+// ExportRow adalah satu listing dengan nama data referensi yang sudah di-join.
+type ExportRow struct {
+	Name            string  `db:"name"`
+	Summary         *string `db:"summary"`
+	Description     *string `db:"description"`
+	Price           *int64  `db:"price"`
+	Stock           int     `db:"stock"`
+	Category        *string `db:"category"`
+	Brand           *string `db:"brand"`
+	Size            *string `db:"size"`
+	Condition       *string `db:"condition"`
+	EraDecade       *int16  `db:"era_decade"`
+	CountryOfOrigin *string `db:"country_of_origin"`
+	Images          string  `db:"images"`
+	Status          string  `db:"status"`
+}
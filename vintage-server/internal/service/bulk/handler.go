@@ -0,0 +1,105 @@
+package bulk
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+	"vintage-server/pkg/middleware"
+	"vintage-server/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Handler adalah struct yang memegang dependency ke Service
+type Handler struct {
+	svc Service
+}
+
+// NewHandler adalah constructor untuk handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// --- Import ---
+
+// CreateImport menerima file csv / xlsx (multipart field "file") dan zip foto opsional ("images").
+// Dengan dry_run=true hanya laporan validasi yang dikembalikan; selain itu job diantrikan (202).
+func (h *Handler) CreateImport(c *gin.Context) {
+	sellerID, _ := middleware.GetAccountID(c)
+
+	var req ImportRequest
+	if err := c.ShouldBind(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Spreadsheet file is required")
+		return
+	}
+
+	result, err := h.svc.CreateImport(c.Request.Context(), sellerID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	if result.DryRun {
+		response.Success(c, http.StatusOK, result)
+		return
+	}
+	response.Success(c, http.StatusAccepted, result)
+}
+
+// GetImports mengembalikan job import terbaru milik shop seller
+func (h *Handler) GetImports(c *gin.Context) {
+	sellerID, _ := middleware.GetAccountID(c)
+
+	jobs, err := h.svc.GetImports(c.Request.Context(), sellerID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, jobs)
+}
+
+// GetImport mengembalikan progres satu job import (dipakai client untuk polling)
+func (h *Handler) GetImport(c *gin.Context) {
+	sellerID, _ := middleware.GetAccountID(c)
+
+	importID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid import id")
+		return
+	}
+
+	job, err := h.svc.GetImport(c.Request.Context(), sellerID, importID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, job)
+}
+
+// --- Export ---
+
+// ExportListings mengunduh listing shop seller sebagai csv / xlsx, ?format=xlsx&status=published
+func (h *Handler) ExportListings(c *gin.Context) {
+	sellerID, _ := middleware.GetAccountID(c)
+
+	var filter ExportFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := h.svc.ExportListings(c.Request.Context(), sellerID, filter, &buf); err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	format := filter.Format
+	if format == "" {
+		format = "csv"
+	}
+	fileName := fmt.Sprintf("listings-%s.%s", time.Now().Format("20060102"), format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Data(http.StatusOK, ContentType(format), buf.Bytes())
+}
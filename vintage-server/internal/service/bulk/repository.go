@@ -0,0 +1,228 @@
+package bulk
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/staff"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// maxStoredErrors membatasi jumlah error per baris yang disimpan di listing_imports.errors.
+const maxStoredErrors = 500
+
+// importColumns adalah kolom listing_imports tanpa isi file (source / images).
+const importColumns = `
+	id, shop_id, account_id, format, file_name, mapping, status, total_rows, next_row,
+	created_count, failed_count, errors, last_error, attempts, locked_until,
+	created_at, updated_at, finished_at`
+
+// repository adalah struct yang mengimplementasikan kontrak Repository dari domain.go
+type repository struct {
+	db *sqlx.DB
+}
+
+// NewRepository adalah constructor untuk implementasi repository
+func NewRepository(db *sqlx.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) FindShopMembership(ctx context.Context, accountID uuid.UUID) (model.ShopMember, error) {
+	return staff.FindMembership(ctx, r.db, accountID)
+}
+
+func (r *repository) FindReferences(ctx context.Context) (References, error) {
+	var refs References
+	queries := []struct {
+		dest  *[]ReferenceName
+		query string
+	}{
+		{&refs.Categories, "SELECT id, name, slug FROM product_categories WHERE deleted_at IS NULL"},
+		{&refs.Brands, "SELECT id, name, '' AS slug FROM brands WHERE deleted_at IS NULL"},
+		{&refs.Sizes, "SELECT id, size_name AS name, '' AS slug FROM product_size WHERE deleted_at IS NULL"},
+		{&refs.Conditions, "SELECT id, name, '' AS slug FROM product_conditions WHERE deleted_at IS NULL"},
+	}
+	for _, q := range queries {
+		if err := r.db.SelectContext(ctx, q.dest, q.query); err != nil {
+			return References{}, err
+		}
+	}
+	return refs, nil
+}
+
+// --- Import ---
+
+func (r *repository) SaveImport(ctx context.Context, job model.ListingImport) (model.ListingImport, error) {
+	var saved model.ListingImport
+	query := `
+		INSERT INTO listing_imports (shop_id, account_id, format, file_name, source, images, mapping, status, total_rows, created_at, updated_at)
+		VALUES (:shop_id, :account_id, :format, :file_name, :source, :images, :mapping, :status, :total_rows, :created_at, :updated_at)
+		RETURNING ` + importColumns
+	rows, err := r.db.NamedQueryContext(ctx, query, job)
+	if err != nil {
+		return saved, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return saved, sql.ErrNoRows
+	}
+	err = rows.StructScan(&saved)
+	return saved, err
+}
+
+func (r *repository) FindImports(ctx context.Context, shopID uuid.UUID, limit int) ([]model.ListingImport, error) {
+	jobs := []model.ListingImport{}
+	query := "SELECT " + importColumns + " FROM listing_imports WHERE shop_id = $1 ORDER BY created_at DESC LIMIT $2"
+	err := r.db.SelectContext(ctx, &jobs, query, shopID, limit)
+	return jobs, err
+}
+
+func (r *repository) FindImportByID(ctx context.Context, shopID, importID uuid.UUID) (model.ListingImport, error) {
+	var job model.ListingImport
+	query := "SELECT " + importColumns + " FROM listing_imports WHERE id = $1 AND shop_id = $2"
+	err := r.db.GetContext(ctx, &job, query, importID, shopID)
+	return job, err
+}
+
+func (r *repository) ClaimImport(ctx context.Context, leaseUntil time.Time) (model.ListingImport, error) {
+	var job model.ListingImport
+	// SKIP LOCKED supaya beberapa instance worker tidak mengambil job yang sama
+	query := `
+		UPDATE listing_imports SET
+			status = 'running', attempts = attempts + 1, locked_until = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = (
+			SELECT id FROM listing_imports
+			WHERE status IN ('queued', 'running') AND (locked_until IS NULL OR locked_until < CURRENT_TIMESTAMP)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`
+	err := r.db.GetContext(ctx, &job, query, leaseUntil)
+	return job, err
+}
+
+func (r *repository) TransactionImportBatch(ctx context.Context, batch ImportBatch) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Simpan listing (draft) beserta gambarnya
+	for _, listing := range batch.Listings {
+		var productID uuid.UUID
+		query := `
+			INSERT INTO products (
+				shop_id, condition_id, category_id, size_id, brand_id, name, slug, summary, description,
+				price, stock, era_decade, country_of_origin, status, created_at, updated_at
+			) VALUES (
+				:shop_id, :condition_id, :category_id, :size_id, :brand_id, :name, :slug, :summary, :description,
+				:price, :stock, :era_decade, :country_of_origin, :status, :created_at, :updated_at
+			)
+			RETURNING id`
+		bound, args, err := tx.BindNamed(query, listing.Product)
+		if err != nil {
+			return err
+		}
+		if err := tx.GetContext(ctx, &productID, bound, args...); err != nil {
+			return err
+		}
+
+		for i, url := range listing.ImageURLs {
+			query := "INSERT INTO product_images (product_id, image_index, url) VALUES ($1, $2, $3)"
+			if _, err := tx.ExecContext(ctx, query, productID, i, url); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Query 2: Catat progres dan error per baris
+	rowErrors, err := json.Marshal(batch.RowErrors)
+	if err != nil {
+		return err
+	}
+	query := `
+		UPDATE listing_imports SET
+			next_row = $2,
+			created_count = created_count + $3,
+			failed_count = failed_count + $4,
+			errors = CASE WHEN jsonb_array_length(errors) >= $6 THEN errors ELSE errors || $5::jsonb END,
+			locked_until = $7,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`
+	_, err = tx.ExecContext(ctx, query, batch.ImportID, batch.NextRow, len(batch.Listings),
+		failedRows(batch.RowErrors), string(rowErrors), maxStoredErrors, batch.LeaseUntil)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *repository) FindProductSlugs(ctx context.Context, base string) ([]string, error) {
+	slugs := []string{}
+	query := `
+		SELECT slug FROM products WHERE slug = $1 OR slug LIKE $1 || '-%'
+		UNION
+		SELECT slug FROM product_slug_history WHERE slug = $1 OR slug LIKE $1 || '-%'`
+	err := r.db.SelectContext(ctx, &slugs, query, base)
+	return slugs, err
+}
+
+func (r *repository) FinishImport(ctx context.Context, importID uuid.UUID, status string, lastError *string) error {
+	query := `
+		UPDATE listing_imports SET
+			status = $2, last_error = $3, locked_until = NULL,
+			finished_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP,
+			-- File asli tidak dibutuhkan lagi setelah job selesai
+			source = '', images = NULL
+		WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, importID, status, lastError)
+	return err
+}
+
+// --- Export ---
+
+func (r *repository) FindExportRows(ctx context.Context, shopID uuid.UUID, status string) ([]ExportRow, error) {
+	rows := []ExportRow{}
+	// Nama kategori yang dipakai lebih dari satu kategori diekspor sebagai slug supaya bisa diimpor ulang
+	query := `
+		SELECT
+			p.name, p.summary, p.description, p.price, p.stock,
+			CASE WHEN (
+				SELECT COUNT(*) FROM product_categories x
+				WHERE lower(x.name) = lower(c.name) AND x.deleted_at IS NULL
+			) > 1 THEN c.slug ELSE c.name END AS category,
+			b.name AS brand, sz.size_name AS size, pc.name AS condition,
+			p.era_decade, p.country_of_origin,
+			COALESCE((
+				SELECT string_agg(pi.url, ' | ' ORDER BY pi.image_index)
+				FROM product_images pi WHERE pi.product_id = p.id
+			), '') AS images,
+			p.status
+		FROM products p
+		LEFT JOIN product_categories c ON c.id = p.category_id
+		LEFT JOIN brands b ON b.id = p.brand_id
+		LEFT JOIN product_size sz ON sz.id = p.size_id
+		LEFT JOIN product_conditions pc ON pc.id = p.condition_id
+		WHERE p.shop_id = $1 AND p.deleted_at IS NULL AND ($2 = '' OR p.status = $2)
+		ORDER BY p.created_at`
+	err := r.db.SelectContext(ctx, &rows, query, shopID, status)
+	return rows, err
+}
+
+// failedRows menghitung jumlah baris unik yang gagal (satu baris bisa punya beberapa error).
+func failedRows(rowErrors []RowError) int {
+	rows := make(map[int]bool, len(rowErrors))
+	for _, e := range rowErrors {
+		rows[e.Row] = true
+	}
+	return len(rows)
+}
@@ -0,0 +1,212 @@
+package bulk
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Field produk yang bisa diisi dari spreadsheet. Urutannya sekaligus urutan kolom export.
+const (
+	fieldName        = "name"
+	fieldSummary     = "summary"
+	fieldDescription = "description"
+	fieldPrice       = "price"
+	fieldStock       = "stock"
+	fieldCategory    = "category"
+	fieldBrand       = "brand"
+	fieldSize        = "size"
+	fieldCondition   = "condition"
+	fieldEra         = "era"
+	fieldCountry     = "country"
+	fieldImages      = "images"
+)
+
+var importFields = []string{
+	fieldName, fieldSummary, fieldDescription, fieldPrice, fieldStock, fieldCategory,
+	fieldBrand, fieldSize, fieldCondition, fieldEra, fieldCountry, fieldImages,
+}
+
+// headerAliases memetakan header umum (termasuk export Instagram / Shopee berbahasa Indonesia) ke field.
+var headerAliases = map[string]string{
+	"name": fieldName, "nama": fieldName, "title": fieldName, "judul": fieldName,
+	"product name": fieldName, "nama produk": fieldName,
+	"summary": fieldSummary, "ringkasan": fieldSummary,
+	"description": fieldDescription, "deskripsi": fieldDescription, "deskripsi produk": fieldDescription, "caption": fieldDescription,
+	"price": fieldPrice, "harga": fieldPrice,
+	"stock": fieldStock, "stok": fieldStock, "qty": fieldStock, "quantity": fieldStock,
+	"category": fieldCategory, "kategori": fieldCategory,
+	"brand": fieldBrand, "merek": fieldBrand, "merk": fieldBrand,
+	"size": fieldSize, "ukuran": fieldSize,
+	"condition": fieldCondition, "kondisi": fieldCondition,
+	"era": fieldEra, "decade": fieldEra, "dekade": fieldEra,
+	"country": fieldCountry, "country of origin": fieldCountry, "negara": fieldCountry, "negara asal": fieldCountry,
+	"images": fieldImages, "image": fieldImages, "image urls": fieldImages, "gambar": fieldImages, "foto": fieldImages, "photos": fieldImages,
+}
+
+// columnMap adalah hasil pemetaan header: indeks kolom per field.
+type columnMap struct {
+	index   map[string]int
+	used    map[string]string
+	ignored []string
+}
+
+// mapColumns memetakan header ke field. mapping dari seller diprioritaskan di atas alias bawaan.
+func mapColumns(header []string, mapping map[string]string) (columnMap, error) {
+	custom := make(map[string]string, len(mapping))
+	for column, field := range mapping {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field != "" && field != "-" && !isImportField(field) {
+			return columnMap{}, fmt.Errorf("mapping for %q uses unknown field %q", column, field)
+		}
+		custom[normalizeName(column)] = field
+	}
+
+	columns := columnMap{index: map[string]int{}, used: map[string]string{}}
+	for i, raw := range header {
+		key := normalizeName(raw)
+		field, ok := custom[key]
+		if !ok {
+			field = headerAliases[key]
+		}
+		if field == "" || field == "-" {
+			if key != "" {
+				columns.ignored = append(columns.ignored, raw)
+			}
+			continue
+		}
+		if _, dup := columns.index[field]; dup {
+			return columnMap{}, fmt.Errorf("more than one column is mapped to %q", field)
+		}
+		columns.index[field] = i
+		columns.used[raw] = field
+	}
+
+	if _, ok := columns.index[fieldName]; !ok {
+		return columnMap{}, fmt.Errorf("no column is mapped to %q", fieldName)
+	}
+	return columns, nil
+}
+
+// value mengambil isi sel untuk field tertentu ("" jika kolom tidak ada / baris lebih pendek).
+func (m columnMap) value(row []string, field string) string {
+	i, ok := m.index[field]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+func isImportField(field string) bool {
+	for _, f := range importFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// referenceIndex mencocokkan nama (atau slug untuk kategori) dengan id data referensi.
+type referenceIndex struct {
+	byName map[string][]ReferenceName
+	bySlug map[string]ReferenceName
+	all    []ReferenceName
+}
+
+func newReferenceIndex(items []ReferenceName) referenceIndex {
+	index := referenceIndex{
+		byName: make(map[string][]ReferenceName, len(items)),
+		bySlug: make(map[string]ReferenceName),
+		all:    items,
+	}
+	for _, item := range items {
+		key := normalizeName(item.Name)
+		index.byName[key] = append(index.byName[key], item)
+		if item.Slug != "" {
+			index.bySlug[item.Slug] = item
+		}
+	}
+	return index
+}
+
+// resolve mengembalikan id untuk value. Jika tidak ketemu (atau ambigu), suggestions berisi
+// kandidat terdekat yang bisa dipakai seller untuk memperbaiki spreadsheet.
+func (ix referenceIndex) resolve(value string) (id int, suggestions []string, ok bool) {
+	if item, found := ix.bySlug[strings.ToLower(strings.TrimSpace(value))]; found {
+		return item.ID, nil, true
+	}
+
+	matches := ix.byName[normalizeName(value)]
+	if len(matches) == 1 {
+		return matches[0].ID, nil, true
+	}
+	if len(matches) > 1 {
+		// Nama kategori bisa sama di cabang berbeda (misal "Jackets" pria & wanita)
+		for _, m := range matches {
+			suggestions = append(suggestions, m.Slug)
+		}
+		return 0, suggestions, false
+	}
+	return 0, ix.suggest(value), false
+}
+
+// suggest mencari maksimal 3 nama dengan jarak edit terdekat.
+func (ix referenceIndex) suggest(value string) []string {
+	key := normalizeName(value)
+	limit := utf8.RuneCountInString(key) / 3
+	if limit < 2 {
+		limit = 2
+	}
+
+	type candidate struct {
+		name     string
+		distance int
+	}
+	var candidates []candidate
+	for _, item := range ix.all {
+		name := normalizeName(item.Name)
+		distance := levenshtein(key, name)
+		// "denim" untuk "Denim Jackets" tetap disarankan walau jarak editnya jauh
+		if key != "" && (strings.Contains(name, key) || strings.Contains(key, name)) {
+			distance = 1
+		}
+		if distance <= limit {
+			candidates = append(candidates, candidate{name: item.Name, distance: distance})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+
+	var suggestions []string
+	for i := 0; i < len(candidates) && i < 3; i++ {
+		suggestions = append(suggestions, candidates[i].name)
+	}
+	return suggestions
+}
+
+// normalizeName menyamakan huruf besar/kecil dan spasi berlebih.
+func normalizeName(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+}
+
+// levenshtein menghitung jarak edit antar dua string (per rune).
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
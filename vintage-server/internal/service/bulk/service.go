@@ -0,0 +1,764 @@
+package bulk
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/staff"
+	"vintage-server/pkg/apperror"
+	"vintage-server/pkg/slug"
+	"vintage-server/pkg/spreadsheet"
+	"vintage-server/pkg/storage"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxImportFileSize adalah batas ukuran file csv / xlsx (10 MB).
+	maxImportFileSize = 10 << 20
+	// maxImagesZipSize adalah batas ukuran zip foto (50 MB).
+	maxImagesZipSize = 50 << 20
+	// maxImageSize adalah batas ukuran satu foto di dalam zip (5 MB).
+	maxImageSize  = 5 << 20
+	maxImportRows = 2000
+	// maxImagesPerListing sama dengan jumlah foto maksimal per listing.
+	maxImagesPerListing = 10
+	maxReportErrors     = 500
+	importListLimit     = 20
+	// maxSlugBase menyisakan ruang suffix "-N" di kolom products.slug (160)
+	maxSlugBase = 140
+
+	// importBatchSize adalah jumlah baris per transaksi; progres tersimpan setiap batch.
+	importBatchSize = 50
+	// importLease adalah lama job dikunci satu worker sebelum boleh diambil worker lain.
+	importLease       = 2 * time.Minute
+	maxImportAttempts = 5
+
+	minEraDecade = 1900
+)
+
+// imageExtensions adalah content type foto yang diterima beserta ekstensi file-nya.
+var imageExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/webp": ".webp",
+}
+
+// formatContentTypes dipakai handler export untuk header Content-Type.
+var formatContentTypes = map[string]string{
+	spreadsheet.FormatCSV:  "text/csv; charset=utf-8",
+	spreadsheet.FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// service adalah struct yang akan mengimplementasikan interface Service dari domain.go
+type service struct {
+	repo    Repository
+	storage storage.Storage
+}
+
+// NewService adalah constructor untuk service
+func NewService(repo Repository, store storage.Storage) Service {
+	return &service{
+		repo:    repo,
+		storage: store,
+	}
+}
+
+// resolver berisi indeks data referensi untuk satu kali validasi / job.
+type resolver struct {
+	categories referenceIndex
+	brands     referenceIndex
+	sizes      referenceIndex
+	conditions referenceIndex
+}
+
+// pendingListing adalah baris valid yang fotonya belum disimpan ke storage.
+type pendingListing struct {
+	product model.Product
+	images  []imageRef
+}
+
+// imageRef adalah URL eksternal atau file di dalam zip foto.
+type imageRef struct {
+	url  string
+	file *zip.File
+}
+
+// --- Import ---
+
+func (s *service) CreateImport(ctx context.Context, sellerID uuid.UUID, req ImportRequest) (ImportResult, error) {
+	shopID, err := s.sellerShopID(ctx, sellerID)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(req.File.Filename)), ".")
+	if format != spreadsheet.FormatCSV && format != spreadsheet.FormatXLSX {
+		return ImportResult{}, apperror.New(apperror.ErrCodeValidation, "file must be a .csv or .xlsx spreadsheet")
+	}
+	source, err := readUpload(req.File, maxImportFileSize, "file must be at most 10 MB")
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	mapping := map[string]string{}
+	if strings.TrimSpace(req.Mapping) != "" {
+		if err := json.Unmarshal([]byte(req.Mapping), &mapping); err != nil {
+			return ImportResult{}, apperror.New(apperror.ErrCodeValidation, `mapping must be a JSON object like {"Harga": "price"}`)
+		}
+	}
+
+	rows, err := spreadsheet.Read(format, source)
+	if err != nil {
+		return ImportResult{}, apperror.New(apperror.ErrCodeValidation, "could not read spreadsheet: "+err.Error())
+	}
+	if len(rows) < 2 {
+		return ImportResult{}, apperror.New(apperror.ErrCodeValidation, "spreadsheet has no data rows")
+	}
+	if len(rows)-1 > maxImportRows {
+		return ImportResult{}, apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("spreadsheet can have at most %d rows per import", maxImportRows))
+	}
+	columns, err := mapColumns(rows[0], mapping)
+	if err != nil {
+		return ImportResult{}, apperror.New(apperror.ErrCodeValidation, err.Error())
+	}
+
+	var images []byte
+	var archive *zip.Reader
+	if req.Images != nil {
+		images, err = readUpload(req.Images, maxImagesZipSize, "images zip must be at most 50 MB")
+		if err != nil {
+			return ImportResult{}, err
+		}
+		if archive, err = zip.NewReader(bytes.NewReader(images), int64(len(images))); err != nil {
+			return ImportResult{}, apperror.New(apperror.ErrCodeValidation, "images must be a zip file")
+		}
+	}
+
+	if req.DryRun {
+		report, err := s.validate(ctx, rows, columns, archive)
+		if err != nil {
+			return ImportResult{}, err
+		}
+		return ImportResult{DryRun: true, Report: &report}, nil
+	}
+
+	mappingJSON, _ := json.Marshal(mapping)
+	now := time.Now()
+	job, err := s.repo.SaveImport(ctx, model.ListingImport{
+		ShopID:    shopID,
+		AccountID: sellerID,
+		Format:    format,
+		FileName:  req.File.Filename,
+		Source:    source,
+		Images:    images,
+		Mapping:   mappingJSON,
+		Status:    model.ImportStatusQueued,
+		TotalRows: len(rows) - 1,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		log.Printf("Error saving listing import: %v", err)
+		return ImportResult{}, apperror.New(apperror.ErrCodeInternal, "failed to queue import")
+	}
+	return ImportResult{Job: &job}, nil
+}
+
+func (s *service) GetImports(ctx context.Context, sellerID uuid.UUID) ([]model.ListingImport, error) {
+	shopID, err := s.sellerShopID(ctx, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := s.repo.FindImports(ctx, shopID, importListLimit)
+	if err != nil {
+		log.Printf("Error finding listing imports: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return jobs, nil
+}
+
+func (s *service) GetImport(ctx context.Context, sellerID, importID uuid.UUID) (model.ListingImport, error) {
+	shopID, err := s.sellerShopID(ctx, sellerID)
+	if err != nil {
+		return model.ListingImport{}, err
+	}
+
+	job, err := s.repo.FindImportByID(ctx, shopID, importID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ListingImport{}, apperror.New(apperror.ErrCodeNotFound, "import not found")
+		}
+		log.Printf("Error finding listing import: %v", err)
+		return model.ListingImport{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return job, nil
+}
+
+func (s *service) ProcessImports(ctx context.Context) (int, error) {
+	processed := 0
+	for {
+		job, err := s.repo.ClaimImport(ctx, time.Now().Add(importLease))
+		if errors.Is(err, sql.ErrNoRows) {
+			return processed, nil
+		}
+		if err != nil {
+			return processed, fmt.Errorf("claim import: %w", err)
+		}
+
+		if err := s.runImport(ctx, job); err != nil {
+			// Job tetap 'running' dan akan dilanjutkan setelah lease habis, kecuali sudah terlalu sering gagal
+			log.Printf("Error running listing import %s (attempt %d): %v", job.ID, job.Attempts, err)
+			if job.Attempts >= maxImportAttempts {
+				msg := "import stopped after repeated errors, please try again"
+				if err := s.repo.FinishImport(ctx, job.ID, model.ImportStatusFailed, &msg); err != nil {
+					return processed, fmt.Errorf("finish import: %w", err)
+				}
+			}
+			continue
+		}
+		processed++
+	}
+}
+
+// StartImportWorker menjalankan ProcessImports secara berkala sampai ctx dibatalkan.
+func StartImportWorker(ctx context.Context, svc Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			done, err := svc.ProcessImports(ctx)
+			if err != nil {
+				log.Printf("Error processing listing imports: %v", err)
+				continue
+			}
+			if done > 0 {
+				log.Printf("Import worker finished %d job(s)", done)
+			}
+		}
+	}
+}
+
+// runImport melanjutkan job dari job.NextRow. Error yang dikembalikan bersifat sementara (DB / storage);
+// file yang memang tidak bisa dibaca langsung menandai job gagal.
+func (s *service) runImport(ctx context.Context, job model.ListingImport) error {
+	fail := func(msg string) error {
+		return s.repo.FinishImport(ctx, job.ID, model.ImportStatusFailed, &msg)
+	}
+
+	rows, err := spreadsheet.Read(job.Format, job.Source)
+	if err != nil {
+		return fail("could not read spreadsheet: " + err.Error())
+	}
+	if len(rows) < 2 {
+		return fail("spreadsheet has no data rows")
+	}
+	mapping := map[string]string{}
+	if err := json.Unmarshal(job.Mapping, &mapping); err != nil {
+		return fail("invalid column mapping")
+	}
+	columns, err := mapColumns(rows[0], mapping)
+	if err != nil {
+		return fail(err.Error())
+	}
+	var archive *zip.Reader
+	if len(job.Images) > 0 {
+		if archive, err = zip.NewReader(bytes.NewReader(job.Images), int64(len(job.Images))); err != nil {
+			return fail("images must be a zip file")
+		}
+	}
+
+	refs, err := s.loadResolver(ctx)
+	if err != nil {
+		return err
+	}
+
+	data := rows[1:]
+	for start := job.NextRow; start < len(data); start += importBatchSize {
+		end := min(start+importBatchSize, len(data))
+		batch := ImportBatch{ImportID: job.ID, NextRow: end}
+
+		var pending []pendingListing
+		for i := start; i < end; i++ {
+			listing, rowErrors := refs.parseRow(data[i], i+2, columns, archive)
+			if len(rowErrors) > 0 {
+				batch.RowErrors = append(batch.RowErrors, rowErrors...)
+				continue
+			}
+			listing.product.ShopID = job.ShopID
+			pending = append(pending, listing)
+		}
+		if err := s.assignSlugs(ctx, pending); err != nil {
+			return err
+		}
+
+		listings, keys, err := s.storeImages(ctx, job.ShopID, pending)
+		if err != nil {
+			return err
+		}
+		batch.Listings = listings
+		batch.LeaseUntil = time.Now().Add(importLease)
+
+		if err := s.repo.TransactionImportBatch(ctx, batch); err != nil {
+			for _, key := range keys {
+				s.storage.Delete(ctx, key)
+			}
+			return err
+		}
+	}
+
+	return s.repo.FinishImport(ctx, job.ID, model.ImportStatusCompleted, nil)
+}
+
+// validate menjalankan validasi yang sama dengan job tanpa menyimpan apa pun (dry-run).
+func (s *service) validate(ctx context.Context, rows [][]string, columns columnMap, archive *zip.Reader) (ValidationReport, error) {
+	refs, err := s.loadResolver(ctx)
+	if err != nil {
+		log.Printf("Error loading import references: %v", err)
+		return ValidationReport{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	report := ValidationReport{
+		TotalRows:      len(rows) - 1,
+		Columns:        columns.used,
+		IgnoredColumns: columns.ignored,
+		Errors:         []RowError{},
+	}
+	for i, row := range rows[1:] {
+		_, rowErrors := refs.parseRow(row, i+2, columns, archive)
+		if len(rowErrors) == 0 {
+			report.ValidRows++
+			continue
+		}
+		for _, e := range rowErrors {
+			if len(report.Errors) >= maxReportErrors {
+				report.Truncated = true
+				break
+			}
+			report.Errors = append(report.Errors, e)
+		}
+	}
+	return report, nil
+}
+
+func (s *service) loadResolver(ctx context.Context) (resolver, error) {
+	refs, err := s.repo.FindReferences(ctx)
+	if err != nil {
+		return resolver{}, err
+	}
+	return resolver{
+		categories: newReferenceIndex(refs.Categories),
+		brands:     newReferenceIndex(refs.Brands),
+		sizes:      newReferenceIndex(refs.Sizes),
+		conditions: newReferenceIndex(refs.Conditions),
+	}, nil
+}
+
+// parseRow mengubah satu baris spreadsheet menjadi listing draft. line adalah nomor baris di file.
+func (r resolver) parseRow(row []string, line int, columns columnMap, archive *zip.Reader) (pendingListing, []RowError) {
+	var rowErrors []RowError
+	addError := func(column, message string, suggestions ...string) {
+		rowErrors = append(rowErrors, RowError{Row: line, Column: column, Message: message, Suggestions: suggestions})
+	}
+	optional := func(value string) *string {
+		if value == "" {
+			return nil
+		}
+		return &value
+	}
+
+	now := time.Now()
+	product := model.Product{
+		Status:    model.ListingStatusDraft,
+		Stock:     1,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	product.Name = columns.value(row, fieldName)
+	if product.Name == "" {
+		addError(fieldName, "name is required")
+	} else if utf8.RuneCountInString(product.Name) > 128 {
+		addError(fieldName, "name must be at most 128 characters")
+	}
+	product.Summary = optional(columns.value(row, fieldSummary))
+	if product.Summary != nil && utf8.RuneCountInString(*product.Summary) > 255 {
+		addError(fieldSummary, "summary must be at most 255 characters")
+	}
+	product.Description = optional(columns.value(row, fieldDescription))
+
+	if value := columns.value(row, fieldPrice); value != "" {
+		price, ok := parsePrice(value)
+		if !ok {
+			addError(fieldPrice, fmt.Sprintf("price %q is not a valid amount", value))
+		}
+		product.Price = &price
+	}
+	if value := columns.value(row, fieldStock); value != "" {
+		stock, err := strconv.Atoi(value)
+		if err != nil || stock < 0 {
+			addError(fieldStock, fmt.Sprintf("stock %q must be a whole number", value))
+		}
+		product.Stock = stock
+	}
+
+	references := []struct {
+		field string
+		index referenceIndex
+		set   func(id int)
+	}{
+		{fieldCategory, r.categories, func(id int) { product.CategoryID = &id }},
+		{fieldBrand, r.brands, func(id int) { product.BrandID = &id }},
+		{fieldSize, r.sizes, func(id int) { product.SizeID = &id }},
+		{fieldCondition, r.conditions, func(id int) { conditionID := int16(id); product.ConditionID = &conditionID }},
+	}
+	for _, ref := range references {
+		value := columns.value(row, ref.field)
+		if value == "" {
+			continue
+		}
+		id, suggestions, ok := ref.index.resolve(value)
+		if !ok {
+			addError(ref.field, fmt.Sprintf("unknown %s %q", ref.field, value), suggestions...)
+			continue
+		}
+		ref.set(id)
+	}
+
+	if value := columns.value(row, fieldEra); value != "" {
+		decade, ok := parseEra(value)
+		if !ok {
+			addError(fieldEra, fmt.Sprintf("era %q must be a decade like 1990s or 90s", value))
+		}
+		product.EraDecade = &decade
+	}
+	if value := strings.ToUpper(columns.value(row, fieldCountry)); value != "" {
+		if !isCountryCode(value) {
+			addError(fieldCountry, fmt.Sprintf("country %q must be a 2-letter ISO code like JP", value))
+		}
+		product.CountryOfOrigin = &value
+	}
+
+	images, imageErrors := parseImages(columns.value(row, fieldImages), archive)
+	for _, msg := range imageErrors {
+		addError(fieldImages, msg)
+	}
+
+	return pendingListing{product: product, images: images}, rowErrors
+}
+
+// storeImages menyimpan foto dari zip ke storage. keys dikembalikan agar bisa dihapus jika batch gagal.
+// assignSlugs memberi slug unik ke setiap listing batch, termasuk antar listing
+// bernama sama di dalam batch yang sama.
+func (s *service) assignSlugs(ctx context.Context, pending []pendingListing) error {
+	assigned := map[string][]string{}
+	for i := range pending {
+		base := slug.Truncate(slug.Make(pending[i].product.Name), maxSlugBase)
+		if base == "" {
+			base = "product"
+		}
+		if _, ok := assigned[base]; !ok {
+			taken, err := s.repo.FindProductSlugs(ctx, base)
+			if err != nil {
+				return err
+			}
+			assigned[base] = taken
+		}
+		pending[i].product.Slug = slug.Unique(base, assigned[base])
+		assigned[base] = append(assigned[base], pending[i].product.Slug)
+	}
+	return nil
+}
+
+func (s *service) storeImages(ctx context.Context, shopID uuid.UUID, pending []pendingListing) ([]ImportedListing, []string, error) {
+	var keys []string
+	listings := make([]ImportedListing, 0, len(pending))
+	for _, p := range pending {
+		listing := ImportedListing{Product: p.product}
+		for _, img := range p.images {
+			if img.file == nil {
+				listing.ImageURLs = append(listing.ImageURLs, img.url)
+				continue
+			}
+
+			content, ext, err := readZipImage(img.file)
+			if err != nil {
+				s.deleteKeys(ctx, keys)
+				return nil, nil, err
+			}
+			key := path.Join("products", shopID.String(), uuid.NewString()+ext)
+			url, err := s.storage.Save(ctx, key, bytes.NewReader(content))
+			if err != nil {
+				s.deleteKeys(ctx, keys)
+				return nil, nil, fmt.Errorf("store image %s: %w", img.file.Name, err)
+			}
+			keys = append(keys, key)
+			listing.ImageURLs = append(listing.ImageURLs, url)
+		}
+		listings = append(listings, listing)
+	}
+	return listings, keys, nil
+}
+
+func (s *service) deleteKeys(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		s.storage.Delete(ctx, key)
+	}
+}
+
+// --- Export ---
+
+func (s *service) ExportListings(ctx context.Context, sellerID uuid.UUID, filter ExportFilter, w io.Writer) error {
+	shopID, err := s.sellerShopID(ctx, sellerID)
+	if err != nil {
+		return err
+	}
+	if filter.Format == "" {
+		filter.Format = spreadsheet.FormatCSV
+	}
+	if _, ok := formatContentTypes[filter.Format]; !ok {
+		return apperror.New(apperror.ErrCodeValidation, "format must be csv or xlsx")
+	}
+
+	listings, err := s.repo.FindExportRows(ctx, shopID, filter.Status)
+	if err != nil {
+		log.Printf("Error finding export rows: %v", err)
+		return apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	text := func(value *string) string {
+		if value == nil {
+			return ""
+		}
+		return *value
+	}
+	header := append(append([]string{}, importFields...), "status")
+	rows := [][]string{header}
+	for _, l := range listings {
+		price, era := "", ""
+		if l.Price != nil {
+			price = strconv.FormatInt(*l.Price, 10)
+		}
+		if l.EraDecade != nil {
+			era = fmt.Sprintf("%ds", *l.EraDecade)
+		}
+		rows = append(rows, []string{
+			l.Name, text(l.Summary), text(l.Description), price, strconv.Itoa(l.Stock), text(l.Category),
+			text(l.Brand), text(l.Size), text(l.Condition), era, text(l.CountryOfOrigin), l.Images, l.Status,
+		})
+	}
+
+	if err := spreadsheet.Write(filter.Format, w, rows); err != nil {
+		log.Printf("Error writing export: %v", err)
+		return apperror.New(apperror.ErrCodeInternal, "failed to write export")
+	}
+	return nil
+}
+
+// ContentType mengembalikan Content-Type untuk format export.
+func ContentType(format string) string {
+	if contentType, ok := formatContentTypes[format]; ok {
+		return contentType
+	}
+	return formatContentTypes[spreadsheet.FormatCSV]
+}
+
+func (s *service) sellerShopID(ctx context.Context, sellerID uuid.UUID) (uuid.UUID, error) {
+	member, err := s.repo.FindShopMembership(ctx, sellerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, apperror.New(apperror.ErrCodeForbidden, "open a shop before listing products")
+		}
+		log.Printf("Error finding seller shop: %v", err)
+		return uuid.Nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if !staff.Can(member.Role, staff.PermissionListings) {
+		return uuid.Nil, apperror.New(apperror.ErrCodeForbidden, "your shop role does not allow managing listings")
+	}
+	return member.ShopID, nil
+}
+
+// readUpload membaca file upload ke memori dengan batas ukuran.
+func readUpload(file *multipart.FileHeader, maxSize int64, tooLarge string) ([]byte, error) {
+	if file.Size > maxSize {
+		return nil, apperror.New(apperror.ErrCodeValidation, tooLarge)
+	}
+	src, err := file.Open()
+	if err != nil {
+		return nil, apperror.New(apperror.ErrCodeValidation, "failed to read uploaded file")
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, maxSize+1))
+	if err != nil {
+		return nil, apperror.New(apperror.ErrCodeValidation, "failed to read uploaded file")
+	}
+	if int64(len(data)) > maxSize {
+		return nil, apperror.New(apperror.ErrCodeValidation, tooLarge)
+	}
+	return data, nil
+}
+
+// parseImages memecah kolom images (dipisah '|' atau baris baru) menjadi URL eksternal / file zip.
+func parseImages(value string, archive *zip.Reader) ([]imageRef, []string) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var files map[string]*zip.File
+	if archive != nil {
+		files = make(map[string]*zip.File, len(archive.File))
+		for _, f := range archive.File {
+			if !f.FileInfo().IsDir() {
+				files[strings.ToLower(path.Base(f.Name))] = f
+			}
+		}
+	}
+
+	var images []imageRef
+	var problems []string
+	parts := strings.FieldsFunc(value, func(r rune) bool { return r == '|' || r == '\n' })
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if strings.HasPrefix(part, "http://") || strings.HasPrefix(part, "https://") {
+			if len(part) > 255 {
+				problems = append(problems, "image URL must be at most 255 characters")
+				continue
+			}
+			images = append(images, imageRef{url: part})
+			continue
+		}
+		if files == nil {
+			problems = append(problems, fmt.Sprintf("image %q is not a URL and no images zip was uploaded", part))
+			continue
+		}
+		f, ok := files[strings.ToLower(path.Base(part))]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("image %q not found in the zip", part))
+			continue
+		}
+		if _, _, err := readZipImage(f); err != nil {
+			problems = append(problems, fmt.Sprintf("image %q: %v", part, err))
+			continue
+		}
+		images = append(images, imageRef{file: f})
+	}
+	if len(images) > maxImagesPerListing {
+		problems = append(problems, fmt.Sprintf("a listing can have at most %d images", maxImagesPerListing))
+	}
+	return images, problems
+}
+
+// readZipImage membaca foto dari zip dan menentukan ekstensinya dari isi file.
+func readZipImage(f *zip.File) ([]byte, string, error) {
+	if f.UncompressedSize64 > maxImageSize {
+		return nil, "", errors.New("image must be at most 5 MB")
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, "", err
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(io.LimitReader(rc, maxImageSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(content) > maxImageSize {
+		return nil, "", errors.New("image must be at most 5 MB")
+	}
+	ext, ok := imageExtensions[http.DetectContentType(content)]
+	if !ok {
+		return nil, "", errors.New("image must be a PNG, JPEG or WebP file")
+	}
+	return content, ext, nil
+}
+
+// parsePrice menerima format harga umum seperti "150000", "150.000", "Rp 150.000", atau "150.000,00".
+func parsePrice(value string) (int64, bool) {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && strings.EqualFold(value[:2], "rp") {
+		value = strings.TrimSpace(value[2:])
+	}
+	// Dua digit setelah pemisah terakhir adalah sen; rupiah tidak memakai sen jadi harus "00"
+	if i := strings.LastIndexAny(value, ".,"); i >= 0 && len(value)-i-1 == 2 {
+		if value[i+1:] != "00" {
+			return 0, false
+		}
+		value = value[:i]
+	}
+
+	// Selain kelompok pertama, setiap kelompok ribuan harus tepat 3 digit ("1,5" ditolak)
+	groups := strings.FieldsFunc(value, func(r rune) bool { return r == '.' || r == ',' || r == ' ' })
+	if len(groups) == 0 {
+		return 0, false
+	}
+	for i, group := range groups {
+		if i > 0 && len(group) != 3 {
+			return 0, false
+		}
+	}
+	price, err := strconv.ParseInt(strings.Join(groups, ""), 10, 64)
+	return price, err == nil && price >= 0
+}
+
+// parseEra menerima "1990", "1990s", "90s", "90's", atau "90-an" dan membulatkan tahun ke dekadenya.
+func parseEra(value string) (int16, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "s"), "an")
+	value = strings.Trim(value, "'-")
+
+	year, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+	if len(value) == 2 {
+		// Untuk barang vintage "20s" lebih mungkin 1920-an; hanya "00s" dan "10s" yang dianggap 2000-an
+		if year < 20 {
+			year += 2000
+		} else {
+			year += 1900
+		}
+	}
+
+	decade := year / 10 * 10
+	if decade < minEraDecade || decade > time.Now().Year()/10*10 {
+		return 0, false
+	}
+	return int16(decade), true
+}
+
+func isCountryCode(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+package feed
+
+// File: internal/service/feed/domain.go
+
+import (
+	"context"
+	"mime/multipart"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/audit"
+
+	"github.com/google/uuid"
+)
+
+// =================================================================================
+// KONTRAK UNTUK SERVICE (Logika Bisnis) 🧠
+// =================================================================================
+type Service interface {
+	// --- Home ---
+	// Usecase: CustomerView Home Feed (new arrivals, koleksi aktif, dan "just dropped" jika login)
+	GetHomeFeed(ctx context.Context, viewerID *uuid.UUID) (HomeFeed, error)
+	// Usecase: CustomerBrowse New Arrivals
+	GetNewArrivals(ctx context.Context, filter NewArrivalsFilter) (ProductCardPage, error)
+	// Usecase: CustomerView Just Dropped (listing baru dari shop yang diikuti)
+	GetJustDropped(ctx context.Context, accountID uuid.UUID, page PageRequest) (ProductCardPage, error)
+
+	// --- Follow ---
+	// Usecase: CustomerFollow Shop (idempotent; notify opsional untuk notifikasi listing baru)
+	FollowShop(ctx context.Context, accountID, shopID uuid.UUID, req FollowShopRequest) (FollowedShop, error)
+	UnfollowShop(ctx context.Context, accountID, shopID uuid.UUID) error
+	GetFollowedShops(ctx context.Context, accountID uuid.UUID, page PageRequest) (FollowedShopPage, error)
+	// Usecase: CustomerView Following Feed (kronologis, cursor pagination)
+	GetFollowingFeed(ctx context.Context, accountID uuid.UUID, req FollowingFeedRequest) (FollowingFeedPage, error)
+	// Usecase: SystemNotify Shop Drops (dipanggil job berkala; mengembalikan jumlah notifikasi terkirim)
+	SendShopDropAlerts(ctx context.Context) (int, error)
+
+	// --- Collection ---
+	// Usecase: CustomerBrowse Collections
+	GetCollections(ctx context.Context) ([]model.Collection, error)
+	GetCollection(ctx context.Context, slug string) (CollectionDetail, error)
+
+	// Usecase: AdminManage Collections
+	GetAllCollections(ctx context.Context) ([]CollectionSummary, error)
+	// GetCollectionForAdmin mengembalikan koleksi beserta isinya tanpa memperhatikan jadwal tayang.
+	GetCollectionForAdmin(ctx context.Context, collectionID int) (CollectionDetail, error)
+	CreateCollection(ctx context.Context, actor audit.Actor, req CollectionRequest) (model.Collection, error)
+	UpdateCollection(ctx context.Context, actor audit.Actor, collectionID int, req CollectionRequest) (model.Collection, error)
+	DeleteCollection(ctx context.Context, actor audit.Actor, collectionID int) error
+	UploadCollectionCover(ctx context.Context, actor audit.Actor, collectionID int, file *multipart.FileHeader) (model.Collection, error)
+	SetCollectionItems(ctx context.Context, actor audit.Actor, collectionID int, req CollectionItemsRequest) (CollectionDetail, error)
+}
+
+// =================================================================================
+// KONTRAK UNTUK REPOSITORY (Akses Database) 🚚
+// =================================================================================
+// Repository mendefinisikan semua interaksi ke database yang dibutuhkan oleh Service.
+type Repository interface {
+	// --- Home ---
+	// FindNewArrivals mengembalikan listing yang tayang sejak since, terbaru di depan.
+	// categoryID (opsional) ikut mencakup seluruh sub-kategorinya.
+	FindNewArrivals(ctx context.Context, since time.Time, categoryID *int, limit, offset int) ([]ProductCard, error)
+	// FindNewArrivalsByCategory mengembalikan maksimal perCategory listing terbaru untuk tiap kategori root.
+	FindNewArrivalsByCategory(ctx context.Context, since time.Time, perCategory int) ([]CategoryCard, error)
+	FindJustDropped(ctx context.Context, accountID uuid.UUID, since time.Time, limit, offset int) ([]ProductCard, error)
+
+	// --- Follow ---
+	// FindShopOwner hanya menemukan shop aktif.
+	FindShopOwner(ctx context.Context, shopID uuid.UUID) (ShopOwner, error)
+	// TransactionFollowShop menyimpan follow (atau memperbarui notify jika sudah follow) dan menaikkan
+	// follower_count hanya untuk follow baru.
+	TransactionFollowShop(ctx context.Context, accountID, shopID uuid.UUID, notify *bool) error
+	// TransactionUnfollowShop menghapus follow dan menurunkan follower_count; tidak error jika belum follow.
+	TransactionUnfollowShop(ctx context.Context, accountID, shopID uuid.UUID) error
+	FindFollowedShop(ctx context.Context, accountID, shopID uuid.UUID) (FollowedShop, error)
+	FindFollowedShops(ctx context.Context, accountID uuid.UUID, limit, offset int) ([]FollowedShop, error)
+	// FindFollowingFeed mengembalikan listing published dari shop yang diikuti, terbaru di depan,
+	// dimulai setelah cursor (nil berarti dari awal).
+	FindFollowingFeed(ctx context.Context, accountID uuid.UUID, cursor *FeedCursor, limit int) ([]ProductCard, error)
+	// TransactionCollectShopDrops mengelompokkan maksimal batchSize log publish sejak checkpoint menjadi
+	// shop_drops (hanya shop yang punya follower dengan notify). Mengembalikan jumlah log yang diproses.
+	TransactionCollectShopDrops(ctx context.Context, batchSize int) (int, error)
+	// TransactionFanOutShopDrop mengambil satu drop yang belum selesai lalu mengirim notifikasi ke
+	// maksimal chunkSize follower berikutnya. Mengembalikan jumlah notifikasi dan false jika tidak ada drop.
+	TransactionFanOutShopDrop(ctx context.Context, chunkSize int, build func(ShopDrop) (model.Notification, error)) (int, bool, error)
+
+	// --- Collection ---
+	FindLiveCollections(ctx context.Context, now time.Time) ([]model.Collection, error)
+	FindCollections(ctx context.Context) ([]CollectionSummary, error)
+	FindCollectionByID(ctx context.Context, id int) (model.Collection, error)
+	FindCollectionBySlug(ctx context.Context, slug string) (model.Collection, error)
+	// FindCollectionItems mengembalikan isi koleksi sesuai urutan. Jika publicOnly, hanya
+	// listing published / sold yang dikembalikan.
+	FindCollectionItems(ctx context.Context, collectionID int, publicOnly bool, limit int) ([]ProductCard, error)
+	SaveCollection(ctx context.Context, collection model.Collection, entry model.AdminLog) (model.Collection, error)
+	UpdateCollection(ctx context.Context, collection model.Collection, entry model.AdminLog) (model.Collection, error)
+	DeleteCollection(ctx context.Context, id int, entry model.AdminLog) error
+	// TransactionReplaceCollectionItems mengganti isi koleksi dengan urutan productIDs.
+	// Mengembalikan ErrInvalidCollectionItem jika ada produk yang tidak tampil publik.
+	TransactionReplaceCollectionItems(ctx context.Context, collectionID int, productIDs []uuid.UUID, entry model.AdminLog) error
+}
@@ -0,0 +1,163 @@
+package feed
+
+import (
+	"errors"
+	"time"
+	"vintage-server/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrDuplicateSlug dikembalikan repository jika slug koleksi sudah dipakai.
+	ErrDuplicateSlug = errors.New("collection slug already used")
+	// ErrInvalidCollectionItem dikembalikan repository jika produk tidak ada atau tidak tampil publik.
+	ErrInvalidCollectionItem = errors.New("collection item is not a public listing")
+	// ErrInvalidCursor dikembalikan decodeCursor jika cursor feed rusak atau dibuat manual.
+	ErrInvalidCursor = errors.New("invalid feed cursor")
+)
+
+type PageRequest struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+type NewArrivalsFilter struct {
+	CategoryID *int `form:"category_id"`
+	Page       int  `form:"page"`
+	Limit      int  `form:"limit"`
+}
+
+// ProductCard adalah ringkasan listing untuk kartu produk di feed.
+type ProductCard struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	ShopID     uuid.UUID `json:"shop_id" db:"shop_id"`
+	ShopName   string    `json:"shop_name" db:"shop_name"`
+	CategoryID int       `json:"category_id" db:"category_id"`
+	Name       string    `json:"name" db:"name"`
+	Price      int64     `json:"price" db:"price"`
+	ImageURL   *string   `json:"image_url" db:"image_url"`
+	// Status 'sold' ditampilkan client sebagai badge terjual
+	Status      string    `json:"status" db:"status"`
+	PublishedAt time.Time `json:"published_at" db:"published_at"`
+}
+
+// CategoryCard adalah ProductCard beserta kategori root tempat listing itu dikelompokkan.
+type CategoryCard struct {
+	RootID   int    `db:"root_id"`
+	RootName string `db:"root_name"`
+	RootSlug string `db:"root_slug"`
+	ProductCard
+}
+
+type ProductCardPage struct {
+	Items []ProductCard `json:"items"`
+	Page  int           `json:"page"`
+	Limit int           `json:"limit"`
+}
+
+// CategorySection adalah new arrivals untuk satu kategori root di beranda.
+type CategorySection struct {
+	CategoryID int           `json:"category_id"`
+	Name       string        `json:"name"`
+	Slug       string        `json:"slug"`
+	Items      []ProductCard `json:"items"`
+}
+
+// CollectionPreview adalah koleksi aktif beserta beberapa item pertamanya untuk beranda.
+type CollectionPreview struct {
+	model.Collection
+	Items []ProductCard `json:"items"`
+}
+
+type HomeFeed struct {
+	NewArrivals []ProductCard       `json:"new_arrivals"`
+	Categories  []CategorySection   `json:"categories"`
+	Collections []CollectionPreview `json:"collections"`
+	// JustDropped hanya diisi jika pembeli login
+	JustDropped []ProductCard `json:"just_dropped,omitempty"`
+}
+
+type CollectionDetail struct {
+	model.Collection
+	Items []ProductCard `json:"items"`
+}
+
+// CollectionSummary dipakai daftar koleksi di halaman admin.
+type CollectionSummary struct {
+	model.Collection
+	ItemCount int  `json:"item_count" db:"item_count"`
+	Live      bool `json:"live" db:"-"`
+}
+
+// FollowedShop adalah shop yang diikuti akun beserta preferensi notifikasinya.
+type FollowedShop struct {
+	ShopID        uuid.UUID `json:"shop_id" db:"shop_id"`
+	Name          string    `json:"name" db:"name"`
+	Slug          string    `json:"slug" db:"slug"`
+	LogoURL       *string   `json:"logo_url" db:"logo_url"`
+	FollowerCount int       `json:"follower_count" db:"follower_count"`
+	Notify        bool      `json:"notify" db:"notify"`
+	FollowedAt    time.Time `json:"followed_at" db:"followed_at"`
+}
+
+type FollowedShopPage struct {
+	Items []FollowedShop `json:"items"`
+	Page  int            `json:"page"`
+	Limit int            `json:"limit"`
+}
+
+type FollowShopRequest struct {
+	// Notify nil mempertahankan preferensi yang ada (follow baru: tanpa notifikasi)
+	Notify *bool `json:"notify"`
+}
+
+// FollowingFeedRequest memakai cursor dari NextCursor respons sebelumnya; kosong berarti halaman pertama.
+type FollowingFeedRequest struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+// FeedCursor adalah posisi keyset (published_at, id) item terakhir yang sudah dikirim.
+type FeedCursor struct {
+	PublishedAt time.Time
+	ID          uuid.UUID
+}
+
+type FollowingFeedPage struct {
+	Items []ProductCard `json:"items"`
+	// NextCursor kosong jika tidak ada item lagi
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ShopOwner adalah shop aktif yang bisa diikuti beserta pemiliknya.
+type ShopOwner struct {
+	ID        uuid.UUID `db:"id"`
+	AccountID uuid.UUID `db:"account_id"`
+}
+
+// ShopDrop adalah listing baru satu shop yang sedang dikabarkan ke follower-nya.
+type ShopDrop struct {
+	ID           int64          `db:"id"`
+	ShopID       uuid.UUID      `db:"shop_id"`
+	ShopName     string         `db:"shop_name"`
+	ShopSlug     string         `db:"shop_slug"`
+	ListingCount int            `db:"listing_count"`
+	ProductIDs   pq.StringArray `db:"product_ids"`
+}
+
+type CollectionRequest struct {
+	Title string `json:"title" binding:"required,max=96"`
+	// Slug opsional, dibuat dari title jika kosong
+	Slug        string     `json:"slug" binding:"max=96"`
+	Description *string    `json:"description"`
+	Position    int        `json:"position"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+}
+
+type CollectionItemsRequest struct {
+	// ProductIDs disimpan sesuai urutan array
+	ProductIDs []uuid.UUID `json:"product_ids" binding:"max=200"`
+}
@@ -0,0 +1,314 @@
+package feed
+
+import (
+	"net/http"
+	"strconv"
+	"vintage-server/internal/service/audit"
+	"vintage-server/pkg/middleware"
+	"vintage-server/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// publicCacheControl dipakai endpoint feed publik (bagian yang sama untuk semua pengunjung).
+const publicCacheControl = "public, max-age=60"
+
+// Handler adalah struct yang memegang dependency ke Service
+type Handler struct {
+	svc Service
+}
+
+// NewHandler adalah constructor untuk handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// --- Home ---
+
+// GetHomeFeed mengembalikan seluruh bagian beranda; "just dropped" ikut diisi jika pembeli login
+func (h *Handler) GetHomeFeed(c *gin.Context) {
+	var viewer *uuid.UUID
+	if id, ok := middleware.GetAccountID(c); ok {
+		viewer = &id
+	}
+
+	feed, err := h.svc.GetHomeFeed(c.Request.Context(), viewer)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	// Respons personal tidak boleh di-cache oleh proxy bersama
+	if viewer == nil {
+		c.Header("Cache-Control", publicCacheControl)
+	} else {
+		c.Header("Cache-Control", "private, no-cache")
+	}
+	response.Success(c, http.StatusOK, feed)
+}
+
+// GetNewArrivals mengembalikan listing terbaru, bisa difilter ?category_id= (termasuk sub-kategori)
+func (h *Handler) GetNewArrivals(c *gin.Context) {
+	var filter NewArrivalsFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	page, err := h.svc.GetNewArrivals(c.Request.Context(), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Header("Cache-Control", publicCacheControl)
+	response.Success(c, http.StatusOK, page)
+}
+
+// GetJustDropped mengembalikan listing baru dari shop yang diikuti user yang login
+func (h *Handler) GetJustDropped(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var req PageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	page, err := h.svc.GetJustDropped(c.Request.Context(), accountID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, page)
+}
+
+// --- Follow ---
+
+// FollowShop mengikuti shop (idempotent); body opsional {"notify": true} untuk notifikasi listing baru
+func (h *Handler) FollowShop(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+	shopID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid shop id")
+		return
+	}
+
+	var req FollowShopRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	followed, err := h.svc.FollowShop(c.Request.Context(), accountID, shopID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, followed)
+}
+
+// UnfollowShop berhenti mengikuti shop; tetap 204 jika memang belum mengikuti
+func (h *Handler) UnfollowShop(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+	shopID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid shop id")
+		return
+	}
+
+	if err := h.svc.UnfollowShop(c.Request.Context(), accountID, shopID); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetFollowedShops mengembalikan shop yang diikuti user yang login, terbaru di depan
+func (h *Handler) GetFollowedShops(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var req PageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	page, err := h.svc.GetFollowedShops(c.Request.Context(), accountID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, page)
+}
+
+// GetFollowingFeed mengembalikan listing baru dari shop yang diikuti; lanjutkan dengan ?cursor=next_cursor
+func (h *Handler) GetFollowingFeed(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var req FollowingFeedRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	page, err := h.svc.GetFollowingFeed(c.Request.Context(), accountID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "private, no-cache")
+	response.Success(c, http.StatusOK, page)
+}
+
+// --- Collection ---
+
+// GetCollections mengembalikan koleksi yang sedang tayang sesuai urutan
+func (h *Handler) GetCollections(c *gin.Context) {
+	collections, err := h.svc.GetCollections(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Header("Cache-Control", publicCacheControl)
+	response.Success(c, http.StatusOK, collections)
+}
+
+// GetCollection mengembalikan satu koleksi yang sedang tayang beserta isinya
+func (h *Handler) GetCollection(c *gin.Context) {
+	detail, err := h.svc.GetCollection(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Header("Cache-Control", publicCacheControl)
+	response.Success(c, http.StatusOK, detail)
+}
+
+// GetAllCollections adalah handler admin untuk melihat semua koleksi, termasuk yang terjadwal
+func (h *Handler) GetAllCollections(c *gin.Context) {
+	collections, err := h.svc.GetAllCollections(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, collections)
+}
+
+// GetCollectionForAdmin adalah handler admin untuk preview koleksi tanpa memperhatikan jadwal
+func (h *Handler) GetCollectionForAdmin(c *gin.Context) {
+	collectionID, ok := parseIntParam(c, "id")
+	if !ok {
+		return
+	}
+
+	detail, err := h.svc.GetCollectionForAdmin(c.Request.Context(), collectionID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, detail)
+}
+
+// CreateCollection adalah handler admin untuk membuat koleksi baru
+func (h *Handler) CreateCollection(c *gin.Context) {
+	var req CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	collection, err := h.svc.CreateCollection(c.Request.Context(), audit.ActorFromContext(c), req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusCreated, collection)
+}
+
+// UpdateCollection adalah handler admin untuk mengubah judul, urutan, dan jadwal koleksi
+func (h *Handler) UpdateCollection(c *gin.Context) {
+	collectionID, ok := parseIntParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	collection, err := h.svc.UpdateCollection(c.Request.Context(), audit.ActorFromContext(c), collectionID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, collection)
+}
+
+// DeleteCollection adalah handler admin untuk menghapus koleksi
+func (h *Handler) DeleteCollection(c *gin.Context) {
+	collectionID, ok := parseIntParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteCollection(c.Request.Context(), audit.ActorFromContext(c), collectionID); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// UploadCollectionCover adalah handler admin untuk upload cover koleksi (multipart field "cover")
+func (h *Handler) UploadCollectionCover(c *gin.Context) {
+	collectionID, ok := parseIntParam(c, "id")
+	if !ok {
+		return
+	}
+
+	file, err := c.FormFile("cover")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Cover file is required")
+		return
+	}
+
+	collection, err := h.svc.UploadCollectionCover(c.Request.Context(), audit.ActorFromContext(c), collectionID, file)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, collection)
+}
+
+// SetCollectionItems adalah handler admin untuk mengganti isi koleksi beserta urutannya
+func (h *Handler) SetCollectionItems(c *gin.Context) {
+	collectionID, ok := parseIntParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req CollectionItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	detail, err := h.svc.SetCollectionItems(c.Request.Context(), audit.ActorFromContext(c), collectionID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, detail)
+}
+
+func parseIntParam(c *gin.Context, name string) (int, bool) {
+	value, err := strconv.Atoi(c.Param(name))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid "+name)
+		return 0, false
+	}
+	return value, true
+}
@@ -0,0 +1,538 @@
+package feed
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/audit"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// shopDropCheckpoint adalah nama baris di job_checkpoints untuk job pembentukan shop_drops.
+const shopDropCheckpoint = "shop_drop_alerts"
+
+// cardColumns adalah kolom ProductCard (alias tabel 'p', 's' untuk shop, 'pi' untuk gambar utama).
+const cardColumns = `
+	p.id, p.shop_id, s.name AS shop_name, p.category_id, p.name, p.price,
+	pi.url AS image_url, p.status, COALESCE(p.published_at, p.created_at) AS published_at`
+
+// cardJoins melengkapi cardColumns dengan nama shop dan gambar utama listing.
+const cardJoins = `
+	JOIN shop s ON s.id = p.shop_id
+	LEFT JOIN product_images pi ON pi.product_id = p.id AND pi.image_index = 0`
+
+// repository adalah struct yang mengimplementasikan kontrak Repository dari domain.go
+type repository struct {
+	db *sqlx.DB
+}
+
+// NewRepository adalah constructor untuk implementasi repository
+func NewRepository(db *sqlx.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// --- Home ---
+
+func (r *repository) FindNewArrivals(ctx context.Context, since time.Time, categoryID *int, limit, offset int) ([]ProductCard, error) {
+	cards := []ProductCard{}
+	query := `
+		WITH RECURSIVE sub AS (
+			SELECT id FROM product_categories WHERE id = $2
+			UNION ALL
+			SELECT c.id FROM product_categories c JOIN sub ON c.parent_id = sub.id
+			WHERE c.deleted_at IS NULL
+		)
+		SELECT ` + cardColumns + `
+		FROM products p` + cardJoins + `
+		WHERE p.status = 'published' AND p.deleted_at IS NULL AND p.published_at >= $1
+		  AND p.shop_id IN (SELECT id FROM shop WHERE active)
+		  AND ($2::int IS NULL OR p.category_id IN (SELECT id FROM sub))
+		ORDER BY p.published_at DESC, p.id
+		LIMIT $3 OFFSET $4`
+	err := r.db.SelectContext(ctx, &cards, query, since, categoryID, limit, offset)
+	return cards, err
+}
+
+func (r *repository) FindNewArrivalsByCategory(ctx context.Context, since time.Time, perCategory int) ([]CategoryCard, error) {
+	cards := []CategoryCard{}
+	// Setiap kategori dipetakan ke root-nya, lalu listing diberi peringkat per root.
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT id, id AS root_id FROM product_categories
+			WHERE parent_id IS NULL AND deleted_at IS NULL
+			UNION ALL
+			SELECT c.id, tree.root_id FROM product_categories c JOIN tree ON c.parent_id = tree.id
+			WHERE c.deleted_at IS NULL
+		), ranked AS (
+			SELECT tree.root_id, p.id,
+				ROW_NUMBER() OVER (PARTITION BY tree.root_id ORDER BY p.published_at DESC, p.id) AS rn
+			FROM products p
+			JOIN tree ON tree.id = p.category_id
+			WHERE p.status = 'published' AND p.deleted_at IS NULL AND p.published_at >= $1
+			  AND p.shop_id IN (SELECT id FROM shop WHERE active)
+		)
+		SELECT rc.id AS root_id, rc.name AS root_name, rc.slug AS root_slug, ` + cardColumns + `
+		FROM ranked
+		JOIN product_categories rc ON rc.id = ranked.root_id
+		JOIN products p ON p.id = ranked.id` + cardJoins + `
+		WHERE ranked.rn <= $2
+		ORDER BY rc.name, ranked.rn`
+	err := r.db.SelectContext(ctx, &cards, query, since, perCategory)
+	return cards, err
+}
+
+func (r *repository) FindJustDropped(ctx context.Context, accountID uuid.UUID, since time.Time, limit, offset int) ([]ProductCard, error) {
+	cards := []ProductCard{}
+	query := `
+		SELECT ` + cardColumns + `
+		FROM shop_followers f
+		JOIN products p ON p.shop_id = f.shop_id` + cardJoins + `
+		WHERE f.account_id = $1 AND p.status = 'published' AND p.deleted_at IS NULL AND p.published_at >= $2
+		  AND p.shop_id IN (SELECT id FROM shop WHERE active)
+		ORDER BY p.published_at DESC, p.id
+		LIMIT $3 OFFSET $4`
+	err := r.db.SelectContext(ctx, &cards, query, accountID, since, limit, offset)
+	return cards, err
+}
+
+// --- Follow ---
+
+func (r *repository) FindShopOwner(ctx context.Context, shopID uuid.UUID) (ShopOwner, error) {
+	var shop ShopOwner
+	query := "SELECT id, account_id FROM shop WHERE id = $1 AND active AND deleted_at IS NULL"
+	err := r.db.GetContext(ctx, &shop, query, shopID)
+	return shop, err
+}
+
+func (r *repository) TransactionFollowShop(ctx context.Context, accountID, shopID uuid.UUID, notify *bool) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Simpan follow; xmax = 0 berarti baris baru (bukan hasil ON CONFLICT UPDATE)
+	var created bool
+	query := `
+		INSERT INTO shop_followers (account_id, shop_id, notify)
+		VALUES ($1, $2, COALESCE($3, FALSE))
+		ON CONFLICT (account_id, shop_id) DO UPDATE SET notify = COALESCE($3, shop_followers.notify)
+		RETURNING (xmax = 0) AS created`
+	if err := tx.GetContext(ctx, &created, query, accountID, shopID, notify); err != nil {
+		return err
+	}
+
+	// Query 2: Naikkan jumlah follower hanya untuk follow baru
+	if created {
+		if _, err := tx.ExecContext(ctx, "UPDATE shop SET follower_count = follower_count + 1 WHERE id = $1", shopID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *repository) TransactionUnfollowShop(ctx context.Context, accountID, shopID uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Hapus follow
+	result, err := tx.ExecContext(ctx, "DELETE FROM shop_followers WHERE account_id = $1 AND shop_id = $2", accountID, shopID)
+	if err != nil {
+		return err
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	// Query 2: Turunkan jumlah follower jika memang ada yang dihapus
+	if removed > 0 {
+		query := "UPDATE shop SET follower_count = GREATEST(follower_count - 1, 0) WHERE id = $1"
+		if _, err := tx.ExecContext(ctx, query, shopID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *repository) FindFollowedShop(ctx context.Context, accountID, shopID uuid.UUID) (FollowedShop, error) {
+	var shop FollowedShop
+	query := `
+		SELECT f.shop_id, s.name, s.slug, s.logo_url, s.follower_count, f.notify, f.created_at AS followed_at
+		FROM shop_followers f
+		JOIN shop s ON s.id = f.shop_id
+		WHERE f.account_id = $1 AND f.shop_id = $2`
+	err := r.db.GetContext(ctx, &shop, query, accountID, shopID)
+	return shop, err
+}
+
+func (r *repository) FindFollowedShops(ctx context.Context, accountID uuid.UUID, limit, offset int) ([]FollowedShop, error) {
+	shops := []FollowedShop{}
+	// Shop yang sedang tidak aktif disembunyikan, follow-nya tetap disimpan
+	query := `
+		SELECT f.shop_id, s.name, s.slug, s.logo_url, s.follower_count, f.notify, f.created_at AS followed_at
+		FROM shop_followers f
+		JOIN shop s ON s.id = f.shop_id
+		WHERE f.account_id = $1 AND s.active AND s.deleted_at IS NULL
+		ORDER BY f.created_at DESC, f.shop_id
+		LIMIT $2 OFFSET $3`
+	err := r.db.SelectContext(ctx, &shops, query, accountID, limit, offset)
+	return shops, err
+}
+
+func (r *repository) FindFollowingFeed(ctx context.Context, accountID uuid.UUID, cursor *FeedCursor, limit int) ([]ProductCard, error) {
+	var after *time.Time
+	var afterID *uuid.UUID
+	if cursor != nil {
+		after, afterID = &cursor.PublishedAt, &cursor.ID
+	}
+
+	cards := []ProductCard{}
+	// Dibaca saat diminta (tanpa fan-out ke tiap follower): setiap shop memakai
+	// idx_products_shop_published_at sehingga biaya tidak bergantung pada jumlah follower shop
+	query := `
+		SELECT ` + cardColumns + `
+		FROM products p` + cardJoins + `
+		WHERE p.shop_id IN (SELECT shop_id FROM shop_followers WHERE account_id = $1)
+		  AND p.status = 'published' AND p.deleted_at IS NULL AND s.active
+		  AND ($2::timestamptz IS NULL OR (p.published_at, p.id) < ($2, $3::uuid))
+		ORDER BY p.published_at DESC, p.id DESC
+		LIMIT $4`
+	err := r.db.SelectContext(ctx, &cards, query, accountID, after, afterID, limit)
+	return cards, err
+}
+
+func (r *repository) TransactionCollectShopDrops(ctx context.Context, batchSize int) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Kunci checkpoint supaya dua instance job tidak memproses batch yang sama
+	var name string
+	if err := tx.GetContext(ctx, &name, "SELECT name FROM job_checkpoints WHERE name = $1 FOR UPDATE", shopDropCheckpoint); err != nil {
+		return 0, err
+	}
+
+	// Query 2: Listing yang berpindah ke 'published' sejak checkpoint. Posisi dibandingkan per
+	// (xid, id) dan transaksi yang masih berjalan ditunggu, sama seperti job saved search
+	var published []struct {
+		ID        int64     `db:"id"`
+		ProductID uuid.UUID `db:"product_id"`
+	}
+	queryLogs := `
+		SELECT l.id, l.product_id FROM product_status_logs l
+		JOIN job_checkpoints jc ON jc.name = $1
+		WHERE (l.created_xid, l.id) > (jc.last_xid, jc.last_id)
+		  AND l.created_xid < pg_snapshot_xmin(pg_current_snapshot())
+		  AND l.to_status = 'published'
+		ORDER BY l.created_xid, l.id
+		LIMIT $2`
+	if err := tx.SelectContext(ctx, &published, queryLogs, shopDropCheckpoint, batchSize); err != nil {
+		return 0, err
+	}
+	if len(published) == 0 {
+		return 0, nil
+	}
+	productIDs := make([]uuid.UUID, 0, len(published))
+	for _, row := range published {
+		productIDs = append(productIDs, row.ProductID)
+	}
+
+	// Query 3: Kelompokkan per shop, hanya yang masih tayang dan punya follower dengan notify
+	var groups []struct {
+		ShopID     uuid.UUID      `db:"shop_id"`
+		ProductIDs pq.StringArray `db:"product_ids"`
+	}
+	queryGroups := `
+		SELECT p.shop_id, array_agg(p.id ORDER BY p.published_at) AS product_ids
+		FROM products p
+		WHERE p.id = ANY($1) AND p.status = 'published' AND p.deleted_at IS NULL
+		  AND p.shop_id IN (SELECT id FROM shop WHERE active)
+		  AND EXISTS (SELECT 1 FROM shop_followers f WHERE f.shop_id = p.shop_id AND f.notify)
+		GROUP BY p.shop_id`
+	if err := tx.SelectContext(ctx, &groups, queryGroups, pq.Array(productIDs)); err != nil {
+		return 0, err
+	}
+
+	// Query 4: Gabungkan ke drop shop yang fan-out-nya belum dimulai, atau buat drop baru
+	queryMerge := `
+		UPDATE shop_drops d SET product_ids = m.ids, listing_count = cardinality(m.ids)
+		FROM (
+			SELECT id, ARRAY(SELECT DISTINCT unnest(product_ids || $2::uuid[])) AS ids
+			FROM shop_drops
+			WHERE shop_id = $1 AND completed_at IS NULL AND last_account_id IS NULL
+			ORDER BY id DESC
+			LIMIT 1
+		) m
+		WHERE d.id = m.id AND d.last_account_id IS NULL`
+	queryInsert := "INSERT INTO shop_drops (shop_id, listing_count, product_ids) VALUES ($1, cardinality($2::uuid[]), $2)"
+	for _, group := range groups {
+		result, err := tx.ExecContext(ctx, queryMerge, group.ShopID, group.ProductIDs)
+		if err != nil {
+			return 0, err
+		}
+		merged, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		if merged == 0 {
+			if _, err := tx.ExecContext(ctx, queryInsert, group.ShopID, group.ProductIDs); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	// Query 5: Majukan checkpoint
+	updateCheckpoint := `
+		UPDATE job_checkpoints jc SET last_xid = l.created_xid, last_id = l.id, updated_at = CURRENT_TIMESTAMP
+		FROM product_status_logs l
+		WHERE jc.name = $1 AND l.id = $2`
+	if _, err := tx.ExecContext(ctx, updateCheckpoint, shopDropCheckpoint, published[len(published)-1].ID); err != nil {
+		return 0, err
+	}
+
+	return len(published), tx.Commit()
+}
+
+func (r *repository) TransactionFanOutShopDrop(ctx context.Context, chunkSize int, build func(ShopDrop) (model.Notification, error)) (int, bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Ambil drop tertua yang belum selesai; instance job lain melewati drop yang sedang dikirim
+	var drop struct {
+		ShopDrop
+		LastAccountID *uuid.UUID `db:"last_account_id"`
+		ShopActive    bool       `db:"shop_active"`
+	}
+	queryDrop := `
+		SELECT
+			d.id, d.shop_id, s.name AS shop_name, s.slug AS shop_slug, d.listing_count, d.product_ids,
+			d.last_account_id, s.active AS shop_active
+		FROM shop_drops d
+		JOIN shop s ON s.id = d.shop_id
+		WHERE d.completed_at IS NULL
+		ORDER BY d.id
+		LIMIT 1
+		FOR UPDATE OF d SKIP LOCKED`
+	if err := tx.GetContext(ctx, &drop, queryDrop); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	complete := "UPDATE shop_drops SET completed_at = CURRENT_TIMESTAMP WHERE id = $1"
+	// Shop yang disuspend sebelum fan-out selesai tidak dikabarkan lagi
+	if !drop.ShopActive {
+		if _, err := tx.ExecContext(ctx, complete, drop.ID); err != nil {
+			return 0, false, err
+		}
+		return 0, true, tx.Commit()
+	}
+
+	notification, err := build(drop.ShopDrop)
+	if err != nil {
+		return 0, false, err
+	}
+
+	// Query 2: Kirim ke potongan follower berikutnya (keyset account_id) dalam satu INSERT ... SELECT
+	var chunk struct {
+		Sent          int        `db:"sent"`
+		LastAccountID *uuid.UUID `db:"last_account_id"`
+	}
+	queryFanOut := `
+		WITH batch AS (
+			SELECT account_id FROM shop_followers
+			WHERE shop_id = $1 AND notify AND ($2::uuid IS NULL OR account_id > $2)
+			ORDER BY account_id
+			LIMIT $3
+		), inserted AS (
+			INSERT INTO notifications (account_id, type, title, body, data)
+			SELECT account_id, $4, $5, $6, $7::jsonb FROM batch
+			RETURNING 1
+		)
+		SELECT
+			(SELECT COUNT(*) FROM inserted) AS sent,
+			(SELECT account_id FROM batch ORDER BY account_id DESC LIMIT 1) AS last_account_id`
+	err = tx.GetContext(ctx, &chunk, queryFanOut,
+		drop.ShopID, drop.LastAccountID, chunkSize,
+		notification.Type, notification.Title, notification.Body, notification.Data)
+	if err != nil {
+		return 0, false, err
+	}
+
+	// Query 3: Simpan posisi; potongan yang tidak penuh berarti semua follower sudah dikirimi
+	if chunk.LastAccountID != nil {
+		if _, err := tx.ExecContext(ctx, "UPDATE shop_drops SET last_account_id = $2 WHERE id = $1", drop.ID, chunk.LastAccountID); err != nil {
+			return 0, false, err
+		}
+	}
+	if chunk.Sent < chunkSize {
+		if _, err := tx.ExecContext(ctx, complete, drop.ID); err != nil {
+			return 0, false, err
+		}
+	}
+
+	return chunk.Sent, true, tx.Commit()
+}
+
+// --- Collection ---
+
+func (r *repository) FindLiveCollections(ctx context.Context, now time.Time) ([]model.Collection, error) {
+	collections := []model.Collection{}
+	query := `
+		SELECT * FROM collections
+		WHERE (starts_at IS NULL OR starts_at <= $1) AND (ends_at IS NULL OR ends_at > $1)
+		ORDER BY position, id`
+	err := r.db.SelectContext(ctx, &collections, query, now)
+	return collections, err
+}
+
+func (r *repository) FindCollections(ctx context.Context) ([]CollectionSummary, error) {
+	collections := []CollectionSummary{}
+	query := `
+		SELECT c.*, (SELECT COUNT(*) FROM collection_items ci WHERE ci.collection_id = c.id) AS item_count
+		FROM collections c
+		ORDER BY c.position, c.id`
+	err := r.db.SelectContext(ctx, &collections, query)
+	return collections, err
+}
+
+func (r *repository) FindCollectionByID(ctx context.Context, id int) (model.Collection, error) {
+	var collection model.Collection
+	err := r.db.GetContext(ctx, &collection, "SELECT * FROM collections WHERE id = $1", id)
+	return collection, err
+}
+
+func (r *repository) FindCollectionBySlug(ctx context.Context, slug string) (model.Collection, error) {
+	var collection model.Collection
+	err := r.db.GetContext(ctx, &collection, "SELECT * FROM collections WHERE slug = $1", slug)
+	return collection, err
+}
+
+func (r *repository) FindCollectionItems(ctx context.Context, collectionID int, publicOnly bool, limit int) ([]ProductCard, error) {
+	cards := []ProductCard{}
+	query := `
+		SELECT ` + cardColumns + `
+		FROM collection_items ci
+		JOIN products p ON p.id = ci.product_id` + cardJoins + `
+		WHERE ci.collection_id = $1 AND p.deleted_at IS NULL
+		  AND (NOT $2 OR (p.status IN ('published', 'sold') AND p.shop_id IN (SELECT id FROM shop WHERE active)))
+		ORDER BY ci.position
+		LIMIT $3`
+	err := r.db.SelectContext(ctx, &cards, query, collectionID, publicOnly, limit)
+	return cards, err
+}
+
+func (r *repository) SaveCollection(ctx context.Context, collection model.Collection, entry model.AdminLog) (model.Collection, error) {
+	var saved model.Collection
+	err := r.withAdminLog(ctx, entry, func(tx *sqlx.Tx) error {
+		query := `
+			INSERT INTO collections (slug, title, description, cover_image_url, position, starts_at, ends_at, created_at, updated_at)
+			VALUES (:slug, :title, :description, :cover_image_url, :position, :starts_at, :ends_at, :created_at, :updated_at)
+			RETURNING *`
+		return namedGet(ctx, tx, &saved, query, collection)
+	})
+	return saved, err
+}
+
+func (r *repository) UpdateCollection(ctx context.Context, collection model.Collection, entry model.AdminLog) (model.Collection, error) {
+	var updated model.Collection
+	err := r.withAdminLog(ctx, entry, func(tx *sqlx.Tx) error {
+		query := `
+			UPDATE collections SET
+				slug = :slug, title = :title, description = :description, cover_image_url = :cover_image_url,
+				position = :position, starts_at = :starts_at, ends_at = :ends_at, updated_at = :updated_at
+			WHERE id = :id
+			RETURNING *`
+		return namedGet(ctx, tx, &updated, query, collection)
+	})
+	return updated, err
+}
+
+func (r *repository) DeleteCollection(ctx context.Context, id int, entry model.AdminLog) error {
+	return r.withAdminLog(ctx, entry, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, "DELETE FROM collections WHERE id = $1", id)
+		return err
+	})
+}
+
+func (r *repository) TransactionReplaceCollectionItems(ctx context.Context, collectionID int, productIDs []uuid.UUID, entry model.AdminLog) error {
+	return r.withAdminLog(ctx, entry, func(tx *sqlx.Tx) error {
+		// Query 1: Kosongkan isi koleksi lama
+		if _, err := tx.ExecContext(ctx, "DELETE FROM collection_items WHERE collection_id = $1", collectionID); err != nil {
+			return err
+		}
+
+		// Query 2: Simpan isi baru sesuai urutan array, hanya untuk listing yang tampil publik
+		query := `
+			INSERT INTO collection_items (collection_id, product_id, position)
+			SELECT $1, item.product_id, item.position
+			FROM UNNEST($2::uuid[]) WITH ORDINALITY AS item(product_id, position)
+			JOIN products p ON p.id = item.product_id
+			WHERE p.status IN ('published', 'sold') AND p.deleted_at IS NULL`
+		result, err := tx.ExecContext(ctx, query, collectionID, pq.Array(productIDs))
+		if err != nil {
+			return err
+		}
+		inserted, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if int(inserted) != len(productIDs) {
+			return ErrInvalidCollectionItem
+		}
+
+		// Query 3: Tandai koleksi berubah
+		_, err = tx.ExecContext(ctx, "UPDATE collections SET updated_at = CURRENT_TIMESTAMP WHERE id = $1", collectionID)
+		return err
+	})
+}
+
+// withAdminLog menjalankan fn di dalam transaksi lalu mencatat entry ke admin_logs di transaksi yang sama.
+// Pelanggaran unique constraint (slug) diterjemahkan menjadi ErrDuplicateSlug.
+func (r *repository) withAdminLog(ctx context.Context, entry model.AdminLog, fn func(tx *sqlx.Tx) error) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return ErrDuplicateSlug
+		}
+		return err
+	}
+	if err := audit.SaveAdminLog(ctx, tx, entry); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// namedGet menjalankan query ber-parameter nama di dalam transaksi dan men-scan satu baris hasil.
+func namedGet(ctx context.Context, tx *sqlx.Tx, dest any, query string, arg any) error {
+	bound, args, err := tx.BindNamed(query, arg)
+	if err != nil {
+		return err
+	}
+	return tx.GetContext(ctx, dest, bound, args...)
+}
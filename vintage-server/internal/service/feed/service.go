@@ -0,0 +1,600 @@
+package feed
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/audit"
+	"vintage-server/pkg/apperror"
+	"vintage-server/pkg/cache"
+	"vintage-server/pkg/slug"
+	"vintage-server/pkg/storage"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+
+	// newArrivalWindow adalah batas umur listing yang masih dianggap "new arrival".
+	newArrivalWindow = 30 * 24 * time.Hour
+	// justDroppedWindow adalah batas umur listing dari shop yang diikuti.
+	justDroppedWindow = 14 * 24 * time.Hour
+
+	// Jumlah item per bagian di beranda
+	homeNewArrivals       = 12
+	homePerCategory       = 8
+	homeCollectionPreview = 8
+	homeJustDropped       = 12
+
+	// maxCollectionItems sama dengan batas binding CollectionItemsRequest.ProductIDs.
+	maxCollectionItems = 200
+
+	// maxCoverSize adalah batas ukuran file cover koleksi (5 MB).
+	maxCoverSize = 5 << 20
+
+	// cacheKeyHome menyimpan bagian beranda yang sama untuk semua pengunjung.
+	cacheKeyHome = "home"
+
+	// shopDropBatchSize adalah jumlah log publish yang dikelompokkan per transaksi.
+	shopDropBatchSize = 500
+	// shopDropChunkSize adalah jumlah follower yang dikirimi notifikasi per transaksi fan-out.
+	shopDropChunkSize = 1000
+	// maxShopDropChunksPerRun membatasi satu putaran job; sisanya dilanjutkan putaran berikutnya.
+	maxShopDropChunksPerRun = 200
+	// shopDropPreviewItems adalah jumlah product id yang disertakan di data notifikasi.
+	shopDropPreviewItems = 10
+)
+
+// coverExtensions adalah content type cover yang diterima beserta ekstensi file-nya.
+var coverExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/webp": ".webp",
+}
+
+// service adalah struct yang akan mengimplementasikan interface Service dari domain.go
+type service struct {
+	repo    Repository
+	storage storage.Storage
+	cache   *cache.Cache
+}
+
+// NewService adalah constructor untuk service
+func NewService(repo Repository, store storage.Storage, feedCache *cache.Cache) Service {
+	return &service{
+		repo:    repo,
+		storage: store,
+		cache:   feedCache,
+	}
+}
+
+// --- Home ---
+
+func (s *service) GetHomeFeed(ctx context.Context, viewerID *uuid.UUID) (HomeFeed, error) {
+	feed, err := s.publicHome(ctx)
+	if err != nil {
+		return HomeFeed{}, err
+	}
+
+	if viewerID != nil {
+		dropped, err := s.repo.FindJustDropped(ctx, *viewerID, time.Now().Add(-justDroppedWindow), homeJustDropped, 0)
+		if err != nil {
+			log.Printf("Error finding just dropped listings: %v", err)
+			return HomeFeed{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+		}
+		feed.JustDropped = dropped
+	}
+	return feed, nil
+}
+
+func (s *service) GetNewArrivals(ctx context.Context, filter NewArrivalsFilter) (ProductCardPage, error) {
+	page, limit := normalizePage(filter.Page, filter.Limit)
+	since := time.Now().Add(-newArrivalWindow)
+
+	items, err := s.repo.FindNewArrivals(ctx, since, filter.CategoryID, limit, (page-1)*limit)
+	if err != nil {
+		log.Printf("Error finding new arrivals: %v", err)
+		return ProductCardPage{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return ProductCardPage{Items: items, Page: page, Limit: limit}, nil
+}
+
+func (s *service) GetJustDropped(ctx context.Context, accountID uuid.UUID, req PageRequest) (ProductCardPage, error) {
+	page, limit := normalizePage(req.Page, req.Limit)
+	since := time.Now().Add(-justDroppedWindow)
+
+	items, err := s.repo.FindJustDropped(ctx, accountID, since, limit, (page-1)*limit)
+	if err != nil {
+		log.Printf("Error finding just dropped listings: %v", err)
+		return ProductCardPage{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return ProductCardPage{Items: items, Page: page, Limit: limit}, nil
+}
+
+// publicHome menyusun bagian beranda yang tidak bergantung pada pengunjung (di-cache).
+func (s *service) publicHome(ctx context.Context) (HomeFeed, error) {
+	if cached, ok := s.cache.Get(cacheKeyHome); ok {
+		return cached.(HomeFeed), nil
+	}
+
+	since := time.Now().Add(-newArrivalWindow)
+	arrivals, err := s.repo.FindNewArrivals(ctx, since, nil, homeNewArrivals, 0)
+	if err != nil {
+		log.Printf("Error finding new arrivals: %v", err)
+		return HomeFeed{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	cards, err := s.repo.FindNewArrivalsByCategory(ctx, since, homePerCategory)
+	if err != nil {
+		log.Printf("Error finding new arrivals by category: %v", err)
+		return HomeFeed{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	// Hasil query sudah terurut per kategori root, cukup dipotong saat root berganti
+	sections := []CategorySection{}
+	for _, card := range cards {
+		if len(sections) == 0 || sections[len(sections)-1].CategoryID != card.RootID {
+			sections = append(sections, CategorySection{
+				CategoryID: card.RootID,
+				Name:       card.RootName,
+				Slug:       card.RootSlug,
+				Items:      []ProductCard{},
+			})
+		}
+		last := &sections[len(sections)-1]
+		last.Items = append(last.Items, card.ProductCard)
+	}
+
+	collections, err := s.repo.FindLiveCollections(ctx, time.Now())
+	if err != nil {
+		log.Printf("Error finding live collections: %v", err)
+		return HomeFeed{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	previews := make([]CollectionPreview, 0, len(collections))
+	for _, collection := range collections {
+		items, err := s.repo.FindCollectionItems(ctx, collection.ID, true, homeCollectionPreview)
+		if err != nil {
+			log.Printf("Error finding collection items: %v", err)
+			return HomeFeed{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+		}
+		// Koleksi kosong tidak perlu dirender di beranda
+		if len(items) == 0 {
+			continue
+		}
+		previews = append(previews, CollectionPreview{Collection: collection, Items: items})
+	}
+
+	feed := HomeFeed{
+		NewArrivals: arrivals,
+		Categories:  sections,
+		Collections: previews,
+	}
+	s.cache.Set(cacheKeyHome, feed)
+	return feed, nil
+}
+
+// --- Follow ---
+
+func (s *service) FollowShop(ctx context.Context, accountID, shopID uuid.UUID, req FollowShopRequest) (FollowedShop, error) {
+	shop, err := s.repo.FindShopOwner(ctx, shopID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return FollowedShop{}, apperror.New(apperror.ErrCodeNotFound, "shop not found")
+		}
+		log.Printf("Error finding shop: %v", err)
+		return FollowedShop{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if shop.AccountID == accountID {
+		return FollowedShop{}, apperror.New(apperror.ErrCodeValidation, "you cannot follow your own shop")
+	}
+
+	if err := s.repo.TransactionFollowShop(ctx, accountID, shopID, req.Notify); err != nil {
+		log.Printf("Error following shop: %v", err)
+		return FollowedShop{}, apperror.New(apperror.ErrCodeInternal, "failed to follow shop")
+	}
+
+	followed, err := s.repo.FindFollowedShop(ctx, accountID, shopID)
+	if err != nil {
+		log.Printf("Error finding followed shop: %v", err)
+		return FollowedShop{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return followed, nil
+}
+
+func (s *service) UnfollowShop(ctx context.Context, accountID, shopID uuid.UUID) error {
+	if err := s.repo.TransactionUnfollowShop(ctx, accountID, shopID); err != nil {
+		log.Printf("Error unfollowing shop: %v", err)
+		return apperror.New(apperror.ErrCodeInternal, "failed to unfollow shop")
+	}
+	return nil
+}
+
+func (s *service) GetFollowedShops(ctx context.Context, accountID uuid.UUID, req PageRequest) (FollowedShopPage, error) {
+	page, limit := normalizePage(req.Page, req.Limit)
+
+	shops, err := s.repo.FindFollowedShops(ctx, accountID, limit, (page-1)*limit)
+	if err != nil {
+		log.Printf("Error finding followed shops: %v", err)
+		return FollowedShopPage{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return FollowedShopPage{Items: shops, Page: page, Limit: limit}, nil
+}
+
+func (s *service) GetFollowingFeed(ctx context.Context, accountID uuid.UUID, req FollowingFeedRequest) (FollowingFeedPage, error) {
+	_, limit := normalizePage(1, req.Limit)
+
+	var cursor *FeedCursor
+	if req.Cursor != "" {
+		decoded, err := decodeCursor(req.Cursor)
+		if err != nil {
+			return FollowingFeedPage{}, apperror.New(apperror.ErrCodeValidation, "invalid cursor")
+		}
+		cursor = &decoded
+	}
+
+	// Ambil satu item lebih untuk mengetahui apakah masih ada halaman berikutnya
+	items, err := s.repo.FindFollowingFeed(ctx, accountID, cursor, limit+1)
+	if err != nil {
+		log.Printf("Error finding following feed: %v", err)
+		return FollowingFeedPage{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	page := FollowingFeedPage{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		last := page.Items[limit-1]
+		page.NextCursor = encodeCursor(FeedCursor{PublishedAt: last.PublishedAt, ID: last.ID})
+	}
+	return page, nil
+}
+
+func (s *service) SendShopDropAlerts(ctx context.Context) (int, error) {
+	// Kelompokkan semua listing yang baru tayang sebelum mengirim
+	for {
+		processed, err := s.repo.TransactionCollectShopDrops(ctx, shopDropBatchSize)
+		if err != nil {
+			return 0, fmt.Errorf("collect shop drops: %w", err)
+		}
+		if processed < shopDropBatchSize {
+			break
+		}
+	}
+
+	// Fan-out dicicil per potongan follower supaya shop dengan puluhan ribu follower
+	// tidak menahan satu transaksi besar
+	total := 0
+	for i := 0; i < maxShopDropChunksPerRun; i++ {
+		sent, found, err := s.repo.TransactionFanOutShopDrop(ctx, shopDropChunkSize, buildShopDropNotification)
+		if err != nil {
+			return total, fmt.Errorf("fan out shop drop: %w", err)
+		}
+		if !found {
+			break
+		}
+		total += sent
+	}
+	return total, nil
+}
+
+// buildShopDropNotification menyusun notifikasi yang sama untuk semua follower shop.
+func buildShopDropNotification(drop ShopDrop) (model.Notification, error) {
+	preview := drop.ProductIDs
+	if len(preview) > shopDropPreviewItems {
+		preview = preview[:shopDropPreviewItems]
+	}
+	data, err := json.Marshal(map[string]any{
+		"shop_id":       drop.ShopID,
+		"shop_slug":     drop.ShopSlug,
+		"listing_count": drop.ListingCount,
+		"product_ids":   preview,
+	})
+	if err != nil {
+		return model.Notification{}, err
+	}
+
+	body := fmt.Sprintf("%s just listed a new item.", drop.ShopName)
+	if drop.ListingCount > 1 {
+		body = fmt.Sprintf("%s just listed %d new items.", drop.ShopName, drop.ListingCount)
+	}
+	return model.Notification{
+		Type:  model.NotificationTypeShopDrop,
+		Title: fmt.Sprintf("New drop from %s", drop.ShopName),
+		Body:  body,
+		Data:  data,
+	}, nil
+}
+
+// StartShopDropAlertJob menjalankan SendShopDropAlerts secara berkala sampai ctx dibatalkan.
+func StartShopDropAlertJob(ctx context.Context, svc Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sent, err := svc.SendShopDropAlerts(ctx)
+			if err != nil {
+				log.Printf("Error sending shop drop alerts: %v", err)
+				continue
+			}
+			if sent > 0 {
+				log.Printf("Shop drop job sent %d notification(s)", sent)
+			}
+		}
+	}
+}
+
+// --- Collection ---
+
+func (s *service) GetCollections(ctx context.Context) ([]model.Collection, error) {
+	collections, err := s.repo.FindLiveCollections(ctx, time.Now())
+	if err != nil {
+		log.Printf("Error finding live collections: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return collections, nil
+}
+
+func (s *service) GetCollection(ctx context.Context, collectionSlug string) (CollectionDetail, error) {
+	collection, err := s.repo.FindCollectionBySlug(ctx, collectionSlug)
+	if err != nil {
+		return CollectionDetail{}, s.collectionReadError(err)
+	}
+	// Koleksi di luar jadwal tayang diperlakukan seperti tidak ada
+	if !collection.IsLive(time.Now()) {
+		return CollectionDetail{}, apperror.New(apperror.ErrCodeNotFound, "collection not found")
+	}
+	return s.collectionDetail(ctx, collection, true)
+}
+
+func (s *service) GetAllCollections(ctx context.Context) ([]CollectionSummary, error) {
+	collections, err := s.repo.FindCollections(ctx)
+	if err != nil {
+		log.Printf("Error finding collections: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	now := time.Now()
+	for i := range collections {
+		collections[i].Live = collections[i].IsLive(now)
+	}
+	return collections, nil
+}
+
+func (s *service) GetCollectionForAdmin(ctx context.Context, collectionID int) (CollectionDetail, error) {
+	collection, err := s.repo.FindCollectionByID(ctx, collectionID)
+	if err != nil {
+		return CollectionDetail{}, s.collectionReadError(err)
+	}
+	return s.collectionDetail(ctx, collection, false)
+}
+
+func (s *service) CreateCollection(ctx context.Context, actor audit.Actor, req CollectionRequest) (model.Collection, error) {
+	now := time.Now()
+	collection := model.Collection{CreatedAt: now, UpdatedAt: now}
+	if err := applyCollectionRequest(&collection, req); err != nil {
+		return model.Collection{}, err
+	}
+
+	saved, err := s.repo.SaveCollection(ctx, collection, actor.Entry("collection.create", fmt.Sprintf("created collection %q", collection.Slug)))
+	if err != nil {
+		return model.Collection{}, s.collectionWriteError(err)
+	}
+
+	s.cache.Delete(cacheKeyHome)
+	return saved, nil
+}
+
+func (s *service) UpdateCollection(ctx context.Context, actor audit.Actor, collectionID int, req CollectionRequest) (model.Collection, error) {
+	collection, err := s.repo.FindCollectionByID(ctx, collectionID)
+	if err != nil {
+		return model.Collection{}, s.collectionReadError(err)
+	}
+	if err := applyCollectionRequest(&collection, req); err != nil {
+		return model.Collection{}, err
+	}
+	collection.UpdatedAt = time.Now()
+
+	updated, err := s.repo.UpdateCollection(ctx, collection, actor.Entry("collection.update", fmt.Sprintf("updated collection %d (%q)", collectionID, collection.Slug)))
+	if err != nil {
+		return model.Collection{}, s.collectionWriteError(err)
+	}
+
+	s.cache.Delete(cacheKeyHome)
+	return updated, nil
+}
+
+func (s *service) DeleteCollection(ctx context.Context, actor audit.Actor, collectionID int) error {
+	collection, err := s.repo.FindCollectionByID(ctx, collectionID)
+	if err != nil {
+		return s.collectionReadError(err)
+	}
+
+	if err := s.repo.DeleteCollection(ctx, collectionID, actor.Entry("collection.delete", fmt.Sprintf("deleted collection %d (%q)", collectionID, collection.Slug))); err != nil {
+		return s.collectionWriteError(err)
+	}
+
+	s.cache.Delete(cacheKeyHome)
+	return nil
+}
+
+func (s *service) UploadCollectionCover(ctx context.Context, actor audit.Actor, collectionID int, file *multipart.FileHeader) (model.Collection, error) {
+	if file.Size > maxCoverSize {
+		return model.Collection{}, apperror.New(apperror.ErrCodeValidation, "cover must be at most 5 MB")
+	}
+
+	collection, err := s.repo.FindCollectionByID(ctx, collectionID)
+	if err != nil {
+		return model.Collection{}, s.collectionReadError(err)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return model.Collection{}, apperror.New(apperror.ErrCodeValidation, "failed to read cover file")
+	}
+	defer src.Close()
+
+	// Tentukan tipe file dari isinya, bukan dari nama file / header yang dikirim client
+	head := make([]byte, 512)
+	n, _ := src.Read(head)
+	ext, ok := coverExtensions[http.DetectContentType(head[:n])]
+	if !ok {
+		return model.Collection{}, apperror.New(apperror.ErrCodeValidation, "cover must be a PNG, JPEG or WebP image")
+	}
+	if _, err := src.Seek(0, 0); err != nil {
+		return model.Collection{}, apperror.New(apperror.ErrCodeInternal, "failed to read cover file")
+	}
+
+	key := path.Join("collections", fmt.Sprintf("%d-%s%s", collectionID, uuid.NewString(), ext))
+	url, err := s.storage.Save(ctx, key, src)
+	if err != nil {
+		log.Printf("Error storing collection cover: %v", err)
+		return model.Collection{}, apperror.New(apperror.ErrCodeInternal, "failed to store cover")
+	}
+
+	collection.CoverImageURL = &url
+	collection.UpdatedAt = time.Now()
+	updated, err := s.repo.UpdateCollection(ctx, collection, actor.Entry("collection.cover", fmt.Sprintf("uploaded cover for collection %d: %s", collectionID, url)))
+	if err != nil {
+		s.storage.Delete(ctx, key)
+		return model.Collection{}, s.collectionWriteError(err)
+	}
+
+	s.cache.Delete(cacheKeyHome)
+	return updated, nil
+}
+
+func (s *service) SetCollectionItems(ctx context.Context, actor audit.Actor, collectionID int, req CollectionItemsRequest) (CollectionDetail, error) {
+	collection, err := s.repo.FindCollectionByID(ctx, collectionID)
+	if err != nil {
+		return CollectionDetail{}, s.collectionReadError(err)
+	}
+
+	seen := make(map[uuid.UUID]bool, len(req.ProductIDs))
+	for _, id := range req.ProductIDs {
+		if seen[id] {
+			return CollectionDetail{}, apperror.New(apperror.ErrCodeValidation, "product "+id.String()+" is listed more than once")
+		}
+		seen[id] = true
+	}
+
+	entry := actor.Entry("collection.items", fmt.Sprintf("set %d item(s) in collection %d", len(req.ProductIDs), collectionID))
+	if err := s.repo.TransactionReplaceCollectionItems(ctx, collectionID, req.ProductIDs, entry); err != nil {
+		if errors.Is(err, ErrInvalidCollectionItem) {
+			return CollectionDetail{}, apperror.New(apperror.ErrCodeValidation, "collections can only contain published or sold listings")
+		}
+		log.Printf("Error replacing collection items: %v", err)
+		return CollectionDetail{}, apperror.New(apperror.ErrCodeInternal, "failed to save collection items")
+	}
+
+	s.cache.Delete(cacheKeyHome)
+	return s.collectionDetail(ctx, collection, false)
+}
+
+func (s *service) collectionDetail(ctx context.Context, collection model.Collection, publicOnly bool) (CollectionDetail, error) {
+	items, err := s.repo.FindCollectionItems(ctx, collection.ID, publicOnly, maxCollectionItems)
+	if err != nil {
+		log.Printf("Error finding collection items: %v", err)
+		return CollectionDetail{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return CollectionDetail{Collection: collection, Items: items}, nil
+}
+
+// applyCollectionRequest memvalidasi request lalu menyalinnya ke collection.
+func applyCollectionRequest(collection *model.Collection, req CollectionRequest) error {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return apperror.New(apperror.ErrCodeValidation, "title is required")
+	}
+
+	collectionSlug := slug.Make(req.Slug)
+	if strings.TrimSpace(req.Slug) == "" {
+		collectionSlug = slug.Make(title)
+	}
+	if collectionSlug == "" {
+		return apperror.New(apperror.ErrCodeValidation, "slug must contain letters or digits")
+	}
+
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return apperror.New(apperror.ErrCodeValidation, "ends_at must be after starts_at")
+	}
+
+	collection.Title = title
+	collection.Slug = collectionSlug
+	collection.Description = req.Description
+	collection.Position = req.Position
+	collection.StartsAt = req.StartsAt
+	collection.EndsAt = req.EndsAt
+	return nil
+}
+
+func (s *service) collectionReadError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperror.New(apperror.ErrCodeNotFound, "collection not found")
+	}
+	log.Printf("Error finding collection: %v", err)
+	return apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+}
+
+func (s *service) collectionWriteError(err error) error {
+	if errors.Is(err, ErrDuplicateSlug) {
+		return apperror.New(apperror.ErrCodeConflict, "collection slug already exists")
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperror.New(apperror.ErrCodeNotFound, "collection not found")
+	}
+	log.Printf("Error saving collection: %v", err)
+	return apperror.New(apperror.ErrCodeInternal, "failed to save collection")
+}
+
+func normalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	return page, limit
+}
+
+// encodeCursor mengubah posisi keyset menjadi token opaque untuk client.
+func encodeCursor(cursor FeedCursor) string {
+	raw := strconv.FormatInt(cursor.PublishedAt.UnixMicro(), 10) + "." + cursor.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(token string) (FeedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return FeedCursor{}, ErrInvalidCursor
+	}
+	micros, id, ok := strings.Cut(string(raw), ".")
+	if !ok {
+		return FeedCursor{}, ErrInvalidCursor
+	}
+	publishedAt, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return FeedCursor{}, ErrInvalidCursor
+	}
+	productID, err := uuid.Parse(id)
+	if err != nil {
+		return FeedCursor{}, ErrInvalidCursor
+	}
+	return FeedCursor{PublishedAt: time.UnixMicro(publishedAt), ID: productID}, nil
+}
@@ -0,0 +1,86 @@
+package inventory
+
+// File: internal/service/inventory/domain.go
+
+import (
+	"context"
+	"errors"
+	"time"
+	"vintage-server/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	// ErrInsufficientStock dikembalikan jika stok tersedia (stock - hold aktif) kurang dari yang diminta.
+	ErrInsufficientStock = errors.New("insufficient available stock")
+	// ErrHoldNotActive dikembalikan jika hold sudah dilepas / dikonversi sebelumnya.
+	ErrHoldNotActive = errors.New("inventory hold is no longer active")
+	// ErrProductUnavailable dikembalikan jika produk tidak sedang tayang (draft, sold, archived, dll.).
+	ErrProductUnavailable = errors.New("product is not available for purchase")
+	// ErrStockBelowHeld dikembalikan jika stok baru lebih kecil dari quantity yang sedang di-hold.
+	ErrStockBelowHeld = errors.New("stock is below the quantity held by ongoing checkouts")
+)
+
+// HoldRequest adalah permintaan untuk mengunci stok sebuah produk.
+type HoldRequest struct {
+	ProductID uuid.UUID
+	AccountID uuid.UUID
+	OrderID   *uuid.UUID
+	Quantity  int
+	ExpiresAt time.Time
+	// UnitPrice diisi untuk offer yang diterima: harga khusus yang wajib dipakai saat checkout
+	UnitPrice *int64
+}
+
+// =================================================================================
+// KONTRAK UNTUK REPOSITORY (Akses Database) 🚚
+// =================================================================================
+// Repository adalah satu-satunya pintu untuk membaca stok tersedia dan mengubah products.stock.
+// Service lain (cart, order, payment) memanggilnya di dalam transaksi mereka sendiri,
+// karena itu method yang mengubah data menerima *sqlx.Tx, bukan membuka transaksi sendiri.
+type Repository interface {
+	// AvailableStock mengembalikan stock dikurangi hold aktif yang belum kedaluwarsa.
+	// Produk yang tidak berstatus published atau sudah dihapus dianggap tidak ada (sql.ErrNoRows).
+	AvailableStock(ctx context.Context, q sqlx.QueryerContext, productID uuid.UUID) (int, error)
+	// AvailableStockFor sama dengan AvailableStock, tapi hold offer milik accountID yang belum
+	// dipakai order ikut dihitung tersedia, karena stok itu memang disisihkan untuk akun tersebut.
+	// Dipakai untuk pembelian harga tetap, jadi produk yang sedang dilelang juga dianggap tidak ada.
+	AvailableStockFor(ctx context.Context, q sqlx.QueryerContext, productID, accountID uuid.UUID) (int, error)
+
+	// SetStock mengunci baris produk lalu mengganti products.stock dengan nilai dari seller.
+	// Mengembalikan ErrStockBelowHeld jika stok baru lebih kecil dari total hold aktif.
+	// Sama seperti ConvertOrderHolds, listing published yang stoknya menjadi 0 berpindah ke 'sold'.
+	SetStock(ctx context.Context, tx *sqlx.Tx, productID uuid.UUID, stock int) error
+
+	// Hold mengunci baris produk (FOR UPDATE), mengecek stok tersedia, lalu membuat hold.
+	// Mengembalikan ErrProductUnavailable jika produk tidak published / sudah dihapus, atau ErrInsufficientStock jika stok tidak cukup.
+	Hold(ctx context.Context, tx *sqlx.Tx, req HoldRequest) (model.InventoryHold, error)
+
+	// FindOfferHold mengunci hold harga khusus (hasil offer) milik pembeli untuk sebuah produk
+	// yang masih aktif dan belum dipakai order. Mengembalikan sql.ErrNoRows jika tidak ada.
+	FindOfferHold(ctx context.Context, tx *sqlx.Tx, accountID, productID uuid.UUID) (model.InventoryHold, error)
+	// AttachHold memindahkan hold yang sudah ada ke sebuah order dengan masa berlaku baru.
+	// Masa berlaku lama disimpan di offer_expires_at untuk dikembalikan oleh ReleaseOrderHolds.
+	AttachHold(ctx context.Context, tx *sqlx.Tx, holdID, orderID uuid.UUID, expiresAt time.Time) error
+
+	// ReleaseOrderHolds melepas semua hold aktif milik sebuah order (misal order dibatalkan).
+	// Hold offer yang dipasang lewat AttachHold tidak dilepas, tapi dikembalikan ke pembeli
+	// (order_id NULL) dengan masa berlaku offer semula, supaya harga offer tetap bisa dipakai.
+	ReleaseOrderHolds(ctx context.Context, tx *sqlx.Tx, orderID uuid.UUID) error
+
+	// ConvertOrderHolds mengubah hold sebuah order menjadi penjualan: products.stock dikurangi
+	// dan hold ditandai 'converted'. Hold yang sudah kedaluwarsa tetap dikonversi jika stoknya
+	// masih tersedia; jika tidak, mengembalikan ErrInsufficientStock.
+	// Produk published yang stoknya habis otomatis berpindah ke status 'sold'.
+	ConvertOrderHolds(ctx context.Context, tx *sqlx.Tx, orderID uuid.UUID) error
+
+	// ExpireOrderHolds menandai hold aktif milik sebuah order yang sudah lewat expires_at menjadi 'expired'.
+	// Pemanggil wajib sudah mengunci baris order, supaya tidak balapan dengan konversi pembayaran.
+	// Hold offer dilewati; hold itu dikembalikan ke pembeli oleh ReleaseOrderHolds.
+	ExpireOrderHolds(ctx context.Context, tx *sqlx.Tx, orderID uuid.UUID, now time.Time) error
+	// ExpireHolds menandai hold aktif tanpa order (hold offer) yang sudah lewat expires_at
+	// menjadi 'expired' dan mengembalikan jumlahnya. Hold milik order disapu lewat ExpireOrderHolds.
+	ExpireHolds(ctx context.Context, tx *sqlx.Tx, now time.Time) (int64, error)
+}
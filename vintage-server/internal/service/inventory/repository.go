@@ -0,0 +1,261 @@
+package inventory
+
+import (
+	"context"
+	"time"
+	"vintage-server/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// activeHoldsSum menjumlahkan hold aktif yang belum kedaluwarsa untuk produk $1.
+// Hold yang sudah lewat expires_at tapi belum disapu sweeper dianggap sudah lepas.
+const activeHoldsSum = `
+	SELECT COALESCE(SUM(quantity), 0) FROM inventory_holds
+	WHERE product_id = $1 AND status = 'active' AND expires_at > CURRENT_TIMESTAMP`
+
+// repository adalah struct yang mengimplementasikan kontrak Repository dari domain.go.
+// Tidak menyimpan koneksi sendiri, semua query berjalan di koneksi / transaksi pemanggil.
+type repository struct{}
+
+// NewRepository adalah constructor untuk implementasi repository
+func NewRepository() Repository {
+	return &repository{}
+}
+
+func (r *repository) AvailableStock(ctx context.Context, q sqlx.QueryerContext, productID uuid.UUID) (int, error) {
+	var available int
+	query := `SELECT p.stock - (` + activeHoldsSum + `) FROM products p WHERE p.id = $1 AND p.status = 'published' AND p.deleted_at IS NULL AND p.shop_id IN (SELECT id FROM shop WHERE active)`
+	err := sqlx.GetContext(ctx, q, &available, query, productID)
+	return available, err
+}
+
+func (r *repository) AvailableStockFor(ctx context.Context, q sqlx.QueryerContext, productID, accountID uuid.UUID) (int, error) {
+	var available int
+	query := `
+		SELECT p.stock - (
+			SELECT COALESCE(SUM(quantity), 0) FROM inventory_holds
+			WHERE product_id = $1 AND status = 'active' AND expires_at > CURRENT_TIMESTAMP
+			  AND NOT (account_id = $2 AND order_id IS NULL AND unit_price IS NOT NULL)
+		)
+		FROM products p WHERE p.id = $1 AND p.status = 'published' AND p.listing_mode = 'fixed' AND p.deleted_at IS NULL
+		  AND p.shop_id IN (SELECT id FROM shop WHERE active)`
+	err := sqlx.GetContext(ctx, q, &available, query, productID, accountID)
+	return available, err
+}
+
+func (r *repository) SetStock(ctx context.Context, tx *sqlx.Tx, productID uuid.UUID, stock int) error {
+	// Hold dibaca setelah baris produk dikunci, jadi tidak balapan dengan Hold / ConvertOrderHolds
+	var current lockedStock
+	if err := tx.GetContext(ctx, &current, "SELECT stock, status FROM products WHERE id = $1 FOR UPDATE", productID); err != nil {
+		return err
+	}
+	var held int
+	if err := tx.GetContext(ctx, &held, activeHoldsSum, productID); err != nil {
+		return err
+	}
+	if stock < held {
+		return ErrStockBelowHeld
+	}
+	if stock == current.Stock {
+		return nil
+	}
+	return writeStock(ctx, tx, productID, current, stock, "stock set to 0 by seller")
+}
+
+func (r *repository) Hold(ctx context.Context, tx *sqlx.Tx, req HoldRequest) (model.InventoryHold, error) {
+	// 1. Lock baris produk. Semua pembuat hold melewati lock ini, jadi cek di bawah aman dari race.
+	available, purchasable, err := lockAvailable(ctx, tx, req.ProductID)
+	if err != nil {
+		return model.InventoryHold{}, err
+	}
+	if !purchasable {
+		return model.InventoryHold{}, ErrProductUnavailable
+	}
+	if available < req.Quantity {
+		return model.InventoryHold{}, ErrInsufficientStock
+	}
+
+	// 2. Simpan hold
+	var hold model.InventoryHold
+	query := `
+		INSERT INTO inventory_holds (product_id, account_id, order_id, quantity, unit_price, status, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, 'active', $6, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING *`
+	err = tx.GetContext(ctx, &hold, query, req.ProductID, req.AccountID, req.OrderID, req.Quantity, req.UnitPrice, req.ExpiresAt)
+	return hold, err
+}
+
+func (r *repository) FindOfferHold(ctx context.Context, tx *sqlx.Tx, accountID, productID uuid.UUID) (model.InventoryHold, error) {
+	var hold model.InventoryHold
+	query := `
+		SELECT * FROM inventory_holds
+		WHERE account_id = $1 AND product_id = $2 AND order_id IS NULL AND unit_price IS NOT NULL
+		  AND status = 'active' AND expires_at > CURRENT_TIMESTAMP
+		ORDER BY created_at
+		LIMIT 1
+		FOR UPDATE`
+	err := tx.GetContext(ctx, &hold, query, accountID, productID)
+	return hold, err
+}
+
+func (r *repository) AttachHold(ctx context.Context, tx *sqlx.Tx, holdID, orderID uuid.UUID, expiresAt time.Time) error {
+	query := `
+		UPDATE inventory_holds SET order_id = $2, offer_expires_at = expires_at, expires_at = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'active'`
+	result, err := tx.ExecContext(ctx, query, holdID, orderID, expiresAt)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrHoldNotActive
+	}
+	return nil
+}
+
+func (r *repository) ReleaseOrderHolds(ctx context.Context, tx *sqlx.Tx, orderID uuid.UUID) error {
+	// Hold offer kembali menjadi milik pembeli; jika masa berlakunya sudah lewat, ExpireHolds yang menyapunya
+	detachQuery := `
+		UPDATE inventory_holds SET order_id = NULL, expires_at = offer_expires_at, offer_expires_at = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE order_id = $1 AND status = 'active' AND offer_expires_at IS NOT NULL`
+	if _, err := tx.ExecContext(ctx, detachQuery, orderID); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE inventory_holds SET status = 'released', updated_at = CURRENT_TIMESTAMP
+		WHERE order_id = $1 AND status = 'active'`
+	_, err := tx.ExecContext(ctx, query, orderID)
+	return err
+}
+
+func (r *repository) ConvertOrderHolds(ctx context.Context, tx *sqlx.Tx, orderID uuid.UUID) error {
+	var holds []model.InventoryHold
+	// Urutkan per produk supaya urutan lock konsisten dan tidak deadlock dengan transaksi lain
+	query := "SELECT * FROM inventory_holds WHERE order_id = $1 AND status = 'active' ORDER BY product_id FOR UPDATE"
+	if err := tx.SelectContext(ctx, &holds, query, orderID); err != nil {
+		return err
+	}
+	if len(holds) == 0 {
+		return ErrHoldNotActive
+	}
+
+	now := time.Now()
+	for _, hold := range holds {
+		// Status tidak dicek: pembeli sudah membayar meskipun seller mengarsipkan produknya
+		available, _, err := lockAvailable(ctx, tx, hold.ProductID)
+		if err != nil {
+			return err
+		}
+		// Hold yang masih berlaku sudah termasuk di hitungan hold aktif, jadi tambahkan kembali
+		if hold.ExpiresAt.After(now) {
+			available += hold.Quantity
+		}
+		if available < hold.Quantity {
+			return ErrInsufficientStock
+		}
+
+		if err := decrementStock(ctx, tx, hold.ProductID, hold.Quantity); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE inventory_holds SET status = 'converted', updated_at = CURRENT_TIMESTAMP WHERE id = $1", hold.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *repository) ExpireOrderHolds(ctx context.Context, tx *sqlx.Tx, orderID uuid.UUID, now time.Time) error {
+	query := `
+		UPDATE inventory_holds SET status = 'expired', updated_at = CURRENT_TIMESTAMP
+		WHERE order_id = $1 AND status = 'active' AND expires_at <= $2 AND offer_expires_at IS NULL`
+	_, err := tx.ExecContext(ctx, query, orderID, now)
+	return err
+}
+
+func (r *repository) ExpireHolds(ctx context.Context, tx *sqlx.Tx, now time.Time) (int64, error) {
+	query := `
+		UPDATE inventory_holds SET status = 'expired', updated_at = CURRENT_TIMESTAMP
+		WHERE order_id IS NULL AND status = 'active' AND expires_at <= $1`
+	result, err := tx.ExecContext(ctx, query, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// lockAvailable mengunci baris produk lalu menghitung stok tersedia di transaksi yang sama.
+// purchasable menandai listing yang tayang dan belum dihapus, supaya pemanggil bisa menolak produk lain.
+func lockAvailable(ctx context.Context, tx *sqlx.Tx, productID uuid.UUID) (int, bool, error) {
+	var product struct {
+		Stock       int  `db:"stock"`
+		Purchasable bool `db:"purchasable"`
+	}
+	query := `
+		SELECT stock, status = 'published' AND deleted_at IS NULL AND shop_id IN (SELECT id FROM shop WHERE active) AS purchasable
+		FROM products WHERE id = $1 FOR UPDATE`
+	if err := tx.GetContext(ctx, &product, query, productID); err != nil {
+		return 0, false, err
+	}
+
+	var held int
+	if err := tx.GetContext(ctx, &held, activeHoldsSum, productID); err != nil {
+		return 0, false, err
+	}
+	return product.Stock - held, product.Purchasable, nil
+}
+
+// lockedStock adalah stok & status produk yang dibaca dengan FOR UPDATE sebelum diubah.
+type lockedStock struct {
+	Stock  int    `db:"stock"`
+	Status string `db:"status"`
+}
+
+// decrementStock mengurangi stok produk yang sudah dikunci.
+func decrementStock(ctx context.Context, tx *sqlx.Tx, productID uuid.UUID, quantity int) error {
+	var current lockedStock
+	if err := tx.GetContext(ctx, &current, "SELECT stock, status FROM products WHERE id = $1 FOR UPDATE", productID); err != nil {
+		return err
+	}
+	return writeStock(ctx, tx, productID, current, current.Stock-quantity, "stock sold out")
+}
+
+// writeStock menyimpan stok baru produk yang sudah dikunci. Status mengikuti stockStatus,
+// dan perpindahannya dicatat di product_status_logs dengan reason.
+func writeStock(ctx context.Context, tx *sqlx.Tx, productID uuid.UUID, current lockedStock, stock int, reason string) error {
+	status := stockStatus(current.Status, stock)
+	query := `
+		UPDATE products SET
+			stock = $2,
+			status = $3,
+			sold_at = CASE WHEN $3 = 'sold' AND status <> 'sold' THEN CURRENT_TIMESTAMP ELSE sold_at END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, query, productID, stock, status); err != nil {
+		return err
+	}
+	if status == current.Status {
+		return nil
+	}
+
+	logQuery := `
+		INSERT INTO product_status_logs (product_id, from_status, to_status, reason)
+		VALUES ($1, $2, $3, $4)`
+	_, err := tx.ExecContext(ctx, logQuery, productID, current.Status, status, reason)
+	return err
+}
+
+// stockStatus menentukan status listing setelah stoknya menjadi stock. Listing published yang
+// stoknya habis dipindah ke 'sold' supaya tetap bisa dilihat dengan badge terjual; status lain
+// (draft, archived, sold) tidak diubah oleh perubahan stok.
+func stockStatus(status string, stock int) string {
+	if status == model.ListingStatusPublished && stock == 0 {
+		return model.ListingStatusSold
+	}
+	return status
+}
@@ -0,0 +1,28 @@
+package inventory
+
+import (
+	"testing"
+	"vintage-server/internal/model"
+)
+
+func TestStockStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		stock  int
+		want   string
+	}{
+		{"published sold out", model.ListingStatusPublished, 0, model.ListingStatusSold},
+		{"published with stock left", model.ListingStatusPublished, 2, model.ListingStatusPublished},
+		{"draft set to zero stays draft", model.ListingStatusDraft, 0, model.ListingStatusDraft},
+		{"archived set to zero stays archived", model.ListingStatusArchived, 0, model.ListingStatusArchived},
+		{"sold restocked stays sold", model.ListingStatusSold, 3, model.ListingStatusSold},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stockStatus(tt.status, tt.stock); got != tt.want {
+				t.Errorf("stockStatus(%q, %d) = %q, want %q", tt.status, tt.stock, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package kyc
+
+// File: internal/service/kyc/domain.go
+
+import (
+	"context"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/audit"
+
+	"github.com/google/uuid"
+)
+
+// =================================================================================
+// KONTRAK UNTUK SERVICE (Logika Bisnis) 🧠
+// =================================================================================
+type Service interface {
+	// --- Seller ---
+	// Usecase: SellerSubmit KYC (hanya owner shop; melengkapi pengajuan needs_info boleh tanpa
+	// mengirim ulang dokumen yang tidak diminta)
+	SubmitKYC(ctx context.Context, accountID uuid.UUID, req SubmitRequest, docs Documents) (SellerKYC, error)
+	GetMyKYC(ctx context.Context, accountID uuid.UUID) (SellerKYC, error)
+
+	// --- Admin ---
+	// Usecase: AdminReview KYC (antrian urut waktu pengajuan, paling lama di depan)
+	GetQueue(ctx context.Context, filter QueueFilter) (QueuePage, error)
+	GetSubmission(ctx context.Context, submissionID uuid.UUID) (SubmissionDetail, error)
+	// Usecase: AdminApprove / Reject / Request Info KYC
+	Review(ctx context.Context, actor audit.Actor, submissionID uuid.UUID, action Action, req DecisionRequest) (model.KYCSubmission, error)
+	// RevealKTPNumber dan OpenDocument selalu dicatat di admin_logs sebelum datanya diberikan.
+	RevealKTPNumber(ctx context.Context, actor audit.Actor, submissionID uuid.UUID) (KTPNumberResponse, error)
+	OpenDocument(ctx context.Context, actor audit.Actor, submissionID uuid.UUID, kind DocumentKind) (Document, error)
+}
+
+// =================================================================================
+// KONTRAK UNTUK REPOSITORY (Akses Database) 🚚
+// =================================================================================
+// Repository mendefinisikan semua interaksi ke database yang dibutuhkan oleh Service.
+type Repository interface {
+	// FindShopMembership mengambil shop tempat akun menjadi anggota beserta role-nya.
+	FindShopMembership(ctx context.Context, accountID uuid.UUID) (model.ShopMember, error)
+	FindShopByID(ctx context.Context, shopID uuid.UUID) (model.Shop, error)
+	// FindLatestSubmission mengambil pengajuan terbaru shop (sql.ErrNoRows jika belum ada).
+	FindLatestSubmission(ctx context.Context, shopID uuid.UUID) (model.KYCSubmission, error)
+	// KTPNumberInUse memeriksa apakah hash nomor KTP sudah memverifikasi shop selain shopID.
+	KTPNumberInUse(ctx context.Context, hash string, shopID uuid.UUID) (bool, error)
+	// TransactionSubmit menyimpan pengajuan dan menjadikan shop pending, hanya jika kyc_status shop
+	// di DB masih from (ErrKYCChanged jika tidak). Dari needs_info, pengajuan yang terbuka
+	// diperbarui; selain itu pengajuan baru dibuat.
+	TransactionSubmit(ctx context.Context, submission model.KYCSubmission, from string) (model.KYCSubmission, error)
+
+	FindQueue(ctx context.Context, filter QueueFilter) ([]Submission, int64, error)
+	FindSubmission(ctx context.Context, submissionID uuid.UUID) (Submission, error)
+	FindShopSubmissions(ctx context.Context, shopID uuid.UUID) ([]model.KYCSubmission, error)
+	// TransactionReview mengubah status pengajuan hanya jika masih pending (ErrKYCChanged jika
+	// tidak), menyalin statusnya ke shop, lalu menulis admin_logs dan notifikasi seller.
+	// ErrKTPInUse jika KTP yang disetujui sudah memverifikasi shop lain.
+	TransactionReview(ctx context.Context, decision Decision, entry model.AdminLog, notice model.Notification) (model.KYCSubmission, error)
+	SaveAdminLog(ctx context.Context, entry model.AdminLog) error
+}
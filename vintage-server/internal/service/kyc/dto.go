@@ -0,0 +1,112 @@
+package kyc
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"time"
+	"vintage-server/internal/model"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrKYCChanged dikembalikan repository jika status verifikasi berubah sejak dibaca service.
+	ErrKYCChanged = errors.New("kyc status changed concurrently")
+	// ErrKTPInUse dikembalikan repository jika nomor KTP sudah memverifikasi shop lain.
+	ErrKTPInUse = errors.New("ktp number already verified for another shop")
+)
+
+// Action adalah keputusan admin atas pengajuan KYC.
+type Action string
+
+const (
+	// ActionApprove: identitas valid, shop menjadi verified (pending -> approved)
+	ActionApprove Action = "approve"
+	// ActionReject: pengajuan ditolak, seller harus mengajukan ulang dari awal (pending -> rejected)
+	ActionReject Action = "reject"
+	// ActionRequestInfo: seller diminta melengkapi / memperbaiki pengajuan yang sama (pending -> needs_info)
+	ActionRequestInfo Action = "request_info"
+)
+
+// DocumentKind adalah jenis dokumen KYC; nilainya juga nama field form upload.
+type DocumentKind string
+
+const (
+	DocumentKTPPhoto DocumentKind = "ktp_photo"
+	DocumentSelfie   DocumentKind = "selfie"
+)
+
+// SubmitRequest dikirim sebagai multipart form bersama file ktp_photo dan selfie.
+type SubmitRequest struct {
+	KTPNumber         string `form:"ktp_number" binding:"required"`
+	BankAccountHolder string `form:"bank_account_holder" binding:"required,max=100"`
+}
+
+// Documents adalah file yang diupload seller. File nil berarti dokumen sebelumnya dipakai lagi,
+// hanya boleh saat melengkapi pengajuan yang berstatus needs_info.
+type Documents struct {
+	KTPPhoto *multipart.FileHeader
+	Selfie   *multipart.FileHeader
+}
+
+// SellerKYC adalah status verifikasi shop beserta pengajuan terakhirnya (nil jika belum pernah mengajukan).
+type SellerKYC struct {
+	Status     string               `json:"status"`
+	VerifiedAt *time.Time           `json:"verified_at"`
+	Submission *model.KYCSubmission `json:"submission"`
+}
+
+// QueueFilter: Status kosong berarti pending (antrian review).
+type QueueFilter struct {
+	Status string `form:"status"`
+	Page   int    `form:"page"`
+	Limit  int    `form:"limit"`
+}
+
+// Submission adalah pengajuan KYC beserta shop pengajunya, untuk antrian review admin.
+type Submission struct {
+	model.KYCSubmission
+	ShopName string    `json:"shop_name" db:"shop_name"`
+	ShopSlug string    `json:"shop_slug" db:"shop_slug"`
+	OwnerID  uuid.UUID `json:"owner_id" db:"owner_id"`
+}
+
+type QueuePage struct {
+	Items []Submission `json:"items"`
+	Total int64        `json:"total"`
+	Page  int          `json:"page"`
+	Limit int          `json:"limit"`
+}
+
+// SubmissionDetail menyertakan pengajuan-pengajuan shop sebelumnya sebagai konteks reviewer.
+type SubmissionDetail struct {
+	Submission
+	History []model.KYCSubmission `json:"history"`
+}
+
+// DecisionRequest adalah alasan keputusan admin; wajib untuk reject dan request_info.
+type DecisionRequest struct {
+	Reason string `json:"reason" binding:"max=1000"`
+}
+
+type KTPNumberResponse struct {
+	SubmissionID uuid.UUID `json:"submission_id"`
+	KTPNumber    string    `json:"ktp_number"`
+}
+
+// Document adalah isi dokumen KYC yang dibuka admin; Content wajib ditutup pemanggil.
+type Document struct {
+	Content     io.ReadCloser
+	ContentType string
+}
+
+// Decision adalah perubahan status pengajuan beserta status shop yang mengikutinya.
+type Decision struct {
+	SubmissionID uuid.UUID
+	ShopID       uuid.UUID
+	To           string
+	ShopStatus   string
+	Note         *string
+	ReviewerID   uuid.UUID
+}
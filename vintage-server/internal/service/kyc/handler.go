@@ -0,0 +1,153 @@
+package kyc
+
+import (
+	"net/http"
+	"vintage-server/internal/service/audit"
+	"vintage-server/pkg/middleware"
+	"vintage-server/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Handler adalah struct yang memegang dependency ke Service
+type Handler struct {
+	svc Service
+}
+
+// NewHandler adalah constructor untuk handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// --- Seller ---
+
+// SubmitKYC menerima multipart form: ktp_number, bank_account_holder, serta file ktp_photo dan selfie
+func (h *Handler) SubmitKYC(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var req SubmitRequest
+	if err := c.ShouldBind(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	// File yang tidak dikirim dibiarkan nil; service yang menentukan apakah wajib
+	var docs Documents
+	docs.KTPPhoto, _ = c.FormFile(string(DocumentKTPPhoto))
+	docs.Selfie, _ = c.FormFile(string(DocumentSelfie))
+
+	result, err := h.svc.SubmitKYC(c.Request.Context(), accountID, req, docs)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusCreated, result)
+}
+
+// GetMyKYC mengembalikan status verifikasi shop; nomor KTP hanya ditampilkan 4 digit terakhirnya
+func (h *Handler) GetMyKYC(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	result, err := h.svc.GetMyKYC(c.Request.Context(), accountID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, result)
+}
+
+// --- Admin ---
+
+func (h *Handler) GetQueue(c *gin.Context) {
+	var filter QueueFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	page, err := h.svc.GetQueue(c.Request.Context(), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, page)
+}
+
+func (h *Handler) GetSubmission(c *gin.Context) {
+	submissionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid verification id")
+		return
+	}
+
+	detail, err := h.svc.GetSubmission(c.Request.Context(), submissionID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, detail)
+}
+
+// Review mengembalikan handler untuk satu keputusan admin (approve / reject / request_info)
+func (h *Handler) Review(action Action) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		submissionID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid verification id")
+			return
+		}
+
+		// Body boleh kosong untuk keputusan yang tidak mewajibkan alasan
+		var req DecisionRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				response.Error(c, http.StatusBadRequest, "Invalid request body")
+				return
+			}
+		}
+
+		submission, err := h.svc.Review(c.Request.Context(), audit.ActorFromContext(c), submissionID, action, req)
+		if err != nil {
+			response.FromError(c, err)
+			return
+		}
+		response.Success(c, http.StatusOK, submission)
+	}
+}
+
+// RevealKTPNumber mengembalikan nomor KTP lengkap; setiap akses dicatat di admin_logs
+func (h *Handler) RevealKTPNumber(c *gin.Context) {
+	submissionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid verification id")
+		return
+	}
+
+	result, err := h.svc.RevealKTPNumber(c.Request.Context(), audit.ActorFromContext(c), submissionID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	response.Success(c, http.StatusOK, result)
+}
+
+// GetDocument mengirim isi foto KTP / selfie dari private storage; setiap akses dicatat di admin_logs
+func (h *Handler) GetDocument(c *gin.Context) {
+	submissionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid verification id")
+		return
+	}
+
+	doc, err := h.svc.OpenDocument(c.Request.Context(), audit.ActorFromContext(c), submissionID, DocumentKind(c.Param("kind")))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	defer doc.Content.Close()
+
+	c.DataFromReader(http.StatusOK, -1, doc.ContentType, doc.Content, map[string]string{
+		"Cache-Control": "no-store",
+	})
+}
@@ -0,0 +1,223 @@
+package kyc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/audit"
+	"vintage-server/internal/service/notification"
+	"vintage-server/internal/service/staff"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// submissionSelect mengambil pengajuan beserta shop pengajunya (struct Submission).
+const submissionSelect = `
+	SELECT k.*, s.name AS shop_name, s.slug AS shop_slug, s.account_id AS owner_id
+	FROM kyc_submissions k
+	JOIN shop s ON s.id = k.shop_id`
+
+// repository adalah struct yang mengimplementasikan kontrak Repository dari domain.go
+type repository struct {
+	db *sqlx.DB
+}
+
+// NewRepository adalah constructor untuk implementasi repository
+func NewRepository(db *sqlx.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// --- Seller ---
+
+func (r *repository) FindShopMembership(ctx context.Context, accountID uuid.UUID) (model.ShopMember, error) {
+	return staff.FindMembership(ctx, r.db, accountID)
+}
+
+func (r *repository) FindShopByID(ctx context.Context, shopID uuid.UUID) (model.Shop, error) {
+	var shop model.Shop
+	query := "SELECT * FROM shop WHERE id = $1 AND deleted_at IS NULL"
+	err := r.db.GetContext(ctx, &shop, query, shopID)
+	return shop, err
+}
+
+func (r *repository) FindLatestSubmission(ctx context.Context, shopID uuid.UUID) (model.KYCSubmission, error) {
+	var submission model.KYCSubmission
+	query := "SELECT * FROM kyc_submissions WHERE shop_id = $1 ORDER BY created_at DESC, id LIMIT 1"
+	err := r.db.GetContext(ctx, &submission, query, shopID)
+	return submission, err
+}
+
+func (r *repository) KTPNumberInUse(ctx context.Context, hash string, shopID uuid.UUID) (bool, error) {
+	var inUse bool
+	query := "SELECT EXISTS (SELECT 1 FROM kyc_submissions WHERE ktp_number_hash = $1 AND status = 'approved' AND shop_id <> $2)"
+	err := r.db.GetContext(ctx, &inUse, query, hash, shopID)
+	return inUse, err
+}
+
+func (r *repository) TransactionSubmit(ctx context.Context, submission model.KYCSubmission, from string) (model.KYCSubmission, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return model.KYCSubmission{}, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Kunci shop dan pastikan status verifikasinya belum berubah
+	var status string
+	lockQuery := "SELECT kyc_status FROM shop WHERE id = $1 AND deleted_at IS NULL FOR UPDATE"
+	if err := tx.GetContext(ctx, &status, lockQuery, submission.ShopID); err != nil {
+		return model.KYCSubmission{}, err
+	}
+	if status != from {
+		return model.KYCSubmission{}, ErrKYCChanged
+	}
+
+	// Query 2: Lengkapi pengajuan yang diminta info tambahan, atau buat pengajuan baru.
+	// review_note tetap berisi permintaan admin sampai keputusan berikutnya.
+	var saved model.KYCSubmission
+	if from == model.ShopKYCNeedsInfo {
+		query := `
+			UPDATE kyc_submissions SET
+				submitted_by = :submitted_by,
+				status = 'pending',
+				ktp_number_encrypted = :ktp_number_encrypted,
+				ktp_number_hash = :ktp_number_hash,
+				ktp_last4 = :ktp_last4,
+				ktp_photo_key = :ktp_photo_key,
+				selfie_key = :selfie_key,
+				bank_account_holder = :bank_account_holder,
+				submitted_at = CURRENT_TIMESTAMP,
+				updated_at = CURRENT_TIMESTAMP
+			WHERE shop_id = :shop_id AND status = 'needs_info'
+			RETURNING *`
+		err = namedGet(ctx, tx, &saved, query, submission)
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.KYCSubmission{}, ErrKYCChanged
+		}
+	} else {
+		query := `
+			INSERT INTO kyc_submissions (shop_id, submitted_by, status, ktp_number_encrypted, ktp_number_hash,
+				ktp_last4, ktp_photo_key, selfie_key, bank_account_holder)
+			VALUES (:shop_id, :submitted_by, 'pending', :ktp_number_encrypted, :ktp_number_hash,
+				:ktp_last4, :ktp_photo_key, :selfie_key, :bank_account_holder)
+			RETURNING *`
+		err = namedGet(ctx, tx, &saved, query, submission)
+	}
+	if err != nil {
+		return model.KYCSubmission{}, err
+	}
+
+	// Query 3: Shop masuk antrian review
+	shopQuery := "UPDATE shop SET kyc_status = 'pending', updated_at = CURRENT_TIMESTAMP WHERE id = $1"
+	if _, err := tx.ExecContext(ctx, shopQuery, submission.ShopID); err != nil {
+		return model.KYCSubmission{}, err
+	}
+
+	return saved, tx.Commit()
+}
+
+// --- Admin ---
+
+func (r *repository) FindQueue(ctx context.Context, filter QueueFilter) ([]Submission, int64, error) {
+	var total int64
+	countQuery := "SELECT COUNT(*) FROM kyc_submissions WHERE status = $1"
+	if err := r.db.GetContext(ctx, &total, countQuery, filter.Status); err != nil {
+		return nil, 0, err
+	}
+
+	// Antrian review paling lama di atas
+	items := []Submission{}
+	query := submissionSelect + " WHERE k.status = $1 ORDER BY k.submitted_at ASC, k.id LIMIT $2 OFFSET $3"
+	err := r.db.SelectContext(ctx, &items, query, filter.Status, filter.Limit, (filter.Page-1)*filter.Limit)
+	return items, total, err
+}
+
+func (r *repository) FindSubmission(ctx context.Context, submissionID uuid.UUID) (Submission, error) {
+	var submission Submission
+	err := r.db.GetContext(ctx, &submission, submissionSelect+" WHERE k.id = $1", submissionID)
+	return submission, err
+}
+
+func (r *repository) FindShopSubmissions(ctx context.Context, shopID uuid.UUID) ([]model.KYCSubmission, error) {
+	submissions := []model.KYCSubmission{}
+	query := "SELECT * FROM kyc_submissions WHERE shop_id = $1 ORDER BY created_at DESC, id"
+	err := r.db.SelectContext(ctx, &submissions, query, shopID)
+	return submissions, err
+}
+
+func (r *repository) TransactionReview(ctx context.Context, decision Decision, entry model.AdminLog, notice model.Notification) (model.KYCSubmission, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return model.KYCSubmission{}, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Putuskan pengajuan, hanya jika masih menunggu review
+	var submission model.KYCSubmission
+	query := `
+		UPDATE kyc_submissions SET
+			status = $2,
+			review_note = $3,
+			reviewed_by = $4,
+			reviewed_at = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'pending'
+		RETURNING *`
+	err = tx.GetContext(ctx, &submission, query, decision.SubmissionID, decision.To, decision.Note, decision.ReviewerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.KYCSubmission{}, ErrKYCChanged
+	}
+	if err != nil {
+		return model.KYCSubmission{}, uniqueViolation(err)
+	}
+
+	// Query 2: Salin status ke shop; waktu verifikasi hanya diisi saat disetujui
+	shopQuery := `
+		UPDATE shop SET
+			kyc_status = $2,
+			kyc_verified_at = CASE WHEN $2 = 'verified' THEN CURRENT_TIMESTAMP ELSE kyc_verified_at END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, shopQuery, decision.ShopID, decision.ShopStatus); err != nil {
+		return model.KYCSubmission{}, err
+	}
+
+	// Query 3: Keputusan dicatat di admin_logs dan diberitahukan ke seller
+	if err := audit.SaveAdminLog(ctx, tx, entry); err != nil {
+		return model.KYCSubmission{}, err
+	}
+	if err := notification.SaveNotification(ctx, tx, notice); err != nil {
+		return model.KYCSubmission{}, err
+	}
+
+	return submission, tx.Commit()
+}
+
+func (r *repository) SaveAdminLog(ctx context.Context, entry model.AdminLog) error {
+	return audit.SaveAdminLog(ctx, r.db, entry)
+}
+
+// --- Helper ---
+
+// namedGet menjalankan query bernama dengan RETURNING dan memindai satu baris ke dest.
+func namedGet(ctx context.Context, tx *sqlx.Tx, dest any, query string, arg any) error {
+	stmt, err := tx.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	return stmt.GetContext(ctx, dest, arg)
+}
+
+// uniqueViolation menerjemahkan pelanggaran index unik KTP yang sudah disetujui menjadi ErrKTPInUse.
+func uniqueViolation(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "idx_kyc_submissions_ktp" {
+		return ErrKTPInUse
+	}
+	return err
+}
@@ -0,0 +1,463 @@
+package kyc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/audit"
+	"vintage-server/pkg/apperror"
+	"vintage-server/pkg/secret"
+	"vintage-server/pkg/storage"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxDocumentSize adalah batas ukuran foto KTP / selfie
+	maxDocumentSize = 5 << 20
+
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// ktpNumberPattern: NIK pada KTP selalu 16 digit.
+var ktpNumberPattern = regexp.MustCompile(`^\d{16}$`)
+
+// documentExtensions adalah content type dokumen yang diterima beserta ekstensi file-nya.
+var documentExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/webp": ".webp",
+}
+
+// decision adalah hasil satu Action admin untuk pengajuan dan shop-nya.
+type decision struct {
+	submission     string
+	shop           string
+	reasonRequired bool
+	title          string
+}
+
+var decisions = map[Action]decision{
+	ActionApprove:     {submission: model.KYCStatusApproved, shop: model.ShopKYCVerified, title: "Your shop is verified"},
+	ActionReject:      {submission: model.KYCStatusRejected, shop: model.ShopKYCRejected, reasonRequired: true, title: "Your verification was rejected"},
+	ActionRequestInfo: {submission: model.KYCStatusNeedsInfo, shop: model.ShopKYCNeedsInfo, reasonRequired: true, title: "Your verification needs more information"},
+}
+
+// service adalah struct yang akan mengimplementasikan interface Service dari domain.go
+type service struct {
+	repo    Repository
+	storage storage.PrivateStorage
+	cipher  *secret.Cipher
+}
+
+// NewService adalah constructor untuk service. Dokumen disimpan di private storage dan nomor KTP
+// dienkripsi dengan cipher.
+func NewService(repo Repository, storage storage.PrivateStorage, cipher *secret.Cipher) Service {
+	return &service{repo: repo, storage: storage, cipher: cipher}
+}
+
+// --- Seller ---
+
+func (s *service) SubmitKYC(ctx context.Context, accountID uuid.UUID, req SubmitRequest, docs Documents) (SellerKYC, error) {
+	ktpNumber := strings.ReplaceAll(strings.TrimSpace(req.KTPNumber), " ", "")
+	if !ktpNumberPattern.MatchString(ktpNumber) {
+		return SellerKYC{}, apperror.New(apperror.ErrCodeValidation, "ktp_number must be 16 digits")
+	}
+	holder := strings.TrimSpace(req.BankAccountHolder)
+	if holder == "" {
+		return SellerKYC{}, apperror.New(apperror.ErrCodeValidation, "bank_account_holder is required")
+	}
+
+	shop, err := s.findOwnerShop(ctx, accountID)
+	if err != nil {
+		return SellerKYC{}, err
+	}
+	switch shop.KYCStatus {
+	case model.ShopKYCVerified:
+		return SellerKYC{}, apperror.New(apperror.ErrCodeConflict, "your shop is already verified")
+	case model.ShopKYCPending:
+		return SellerKYC{}, apperror.New(apperror.ErrCodeConflict, "your verification is already under review")
+	}
+
+	// Melengkapi pengajuan needs_info boleh memakai ulang dokumen sebelumnya
+	var previous model.KYCSubmission
+	if shop.KYCStatus == model.ShopKYCNeedsInfo {
+		if previous, err = s.repo.FindLatestSubmission(ctx, shop.ID); err != nil {
+			log.Printf("Error finding kyc submission: %v", err)
+			return SellerKYC{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+		}
+	} else if docs.KTPPhoto == nil || docs.Selfie == nil {
+		return SellerKYC{}, apperror.New(apperror.ErrCodeValidation, "ktp_photo and selfie are required")
+	}
+
+	hash := s.cipher.BlindIndex(ktpNumber)
+	inUse, err := s.repo.KTPNumberInUse(ctx, hash, shop.ID)
+	if err != nil {
+		log.Printf("Error checking ktp number: %v", err)
+		return SellerKYC{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if inUse {
+		return SellerKYC{}, apperror.New(apperror.ErrCodeConflict, "this KTP number is already verified for another shop")
+	}
+	encrypted, err := s.cipher.Encrypt([]byte(ktpNumber))
+	if err != nil {
+		log.Printf("Error encrypting ktp number: %v", err)
+		return SellerKYC{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	submission := model.KYCSubmission{
+		ShopID:             shop.ID,
+		SubmittedBy:        &accountID,
+		KTPNumberEncrypted: encrypted,
+		KTPNumberHash:      hash,
+		KTPLast4:           ktpNumber[len(ktpNumber)-4:],
+		KTPPhotoKey:        previous.KTPPhotoKey,
+		SelfieKey:          previous.SelfieKey,
+		BankAccountHolder:  holder,
+	}
+
+	// Dokumen baru dihapus lagi jika pengajuan gagal disimpan; dokumen yang digantikan dihapus
+	// setelah pengajuan tersimpan.
+	var stored, replaced []string
+	for _, doc := range []struct {
+		kind DocumentKind
+		file *multipart.FileHeader
+		key  *string
+	}{
+		{DocumentKTPPhoto, docs.KTPPhoto, &submission.KTPPhotoKey},
+		{DocumentSelfie, docs.Selfie, &submission.SelfieKey},
+	} {
+		if doc.file == nil {
+			continue
+		}
+		key, err := s.storeDocument(ctx, shop.ID, doc.kind, doc.file)
+		if err != nil {
+			s.deleteDocuments(ctx, stored)
+			return SellerKYC{}, err
+		}
+		stored = append(stored, key)
+		if *doc.key != "" {
+			replaced = append(replaced, *doc.key)
+		}
+		*doc.key = key
+	}
+
+	saved, err := s.repo.TransactionSubmit(ctx, submission, shop.KYCStatus)
+	if err != nil {
+		s.deleteDocuments(ctx, stored)
+		if errors.Is(err, ErrKYCChanged) {
+			return SellerKYC{}, apperror.New(apperror.ErrCodeConflict, "your verification status has changed, please reload")
+		}
+		log.Printf("Error saving kyc submission: %v", err)
+		return SellerKYC{}, apperror.New(apperror.ErrCodeInternal, "failed to save verification")
+	}
+	s.deleteDocuments(ctx, replaced)
+
+	return SellerKYC{Status: model.ShopKYCPending, VerifiedAt: shop.KYCVerifiedAt, Submission: &saved}, nil
+}
+
+func (s *service) GetMyKYC(ctx context.Context, accountID uuid.UUID) (SellerKYC, error) {
+	shop, err := s.findOwnerShop(ctx, accountID)
+	if err != nil {
+		return SellerKYC{}, err
+	}
+
+	result := SellerKYC{Status: shop.KYCStatus, VerifiedAt: shop.KYCVerifiedAt}
+	submission, err := s.repo.FindLatestSubmission(ctx, shop.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Error finding kyc submission: %v", err)
+		return SellerKYC{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if err == nil {
+		result.Submission = &submission
+	}
+	return result, nil
+}
+
+// --- Admin ---
+
+func (s *service) GetQueue(ctx context.Context, filter QueueFilter) (QueuePage, error) {
+	if filter.Status == "" {
+		filter.Status = model.KYCStatusPending
+	}
+	if !isSubmissionStatus(filter.Status) {
+		return QueuePage{}, apperror.New(apperror.ErrCodeValidation, "unknown verification status")
+	}
+	filter.Page, filter.Limit = normalizePage(filter.Page, filter.Limit)
+
+	items, total, err := s.repo.FindQueue(ctx, filter)
+	if err != nil {
+		log.Printf("Error finding kyc queue: %v", err)
+		return QueuePage{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return QueuePage{Items: items, Total: total, Page: filter.Page, Limit: filter.Limit}, nil
+}
+
+func (s *service) GetSubmission(ctx context.Context, submissionID uuid.UUID) (SubmissionDetail, error) {
+	submission, err := s.findSubmission(ctx, submissionID)
+	if err != nil {
+		return SubmissionDetail{}, err
+	}
+
+	history, err := s.repo.FindShopSubmissions(ctx, submission.ShopID)
+	if err != nil {
+		log.Printf("Error finding kyc submissions: %v", err)
+		return SubmissionDetail{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	// Pengajuan yang sedang dibuka tidak diulang di riwayat
+	previous := history[:0]
+	for _, h := range history {
+		if h.ID != submission.ID {
+			previous = append(previous, h)
+		}
+	}
+	return SubmissionDetail{Submission: submission, History: previous}, nil
+}
+
+func (s *service) Review(ctx context.Context, actor audit.Actor, submissionID uuid.UUID, action Action, req DecisionRequest) (model.KYCSubmission, error) {
+	outcome, ok := decisions[action]
+	if !ok {
+		return model.KYCSubmission{}, apperror.New(apperror.ErrCodeValidation, "unknown verification action")
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if outcome.reasonRequired && reason == "" {
+		return model.KYCSubmission{}, apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("a reason is required to %s a verification", strings.ReplaceAll(string(action), "_", " ")))
+	}
+
+	submission, err := s.findSubmission(ctx, submissionID)
+	if err != nil {
+		return model.KYCSubmission{}, err
+	}
+	if submission.Status != model.KYCStatusPending {
+		return model.KYCSubmission{}, apperror.New(apperror.ErrCodeConflict, fmt.Sprintf("cannot %s a verification that is %s", action, submission.Status))
+	}
+
+	var note *string
+	description := fmt.Sprintf("%s KYC submission %s for shop %s (%q)", action, submissionID, submission.ShopID, submission.ShopName)
+	if reason != "" {
+		note = &reason
+		description += ": " + reason
+	}
+	notice, err := buildDecisionNotification(submission, outcome, reason)
+	if err != nil {
+		log.Printf("Error building kyc notification: %v", err)
+		return model.KYCSubmission{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	updated, err := s.repo.TransactionReview(ctx, Decision{
+		SubmissionID: submissionID,
+		ShopID:       submission.ShopID,
+		To:           outcome.submission,
+		ShopStatus:   outcome.shop,
+		Note:         note,
+		ReviewerID:   actor.AdminID,
+	}, actor.Entry("kyc."+string(action), description), notice)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrKYCChanged):
+			return model.KYCSubmission{}, apperror.New(apperror.ErrCodeConflict, "verification has changed, please reload")
+		case errors.Is(err, ErrKTPInUse):
+			return model.KYCSubmission{}, apperror.New(apperror.ErrCodeConflict, "this KTP number is already verified for another shop")
+		}
+		log.Printf("Error reviewing kyc submission: %v", err)
+		return model.KYCSubmission{}, apperror.New(apperror.ErrCodeInternal, "failed to save verification")
+	}
+	return updated, nil
+}
+
+func (s *service) RevealKTPNumber(ctx context.Context, actor audit.Actor, submissionID uuid.UUID) (KTPNumberResponse, error) {
+	submission, err := s.findSubmission(ctx, submissionID)
+	if err != nil {
+		return KTPNumberResponse{}, err
+	}
+	ktpNumber, err := s.cipher.Decrypt(submission.KTPNumberEncrypted)
+	if err != nil {
+		log.Printf("Error decrypting ktp number of kyc submission %s: %v", submissionID, err)
+		return KTPNumberResponse{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	// Akses tanpa jejak audit tidak diizinkan
+	entry := actor.Entry("kyc.view_ktp_number", fmt.Sprintf("viewed KTP number of KYC submission %s for shop %s", submissionID, submission.ShopID))
+	if err := s.repo.SaveAdminLog(ctx, entry); err != nil {
+		log.Printf("Error saving admin log: %v", err)
+		return KTPNumberResponse{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return KTPNumberResponse{SubmissionID: submissionID, KTPNumber: string(ktpNumber)}, nil
+}
+
+func (s *service) OpenDocument(ctx context.Context, actor audit.Actor, submissionID uuid.UUID, kind DocumentKind) (Document, error) {
+	submission, err := s.findSubmission(ctx, submissionID)
+	if err != nil {
+		return Document{}, err
+	}
+	var key string
+	switch kind {
+	case DocumentKTPPhoto:
+		key = submission.KTPPhotoKey
+	case DocumentSelfie:
+		key = submission.SelfieKey
+	default:
+		return Document{}, apperror.New(apperror.ErrCodeValidation, "unknown document type")
+	}
+
+	// Akses tanpa jejak audit tidak diizinkan
+	entry := actor.Entry("kyc.view_document", fmt.Sprintf("viewed %s of KYC submission %s for shop %s", kind, submissionID, submission.ShopID))
+	if err := s.repo.SaveAdminLog(ctx, entry); err != nil {
+		log.Printf("Error saving admin log: %v", err)
+		return Document{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	content, err := s.storage.Open(ctx, key)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Document{}, apperror.New(apperror.ErrCodeNotFound, "document not found")
+		}
+		log.Printf("Error opening kyc document: %v", err)
+		return Document{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return Document{Content: content, ContentType: contentType}, nil
+}
+
+// --- Helper ---
+
+// findOwnerShop mengambil shop milik akun; verifikasi identitas hanya bisa diajukan owner.
+func (s *service) findOwnerShop(ctx context.Context, accountID uuid.UUID) (model.Shop, error) {
+	member, err := s.repo.FindShopMembership(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Shop{}, apperror.New(apperror.ErrCodeNotFound, "you do not have a shop yet")
+		}
+		log.Printf("Error finding shop membership: %v", err)
+		return model.Shop{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if member.Role != model.ShopRoleOwner {
+		return model.Shop{}, apperror.New(apperror.ErrCodeForbidden, "only the shop owner can manage identity verification")
+	}
+
+	shop, err := s.repo.FindShopByID(ctx, member.ShopID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Shop{}, apperror.New(apperror.ErrCodeNotFound, "shop not found")
+		}
+		log.Printf("Error finding shop: %v", err)
+		return model.Shop{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return shop, nil
+}
+
+func (s *service) findSubmission(ctx context.Context, submissionID uuid.UUID) (Submission, error) {
+	submission, err := s.repo.FindSubmission(ctx, submissionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Submission{}, apperror.New(apperror.ErrCodeNotFound, "verification not found")
+		}
+		log.Printf("Error finding kyc submission: %v", err)
+		return Submission{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return submission, nil
+}
+
+// storeDocument menyimpan foto KTP / selfie ke private storage dan mengembalikan key-nya.
+func (s *service) storeDocument(ctx context.Context, shopID uuid.UUID, kind DocumentKind, file *multipart.FileHeader) (string, error) {
+	if file.Size > maxDocumentSize {
+		return "", apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("%s must be at most %d MB", kind, maxDocumentSize>>20))
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return "", apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("failed to read %s file", kind))
+	}
+	defer src.Close()
+
+	// Tentukan tipe file dari isinya, bukan dari nama file / header yang dikirim client
+	head := make([]byte, 512)
+	n, _ := src.Read(head)
+	ext, ok := documentExtensions[http.DetectContentType(head[:n])]
+	if !ok {
+		return "", apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("%s must be a PNG, JPEG or WebP image", kind))
+	}
+	if _, err := src.Seek(0, 0); err != nil {
+		return "", apperror.New(apperror.ErrCodeInternal, fmt.Sprintf("failed to read %s file", kind))
+	}
+
+	key := path.Join("kyc", shopID.String(), fmt.Sprintf("%s-%s%s", kind, uuid.NewString(), ext))
+	if err := s.storage.Put(ctx, key, src); err != nil {
+		log.Printf("Error storing kyc %s: %v", kind, err)
+		return "", apperror.New(apperror.ErrCodeInternal, fmt.Sprintf("failed to store %s", kind))
+	}
+	return key, nil
+}
+
+func (s *service) deleteDocuments(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			log.Printf("Error deleting kyc document %s: %v", key, err)
+		}
+	}
+}
+
+// buildDecisionNotification memberi tahu owner shop keputusan admin atas pengajuannya.
+func buildDecisionNotification(submission Submission, outcome decision, reason string) (model.Notification, error) {
+	data, err := json.Marshal(map[string]any{
+		"shop_id":       submission.ShopID,
+		"submission_id": submission.ID,
+		"status":        outcome.shop,
+	})
+	if err != nil {
+		return model.Notification{}, err
+	}
+
+	var body string
+	switch outcome.shop {
+	case model.ShopKYCVerified:
+		body = fmt.Sprintf("%s has passed identity verification.", submission.ShopName)
+	case model.ShopKYCNeedsInfo:
+		body = "Please update your verification. Reason: " + reason
+	default:
+		body = "Your identity verification was rejected. You can submit a new one. Reason: " + reason
+	}
+	return model.Notification{
+		AccountID: submission.OwnerID,
+		Type:      model.NotificationTypeShop,
+		Title:     outcome.title,
+		Body:      body,
+		Data:      data,
+	}, nil
+}
+
+func isSubmissionStatus(status string) bool {
+	switch status {
+	case model.KYCStatusPending, model.KYCStatusNeedsInfo, model.KYCStatusApproved, model.KYCStatusRejected:
+		return true
+	}
+	return false
+}
+
+func normalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	return page, limit
+}
@@ -0,0 +1,53 @@
+package notification
+
+// File: internal/service/notification/domain.go
+
+import (
+	"context"
+	"time"
+	"vintage-server/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// =================================================================================
+// KONTRAK UNTUK SERVICE (Logika Bisnis) 🧠
+// =================================================================================
+type Service interface {
+	// --- Inbox ---
+	// Usecase: CustomerView Notifications
+	ListNotifications(ctx context.Context, accountID uuid.UUID, filter NotificationFilter) (NotificationPage, error)
+	// Usecase: CustomerMark Notifications As Read
+	MarkRead(ctx context.Context, accountID, notificationID uuid.UUID) error
+	MarkAllRead(ctx context.Context, accountID uuid.UUID) error
+
+	// --- Price Drop Alert ---
+	// SendPriceDropAlerts dipanggil job berkala: perubahan harga baru diantrikan per pembeli,
+	// lalu dikirim sebagai satu notifikasi per pembeli dengan batas cooldown.
+	SendPriceDropAlerts(ctx context.Context) (int, error)
+}
+
+// =================================================================================
+// KONTRAK UNTUK REPOSITORY (Akses Database) 🚚
+// =================================================================================
+// Repository mendefinisikan semua interaksi ke database yang dibutuhkan oleh Service.
+type Repository interface {
+	// --- Inbox ---
+	FindNotifications(ctx context.Context, accountID uuid.UUID, filter NotificationFilter) ([]model.Notification, error)
+	CountUnread(ctx context.Context, accountID uuid.UUID) (int64, error)
+	// MarkRead mengembalikan sql.ErrNoRows jika notifikasi bukan milik akun tersebut.
+	MarkRead(ctx context.Context, accountID, notificationID uuid.UUID) error
+	MarkAllRead(ctx context.Context, accountID uuid.UUID) error
+
+	// --- Price Drop Alert ---
+	// TransactionQueuePriceDrops membaca product_price_history sejak checkpoint terakhir dan
+	// mengantrikan produk yang turun >= thresholdPercent untuk setiap akun yang me-wishlist-nya.
+	// Mengembalikan jumlah baris riwayat yang diproses (maksimal batchSize).
+	TransactionQueuePriceDrops(ctx context.Context, thresholdPercent, batchSize int) (int, error)
+	// TransactionDispatchPriceAlerts mengambil antrian milik akun yang tidak menerima alert sejak
+	// cooldownSince, membuat satu notifikasi per akun lewat build, lalu mengosongkan antriannya.
+	TransactionDispatchPriceAlerts(ctx context.Context, thresholdPercent int, cooldownSince time.Time, maxAccounts int, build NotificationBuilder) (int, error)
+}
+
+// NotificationBuilder menyusun isi notifikasi alert untuk satu akun.
+type NotificationBuilder func(accountID uuid.UUID, items []PriceDropItem) (model.Notification, error)
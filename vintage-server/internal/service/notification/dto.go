@@ -0,0 +1,37 @@
+package notification
+
+import (
+	"time"
+	"vintage-server/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// PriceAlertConfig mengatur kapan penurunan harga dianggap layak dikirim ke pembeli.
+type PriceAlertConfig struct {
+	// ThresholdPercent: penurunan minimal (persen dari harga lama).
+	ThresholdPercent int
+	// Cooldown: jarak minimal antar notifikasi price drop untuk satu akun.
+	Cooldown time.Duration
+}
+
+type NotificationFilter struct {
+	UnreadOnly bool `form:"unread"`
+	Page       int  `form:"page"`
+	Limit      int  `form:"limit"`
+}
+
+type NotificationPage struct {
+	Items       []model.Notification `json:"items"`
+	UnreadCount int64                `json:"unread_count"`
+	Page        int                  `json:"page"`
+	Limit       int                  `json:"limit"`
+}
+
+// PriceDropItem adalah satu produk di dalam notifikasi price drop (disimpan di kolom data).
+type PriceDropItem struct {
+	ProductID uuid.UUID `json:"product_id" db:"product_id"`
+	Name      string    `json:"name" db:"name"`
+	OldPrice  int64     `json:"old_price" db:"old_price"`
+	NewPrice  int64     `json:"new_price" db:"new_price"`
+}
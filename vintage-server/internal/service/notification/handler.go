@@ -0,0 +1,68 @@
+package notification
+
+import (
+	"net/http"
+	"vintage-server/pkg/middleware"
+	"vintage-server/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Handler adalah struct yang memegang dependency ke Service
+type Handler struct {
+	svc Service
+}
+
+// NewHandler adalah constructor untuk handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// --- Inbox ---
+
+// ListNotifications mengembalikan notifikasi milik user yang login, terbaru di depan
+func (h *Handler) ListNotifications(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var filter NotificationFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	page, err := h.svc.ListNotifications(c.Request.Context(), accountID, filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, page)
+}
+
+// MarkRead menandai satu notifikasi sebagai sudah dibaca
+func (h *Handler) MarkRead(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	notificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid notification id")
+		return
+	}
+
+	if err := h.svc.MarkRead(c.Request.Context(), accountID, notificationID); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// MarkAllRead menandai semua notifikasi user sebagai sudah dibaca
+func (h *Handler) MarkAllRead(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	if err := h.svc.MarkAllRead(c.Request.Context(), accountID); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
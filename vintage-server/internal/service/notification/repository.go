@@ -0,0 +1,236 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"vintage-server/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// priceDropCheckpoint adalah nama baris di job_checkpoints untuk job alert price drop.
+const priceDropCheckpoint = "price_drop_alerts"
+
+// repository adalah struct yang mengimplementasikan kontrak Repository dari domain.go
+type repository struct {
+	db *sqlx.DB
+}
+
+// NewRepository adalah constructor untuk implementasi repository
+func NewRepository(db *sqlx.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// --- Inbox ---
+
+func (r *repository) FindNotifications(ctx context.Context, accountID uuid.UUID, filter NotificationFilter) ([]model.Notification, error) {
+	notifications := []model.Notification{}
+	query := `
+		SELECT * FROM notifications
+		WHERE account_id = $1 AND (NOT $2 OR read_at IS NULL)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4`
+	err := r.db.SelectContext(ctx, &notifications, query, accountID, filter.UnreadOnly, filter.Limit, (filter.Page-1)*filter.Limit)
+	return notifications, err
+}
+
+func (r *repository) CountUnread(ctx context.Context, accountID uuid.UUID) (int64, error) {
+	var count int64
+	query := "SELECT COUNT(*) FROM notifications WHERE account_id = $1 AND read_at IS NULL"
+	err := r.db.GetContext(ctx, &count, query, accountID)
+	return count, err
+}
+
+func (r *repository) MarkRead(ctx context.Context, accountID, notificationID uuid.UUID) error {
+	query := `
+		UPDATE notifications SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+		WHERE id = $1 AND account_id = $2`
+	result, err := r.db.ExecContext(ctx, query, notificationID, accountID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *repository) MarkAllRead(ctx context.Context, accountID uuid.UUID) error {
+	query := "UPDATE notifications SET read_at = CURRENT_TIMESTAMP WHERE account_id = $1 AND read_at IS NULL"
+	_, err := r.db.ExecContext(ctx, query, accountID)
+	return err
+}
+
+// --- Price Drop Alert ---
+
+func (r *repository) TransactionQueuePriceDrops(ctx context.Context, thresholdPercent, batchSize int) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Kunci checkpoint, sekaligus mencegah dua instance job jalan bersamaan
+	if err := lockCheckpoint(ctx, tx); err != nil {
+		return 0, err
+	}
+
+	// Query 2: Perubahan harga baru sejak checkpoint, urut per (xid, id). Baris dari transaksi
+	// yang belum selesai (xid >= xmin) ditunda, karena transaksi itu masih bisa commit id yang lebih
+	// kecil dari baris yang sudah terlihat.
+	var changes []model.ProductPriceHistory
+	historyQuery := `
+		SELECT h.id, h.product_id, h.old_price, h.new_price, h.changed_at
+		FROM product_price_history h
+		JOIN job_checkpoints jc ON jc.name = $1
+		WHERE (h.created_xid, h.id) > (jc.last_xid, jc.last_id)
+		  AND h.created_xid < pg_snapshot_xmin(pg_current_snapshot())
+		ORDER BY h.created_xid, h.id
+		LIMIT $2`
+	if err := tx.SelectContext(ctx, &changes, historyQuery, priceDropCheckpoint, batchSize); err != nil {
+		return 0, err
+	}
+	if len(changes) == 0 {
+		return 0, nil
+	}
+
+	// Beberapa repricing di batch yang sama digabung: harga lama = harga sebelum perubahan
+	// pertama, harga baru = hasil perubahan terakhir.
+	type priceChange struct{ oldPrice, newPrice int64 }
+	perProduct := make(map[uuid.UUID]*priceChange)
+	order := []uuid.UUID{}
+	for _, c := range changes {
+		if c.OldPrice == nil {
+			continue
+		}
+		pc, ok := perProduct[c.ProductID]
+		if !ok {
+			pc = &priceChange{oldPrice: *c.OldPrice}
+			perProduct[c.ProductID] = pc
+			order = append(order, c.ProductID)
+		}
+		pc.newPrice = c.NewPrice
+	}
+
+	// Query 3: Antrikan untuk setiap akun yang me-wishlist produk yang turun cukup banyak.
+	// Jika sudah ada di antrian, harga lama yang pertama tetap dipakai.
+	queueQuery := `
+		INSERT INTO pending_price_alerts (account_id, product_id, old_price, new_price)
+		SELECT w.account_id, w.product_id, $2, $3
+		FROM wishlist w
+		WHERE w.product_id = $1
+		ON CONFLICT (account_id, product_id) DO UPDATE SET new_price = EXCLUDED.new_price`
+	for _, productID := range order {
+		pc := perProduct[productID]
+		if !isSignificantDrop(pc.oldPrice, pc.newPrice, thresholdPercent) {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, queueQuery, productID, pc.oldPrice, pc.newPrice); err != nil {
+			return 0, err
+		}
+	}
+
+	// Query 4: Maju-kan checkpoint
+	updateCheckpoint := `
+		UPDATE job_checkpoints jc SET last_xid = h.created_xid, last_id = h.id, updated_at = CURRENT_TIMESTAMP
+		FROM product_price_history h
+		WHERE jc.name = $1 AND h.id = $2`
+	if _, err := tx.ExecContext(ctx, updateCheckpoint, priceDropCheckpoint, changes[len(changes)-1].ID); err != nil {
+		return 0, err
+	}
+
+	return len(changes), tx.Commit()
+}
+
+func (r *repository) TransactionDispatchPriceAlerts(ctx context.Context, thresholdPercent int, cooldownSince time.Time, maxAccounts int, build NotificationBuilder) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := lockCheckpoint(ctx, tx); err != nil {
+		return 0, err
+	}
+
+	// Query 1: Akun dengan antrian yang sudah lewat masa cooldown
+	var accountIDs []uuid.UUID
+	accountsQuery := `
+		SELECT DISTINCT pa.account_id
+		FROM pending_price_alerts pa
+		WHERE NOT EXISTS (
+			SELECT 1 FROM notifications n
+			WHERE n.account_id = pa.account_id AND n.type = $1 AND n.created_at > $2
+		)
+		LIMIT $3`
+	if err := tx.SelectContext(ctx, &accountIDs, accountsQuery, model.NotificationTypePriceDrop, cooldownSince, maxAccounts); err != nil {
+		return 0, err
+	}
+
+	// Query 2: Per akun, ambil produk yang masih di wishlist, masih tayang, dan harganya saat ini
+	// masih turun cukup jauh. Antrian dikosongkan baik dikirim maupun tidak.
+	itemsQuery := `
+		SELECT pa.product_id, p.name, pa.old_price, p.price AS new_price
+		FROM pending_price_alerts pa
+		JOIN products p ON p.id = pa.product_id
+		JOIN wishlist w ON w.account_id = pa.account_id AND w.product_id = pa.product_id
+		WHERE pa.account_id = $1
+			AND p.status = 'published' AND p.deleted_at IS NULL AND p.shop_id IN (SELECT id FROM shop WHERE active)
+			AND p.price * 100 <= pa.old_price * (100 - $2)
+		ORDER BY pa.old_price - p.price DESC`
+	sent := 0
+	for _, accountID := range accountIDs {
+		var items []PriceDropItem
+		if err := tx.SelectContext(ctx, &items, itemsQuery, accountID, thresholdPercent); err != nil {
+			return 0, err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM pending_price_alerts WHERE account_id = $1", accountID); err != nil {
+			return 0, err
+		}
+		if len(items) == 0 {
+			continue
+		}
+
+		notification, err := build(accountID, items)
+		if err != nil {
+			return 0, err
+		}
+		if err := SaveNotification(ctx, tx, notification); err != nil {
+			return 0, err
+		}
+		sent++
+	}
+
+	return sent, tx.Commit()
+}
+
+// SaveNotification menyimpan satu notifikasi ke inbox akun.
+// exec bisa berupa *sqlx.DB atau *sqlx.Tx, sehingga service lain bisa mengirim notifikasi
+// di transaksi yang sama dengan perubahan datanya.
+func SaveNotification(ctx context.Context, exec sqlx.ExtContext, notification model.Notification) error {
+	query := `
+		INSERT INTO notifications (account_id, type, title, body, data)
+		VALUES (:account_id, :type, :title, :body, :data)`
+	_, err := sqlx.NamedExecContext(ctx, exec, query, notification)
+	return err
+}
+
+// lockCheckpoint mengunci baris checkpoint job price drop supaya job queue & dispatch tidak jalan bersamaan.
+func lockCheckpoint(ctx context.Context, tx *sqlx.Tx) error {
+	var name string
+	query := "SELECT name FROM job_checkpoints WHERE name = $1 FOR UPDATE"
+	return tx.GetContext(ctx, &name, query, priceDropCheckpoint)
+}
+
+// isSignificantDrop mengecek penurunan harga minimal thresholdPercent (dihitung tanpa float).
+func isSignificantDrop(oldPrice, newPrice int64, thresholdPercent int) bool {
+	return newPrice < oldPrice && newPrice*100 <= oldPrice*int64(100-thresholdPercent)
+}
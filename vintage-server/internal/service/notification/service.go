@@ -0,0 +1,158 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/pkg/apperror"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+
+	// priceHistoryBatchSize adalah jumlah baris riwayat harga yang dibaca per putaran job.
+	priceHistoryBatchSize = 5000
+	// maxAlertAccountsPerRun membatasi jumlah notifikasi yang dibuat per putaran job.
+	maxAlertAccountsPerRun = 500
+)
+
+// service adalah struct yang akan mengimplementasikan interface Service dari domain.go
+type service struct {
+	repo        Repository
+	priceAlerts PriceAlertConfig
+}
+
+// NewService adalah constructor untuk service
+func NewService(repo Repository, priceAlerts PriceAlertConfig) Service {
+	return &service{
+		repo:        repo,
+		priceAlerts: priceAlerts,
+	}
+}
+
+// --- Inbox ---
+
+func (s *service) ListNotifications(ctx context.Context, accountID uuid.UUID, filter NotificationFilter) (NotificationPage, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = defaultPageLimit
+	}
+	if filter.Limit > maxPageLimit {
+		filter.Limit = maxPageLimit
+	}
+
+	items, err := s.repo.FindNotifications(ctx, accountID, filter)
+	if err != nil {
+		log.Printf("Error finding notifications: %v", err)
+		return NotificationPage{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	unread, err := s.repo.CountUnread(ctx, accountID)
+	if err != nil {
+		log.Printf("Error counting unread notifications: %v", err)
+		return NotificationPage{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	return NotificationPage{
+		Items:       items,
+		UnreadCount: unread,
+		Page:        filter.Page,
+		Limit:       filter.Limit,
+	}, nil
+}
+
+func (s *service) MarkRead(ctx context.Context, accountID, notificationID uuid.UUID) error {
+	if err := s.repo.MarkRead(ctx, accountID, notificationID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.New(apperror.ErrCodeNotFound, "notification not found")
+		}
+		log.Printf("Error marking notification as read: %v", err)
+		return apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return nil
+}
+
+func (s *service) MarkAllRead(ctx context.Context, accountID uuid.UUID) error {
+	if err := s.repo.MarkAllRead(ctx, accountID); err != nil {
+		log.Printf("Error marking notifications as read: %v", err)
+		return apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return nil
+}
+
+// --- Price Drop Alert ---
+
+func (s *service) SendPriceDropAlerts(ctx context.Context) (int, error) {
+	// Antrikan semua perubahan harga yang tertunda sebelum mengirim
+	for {
+		processed, err := s.repo.TransactionQueuePriceDrops(ctx, s.priceAlerts.ThresholdPercent, priceHistoryBatchSize)
+		if err != nil {
+			return 0, fmt.Errorf("queue price drops: %w", err)
+		}
+		if processed < priceHistoryBatchSize {
+			break
+		}
+	}
+
+	cooldownSince := time.Now().Add(-s.priceAlerts.Cooldown)
+	sent, err := s.repo.TransactionDispatchPriceAlerts(ctx, s.priceAlerts.ThresholdPercent, cooldownSince, maxAlertAccountsPerRun, buildPriceDropNotification)
+	if err != nil {
+		return 0, fmt.Errorf("dispatch price alerts: %w", err)
+	}
+	return sent, nil
+}
+
+// StartPriceAlertJob menjalankan SendPriceDropAlerts secara berkala sampai ctx dibatalkan.
+func StartPriceAlertJob(ctx context.Context, svc Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sent, err := svc.SendPriceDropAlerts(ctx)
+			if err != nil {
+				log.Printf("Error sending price drop alerts: %v", err)
+				continue
+			}
+			if sent > 0 {
+				log.Printf("Price alert job sent %d notification(s)", sent)
+			}
+		}
+	}
+}
+
+// buildPriceDropNotification menyusun satu notifikasi untuk semua produk yang turun harga.
+func buildPriceDropNotification(accountID uuid.UUID, items []PriceDropItem) (model.Notification, error) {
+	data, err := json.Marshal(map[string]any{"items": items})
+	if err != nil {
+		return model.Notification{}, err
+	}
+
+	top := items[0]
+	title := "A wishlist item dropped in price"
+	body := fmt.Sprintf("%s is now %d (was %d).", top.Name, top.NewPrice, top.OldPrice)
+	if len(items) > 1 {
+		title = fmt.Sprintf("%d wishlist items dropped in price", len(items))
+		body = fmt.Sprintf("%s is now %d (was %d), plus %d more.", top.Name, top.NewPrice, top.OldPrice, len(items)-1)
+	}
+
+	return model.Notification{
+		AccountID: accountID,
+		Type:      model.NotificationTypePriceDrop,
+		Title:     title,
+		Body:      body,
+		Data:      data,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package offer
+
+// File: internal/service/offer/domain.go
+
+import (
+	"context"
+	"time"
+	"vintage-server/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// =================================================================================
+// KONTRAK UNTUK SERVICE (Logika Bisnis) 🧠
+// =================================================================================
+type Service interface {
+	// Usecase: CustomerMake Offer (di bawah harga listing, dibatasi per pembeli per produk)
+	CreateOffer(ctx context.Context, buyerID uuid.UUID, req CreateOfferRequest) (model.Offer, error)
+	// Usecase: CustomerView My Offers
+	GetBuyerOffers(ctx context.Context, buyerID uuid.UUID, filter OfferFilter) (OfferPage, error)
+	// Usecase: SellerView Incoming Offers (semua anggota shop dengan akses listing)
+	GetSellerOffers(ctx context.Context, sellerID uuid.UUID, filter OfferFilter) (OfferPage, error)
+	// Usecase: Customer/Seller View Offer beserta riwayat negosiasinya
+	GetOffer(ctx context.Context, accountID, offerID uuid.UUID) (OfferDetail, error)
+	// Usecase: Customer/Seller Respond Offer (accept, decline, counter; pembeli juga bisa withdraw).
+	// Hanya pihak yang sedang ditunggu yang boleh menjawab; pihak lain dinotifikasi.
+	RespondOffer(ctx context.Context, accountID, offerID uuid.UUID, req RespondOfferRequest) (model.Offer, error)
+
+	// ExpireOffers dipanggil job berkala: offer yang tidak dijawab tepat waktu dan harga khusus
+	// yang tidak dipakai checkout dinyatakan kedaluwarsa.
+	ExpireOffers(ctx context.Context) (int, error)
+}
+
+// =================================================================================
+// KONTRAK UNTUK REPOSITORY (Akses Database) 🚚
+// =================================================================================
+// Repository mendefinisikan semua interaksi ke database yang dibutuhkan oleh Service.
+// Setiap perubahan offer menulis offer_events dan notifikasi di transaksi yang sama.
+type Repository interface {
+	// FindProduct hanya menemukan listing harga tetap yang sedang tayang.
+	FindProduct(ctx context.Context, productID uuid.UUID) (OfferProduct, error)
+	// CountBuyerOffers menghitung semua offer (status apa pun) pembeli untuk satu produk.
+	CountBuyerOffers(ctx context.Context, buyerID, productID uuid.UUID) (int, error)
+	// SaveOffer mengembalikan ErrOfferOpen jika pembeli masih punya offer berjalan untuk produk itu.
+	SaveOffer(ctx context.Context, offer model.Offer, event model.OfferEvent, notifications []model.Notification) (model.Offer, error)
+
+	FindOffer(ctx context.Context, offerID uuid.UUID) (OfferSummary, error)
+	FindOfferEvents(ctx context.Context, offerID uuid.UUID) ([]model.OfferEvent, error)
+	FindBuyerOffers(ctx context.Context, buyerID uuid.UUID, status string, limit, offset int) ([]OfferSummary, int64, error)
+	FindShopOffers(ctx context.Context, shopID uuid.UUID, status string, limit, offset int) ([]OfferSummary, int64, error)
+	// FindShopMembership mengambil shop tempat akun menjadi anggota beserta role-nya.
+	FindShopMembership(ctx context.Context, accountID uuid.UUID) (model.ShopMember, error)
+
+	// TransactionChangeOffer mengubah offer sesuai change, membuat hold harga khusus jika
+	// change.Hold diisi, lalu mencatat riwayat dan notifikasi. Mengembalikan ErrOfferChanged
+	// jika status di DB sudah bukan change.From.
+	TransactionChangeOffer(ctx context.Context, change OfferChange) (model.Offer, error)
+	// TransactionExpireOffers menutup offer berjalan yang lewat expires_at dan offer diterima yang
+	// hold-nya sudah selesai (dibeli atau dilepas). Mengembalikan jumlah offer yang ditutup.
+	TransactionExpireOffers(ctx context.Context, now time.Time, notify ClosedOfferNotifier) (int, error)
+}
+
+// ClosedOfferNotifier menyusun notifikasi untuk offer yang ditutup job (status baru: expired / purchased).
+type ClosedOfferNotifier func(offer OfferSummary, status string) ([]model.Notification, error)
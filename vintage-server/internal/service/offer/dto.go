@@ -0,0 +1,94 @@
+package offer
+
+import (
+	"errors"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/inventory"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrOfferOpen dikembalikan repository jika pembeli masih punya offer berjalan untuk produk yang sama.
+	ErrOfferOpen = errors.New("buyer already has an open offer on this product")
+	// ErrOfferChanged dikembalikan repository jika status offer berubah sejak dibaca service.
+	ErrOfferChanged = errors.New("offer status changed concurrently")
+)
+
+// OfferPolicy mengatur masa berlaku dan batas offer.
+type OfferPolicy struct {
+	// TTL: lama offer / counter menunggu jawaban sebelum kedaluwarsa.
+	TTL time.Duration
+	// AcceptedTTL: lama harga khusus di-hold untuk pembeli setelah offer diterima.
+	AcceptedTTL time.Duration
+	// MaxPerProduct: jumlah offer yang boleh dibuat satu pembeli untuk satu produk.
+	MaxPerProduct int
+}
+
+// OfferProduct adalah data listing yang dibutuhkan untuk memvalidasi offer baru.
+type OfferProduct struct {
+	ID       uuid.UUID `db:"id"`
+	ShopID   uuid.UUID `db:"shop_id"`
+	SellerID uuid.UUID `db:"seller_id"`
+	Name     string    `db:"name"`
+	Price    int64     `db:"price"`
+	// ShopOnVacation: seller sedang libur, offer baru ditolak
+	ShopOnVacation bool `db:"shop_on_vacation"`
+}
+
+type CreateOfferRequest struct {
+	ProductID uuid.UUID `json:"product_id" binding:"required"`
+	// Amount adalah harga per unit yang ditawarkan
+	Amount   int64  `json:"amount" binding:"required,min=1"`
+	Quantity int    `json:"quantity" binding:"omitempty,min=1"`
+	Message  string `json:"message" binding:"max=500"`
+}
+
+// RespondOfferRequest adalah jawaban atas offer. Amount wajib untuk counter.
+type RespondOfferRequest struct {
+	Action  string `json:"action" binding:"required,oneof=accept decline counter withdraw"`
+	Amount  int64  `json:"amount" binding:"omitempty,min=1"`
+	Message string `json:"message" binding:"max=500"`
+}
+
+type OfferFilter struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending countered accepted declined withdrawn expired purchased"`
+	Page   int    `form:"page"`
+	Limit  int    `form:"limit"`
+}
+
+// OfferSummary adalah offer beserta info listing-nya.
+type OfferSummary struct {
+	model.Offer
+	ProductName string    `json:"product_name" db:"product_name"`
+	ProductSlug string    `json:"product_slug" db:"product_slug"`
+	ListPrice   int64     `json:"list_price" db:"list_price"`
+	SellerID    uuid.UUID `json:"seller_id" db:"seller_id"`
+}
+
+type OfferPage struct {
+	Items []OfferSummary `json:"items"`
+	Total int64          `json:"total"`
+	Page  int            `json:"page"`
+	Limit int            `json:"limit"`
+}
+
+type OfferDetail struct {
+	Offer   OfferSummary       `json:"offer"`
+	History []model.OfferEvent `json:"history"`
+}
+
+// OfferChange adalah satu langkah negosiasi yang disimpan repository dalam satu transaksi.
+type OfferChange struct {
+	OfferID uuid.UUID
+	// From adalah status yang dibaca service; perubahan ditolak jika status di DB sudah berbeda
+	From      string
+	To        string
+	Amount    int64
+	ExpiresAt time.Time
+	// Hold diisi saat offer diterima: stok di-hold untuk pembeli dengan harga khusus
+	Hold          *inventory.HoldRequest
+	Event         model.OfferEvent
+	Notifications []model.Notification
+}
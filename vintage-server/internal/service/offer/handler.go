@@ -0,0 +1,116 @@
+package offer
+
+import (
+	"net/http"
+	"vintage-server/pkg/middleware"
+	"vintage-server/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Handler adalah struct yang memegang dependency ke Service
+type Handler struct {
+	svc Service
+}
+
+// NewHandler adalah constructor untuk handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// CreateOffer membuat offer baru untuk sebuah produk
+func (h *Handler) CreateOffer(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var req CreateOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	offer, err := h.svc.CreateOffer(c.Request.Context(), accountID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusCreated, offer)
+}
+
+// GetBuyerOffers mengembalikan offer yang dibuat customer yang sedang login
+func (h *Handler) GetBuyerOffers(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var filter OfferFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	offers, err := h.svc.GetBuyerOffers(c.Request.Context(), accountID, filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, offers)
+}
+
+// GetSellerOffers mengembalikan offer yang masuk ke toko seller yang sedang login
+func (h *Handler) GetSellerOffers(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var filter OfferFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	offers, err := h.svc.GetSellerOffers(c.Request.Context(), accountID, filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, offers)
+}
+
+// GetOffer mengembalikan detail offer beserta riwayat negosiasinya
+func (h *Handler) GetOffer(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	offerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid offer id")
+		return
+	}
+
+	offer, err := h.svc.GetOffer(c.Request.Context(), accountID, offerID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, offer)
+}
+
+// RespondOffer menerima, menolak, membalas (counter) atau menarik offer
+func (h *Handler) RespondOffer(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	offerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid offer id")
+		return
+	}
+
+	var req RespondOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	offer, err := h.svc.RespondOffer(c.Request.Context(), accountID, offerID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, offer)
+}
@@ -0,0 +1,248 @@
+package offer
+
+import (
+	"context"
+	"errors"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/inventory"
+	"vintage-server/internal/service/notification"
+	"vintage-server/internal/service/staff"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// summaryColumns & summaryJoins membentuk OfferSummary dari tabel offers (alias o).
+const summaryColumns = `
+	o.*, p.name AS product_name, p.slug AS product_slug, p.price AS list_price, s.account_id AS seller_id`
+
+const summaryJoins = `
+	JOIN products p ON p.id = o.product_id
+	JOIN shop s ON s.id = o.shop_id`
+
+// repository adalah struct yang mengimplementasikan kontrak Repository dari domain.go
+type repository struct {
+	db        *sqlx.DB
+	inventory inventory.Repository
+}
+
+// NewRepository adalah constructor untuk implementasi repository.
+// Hold harga khusus dibuat lewat inventory.Repository di dalam transaksi offer.
+func NewRepository(db *sqlx.DB, inventoryRepo inventory.Repository) Repository {
+	return &repository{
+		db:        db,
+		inventory: inventoryRepo,
+	}
+}
+
+func (r *repository) FindProduct(ctx context.Context, productID uuid.UUID) (OfferProduct, error) {
+	var product OfferProduct
+	query := `
+		SELECT p.id, p.shop_id, s.account_id AS seller_id, p.name, p.price, s.vacation_active AS shop_on_vacation
+		FROM products p
+		JOIN shop s ON s.id = p.shop_id
+		WHERE p.id = $1 AND p.status = 'published' AND p.listing_mode = 'fixed'
+		  AND p.deleted_at IS NULL AND s.deleted_at IS NULL AND s.active`
+	err := r.db.GetContext(ctx, &product, query, productID)
+	return product, err
+}
+
+func (r *repository) CountBuyerOffers(ctx context.Context, buyerID, productID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, "SELECT COUNT(*) FROM offers WHERE buyer_id = $1 AND product_id = $2", buyerID, productID)
+	return count, err
+}
+
+func (r *repository) SaveOffer(ctx context.Context, offer model.Offer, event model.OfferEvent, notifications []model.Notification) (model.Offer, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return model.Offer{}, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Simpan offer; unique index menjaga satu negosiasi berjalan per pembeli per produk
+	var saved model.Offer
+	query := `
+		INSERT INTO offers (product_id, shop_id, buyer_id, status, amount, quantity, expires_at, created_at, updated_at)
+		VALUES (:product_id, :shop_id, :buyer_id, :status, :amount, :quantity, :expires_at, :created_at, :updated_at)
+		RETURNING *`
+	bound, args, err := tx.BindNamed(query, offer)
+	if err != nil {
+		return model.Offer{}, err
+	}
+	if err := tx.GetContext(ctx, &saved, bound, args...); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return model.Offer{}, ErrOfferOpen
+		}
+		return model.Offer{}, err
+	}
+
+	// Query 2: Riwayat & notifikasi
+	event.OfferID = saved.ID
+	if err := saveEventAndNotify(ctx, tx, event, notifications); err != nil {
+		return model.Offer{}, err
+	}
+	return saved, tx.Commit()
+}
+
+func (r *repository) FindOffer(ctx context.Context, offerID uuid.UUID) (OfferSummary, error) {
+	var offer OfferSummary
+	query := `SELECT ` + summaryColumns + ` FROM offers o` + summaryJoins + ` WHERE o.id = $1`
+	err := r.db.GetContext(ctx, &offer, query, offerID)
+	return offer, err
+}
+
+func (r *repository) FindOfferEvents(ctx context.Context, offerID uuid.UUID) ([]model.OfferEvent, error) {
+	events := []model.OfferEvent{}
+	err := r.db.SelectContext(ctx, &events, "SELECT * FROM offer_events WHERE offer_id = $1 ORDER BY id", offerID)
+	return events, err
+}
+
+func (r *repository) FindBuyerOffers(ctx context.Context, buyerID uuid.UUID, status string, limit, offset int) ([]OfferSummary, int64, error) {
+	return r.findOffers(ctx, "o.buyer_id = $1", buyerID, status, limit, offset)
+}
+
+func (r *repository) FindShopOffers(ctx context.Context, shopID uuid.UUID, status string, limit, offset int) ([]OfferSummary, int64, error) {
+	return r.findOffers(ctx, "o.shop_id = $1", shopID, status, limit, offset)
+}
+
+func (r *repository) FindShopMembership(ctx context.Context, accountID uuid.UUID) (model.ShopMember, error) {
+	return staff.FindMembership(ctx, r.db, accountID)
+}
+
+// findOffers menjalankan daftar offer untuk satu pihak; owner adalah kondisi pemilik dengan parameter $1.
+func (r *repository) findOffers(ctx context.Context, owner string, ownerID uuid.UUID, status string, limit, offset int) ([]OfferSummary, int64, error) {
+	where := owner + " AND ($2 = '' OR o.status = $2)"
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM offers o` + summaryJoins + ` WHERE ` + where
+	if err := r.db.GetContext(ctx, &total, countQuery, ownerID, status); err != nil {
+		return nil, 0, err
+	}
+
+	offers := []OfferSummary{}
+	query := `
+		SELECT ` + summaryColumns + `
+		FROM offers o` + summaryJoins + `
+		WHERE ` + where + `
+		ORDER BY o.updated_at DESC, o.id
+		LIMIT $3 OFFSET $4`
+	err := r.db.SelectContext(ctx, &offers, query, ownerID, status, limit, offset)
+	return offers, total, err
+}
+
+func (r *repository) TransactionChangeOffer(ctx context.Context, change OfferChange) (model.Offer, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return model.Offer{}, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Kunci offer, hanya lanjut jika status masih sama dengan yang dibaca service
+	var status string
+	if err := tx.GetContext(ctx, &status, "SELECT status FROM offers WHERE id = $1 FOR UPDATE", change.OfferID); err != nil {
+		return model.Offer{}, err
+	}
+	if status != change.From {
+		return model.Offer{}, ErrOfferChanged
+	}
+
+	// Query 2: Offer diterima: stok di-hold untuk pembeli dengan harga yang disepakati
+	var holdID *uuid.UUID
+	if change.Hold != nil {
+		hold, err := r.inventory.Hold(ctx, tx, *change.Hold)
+		if err != nil {
+			return model.Offer{}, err
+		}
+		holdID = &hold.ID
+	}
+
+	// Query 3: Simpan langkah negosiasi
+	var updated model.Offer
+	query := `
+		UPDATE offers SET
+			status = $2, amount = $3, expires_at = $4,
+			hold_id = COALESCE($5, hold_id), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING *`
+	if err := tx.GetContext(ctx, &updated, query, change.OfferID, change.To, change.Amount, change.ExpiresAt, holdID); err != nil {
+		return model.Offer{}, err
+	}
+
+	// Query 4: Riwayat & notifikasi
+	event := change.Event
+	event.OfferID = change.OfferID
+	if err := saveEventAndNotify(ctx, tx, event, change.Notifications); err != nil {
+		return model.Offer{}, err
+	}
+	return updated, tx.Commit()
+}
+
+func (r *repository) TransactionExpireOffers(ctx context.Context, now time.Time, notify ClosedOfferNotifier) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Offer berjalan yang tidak dijawab, dan offer diterima yang hold-nya sudah selesai.
+	// Hold yang sudah dipakai order tetap ditunggu sampai pembayaran selesai atau order batal.
+	var closing []struct {
+		OfferSummary
+		HoldStatus *string `db:"hold_status"`
+	}
+	query := `
+		SELECT ` + summaryColumns + `, h.status AS hold_status
+		FROM offers o` + summaryJoins + `
+		LEFT JOIN inventory_holds h ON h.id = o.hold_id
+		WHERE (o.status IN ('pending', 'countered') AND o.expires_at <= $1)
+		   OR (o.status = 'accepted' AND (
+				h.id IS NULL OR h.status <> 'active' OR (h.order_id IS NULL AND h.expires_at <= $1)))
+		ORDER BY o.expires_at
+		LIMIT 500
+		FOR UPDATE OF o SKIP LOCKED`
+	if err := tx.SelectContext(ctx, &closing, query, now); err != nil {
+		return 0, err
+	}
+
+	for _, row := range closing {
+		status, action := model.OfferStatusExpired, model.OfferActionExpire
+		if row.HoldStatus != nil && *row.HoldStatus == model.HoldStatusConverted {
+			status, action = model.OfferStatusPurchased, model.OfferActionPurchase
+		}
+
+		// Query 2: Tutup offer, catat riwayat & kirim notifikasi
+		if _, err := tx.ExecContext(ctx, "UPDATE offers SET status = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1", row.ID, status); err != nil {
+			return 0, err
+		}
+		notifications, err := notify(row.OfferSummary, status)
+		if err != nil {
+			return 0, err
+		}
+		event := model.OfferEvent{OfferID: row.ID, Action: action, Amount: &row.Amount}
+		if err := saveEventAndNotify(ctx, tx, event, notifications); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(closing), tx.Commit()
+}
+
+// saveEventAndNotify menyimpan satu baris offer_events lalu notifikasi untuk pihak terkait.
+func saveEventAndNotify(ctx context.Context, tx *sqlx.Tx, event model.OfferEvent, notifications []model.Notification) error {
+	query := `
+		INSERT INTO offer_events (offer_id, action, amount, actor_id, note)
+		VALUES (:offer_id, :action, :amount, :actor_id, :note)`
+	if _, err := tx.NamedExecContext(ctx, query, event); err != nil {
+		return err
+	}
+	for _, n := range notifications {
+		if err := notification.SaveNotification(ctx, tx, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
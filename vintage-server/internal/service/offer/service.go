@@ -0,0 +1,393 @@
+package offer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/inventory"
+	"vintage-server/internal/service/staff"
+	"vintage-server/pkg/apperror"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+
+	// minOfferPercent adalah batas bawah offer terhadap harga listing, supaya seller tidak dibanjiri tawaran asal.
+	minOfferPercent = 50
+)
+
+// service adalah struct yang akan mengimplementasikan interface Service dari domain.go
+type service struct {
+	repo   Repository
+	policy OfferPolicy
+}
+
+// NewService adalah constructor untuk service
+func NewService(repo Repository, policy OfferPolicy) Service {
+	return &service{
+		repo:   repo,
+		policy: policy,
+	}
+}
+
+func (s *service) CreateOffer(ctx context.Context, buyerID uuid.UUID, req CreateOfferRequest) (model.Offer, error) {
+	product, err := s.repo.FindProduct(ctx, req.ProductID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Offer{}, apperror.New(apperror.ErrCodeNotFound, "product not found")
+		}
+		log.Printf("Error finding product for offer: %v", err)
+		return model.Offer{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	// Owner maupun staff shop tidak boleh menawar listing shop-nya sendiri
+	member, err := s.repo.FindShopMembership(ctx, buyerID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Error finding shop membership: %v", err)
+		return model.Offer{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if err == nil && member.ShopID == product.ShopID {
+		return model.Offer{}, apperror.New(apperror.ErrCodeForbidden, "cannot make an offer on your own listing")
+	}
+	if product.ShopOnVacation {
+		return model.Offer{}, apperror.New(apperror.ErrCodeConflict, "the shop is on vacation and not accepting offers")
+	}
+	if req.Amount >= product.Price {
+		return model.Offer{}, apperror.New(apperror.ErrCodeValidation, "offer must be below the listing price")
+	}
+	if req.Amount*100 < product.Price*minOfferPercent {
+		return model.Offer{}, apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("offer must be at least %d%% of the listing price", minOfferPercent))
+	}
+	if req.Quantity == 0 {
+		req.Quantity = 1
+	}
+
+	count, err := s.repo.CountBuyerOffers(ctx, buyerID, product.ID)
+	if err != nil {
+		log.Printf("Error counting buyer offers: %v", err)
+		return model.Offer{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if count >= s.policy.MaxPerProduct {
+		return model.Offer{}, apperror.New(apperror.ErrCodeConflict, "offer limit reached for this product")
+	}
+
+	now := time.Now()
+	offer := model.Offer{
+		ProductID: product.ID,
+		ShopID:    product.ShopID,
+		BuyerID:   buyerID,
+		Status:    model.OfferStatusPending,
+		Amount:    req.Amount,
+		Quantity:  req.Quantity,
+		ExpiresAt: now.Add(s.policy.TTL),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	event := model.OfferEvent{Action: model.OfferActionOffer, Amount: &req.Amount, ActorID: &buyerID, Note: optionalNote(req.Message)}
+
+	summary := OfferSummary{Offer: offer, ProductName: product.Name, ListPrice: product.Price, SellerID: product.SellerID}
+	n, err := buildOfferNotification(product.SellerID, summary, "New offer received",
+		fmt.Sprintf("You received an offer of %d for %s.", req.Amount, product.Name))
+	if err != nil {
+		log.Printf("Error building offer notification: %v", err)
+		return model.Offer{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	saved, err := s.repo.SaveOffer(ctx, offer, event, []model.Notification{n})
+	if err != nil {
+		if errors.Is(err, ErrOfferOpen) {
+			return model.Offer{}, apperror.New(apperror.ErrCodeConflict, "you already have an open offer on this product")
+		}
+		log.Printf("Error saving offer: %v", err)
+		return model.Offer{}, apperror.New(apperror.ErrCodeInternal, "failed to make offer")
+	}
+	return saved, nil
+}
+
+func (s *service) GetBuyerOffers(ctx context.Context, buyerID uuid.UUID, filter OfferFilter) (OfferPage, error) {
+	page, limit := normalizePage(filter.Page, filter.Limit)
+	offers, total, err := s.repo.FindBuyerOffers(ctx, buyerID, filter.Status, limit, (page-1)*limit)
+	if err != nil {
+		log.Printf("Error finding buyer offers: %v", err)
+		return OfferPage{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return OfferPage{Items: offers, Total: total, Page: page, Limit: limit}, nil
+}
+
+func (s *service) GetSellerOffers(ctx context.Context, sellerID uuid.UUID, filter OfferFilter) (OfferPage, error) {
+	member, err := s.repo.FindShopMembership(ctx, sellerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return OfferPage{}, apperror.New(apperror.ErrCodeForbidden, "you do not have a shop yet")
+		}
+		log.Printf("Error finding shop membership: %v", err)
+		return OfferPage{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if !staff.Can(member.Role, staff.PermissionListings) {
+		return OfferPage{}, apperror.New(apperror.ErrCodeForbidden, "your shop role does not allow managing offers")
+	}
+
+	page, limit := normalizePage(filter.Page, filter.Limit)
+	offers, total, err := s.repo.FindShopOffers(ctx, member.ShopID, filter.Status, limit, (page-1)*limit)
+	if err != nil {
+		log.Printf("Error finding seller offers: %v", err)
+		return OfferPage{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return OfferPage{Items: offers, Total: total, Page: page, Limit: limit}, nil
+}
+
+func (s *service) GetOffer(ctx context.Context, accountID, offerID uuid.UUID) (OfferDetail, error) {
+	offer, err := s.findOwnOffer(ctx, accountID, offerID)
+	if err != nil {
+		return OfferDetail{}, err
+	}
+
+	history, err := s.repo.FindOfferEvents(ctx, offerID)
+	if err != nil {
+		log.Printf("Error finding offer events: %v", err)
+		return OfferDetail{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return OfferDetail{Offer: offer, History: history}, nil
+}
+
+func (s *service) RespondOffer(ctx context.Context, accountID, offerID uuid.UUID, req RespondOfferRequest) (model.Offer, error) {
+	offer, err := s.findOwnOffer(ctx, accountID, offerID)
+	if err != nil {
+		return model.Offer{}, err
+	}
+
+	isBuyer := offer.BuyerID == accountID
+	if offer.Status != model.OfferStatusPending && offer.Status != model.OfferStatusCountered {
+		return model.Offer{}, apperror.New(apperror.ErrCodeConflict, "offer is no longer open")
+	}
+	now := time.Now()
+	if !offer.ExpiresAt.After(now) {
+		return model.Offer{}, apperror.New(apperror.ErrCodeConflict, "offer has expired")
+	}
+
+	// Pending menunggu seller, countered menunggu pembeli. Pembeli boleh withdraw kapan saja selama offer berjalan.
+	awaitingBuyer := offer.Status == model.OfferStatusCountered
+	if req.Action == model.OfferActionWithdraw {
+		if !isBuyer {
+			return model.Offer{}, apperror.New(apperror.ErrCodeForbidden, "only the buyer can withdraw an offer")
+		}
+	} else if isBuyer != awaitingBuyer {
+		return model.Offer{}, apperror.New(apperror.ErrCodeConflict, "offer is waiting for the other party")
+	}
+
+	counterpart := offer.BuyerID
+	if isBuyer {
+		counterpart = offer.SellerID
+	}
+
+	change := OfferChange{
+		OfferID:   offer.ID,
+		From:      offer.Status,
+		Amount:    offer.Amount,
+		ExpiresAt: offer.ExpiresAt,
+		Event:     model.OfferEvent{Action: req.Action, ActorID: &accountID, Note: optionalNote(req.Message)},
+	}
+	var title, body string
+	recipients := []uuid.UUID{counterpart}
+
+	switch req.Action {
+	case model.OfferActionCounter:
+		if req.Amount == 0 {
+			return model.Offer{}, apperror.New(apperror.ErrCodeValidation, "amount is required to counter")
+		}
+		if req.Amount >= offer.ListPrice {
+			return model.Offer{}, apperror.New(apperror.ErrCodeValidation, "counter must be below the listing price")
+		}
+		// Counter harus berada di antara posisi kedua pihak: seller di atas tawaran pembeli, pembeli di bawah counter seller.
+		if isBuyer && req.Amount >= offer.Amount {
+			return model.Offer{}, apperror.New(apperror.ErrCodeValidation, "counter must be below the seller's counter")
+		}
+		if !isBuyer && req.Amount <= offer.Amount {
+			return model.Offer{}, apperror.New(apperror.ErrCodeValidation, "counter must be above the buyer's offer")
+		}
+		change.To = model.OfferStatusCountered
+		if isBuyer {
+			change.To = model.OfferStatusPending
+		}
+		change.Amount = req.Amount
+		change.ExpiresAt = now.Add(s.policy.TTL)
+		title = "Offer countered"
+		body = fmt.Sprintf("A counter offer of %d was made for %s.", req.Amount, offer.ProductName)
+
+	case model.OfferActionAccept:
+		change.To = model.OfferStatusAccepted
+		change.ExpiresAt = now.Add(s.policy.AcceptedTTL)
+		unitPrice := offer.Amount
+		change.Hold = &inventory.HoldRequest{
+			ProductID: offer.ProductID,
+			AccountID: offer.BuyerID,
+			Quantity:  offer.Quantity,
+			ExpiresAt: change.ExpiresAt,
+			UnitPrice: &unitPrice,
+		}
+		title = "Offer accepted"
+		body = fmt.Sprintf("The offer of %d for %s was accepted. The buyer can check out at this price until %s.",
+			offer.Amount, offer.ProductName, change.ExpiresAt.Format(time.RFC3339))
+		recipients = []uuid.UUID{offer.BuyerID, offer.SellerID}
+
+	case model.OfferActionDecline:
+		change.To = model.OfferStatusDeclined
+		title = "Offer declined"
+		body = fmt.Sprintf("The offer of %d for %s was declined.", offer.Amount, offer.ProductName)
+
+	case model.OfferActionWithdraw:
+		change.To = model.OfferStatusWithdrawn
+		title = "Offer withdrawn"
+		body = fmt.Sprintf("The offer of %d for %s was withdrawn.", offer.Amount, offer.ProductName)
+	}
+	change.Event.Amount = &change.Amount
+
+	next := offer
+	next.Status, next.Amount, next.ExpiresAt = change.To, change.Amount, change.ExpiresAt
+	for _, recipient := range recipients {
+		n, err := buildOfferNotification(recipient, next, title, body)
+		if err != nil {
+			log.Printf("Error building offer notification: %v", err)
+			return model.Offer{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+		}
+		change.Notifications = append(change.Notifications, n)
+	}
+
+	updated, err := s.repo.TransactionChangeOffer(ctx, change)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrOfferChanged):
+			return model.Offer{}, apperror.New(apperror.ErrCodeConflict, "offer was updated by the other party, please refresh")
+		case errors.Is(err, inventory.ErrInsufficientStock):
+			return model.Offer{}, apperror.New(apperror.ErrCodeConflict, "not enough stock available to accept this offer")
+		case errors.Is(err, inventory.ErrProductUnavailable):
+			return model.Offer{}, apperror.New(apperror.ErrCodeConflict, "product is no longer available")
+		}
+		log.Printf("Error changing offer: %v", err)
+		return model.Offer{}, apperror.New(apperror.ErrCodeInternal, "failed to respond to offer")
+	}
+	return updated, nil
+}
+
+func (s *service) ExpireOffers(ctx context.Context) (int, error) {
+	return s.repo.TransactionExpireOffers(ctx, time.Now(), notifyClosedOffer)
+}
+
+// findOwnOffer mengembalikan offer jika accountID adalah pembeli atau anggota shop-nya yang boleh
+// mengelola listing. Offer milik orang lain dilaporkan sebagai not found.
+func (s *service) findOwnOffer(ctx context.Context, accountID, offerID uuid.UUID) (OfferSummary, error) {
+	offer, err := s.repo.FindOffer(ctx, offerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return OfferSummary{}, apperror.New(apperror.ErrCodeNotFound, "offer not found")
+		}
+		log.Printf("Error finding offer: %v", err)
+		return OfferSummary{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if offer.BuyerID == accountID {
+		return offer, nil
+	}
+
+	member, err := s.repo.FindShopMembership(ctx, accountID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Error finding shop membership: %v", err)
+		return OfferSummary{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if err != nil || member.ShopID != offer.ShopID || !staff.Can(member.Role, staff.PermissionListings) {
+		return OfferSummary{}, apperror.New(apperror.ErrCodeNotFound, "offer not found")
+	}
+	return offer, nil
+}
+
+// notifyClosedOffer memberi tahu kedua pihak bahwa offer ditutup oleh job.
+func notifyClosedOffer(offer OfferSummary, status string) ([]model.Notification, error) {
+	title := "Offer expired"
+	body := fmt.Sprintf("The offer of %d for %s has expired.", offer.Amount, offer.ProductName)
+	if status == model.OfferStatusPurchased {
+		title = "Offer completed"
+		body = fmt.Sprintf("%s was purchased at the offer price of %d.", offer.ProductName, offer.Amount)
+	}
+
+	offer.Status = status
+	var notifications []model.Notification
+	for _, recipient := range []uuid.UUID{offer.BuyerID, offer.SellerID} {
+		n, err := buildOfferNotification(recipient, offer, title, body)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, nil
+}
+
+// buildOfferNotification menyusun notifikasi offer untuk satu penerima.
+func buildOfferNotification(accountID uuid.UUID, offer OfferSummary, title, body string) (model.Notification, error) {
+	data, err := json.Marshal(map[string]any{
+		"offer_id":   offer.ID,
+		"product_id": offer.ProductID,
+		"status":     offer.Status,
+		"amount":     offer.Amount,
+	})
+	if err != nil {
+		return model.Notification{}, err
+	}
+
+	return model.Notification{
+		AccountID: accountID,
+		Type:      model.NotificationTypeOffer,
+		Title:     title,
+		Body:      body,
+		Data:      data,
+	}, nil
+}
+
+// optionalNote mengubah pesan kosong menjadi NULL di riwayat.
+func optionalNote(message string) *string {
+	if message == "" {
+		return nil
+	}
+	return &message
+}
+
+// normalizePage memberi nilai default dan batas atas untuk pagination.
+func normalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	return page, limit
+}
+
+// StartExpiryJob menjalankan ExpireOffers secara berkala sampai ctx dibatalkan.
+func StartExpiryJob(ctx context.Context, svc Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			closed, err := svc.ExpireOffers(ctx)
+			if err != nil {
+				log.Printf("Error expiring offers: %v", err)
+				continue
+			}
+			if closed > 0 {
+				log.Printf("Offer expiry job closed %d offer(s)", closed)
+			}
+		}
+	}
+}
@@ -1 +1,97 @@
 package order
+
+// File: internal/service/order/domain.go
+
+import (
+	"context"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/shipment"
+
+	"github.com/google/uuid"
+)
+
+// =================================================================================
+// KONTRAK UNTUK SERVICE (Logika Bisnis) 🧠
+// =================================================================================
+type Service interface {
+	// --- Cart ---
+	// Usecase: CustomerManage Cart (stok dicek terhadap stok tersedia, bukan products.stock)
+	GetCart(ctx context.Context, accountID uuid.UUID) (CartResponse, error)
+	AddCartItem(ctx context.Context, accountID uuid.UUID, req AddCartItemRequest) (CartResponse, error)
+	RemoveCartItem(ctx context.Context, accountID, productID uuid.UUID) (CartResponse, error)
+	// Usecase: CustomerChoose Shipping (ongkir setiap layanan kurir shop di cart ke alamat pembeli)
+	GetShippingOptions(ctx context.Context, accountID uuid.UUID, filter ShippingOptionsFilter) ([]ShopShippingOptions, error)
+
+	// --- Checkout & Order ---
+	// Usecase: CustomerCheckout (isi cart di-hold selama TTL sampai pembayaran masuk; satu shipment per shop)
+	Checkout(ctx context.Context, accountID uuid.UUID, req CheckoutRequest) (CheckoutResponse, error)
+	// Usecase: CustomerCancel Order (hanya order yang belum dibayar)
+	CancelOrder(ctx context.Context, accountID, orderID uuid.UUID) error
+	// Usecase: CustomerView Order History (produk yang sudah dihapus tetap ditampilkan)
+	GetOrders(ctx context.Context, accountID uuid.UUID, filter OrderFilter) (OrderPage, error)
+	GetOrder(ctx context.Context, accountID, orderID uuid.UUID) (OrderDetail, error)
+	// Usecase: CustomerChoose Shipping untuk order pemenang lelang (dibuat tanpa shipment dan ongkir);
+	// ongkir ditambahkan ke total order sebelum dibayar.
+	GetOrderShippingOptions(ctx context.Context, accountID, orderID uuid.UUID, filter ShippingOptionsFilter) ([]ShopShippingOptions, error)
+	ChooseOrderShipping(ctx context.Context, accountID, orderID uuid.UUID, req ChooseShippingRequest) (OrderDetail, error)
+
+	// --- Seller ---
+	// Usecase: SellerView Shop Orders (anggota shop dengan akses fulfillment; hanya item milik shop yang terlihat)
+	GetShopOrders(ctx context.Context, accountID uuid.UUID, filter ShopOrderFilter) (ShopOrderPage, error)
+	GetShopOrder(ctx context.Context, accountID, orderID uuid.UUID) (ShopOrderDetail, error)
+
+	// ExpireStaleCheckouts dipanggil sweeper: hold kedaluwarsa dilepas dan order-nya dibatalkan.
+	ExpireStaleCheckouts(ctx context.Context) (int, error)
+}
+
+// =================================================================================
+// KONTRAK UNTUK REPOSITORY (Akses Database) 🚚
+// =================================================================================
+// Repository mendefinisikan semua interaksi ke database yang dibutuhkan oleh Service.
+// Semua perubahan stok didelegasikan ke inventory.Repository.
+type Repository interface {
+	// --- Cart ---
+	FindCartItems(ctx context.Context, accountID uuid.UUID) ([]CartItemDetail, error)
+	// UpsertCartItem membuat cart jika belum ada lalu menyimpan quantity item.
+	UpsertCartItem(ctx context.Context, accountID, productID uuid.UUID, quantity int) error
+	DeleteCartItem(ctx context.Context, accountID, productID uuid.UUID) error
+	// AvailableStock menghitung stok tersedia untuk accountID (hold offer miliknya tidak mengurangi).
+	AvailableStock(ctx context.Context, accountID, productID uuid.UUID) (int, error)
+	// FindCartShippingSettings mengambil pengaturan pengiriman setiap shop yang produknya ada di cart.
+	FindCartShippingSettings(ctx context.Context, accountID uuid.UUID) ([]shipment.Settings, error)
+	// FindAddressLocation mengambil wilayah alamat milik accountID (sql.ErrNoRows jika bukan miliknya).
+	FindAddressLocation(ctx context.Context, accountID uuid.UUID, addressID int64) (shipment.Location, error)
+
+	// --- Order ---
+	// TransactionCheckout membuat order + order_items + hold + payment pending dari isi cart,
+	// lalu mengosongkan cart, semuanya dalam satu transaksi. quotes berisi shipment hasil quote
+	// untuk setiap shop di cart (dihitung sebelum transaksi); ongkir ikut dijumlahkan ke total order.
+	TransactionCheckout(ctx context.Context, accountID uuid.UUID, holdExpiresAt time.Time, quotes map[uuid.UUID]model.Shipment) (CheckoutResponse, error)
+	// FindOrderShippingSettings mengambil pengaturan pengiriman setiap shop di order milik accountID.
+	FindOrderShippingSettings(ctx context.Context, accountID, orderID uuid.UUID) ([]shipment.Settings, error)
+	// TransactionChooseShipping menyimpan shipment hasil quote untuk order pending yang belum punya
+	// shipment, lalu menambahkan ongkirnya ke shipping_cost & total_price order.
+	// Mengembalikan ErrOrderNotPending atau ErrShippingAlreadyChosen.
+	TransactionChooseShipping(ctx context.Context, accountID, orderID uuid.UUID, quotes map[uuid.UUID]model.Shipment) (model.Order, []model.Shipment, error)
+	// TransactionCancelOrder membatalkan order pending milik account dan melepas hold-nya.
+	TransactionCancelOrder(ctx context.Context, accountID, orderID uuid.UUID) error
+	// TransactionExpireHolds menandai hold kedaluwarsa lalu membatalkan order pending terkait.
+	TransactionExpireHolds(ctx context.Context, now time.Time) (int, error)
+
+	// --- Order History ---
+	FindOrders(ctx context.Context, accountID uuid.UUID, limit, offset int) ([]model.Order, int64, error)
+	FindOrder(ctx context.Context, accountID, orderID uuid.UUID) (model.Order, error)
+	// FindOrderItems sengaja ikut membaca produk yang sudah di-soft-delete.
+	FindOrderItems(ctx context.Context, orderID uuid.UUID) ([]OrderItemDetail, error)
+	FindOrderShipments(ctx context.Context, orderID uuid.UUID) ([]model.Shipment, error)
+
+	// --- Seller ---
+	// FindShopMembership mengambil shop tempat akun menjadi anggota beserta role-nya.
+	FindShopMembership(ctx context.Context, accountID uuid.UUID) (model.ShopMember, error)
+	// FindShopOrders mengembalikan order yang memuat produk shopID; status 0 berarti semua status.
+	FindShopOrders(ctx context.Context, shopID uuid.UUID, status int16, limit, offset int) ([]ShopOrder, int64, error)
+	FindShopOrder(ctx context.Context, shopID, orderID uuid.UUID) (ShopOrder, error)
+	// FindShopOrderItems hanya mengembalikan item order milik shopID.
+	FindShopOrderItems(ctx context.Context, shopID, orderID uuid.UUID) ([]OrderItemDetail, error)
+}
@@ -0,0 +1,157 @@
+package order
+
+import (
+	"errors"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/shipment"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrEmptyCart dikembalikan repository saat checkout dengan cart kosong.
+	ErrEmptyCart = errors.New("cart is empty")
+	// ErrOrderNotCancellable dikembalikan jika order sudah dibayar / dibatalkan.
+	ErrOrderNotCancellable = errors.New("order can no longer be cancelled")
+	// ErrShopOnVacation dikembalikan saat checkout jika ada item dari shop yang sedang libur.
+	ErrShopOnVacation = errors.New("shop is on vacation")
+	// ErrShippingChoiceMissing dikembalikan saat checkout jika pembeli tidak memilih pengiriman untuk sebuah shop.
+	ErrShippingChoiceMissing = errors.New("choose a shipping option for this shop")
+	// ErrOfferQuantityExceedsCart dikembalikan saat checkout jika quantity offer yang diterima lebih
+	// banyak dari quantity produk itu di cart; pembeli harus menaikkan quantity di cart.
+	ErrOfferQuantityExceedsCart = errors.New("accepted offer quantity is larger than the cart quantity")
+	// ErrOrderNotPending dikembalikan jika order sudah dibayar / dibatalkan.
+	ErrOrderNotPending = errors.New("order is no longer waiting for payment")
+	// ErrShippingAlreadyChosen dikembalikan jika order sudah punya shipment (misal hasil checkout cart).
+	ErrShippingAlreadyChosen = errors.New("shipping has already been chosen for this order")
+)
+
+// CartItemDetail adalah item cart beserta detail produk dan stok tersedianya.
+type CartItemDetail struct {
+	ProductID    uuid.UUID `json:"product_id" db:"product_id"`
+	ProductName  string    `json:"product_name" db:"product_name"`
+	ProductPrice int64     `json:"product_price" db:"product_price"`
+	// ProductStatus dipakai client untuk menandai item yang sudah terjual / tidak tayang
+	ProductStatus   string  `json:"product_status" db:"product_status"`
+	ProductImageURL *string `json:"product_image_url" db:"product_image_url"`
+	Quantity        int     `json:"quantity" db:"quantity"`
+	AvailableStock  int     `json:"available_stock" db:"available_stock"`
+	// ShopOnVacation: item tetap di cart tapi tidak bisa di-checkout sampai seller kembali
+	ShopOnVacation bool `json:"shop_on_vacation" db:"shop_on_vacation"`
+}
+
+type CartResponse struct {
+	Items      []CartItemDetail `json:"items"`
+	TotalPrice int64            `json:"total_price"`
+}
+
+type AddCartItemRequest struct {
+	ProductID uuid.UUID `json:"product_id" binding:"required"`
+	Quantity  int       `json:"quantity" binding:"required,min=1"`
+}
+
+// CheckoutRequest memuat pilihan pengiriman untuk setiap shop di cart. AddressID wajib kecuali
+// semua shop dipilih ambil sendiri.
+type CheckoutRequest struct {
+	AddressID *int64           `json:"address_id"`
+	Shipping  []ShippingChoice `json:"shipping" binding:"required,min=1,dive"`
+}
+
+// ShippingChoice: Courier & Service mengikuti kurir yang diaktifkan shop, lihat GET /cart/shipping-options.
+type ShippingChoice struct {
+	ShopID     uuid.UUID `json:"shop_id" binding:"required"`
+	Courier    string    `json:"courier" binding:"required_without=SelfPickup"`
+	Service    string    `json:"service" binding:"required_without=SelfPickup"`
+	SelfPickup bool      `json:"self_pickup"`
+}
+
+type CheckoutResponse struct {
+	Order         model.Order       `json:"order"`
+	Items         []model.OrderItem `json:"items"`
+	Shipments     []model.Shipment  `json:"shipments"`
+	HoldExpiresAt time.Time         `json:"hold_expires_at"`
+}
+
+type ShippingOptionsFilter struct {
+	AddressID int64 `form:"address_id" binding:"required"`
+}
+
+// ChooseShippingRequest memilih pengiriman untuk order yang dibuat tanpa checkout cart (pemenang
+// lelang). Aturannya sama dengan CheckoutRequest: AddressID wajib kecuali semua shop diambil sendiri.
+type ChooseShippingRequest struct {
+	AddressID *int64           `json:"address_id"`
+	Shipping  []ShippingChoice `json:"shipping" binding:"required,min=1,dive"`
+}
+
+// ShopShippingOptions adalah pilihan pengiriman satu shop di cart / order ke alamat pembeli.
+// Options kosong jika shop belum mengisi alamat asal; shop seperti ini belum bisa di-checkout.
+type ShopShippingOptions struct {
+	ShopID       uuid.UUID         `json:"shop_id"`
+	ShopName     string            `json:"shop_name"`
+	OriginCity   *string           `json:"origin_city"`
+	HandlingDays *int16            `json:"handling_days"`
+	SelfPickup   bool              `json:"self_pickup"`
+	Options      []shipment.Option `json:"options"`
+}
+
+type OrderFilter struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+type OrderPage struct {
+	Items []model.Order `json:"items"`
+	Total int64         `json:"total"`
+	Page  int           `json:"page"`
+	Limit int           `json:"limit"`
+}
+
+// OrderItemDetail adalah item order beserta snapshot produk saat ini.
+// Produk yang sudah dihapus seller / admin tetap dikembalikan dengan ProductDeleted = true.
+type OrderItemDetail struct {
+	model.OrderItem
+	ProductName     string  `json:"product_name" db:"product_name"`
+	ProductSlug     string  `json:"product_slug" db:"product_slug"`
+	ProductImageURL *string `json:"product_image_url" db:"product_image_url"`
+	ProductDeleted  bool    `json:"product_deleted" db:"product_deleted"`
+}
+
+type OrderDetail struct {
+	Order     model.Order       `json:"order"`
+	Items     []OrderItemDetail `json:"items"`
+	Shipments []model.Shipment  `json:"shipments"`
+}
+
+// ShopOrderFilter: Status 0 berarti semua status, lihat model.OrderStatus*.
+type ShopOrderFilter struct {
+	Status int16 `form:"status" binding:"min=0,max=5"`
+	Page   int   `form:"page"`
+	Limit  int   `form:"limit"`
+}
+
+// ShopOrder adalah order dilihat dari satu shop: total dan jumlah item hanya menghitung produk shop itu.
+type ShopOrder struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	BuyerID   uuid.UUID  `json:"buyer_id" db:"account_id"`
+	Status    int16      `json:"status" db:"status"`
+	ShipBy    *time.Time `json:"ship_by" db:"ship_by"`
+	ShopTotal int64      `json:"shop_total" db:"shop_total"`
+	ItemCount int        `json:"item_count" db:"item_count"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+type ShopOrderPage struct {
+	Items []ShopOrder `json:"items"`
+	Total int64       `json:"total"`
+	Page  int         `json:"page"`
+	Limit int         `json:"limit"`
+}
+
+type ShopOrderDetail struct {
+	Order ShopOrder         `json:"order"`
+	Items []OrderItemDetail `json:"items"`
+	// Shipment nil selama pemenang lelang belum memilih pengiriman (POST /orders/:id/shipping)
+	Shipment *model.Shipment `json:"shipment"`
+}
@@ -1 +1,243 @@
 package order
+
+import (
+	"net/http"
+	"vintage-server/pkg/middleware"
+	"vintage-server/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Handler adalah struct yang memegang dependency ke Service
+type Handler struct {
+	svc Service
+}
+
+// NewHandler adalah constructor untuk handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// --- Cart ---
+
+// GetCart mengembalikan isi cart customer yang sedang login
+func (h *Handler) GetCart(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	cart, err := h.svc.GetCart(c.Request.Context(), accountID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, cart)
+}
+
+// AddCartItem menambah / mengubah quantity produk di cart
+func (h *Handler) AddCartItem(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var req AddCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	cart, err := h.svc.AddCartItem(c.Request.Context(), accountID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, cart)
+}
+
+// RemoveCartItem menghapus produk dari cart
+func (h *Handler) RemoveCartItem(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	productID, err := uuid.Parse(c.Param("product_id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid product id")
+		return
+	}
+
+	cart, err := h.svc.RemoveCartItem(c.Request.Context(), accountID, productID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, cart)
+}
+
+// GetShippingOptions mengembalikan pilihan kurir & ongkir setiap shop di cart ke alamat address_id
+func (h *Handler) GetShippingOptions(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var filter ShippingOptionsFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	options, err := h.svc.GetShippingOptions(c.Request.Context(), accountID, filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, options)
+}
+
+// --- Checkout & Order ---
+
+// Checkout membuat order dari isi cart beserta shipment per shop, lalu meng-hold stoknya
+func (h *Handler) Checkout(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var req CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.svc.Checkout(c.Request.Context(), accountID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusCreated, result)
+}
+
+// GetOrderShippingOptions mengembalikan ongkir setiap layanan kurir untuk order pemenang lelang
+func (h *Handler) GetOrderShippingOptions(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid order id")
+		return
+	}
+	var filter ShippingOptionsFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	options, err := h.svc.GetOrderShippingOptions(c.Request.Context(), accountID, orderID, filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, options)
+}
+
+// ChooseOrderShipping menyimpan pilihan pengiriman order pemenang lelang dan menambahkan ongkirnya
+func (h *Handler) ChooseOrderShipping(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid order id")
+		return
+	}
+	var req ChooseShippingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	detail, err := h.svc.ChooseOrderShipping(c.Request.Context(), accountID, orderID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, detail)
+}
+
+// CancelOrder membatalkan order yang belum dibayar dan melepas hold stoknya
+func (h *Handler) CancelOrder(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid order id")
+		return
+	}
+
+	if err := h.svc.CancelOrder(c.Request.Context(), accountID, orderID); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetOrders mengembalikan riwayat order customer yang sedang login
+func (h *Handler) GetOrders(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var filter OrderFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	orders, err := h.svc.GetOrders(c.Request.Context(), accountID, filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, orders)
+}
+
+// GetOrder mengembalikan detail satu order beserta item-nya
+func (h *Handler) GetOrder(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid order id")
+		return
+	}
+
+	order, err := h.svc.GetOrder(c.Request.Context(), accountID, orderID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, order)
+}
+
+// GetShopOrders adalah handler seller untuk daftar order yang memuat produk shop-nya (?status=2 untuk yang menunggu dikirim)
+func (h *Handler) GetShopOrders(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var filter ShopOrderFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	orders, err := h.svc.GetShopOrders(c.Request.Context(), accountID, filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, orders)
+}
+
+// GetShopOrder mengembalikan detail order untuk seller, hanya berisi item milik shop-nya
+func (h *Handler) GetShopOrder(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid order id")
+		return
+	}
+
+	order, err := h.svc.GetShopOrder(c.Request.Context(), accountID, orderID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, order)
+}
@@ -1 +1,608 @@
 package order
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/inventory"
+	"vintage-server/internal/service/shipment"
+	"vintage-server/internal/service/staff"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// repository adalah struct yang mengimplementasikan kontrak Repository dari domain.go
+type repository struct {
+	db        *sqlx.DB
+	inventory inventory.Repository
+	shipment  shipment.Repository
+}
+
+// NewRepository adalah constructor untuk implementasi repository
+func NewRepository(db *sqlx.DB, inv inventory.Repository, ship shipment.Repository) Repository {
+	return &repository{
+		db:        db,
+		inventory: inv,
+		shipment:  ship,
+	}
+}
+
+// --- Cart ---
+
+func (r *repository) FindCartItems(ctx context.Context, accountID uuid.UUID) ([]CartItemDetail, error) {
+	items := []CartItemDetail{}
+	query := `
+		SELECT
+			ci.product_id,
+			p.name AS product_name,
+			p.price AS product_price,
+			p.status AS product_status,
+			pi.url AS product_image_url,
+			ci.quantity,
+			p.stock - COALESCE((
+				SELECT SUM(h.quantity) FROM inventory_holds h
+				WHERE h.product_id = p.id AND h.status = 'active' AND h.expires_at > CURRENT_TIMESTAMP
+				  AND NOT (h.account_id = c.account_id AND h.order_id IS NULL AND h.unit_price IS NOT NULL)
+			), 0) AS available_stock,
+			s.vacation_active AS shop_on_vacation
+		FROM cart c
+		JOIN cart_items ci ON ci.cart_id = c.id
+		JOIN products p ON p.id = ci.product_id AND p.deleted_at IS NULL
+		JOIN shop s ON s.id = p.shop_id
+		LEFT JOIN product_images pi ON p.id = pi.product_id AND pi.image_index = 0
+		WHERE c.account_id = $1
+		ORDER BY ci.created_at ASC`
+	err := r.db.SelectContext(ctx, &items, query, accountID)
+	return items, err
+}
+
+func (r *repository) UpsertCartItem(ctx context.Context, accountID, productID uuid.UUID, quantity int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Pastikan cart milik account sudah ada
+	var cartID uuid.UUID
+	queryCart := `
+		INSERT INTO cart (account_id) VALUES ($1)
+		ON CONFLICT (account_id) DO UPDATE SET updated_at = CURRENT_TIMESTAMP
+		RETURNING id`
+	if err := tx.GetContext(ctx, &cartID, queryCart, accountID); err != nil {
+		return err
+	}
+
+	// Query 2: Simpan quantity item (menimpa quantity lama)
+	queryItem := `
+		INSERT INTO cart_items (cart_id, product_id, quantity) VALUES ($1, $2, $3)
+		ON CONFLICT (cart_id, product_id) DO UPDATE SET quantity = EXCLUDED.quantity, updated_at = CURRENT_TIMESTAMP`
+	if _, err := tx.ExecContext(ctx, queryItem, cartID, productID, quantity); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *repository) DeleteCartItem(ctx context.Context, accountID, productID uuid.UUID) error {
+	query := `
+		DELETE FROM cart_items ci USING cart c
+		WHERE ci.cart_id = c.id AND c.account_id = $1 AND ci.product_id = $2`
+	_, err := r.db.ExecContext(ctx, query, accountID, productID)
+	return err
+}
+
+func (r *repository) AvailableStock(ctx context.Context, accountID, productID uuid.UUID) (int, error) {
+	return r.inventory.AvailableStockFor(ctx, r.db, productID, accountID)
+}
+
+func (r *repository) FindCartShippingSettings(ctx context.Context, accountID uuid.UUID) ([]shipment.Settings, error) {
+	var shopIDs []uuid.UUID
+	query := `
+		SELECT p.shop_id
+		FROM cart c
+		JOIN cart_items ci ON ci.cart_id = c.id
+		JOIN products p ON p.id = ci.product_id AND p.deleted_at IS NULL
+		WHERE c.account_id = $1
+		GROUP BY p.shop_id
+		ORDER BY MIN(ci.created_at)`
+	if err := r.db.SelectContext(ctx, &shopIDs, query, accountID); err != nil {
+		return nil, err
+	}
+
+	settings, err := r.shipment.FindSettings(ctx, r.db, shopIDs)
+	if err != nil {
+		return nil, err
+	}
+	// Urutan mengikuti urutan shop di cart
+	result := make([]shipment.Settings, 0, len(shopIDs))
+	for _, shopID := range shopIDs {
+		if entry, ok := settings[shopID]; ok {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+func (r *repository) FindOrderShippingSettings(ctx context.Context, accountID, orderID uuid.UUID) ([]shipment.Settings, error) {
+	var shopIDs []uuid.UUID
+	query := `
+		SELECT DISTINCT p.shop_id
+		FROM orders o
+		JOIN order_items oi ON oi.order_id = o.id
+		JOIN products p ON p.id = oi.product_id
+		WHERE o.id = $1 AND o.account_id = $2`
+	if err := r.db.SelectContext(ctx, &shopIDs, query, orderID, accountID); err != nil {
+		return nil, err
+	}
+
+	settings, err := r.shipment.FindSettings(ctx, r.db, shopIDs)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]shipment.Settings, 0, len(shopIDs))
+	for _, shopID := range shopIDs {
+		if entry, ok := settings[shopID]; ok {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+func (r *repository) FindAddressLocation(ctx context.Context, accountID uuid.UUID, addressID int64) (shipment.Location, error) {
+	return r.shipment.FindAddressLocation(ctx, r.db, accountID, addressID)
+}
+
+// --- Order ---
+
+// checkoutItem adalah satu baris order yang akan dibuat; HoldID diisi jika memakai hold offer.
+type checkoutItem struct {
+	ProductID uuid.UUID
+	Price     int64
+	Quantity  int
+	HoldID    *uuid.UUID
+}
+
+func (r *repository) TransactionCheckout(ctx context.Context, accountID uuid.UUID, holdExpiresAt time.Time, quotes map[uuid.UUID]model.Shipment) (CheckoutResponse, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return CheckoutResponse{}, err
+	}
+	defer tx.Rollback()
+
+	// 1. Ambil isi cart beserta harga saat ini. Urut per produk supaya urutan lock konsisten.
+	var lines []struct {
+		ProductID      uuid.UUID `db:"product_id"`
+		ShopID         uuid.UUID `db:"shop_id"`
+		Price          int64     `db:"price"`
+		Quantity       int       `db:"quantity"`
+		ListingMode    string    `db:"listing_mode"`
+		ShopOnVacation bool      `db:"shop_on_vacation"`
+	}
+	queryLines := `
+		SELECT ci.product_id, p.shop_id, p.price, ci.quantity, p.listing_mode, s.vacation_active AS shop_on_vacation
+		FROM cart c
+		JOIN cart_items ci ON ci.cart_id = c.id
+		JOIN products p ON p.id = ci.product_id AND p.deleted_at IS NULL
+		JOIN shop s ON s.id = p.shop_id
+		WHERE c.account_id = $1
+		ORDER BY ci.product_id`
+	if err := tx.SelectContext(ctx, &lines, queryLines, accountID); err != nil {
+		return CheckoutResponse{}, err
+	}
+	if len(lines) == 0 {
+		return CheckoutResponse{}, ErrEmptyCart
+	}
+
+	// 2. Offer yang sudah diterima memakai hold harga khusus pembeli; sisa quantity di cart
+	// (jika lebih banyak dari quantity offer) dibeli dengan harga listing. Hold offer tidak bisa
+	// dipakai sebagian, jadi cart yang lebih sedikit dari quantity offer ditolak.
+	// Produk yang sedang dilelang hanya bisa dibeli lewat lelang, produk shop yang libur ditunda.
+	var items []checkoutItem
+	for _, line := range lines {
+		if line.ListingMode != model.ListingModeFixed {
+			return CheckoutResponse{}, fmt.Errorf("product %s: %w", line.ProductID, inventory.ErrProductUnavailable)
+		}
+		if line.ShopOnVacation {
+			return CheckoutResponse{}, fmt.Errorf("product %s: %w", line.ProductID, ErrShopOnVacation)
+		}
+		quantity := line.Quantity
+		offerHold, err := r.inventory.FindOfferHold(ctx, tx, accountID, line.ProductID)
+		if err != nil && err != sql.ErrNoRows {
+			return CheckoutResponse{}, err
+		}
+		if err == nil {
+			if offerHold.Quantity > quantity {
+				return CheckoutResponse{}, fmt.Errorf("product %s: offer is for %d, cart has %d: %w", line.ProductID, offerHold.Quantity, quantity, ErrOfferQuantityExceedsCart)
+			}
+			items = append(items, checkoutItem{
+				ProductID: line.ProductID,
+				Price:     *offerHold.UnitPrice,
+				Quantity:  offerHold.Quantity,
+				HoldID:    &offerHold.ID,
+			})
+			quantity -= offerHold.Quantity
+		}
+		if quantity > 0 {
+			items = append(items, checkoutItem{ProductID: line.ProductID, Price: line.Price, Quantity: quantity})
+		}
+	}
+
+	// 3. Satu shipment per shop dari hasil quote; shop yang tidak ikut di-quote berarti cart
+	// berubah sejak ongkir dihitung
+	var shipments []model.Shipment
+	var shippingCost int64
+	seen := make(map[uuid.UUID]bool)
+	for _, line := range lines {
+		if seen[line.ShopID] {
+			continue
+		}
+		seen[line.ShopID] = true
+		quoted, ok := quotes[line.ShopID]
+		if !ok {
+			return CheckoutResponse{}, fmt.Errorf("shop %s: %w", line.ShopID, ErrShippingChoiceMissing)
+		}
+		shipments = append(shipments, quoted)
+		shippingCost += quoted.ShippingCost
+	}
+
+	// 4. Buat order menunggu pembayaran beserta item dengan harga saat checkout
+	lineItems := make([]model.OrderItem, 0, len(items))
+	for _, item := range items {
+		lineItems = append(lineItems, model.OrderItem{ProductID: item.ProductID, Quantity: item.Quantity, PriceAtPurchase: item.Price})
+	}
+	order, orderItems, err := CreatePendingOrder(ctx, tx, accountID, lineItems, shippingCost, "checkout", &accountID)
+	if err != nil {
+		return CheckoutResponse{}, err
+	}
+	savedShipments := make([]model.Shipment, 0, len(shipments))
+	for _, quoted := range shipments {
+		quoted.OrderID = order.ID
+		saved, err := r.shipment.SaveShipment(ctx, tx, quoted)
+		if err != nil {
+			return CheckoutResponse{}, err
+		}
+		savedShipments = append(savedShipments, saved)
+	}
+
+	// 5. Hold stok (atau pakai hold offer) untuk setiap item
+	for _, line := range items {
+		if line.HoldID != nil {
+			err = r.inventory.AttachHold(ctx, tx, *line.HoldID, order.ID, holdExpiresAt)
+		} else {
+			_, err = r.inventory.Hold(ctx, tx, inventory.HoldRequest{
+				ProductID: line.ProductID,
+				AccountID: accountID,
+				OrderID:   &order.ID,
+				Quantity:  line.Quantity,
+				ExpiresAt: holdExpiresAt,
+			})
+		}
+		if err != nil {
+			return CheckoutResponse{}, fmt.Errorf("product %s: %w", line.ProductID, err)
+		}
+	}
+
+	// 6. Kosongkan cart
+	queryClear := "DELETE FROM cart_items ci USING cart c WHERE ci.cart_id = c.id AND c.account_id = $1"
+	if _, err := tx.ExecContext(ctx, queryClear, accountID); err != nil {
+		return CheckoutResponse{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return CheckoutResponse{}, err
+	}
+	return CheckoutResponse{Order: order, Items: orderItems, Shipments: savedShipments, HoldExpiresAt: holdExpiresAt}, nil
+}
+
+func (r *repository) TransactionChooseShipping(ctx context.Context, accountID, orderID uuid.UUID, quotes map[uuid.UUID]model.Shipment) (model.Order, []model.Shipment, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return model.Order{}, nil, err
+	}
+	defer tx.Rollback()
+
+	// 1. Kunci order supaya tidak balapan dengan pembayaran / sweeper hold
+	var status int16
+	queryLock := "SELECT status FROM orders WHERE id = $1 AND account_id = $2 FOR UPDATE"
+	if err := tx.GetContext(ctx, &status, queryLock, orderID, accountID); err != nil {
+		return model.Order{}, nil, err
+	}
+	if status != model.OrderStatusPendingPayment {
+		return model.Order{}, nil, ErrOrderNotPending
+	}
+	existing, err := r.shipment.FindOrderShipments(ctx, tx, orderID)
+	if err != nil {
+		return model.Order{}, nil, err
+	}
+	if len(existing) > 0 {
+		return model.Order{}, nil, ErrShippingAlreadyChosen
+	}
+
+	// 2. Setiap shop di order wajib punya shipment hasil quote
+	var shopIDs []uuid.UUID
+	queryShops := `
+		SELECT DISTINCT p.shop_id FROM order_items oi JOIN products p ON p.id = oi.product_id
+		WHERE oi.order_id = $1
+		ORDER BY p.shop_id`
+	if err := tx.SelectContext(ctx, &shopIDs, queryShops, orderID); err != nil {
+		return model.Order{}, nil, err
+	}
+	var shippingCost int64
+	saved := make([]model.Shipment, 0, len(shopIDs))
+	for _, shopID := range shopIDs {
+		quoted, ok := quotes[shopID]
+		if !ok {
+			return model.Order{}, nil, fmt.Errorf("shop %s: %w", shopID, ErrShippingChoiceMissing)
+		}
+		quoted.OrderID = orderID
+		shipment, err := r.shipment.SaveShipment(ctx, tx, quoted)
+		if err != nil {
+			return model.Order{}, nil, err
+		}
+		saved = append(saved, shipment)
+		shippingCost += quoted.ShippingCost
+	}
+
+	// 3. Ongkir masuk ke total yang harus dibayar
+	var order model.Order
+	queryOrder := `
+		UPDATE orders SET shipping_cost = shipping_cost + $2, total_price = total_price + $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING *`
+	if err := tx.GetContext(ctx, &order, queryOrder, orderID, shippingCost); err != nil {
+		return model.Order{}, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Order{}, nil, err
+	}
+	return order, saved, nil
+}
+
+func (r *repository) TransactionCancelOrder(ctx context.Context, accountID, orderID uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var status int16
+	queryLock := "SELECT status FROM orders WHERE id = $1 AND account_id = $2 FOR UPDATE"
+	if err := tx.GetContext(ctx, &status, queryLock, orderID, accountID); err != nil {
+		return err
+	}
+	if status != model.OrderStatusPendingPayment {
+		return ErrOrderNotCancellable
+	}
+
+	if err := r.cancelPendingOrder(ctx, tx, orderID, status, "cancelled by buyer", &accountID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *repository) TransactionExpireHolds(ctx context.Context, now time.Time) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// 1. Kunci dulu order pending yang punya hold kedaluwarsa. SKIP LOCKED: order yang sedang
+	// diproses payment dilewati, dan karena hold-nya belum disentuh, order itu tetap terpilih
+	// di putaran berikutnya jika pembayarannya gagal.
+	var orderIDs []uuid.UUID
+	queryLock := `
+		SELECT o.id FROM orders o
+		WHERE o.status = $2
+		  AND EXISTS (
+			SELECT 1 FROM inventory_holds h
+			WHERE h.order_id = o.id AND h.status = 'active' AND h.expires_at <= $1
+		  )
+		ORDER BY o.id
+		FOR UPDATE SKIP LOCKED`
+	if err := tx.SelectContext(ctx, &orderIDs, queryLock, now, model.OrderStatusPendingPayment); err != nil {
+		return 0, err
+	}
+
+	// 2. Hanya hold milik order yang berhasil dikunci yang ditandai kedaluwarsa, lalu ordernya dibatalkan
+	for _, orderID := range orderIDs {
+		if err := r.inventory.ExpireOrderHolds(ctx, tx, orderID, now); err != nil {
+			return 0, err
+		}
+		if err := r.cancelPendingOrder(ctx, tx, orderID, model.OrderStatusPendingPayment, "checkout hold expired", nil); err != nil {
+			return 0, err
+		}
+	}
+
+	// 3. Hold offer yang tidak pernah dipakai checkout
+	if _, err := r.inventory.ExpireHolds(ctx, tx, now); err != nil {
+		return 0, err
+	}
+
+	return len(orderIDs), tx.Commit()
+}
+
+// --- Order History ---
+
+func (r *repository) FindOrders(ctx context.Context, accountID uuid.UUID, limit, offset int) ([]model.Order, int64, error) {
+	var total int64
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM orders WHERE account_id = $1", accountID); err != nil {
+		return nil, 0, err
+	}
+
+	orders := []model.Order{}
+	query := `
+		SELECT * FROM orders
+		WHERE account_id = $1
+		ORDER BY created_at DESC, id
+		LIMIT $2 OFFSET $3`
+	err := r.db.SelectContext(ctx, &orders, query, accountID, limit, offset)
+	return orders, total, err
+}
+
+func (r *repository) FindOrder(ctx context.Context, accountID, orderID uuid.UUID) (model.Order, error) {
+	var order model.Order
+	err := r.db.GetContext(ctx, &order, "SELECT * FROM orders WHERE id = $1 AND account_id = $2", orderID, accountID)
+	return order, err
+}
+
+func (r *repository) FindOrderItems(ctx context.Context, orderID uuid.UUID) ([]OrderItemDetail, error) {
+	items := []OrderItemDetail{}
+	// Tanpa filter deleted_at: riwayat order harus tetap utuh walaupun listing sudah dihapus.
+	query := `
+		SELECT
+			oi.*,
+			p.name AS product_name,
+			p.slug AS product_slug,
+			pi.url AS product_image_url,
+			p.deleted_at IS NOT NULL AS product_deleted
+		FROM order_items oi
+		JOIN products p ON p.id = oi.product_id
+		LEFT JOIN product_images pi ON pi.product_id = p.id AND pi.image_index = 0
+		WHERE oi.order_id = $1
+		ORDER BY oi.id`
+	err := r.db.SelectContext(ctx, &items, query, orderID)
+	return items, err
+}
+
+func (r *repository) FindOrderShipments(ctx context.Context, orderID uuid.UUID) ([]model.Shipment, error) {
+	return r.shipment.FindOrderShipments(ctx, r.db, orderID)
+}
+
+// --- Seller ---
+
+// shopOrderSelect membentuk ShopOrder dari orders (alias o) dan item milik shop $1.
+const shopOrderSelect = `
+	SELECT
+		o.id, o.account_id, o.status, o.ship_by, o.created_at, o.updated_at,
+		SUM(oi.price_at_purchase * oi.quantity) AS shop_total,
+		SUM(oi.quantity) AS item_count
+	FROM orders o
+	JOIN order_items oi ON oi.order_id = o.id
+	JOIN products p ON p.id = oi.product_id
+	WHERE p.shop_id = $1`
+
+func (r *repository) FindShopMembership(ctx context.Context, accountID uuid.UUID) (model.ShopMember, error) {
+	return staff.FindMembership(ctx, r.db, accountID)
+}
+
+func (r *repository) FindShopOrders(ctx context.Context, shopID uuid.UUID, status int16, limit, offset int) ([]ShopOrder, int64, error) {
+	var total int64
+	countQuery := `
+		SELECT COUNT(DISTINCT o.id)
+		FROM orders o
+		JOIN order_items oi ON oi.order_id = o.id
+		JOIN products p ON p.id = oi.product_id
+		WHERE p.shop_id = $1 AND ($2 = 0 OR o.status = $2)`
+	if err := r.db.GetContext(ctx, &total, countQuery, shopID, status); err != nil {
+		return nil, 0, err
+	}
+
+	orders := []ShopOrder{}
+	query := shopOrderSelect + ` AND ($2 = 0 OR o.status = $2)
+		GROUP BY o.id
+		ORDER BY o.created_at DESC, o.id
+		LIMIT $3 OFFSET $4`
+	err := r.db.SelectContext(ctx, &orders, query, shopID, status, limit, offset)
+	return orders, total, err
+}
+
+func (r *repository) FindShopOrder(ctx context.Context, shopID, orderID uuid.UUID) (ShopOrder, error) {
+	var order ShopOrder
+	query := shopOrderSelect + " AND o.id = $2 GROUP BY o.id"
+	err := r.db.GetContext(ctx, &order, query, shopID, orderID)
+	return order, err
+}
+
+func (r *repository) FindShopOrderItems(ctx context.Context, shopID, orderID uuid.UUID) ([]OrderItemDetail, error) {
+	items := []OrderItemDetail{}
+	query := `
+		SELECT
+			oi.*,
+			p.name AS product_name,
+			p.slug AS product_slug,
+			pi.url AS product_image_url,
+			p.deleted_at IS NOT NULL AS product_deleted
+		FROM order_items oi
+		JOIN products p ON p.id = oi.product_id
+		LEFT JOIN product_images pi ON pi.product_id = p.id AND pi.image_index = 0
+		WHERE oi.order_id = $1 AND p.shop_id = $2
+		ORDER BY oi.id`
+	err := r.db.SelectContext(ctx, &items, query, orderID, shopID)
+	return items, err
+}
+
+// cancelPendingOrder melepas hold, membatalkan order & payment-nya, lalu mencatat status log.
+// Pemanggil wajib sudah mengunci baris order.
+func (r *repository) cancelPendingOrder(ctx context.Context, tx *sqlx.Tx, orderID uuid.UUID, oldStatus int16, note string, by *uuid.UUID) error {
+	if err := r.inventory.ReleaseOrderHolds(ctx, tx, orderID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE orders SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2", model.OrderStatusCancelled, orderID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE payments SET payment_status = 'cancel', updated_at = CURRENT_TIMESTAMP WHERE order_id = $1 AND payment_status = 'pending'", orderID); err != nil {
+		return err
+	}
+	return insertStatusLog(ctx, tx, orderID, &oldStatus, model.OrderStatusCancelled, note, by)
+}
+
+// CreatePendingOrder menyimpan order menunggu pembayaran beserta order_items, log status awal dan
+// baris payment pending di transaksi pemanggil. Hold stok dan shipment menjadi tanggung jawab pemanggil;
+// shippingCost ikut dijumlahkan ke total_price. Dipakai checkout cart dan konversi pemenang lelang.
+func CreatePendingOrder(ctx context.Context, tx *sqlx.Tx, accountID uuid.UUID, items []model.OrderItem, shippingCost int64, note string, by *uuid.UUID) (model.Order, []model.OrderItem, error) {
+	total := shippingCost
+	for _, item := range items {
+		total += item.PriceAtPurchase * int64(item.Quantity)
+	}
+
+	// Query 1: Order dengan status menunggu pembayaran
+	var order model.Order
+	queryOrder := `
+		INSERT INTO orders (account_id, total_price, shipping_cost, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING *`
+	if err := tx.GetContext(ctx, &order, queryOrder, accountID, total, shippingCost, model.OrderStatusPendingPayment); err != nil {
+		return model.Order{}, nil, err
+	}
+
+	// Query 2: Item order
+	saved := make([]model.OrderItem, 0, len(items))
+	queryItem := `
+		INSERT INTO order_items (order_id, product_id, quantity, price_at_purchase)
+		VALUES ($1, $2, $3, $4)
+		RETURNING *`
+	for _, item := range items {
+		var row model.OrderItem
+		if err := tx.GetContext(ctx, &row, queryItem, order.ID, item.ProductID, item.Quantity, item.PriceAtPurchase); err != nil {
+			return model.Order{}, nil, err
+		}
+		saved = append(saved, row)
+	}
+
+	// Query 3: Status awal & baris payment
+	if err := insertStatusLog(ctx, tx, order.ID, nil, model.OrderStatusPendingPayment, note, by); err != nil {
+		return model.Order{}, nil, err
+	}
+	queryPayment := `
+		INSERT INTO payments (order_id, payment_status, midtrans_order_id)
+		VALUES ($1, 'pending', $2)`
+	if _, err := tx.ExecContext(ctx, queryPayment, order.ID, order.ID.String()); err != nil {
+		return model.Order{}, nil, err
+	}
+	return order, saved, nil
+}
+
+// insertStatusLog mencatat perubahan status order ke order_status_logs.
+func insertStatusLog(ctx context.Context, tx *sqlx.Tx, orderID uuid.UUID, oldStatus *int16, newStatus int16, note string, by *uuid.UUID) error {
+	query := `
+		INSERT INTO order_status_logs (order_id, old_status, new_status, note, created_by)
+		VALUES ($1, $2, $3, $4, $5)`
+	_, err := tx.ExecContext(ctx, query, orderID, oldStatus, newStatus, note, by)
+	return err
+}
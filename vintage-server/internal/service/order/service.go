@@ -1 +1,459 @@
 package order
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/inventory"
+	"vintage-server/internal/service/shipment"
+	"vintage-server/internal/service/staff"
+	"vintage-server/pkg/apperror"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// service adalah struct yang akan mengimplementasikan interface Service dari domain.go
+type service struct {
+	repo    Repository
+	holdTTL time.Duration
+	rates   shipment.RateProvider
+}
+
+// NewService adalah constructor untuk service.
+// holdTTL adalah berapa lama stok dikunci untuk pembeli sejak checkout dimulai; rates menghitung ongkir.
+func NewService(repo Repository, holdTTL time.Duration, rates shipment.RateProvider) Service {
+	return &service{
+		repo:    repo,
+		holdTTL: holdTTL,
+		rates:   rates,
+	}
+}
+
+// --- Cart ---
+
+func (s *service) GetCart(ctx context.Context, accountID uuid.UUID) (CartResponse, error) {
+	items, err := s.repo.FindCartItems(ctx, accountID)
+	if err != nil {
+		log.Printf("Error finding cart items: %v", err)
+		return CartResponse{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	// Item yang sudah tidak tayang atau shop-nya sedang libur tetap ditampilkan tapi tidak ikut dihitung
+	var total int64
+	for _, item := range items {
+		if item.ProductStatus != model.ListingStatusPublished || item.ShopOnVacation {
+			continue
+		}
+		total += item.ProductPrice * int64(item.Quantity)
+	}
+	return CartResponse{Items: items, TotalPrice: total}, nil
+}
+
+func (s *service) AddCartItem(ctx context.Context, accountID uuid.UUID, req AddCartItemRequest) (CartResponse, error) {
+	// Cart tidak meng-hold stok, hanya menolak quantity yang jelas tidak mungkin dibeli
+	available, err := s.repo.AvailableStock(ctx, accountID, req.ProductID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return CartResponse{}, apperror.New(apperror.ErrCodeNotFound, "product not found")
+		}
+		log.Printf("Error checking available stock: %v", err)
+		return CartResponse{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if available < req.Quantity {
+		return CartResponse{}, apperror.New(apperror.ErrCodeConflict, "not enough stock available")
+	}
+
+	if err := s.repo.UpsertCartItem(ctx, accountID, req.ProductID, req.Quantity); err != nil {
+		log.Printf("Error saving cart item: %v", err)
+		return CartResponse{}, apperror.New(apperror.ErrCodeInternal, "failed to update cart")
+	}
+	return s.GetCart(ctx, accountID)
+}
+
+func (s *service) RemoveCartItem(ctx context.Context, accountID, productID uuid.UUID) (CartResponse, error) {
+	if err := s.repo.DeleteCartItem(ctx, accountID, productID); err != nil {
+		log.Printf("Error deleting cart item: %v", err)
+		return CartResponse{}, apperror.New(apperror.ErrCodeInternal, "failed to update cart")
+	}
+	return s.GetCart(ctx, accountID)
+}
+
+func (s *service) GetShippingOptions(ctx context.Context, accountID uuid.UUID, filter ShippingOptionsFilter) ([]ShopShippingOptions, error) {
+	destination, err := s.findDestination(ctx, accountID, filter.AddressID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.repo.FindCartShippingSettings(ctx, accountID)
+	if err != nil {
+		log.Printf("Error finding cart shipping settings: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return s.shippingOptions(ctx, settings, destination)
+}
+
+// --- Checkout & Order ---
+
+func (s *service) Checkout(ctx context.Context, accountID uuid.UUID, req CheckoutRequest) (CheckoutResponse, error) {
+	destination, err := s.findOptionalDestination(ctx, accountID, req.AddressID)
+	if err != nil {
+		return CheckoutResponse{}, err
+	}
+
+	// Ongkir dihitung sebelum transaksi dibuka, supaya RateProvider (bisa berupa API kurir) tidak
+	// berjalan selama baris produk & hold terkunci
+	var result CheckoutResponse
+	settings, err := s.repo.FindCartShippingSettings(ctx, accountID)
+	if err == nil {
+		var quotes map[uuid.UUID]model.Shipment
+		quotes, err = s.quoteShops(ctx, settings, destination, req.AddressID, req.Shipping)
+		if err == nil {
+			result, err = s.repo.TransactionCheckout(ctx, accountID, time.Now().Add(s.holdTTL), quotes)
+		}
+	}
+	if err != nil {
+		if errors.Is(err, ErrEmptyCart) {
+			return CheckoutResponse{}, apperror.New(apperror.ErrCodeValidation, "cart is empty")
+		}
+		if errors.Is(err, inventory.ErrInsufficientStock) || errors.Is(err, inventory.ErrProductUnavailable) || errors.Is(err, ErrShopOnVacation) {
+			// Pesan berisi id produk yang stoknya sudah diambil pembeli lain, sudah tidak tayang, atau shop-nya libur
+			return CheckoutResponse{}, apperror.New(apperror.ErrCodeConflict, err.Error())
+		}
+		if errors.Is(err, shipment.ErrOriginNotSet) {
+			// Pesan berisi id shop yang belum bisa mengirim pesanan
+			return CheckoutResponse{}, apperror.New(apperror.ErrCodeConflict, err.Error())
+		}
+		if errors.Is(err, ErrOfferQuantityExceedsCart) {
+			// Pesan berisi id produk dan quantity offer yang harus ada di cart
+			return CheckoutResponse{}, apperror.New(apperror.ErrCodeValidation, err.Error())
+		}
+		if errors.Is(err, ErrShippingChoiceMissing) || errors.Is(err, shipment.ErrServiceNotEnabled) ||
+			errors.Is(err, shipment.ErrPickupNotAvailable) || errors.Is(err, shipment.ErrDestinationRequired) {
+			return CheckoutResponse{}, apperror.New(apperror.ErrCodeValidation, err.Error())
+		}
+		log.Printf("Error during checkout: %v", err)
+		return CheckoutResponse{}, apperror.New(apperror.ErrCodeInternal, "failed to checkout")
+	}
+	return result, nil
+}
+
+func (s *service) CancelOrder(ctx context.Context, accountID, orderID uuid.UUID) error {
+	if err := s.repo.TransactionCancelOrder(ctx, accountID, orderID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.New(apperror.ErrCodeNotFound, "order not found")
+		}
+		if errors.Is(err, ErrOrderNotCancellable) {
+			return apperror.New(apperror.ErrCodeConflict, "order can no longer be cancelled")
+		}
+		log.Printf("Error cancelling order: %v", err)
+		return apperror.New(apperror.ErrCodeInternal, "failed to cancel order")
+	}
+	return nil
+}
+
+func (s *service) GetOrders(ctx context.Context, accountID uuid.UUID, filter OrderFilter) (OrderPage, error) {
+	page, limit := normalizePage(filter.Page, filter.Limit)
+	orders, total, err := s.repo.FindOrders(ctx, accountID, limit, (page-1)*limit)
+	if err != nil {
+		log.Printf("Error finding orders: %v", err)
+		return OrderPage{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return OrderPage{Items: orders, Total: total, Page: page, Limit: limit}, nil
+}
+
+func (s *service) GetOrder(ctx context.Context, accountID, orderID uuid.UUID) (OrderDetail, error) {
+	order, err := s.repo.FindOrder(ctx, accountID, orderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return OrderDetail{}, apperror.New(apperror.ErrCodeNotFound, "order not found")
+		}
+		log.Printf("Error finding order: %v", err)
+		return OrderDetail{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	items, err := s.repo.FindOrderItems(ctx, orderID)
+	if err != nil {
+		log.Printf("Error finding order items: %v", err)
+		return OrderDetail{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	shipments, err := s.repo.FindOrderShipments(ctx, orderID)
+	if err != nil {
+		log.Printf("Error finding order shipments: %v", err)
+		return OrderDetail{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return OrderDetail{Order: order, Items: items, Shipments: shipments}, nil
+}
+
+func (s *service) GetOrderShippingOptions(ctx context.Context, accountID, orderID uuid.UUID, filter ShippingOptionsFilter) ([]ShopShippingOptions, error) {
+	if _, err := s.findPendingOrder(ctx, accountID, orderID); err != nil {
+		return nil, err
+	}
+	destination, err := s.findDestination(ctx, accountID, filter.AddressID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.repo.FindOrderShippingSettings(ctx, accountID, orderID)
+	if err != nil {
+		log.Printf("Error finding order shipping settings: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return s.shippingOptions(ctx, settings, destination)
+}
+
+func (s *service) ChooseOrderShipping(ctx context.Context, accountID, orderID uuid.UUID, req ChooseShippingRequest) (OrderDetail, error) {
+	if _, err := s.findPendingOrder(ctx, accountID, orderID); err != nil {
+		return OrderDetail{}, err
+	}
+	destination, err := s.findOptionalDestination(ctx, accountID, req.AddressID)
+	if err != nil {
+		return OrderDetail{}, err
+	}
+
+	// Sama seperti checkout: ongkir dihitung di luar transaksi
+	settings, err := s.repo.FindOrderShippingSettings(ctx, accountID, orderID)
+	if err != nil {
+		log.Printf("Error finding order shipping settings: %v", err)
+		return OrderDetail{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	quotes, err := s.quoteShops(ctx, settings, destination, req.AddressID, req.Shipping)
+	var order model.Order
+	var shipments []model.Shipment
+	if err == nil {
+		order, shipments, err = s.repo.TransactionChooseShipping(ctx, accountID, orderID, quotes)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return OrderDetail{}, apperror.New(apperror.ErrCodeNotFound, "order not found")
+		}
+		if errors.Is(err, ErrOrderNotPending) || errors.Is(err, ErrShippingAlreadyChosen) {
+			return OrderDetail{}, apperror.New(apperror.ErrCodeConflict, err.Error())
+		}
+		if errors.Is(err, shipment.ErrOriginNotSet) {
+			return OrderDetail{}, apperror.New(apperror.ErrCodeConflict, err.Error())
+		}
+		if errors.Is(err, ErrShippingChoiceMissing) || errors.Is(err, shipment.ErrServiceNotEnabled) ||
+			errors.Is(err, shipment.ErrPickupNotAvailable) || errors.Is(err, shipment.ErrDestinationRequired) {
+			return OrderDetail{}, apperror.New(apperror.ErrCodeValidation, err.Error())
+		}
+		log.Printf("Error choosing order shipping: %v", err)
+		return OrderDetail{}, apperror.New(apperror.ErrCodeInternal, "failed to save shipping")
+	}
+
+	items, err := s.repo.FindOrderItems(ctx, orderID)
+	if err != nil {
+		log.Printf("Error finding order items: %v", err)
+		return OrderDetail{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return OrderDetail{Order: order, Items: items, Shipments: shipments}, nil
+}
+
+// --- Seller ---
+
+func (s *service) GetShopOrders(ctx context.Context, accountID uuid.UUID, filter ShopOrderFilter) (ShopOrderPage, error) {
+	shopID, err := s.fulfillmentShopID(ctx, accountID)
+	if err != nil {
+		return ShopOrderPage{}, err
+	}
+
+	page, limit := normalizePage(filter.Page, filter.Limit)
+	orders, total, err := s.repo.FindShopOrders(ctx, shopID, filter.Status, limit, (page-1)*limit)
+	if err != nil {
+		log.Printf("Error finding shop orders: %v", err)
+		return ShopOrderPage{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return ShopOrderPage{Items: orders, Total: total, Page: page, Limit: limit}, nil
+}
+
+func (s *service) GetShopOrder(ctx context.Context, accountID, orderID uuid.UUID) (ShopOrderDetail, error) {
+	shopID, err := s.fulfillmentShopID(ctx, accountID)
+	if err != nil {
+		return ShopOrderDetail{}, err
+	}
+
+	order, err := s.repo.FindShopOrder(ctx, shopID, orderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ShopOrderDetail{}, apperror.New(apperror.ErrCodeNotFound, "order not found")
+		}
+		log.Printf("Error finding shop order: %v", err)
+		return ShopOrderDetail{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	items, err := s.repo.FindShopOrderItems(ctx, shopID, orderID)
+	if err != nil {
+		log.Printf("Error finding shop order items: %v", err)
+		return ShopOrderDetail{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	shipments, err := s.repo.FindOrderShipments(ctx, orderID)
+	if err != nil {
+		log.Printf("Error finding order shipments: %v", err)
+		return ShopOrderDetail{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	detail := ShopOrderDetail{Order: order, Items: items}
+	for i := range shipments {
+		if shipments[i].ShopID == shopID {
+			detail.Shipment = &shipments[i]
+		}
+	}
+	return detail, nil
+}
+
+func (s *service) ExpireStaleCheckouts(ctx context.Context) (int, error) {
+	return s.repo.TransactionExpireHolds(ctx, time.Now())
+}
+
+// fulfillmentShopID mengambil shop tempat akun menjadi anggota, 403 jika akun belum punya shop
+// atau role-nya tidak boleh memproses order.
+func (s *service) fulfillmentShopID(ctx context.Context, accountID uuid.UUID) (uuid.UUID, error) {
+	member, err := s.repo.FindShopMembership(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, apperror.New(apperror.ErrCodeForbidden, "you do not have a shop yet")
+		}
+		log.Printf("Error finding shop membership: %v", err)
+		return uuid.Nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if !staff.Can(member.Role, staff.PermissionFulfillment) {
+		return uuid.Nil, apperror.New(apperror.ErrCodeForbidden, "your shop role does not allow handling orders")
+	}
+	return member.ShopID, nil
+}
+
+// shippingOptions menyusun pilihan pengiriman setiap shop ke destination.
+func (s *service) shippingOptions(ctx context.Context, settings []shipment.Settings, destination shipment.Location) ([]ShopShippingOptions, error) {
+	result := make([]ShopShippingOptions, 0, len(settings))
+	for _, entry := range settings {
+		options, err := shipment.Options(ctx, s.rates, entry, destination)
+		if err != nil {
+			log.Printf("Error calculating shipping rates: %v", err)
+			return nil, apperror.New(apperror.ErrCodeInternal, "failed to calculate shipping cost")
+		}
+		result = append(result, ShopShippingOptions{
+			ShopID:       entry.ShopID,
+			ShopName:     entry.ShopName,
+			OriginCity:   entry.OriginCity,
+			HandlingDays: entry.HandlingDays,
+			SelfPickup:   entry.SelfPickup && entry.Origin != nil,
+			Options:      options,
+		})
+	}
+	return result, nil
+}
+
+// quoteShops menyusun shipment (belum terikat order) untuk setiap shop sesuai pilihan pembeli.
+// Error dikembalikan apa adanya (dibungkus id shop) supaya pemanggil bisa memetakannya.
+func (s *service) quoteShops(ctx context.Context, settings []shipment.Settings, destination *shipment.Location, addressID *int64, shipping []ShippingChoice) (map[uuid.UUID]model.Shipment, error) {
+	// Kode kurir disimpan huruf kecil dan kode layanan huruf besar, sama seperti katalog shipment.Couriers
+	choices := make(map[uuid.UUID]shipment.Choice, len(shipping))
+	for _, choice := range shipping {
+		choices[choice.ShopID] = shipment.Choice{
+			Courier:    strings.ToLower(strings.TrimSpace(choice.Courier)),
+			Service:    strings.ToUpper(strings.TrimSpace(choice.Service)),
+			SelfPickup: choice.SelfPickup,
+		}
+	}
+
+	quotes := make(map[uuid.UUID]model.Shipment, len(settings))
+	for _, entry := range settings {
+		choice, ok := choices[entry.ShopID]
+		if !ok {
+			return nil, fmt.Errorf("shop %s: %w", entry.ShopID, ErrShippingChoiceMissing)
+		}
+		quoted, err := shipment.Quote(ctx, s.rates, entry, destination, addressID, choice)
+		if err != nil {
+			return nil, fmt.Errorf("shop %s: %w", entry.ShopID, err)
+		}
+		quotes[entry.ShopID] = quoted
+	}
+	return quotes, nil
+}
+
+// findPendingOrder mengambil order milik pembeli yang masih menunggu pembayaran.
+func (s *service) findPendingOrder(ctx context.Context, accountID, orderID uuid.UUID) (model.Order, error) {
+	order, err := s.repo.FindOrder(ctx, accountID, orderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Order{}, apperror.New(apperror.ErrCodeNotFound, "order not found")
+		}
+		log.Printf("Error finding order: %v", err)
+		return model.Order{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if order.Status != model.OrderStatusPendingPayment {
+		return model.Order{}, apperror.New(apperror.ErrCodeConflict, "order is no longer waiting for payment")
+	}
+	return order, nil
+}
+
+// findOptionalDestination seperti findDestination, tapi addressID boleh kosong (semua shop diambil sendiri).
+func (s *service) findOptionalDestination(ctx context.Context, accountID uuid.UUID, addressID *int64) (*shipment.Location, error) {
+	if addressID == nil {
+		return nil, nil
+	}
+	location, err := s.findDestination(ctx, accountID, *addressID)
+	if err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+// findDestination mengambil wilayah alamat pengiriman milik pembeli.
+func (s *service) findDestination(ctx context.Context, accountID uuid.UUID, addressID int64) (shipment.Location, error) {
+	location, err := s.repo.FindAddressLocation(ctx, accountID, addressID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return shipment.Location{}, apperror.New(apperror.ErrCodeNotFound, fmt.Sprintf("address %d not found", addressID))
+		}
+		log.Printf("Error finding address: %v", err)
+		return shipment.Location{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return location, nil
+}
+
+// normalizePage memberi nilai default dan batas atas untuk pagination.
+func normalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	return page, limit
+}
+
+// StartHoldSweeper menjalankan ExpireStaleCheckouts secara berkala sampai ctx dibatalkan.
+func StartHoldSweeper(ctx context.Context, svc Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cancelled, err := svc.ExpireStaleCheckouts(ctx)
+			if err != nil {
+				log.Printf("Error sweeping expired holds: %v", err)
+				continue
+			}
+			if cancelled > 0 {
+				log.Printf("Hold sweeper cancelled %d expired checkout(s)", cancelled)
+			}
+		}
+	}
+}
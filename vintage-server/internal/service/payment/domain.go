@@ -1 +1,36 @@
 package payment
+
+// File: internal/service/payment/domain.go
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// =================================================================================
+// KONTRAK UNTUK SERVICE (Logika Bisnis) 🧠
+// =================================================================================
+type Service interface {
+	// Usecase: Midtrans Payment Notification (webhook)
+	// Pembayaran sukses mengonversi hold stok menjadi penjualan, gagal/expire melepas hold.
+	HandleMidtransNotification(ctx context.Context, req MidtransNotification) error
+}
+
+// =================================================================================
+// KONTRAK UNTUK REPOSITORY (Akses Database) 🚚
+// =================================================================================
+// Repository mendefinisikan semua interaksi ke database yang dibutuhkan oleh Service.
+type Repository interface {
+	// TransactionSettlePayment menandai payment lunas, mengonversi hold, memindah order ke 'paid', dan
+	// mengisi ship_by = sekarang + handling_days shop (shipWindow jika belum diatur), dihitung dari
+	// akhir libur jika shop-nya sedang libur.
+	// paid adalah gross_amount dari Midtrans; order pending hanya dilunasi jika paid sama dengan
+	// total_price dan setiap shop di order sudah punya shipment. Jika tidak, order dibatalkan dan
+	// dikembalikan ErrRefundRequired yang membungkus ErrAmountMismatch / ErrShippingNotChosen.
+	// Mengembalikan ErrRefundRequired juga jika order sudah batal atau stoknya sudah tidak ada.
+	TransactionSettlePayment(ctx context.Context, orderID uuid.UUID, update PaymentUpdate, paid int64, shipWindow time.Duration) error
+	// TransactionFailPayment mencatat status gagal dan membatalkan order pending beserta hold-nya.
+	TransactionFailPayment(ctx context.Context, orderID uuid.UUID, update PaymentUpdate) error
+}
@@ -0,0 +1,34 @@
+package payment
+
+import "errors"
+
+// ErrRefundRequired dikembalikan jika uang sudah masuk tapi order tidak bisa dipenuhi
+// (order sudah dibatalkan sweeper atau stok sudah terjual ke pembeli lain).
+var ErrRefundRequired = errors.New("payment settled for an order that cannot be fulfilled")
+
+var (
+	// ErrAmountMismatch: gross_amount tidak sama dengan orders.total_price, misal dibayar sebelum
+	// ongkir pemenang lelang ditambahkan ke total.
+	ErrAmountMismatch = errors.New("paid amount does not match the order total")
+	// ErrShippingNotChosen: ada shop di order yang belum punya shipment, jadi seller tidak tahu kurirnya.
+	ErrShippingNotChosen = errors.New("order was paid before shipping was chosen for every shop")
+)
+
+// MidtransNotification adalah body HTTP notification dari Midtrans.
+type MidtransNotification struct {
+	OrderID           string `json:"order_id" binding:"required"`
+	StatusCode        string `json:"status_code" binding:"required"`
+	GrossAmount       string `json:"gross_amount" binding:"required"`
+	SignatureKey      string `json:"signature_key" binding:"required"`
+	TransactionStatus string `json:"transaction_status" binding:"required"`
+	TransactionID     string `json:"transaction_id"`
+	PaymentType       string `json:"payment_type"`
+	FraudStatus       string `json:"fraud_status"`
+}
+
+// PaymentUpdate adalah data payment yang disimpan dari sebuah notification.
+type PaymentUpdate struct {
+	Status        string
+	TransactionID *string
+	PaymentMethod *string
+}
@@ -1 +1,33 @@
 package payment
+
+import (
+	"net/http"
+	"vintage-server/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler adalah struct yang memegang dependency ke Service
+type Handler struct {
+	svc Service
+}
+
+// NewHandler adalah constructor untuk handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// MidtransNotification adalah webhook yang dipanggil Midtrans setiap status transaksi berubah
+func (h *Handler) MidtransNotification(c *gin.Context) {
+	var req MidtransNotification
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.svc.HandleMidtransNotification(c.Request.Context(), req); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
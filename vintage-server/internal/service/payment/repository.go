@@ -1 +1,200 @@
 package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/inventory"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// repository adalah struct yang mengimplementasikan kontrak Repository dari domain.go
+type repository struct {
+	db        *sqlx.DB
+	inventory inventory.Repository
+}
+
+// NewRepository adalah constructor untuk implementasi repository
+func NewRepository(db *sqlx.DB, inv inventory.Repository) Repository {
+	return &repository{
+		db:        db,
+		inventory: inv,
+	}
+}
+
+func (r *repository) TransactionSettlePayment(ctx context.Context, orderID uuid.UUID, update PaymentUpdate, paid int64, shipWindow time.Duration) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := lockOrder(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+	if err := savePayment(ctx, tx, orderID, update); err != nil {
+		return err
+	}
+
+	// Notification bisa dikirim ulang oleh Midtrans, order yang sudah paid diabaikan
+	if status != model.OrderStatusPendingPayment {
+		if status == model.OrderStatusCancelled {
+			if err := tx.Commit(); err != nil {
+				return err
+			}
+			return ErrRefundRequired
+		}
+		return tx.Commit()
+	}
+
+	// Total order bisa naik setelah payment dibuat (ongkir pemenang lelang), dan order tanpa
+	// shipment tidak bisa dikirim seller: keduanya dibatalkan untuk refund, bukan dilunasi
+	var check struct {
+		TotalPrice   int64 `db:"total_price"`
+		MissingShops int   `db:"missing_shops"`
+	}
+	queryCheck := `
+		SELECT o.total_price, (
+			SELECT COUNT(DISTINCT p.shop_id)
+			FROM order_items oi
+			JOIN products p ON p.id = oi.product_id
+			WHERE oi.order_id = o.id
+			  AND NOT EXISTS (SELECT 1 FROM shipments sh WHERE sh.order_id = o.id AND sh.shop_id = p.shop_id)
+		) AS missing_shops
+		FROM orders o WHERE o.id = $1`
+	if err := tx.GetContext(ctx, &check, queryCheck, orderID); err != nil {
+		return err
+	}
+	if reason := settlementProblem(check.TotalPrice, paid, check.MissingShops); reason != nil {
+		if err := setOrderStatus(ctx, tx, orderID, status, model.OrderStatusCancelled, reason.Error()+", refund required"); err != nil {
+			return err
+		}
+		if err := r.inventory.ReleaseOrderHolds(ctx, tx, orderID); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: %w", ErrRefundRequired, reason)
+	}
+
+	if err := r.inventory.ConvertOrderHolds(ctx, tx, orderID); err != nil {
+		if !errors.Is(err, inventory.ErrInsufficientStock) && !errors.Is(err, inventory.ErrHoldNotActive) {
+			return err
+		}
+		// Stok sudah habis: batalkan order, payment tetap tercatat lunas untuk proses refund
+		if err := setOrderStatus(ctx, tx, orderID, status, model.OrderStatusCancelled, "paid but stock no longer available, refund required"); err != nil {
+			return err
+		}
+		if err := r.inventory.ReleaseOrderHolds(ctx, tx, orderID); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		return ErrRefundRequired
+	}
+
+	if err := setOrderStatus(ctx, tx, orderID, status, model.OrderStatusPaid, "payment settled"); err != nil {
+		return err
+	}
+	if err := setShipBy(ctx, tx, orderID, shipWindow); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *repository) TransactionFailPayment(ctx context.Context, orderID uuid.UUID, update PaymentUpdate) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := lockOrder(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+	if err := savePayment(ctx, tx, orderID, update); err != nil {
+		return err
+	}
+
+	if status == model.OrderStatusPendingPayment {
+		if err := r.inventory.ReleaseOrderHolds(ctx, tx, orderID); err != nil {
+			return err
+		}
+		if err := setOrderStatus(ctx, tx, orderID, status, model.OrderStatusCancelled, "payment "+update.Status); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// settlementProblem mengembalikan alasan order pending tidak boleh dilunasi, atau nil jika boleh.
+func settlementProblem(total, paid int64, missingShops int) error {
+	if paid != total {
+		return ErrAmountMismatch
+	}
+	if missingShops > 0 {
+		return ErrShippingNotChosen
+	}
+	return nil
+}
+
+// lockOrder mengunci baris order dan mengembalikan status saat ini.
+func lockOrder(ctx context.Context, tx *sqlx.Tx, orderID uuid.UUID) (int16, error) {
+	var status int16
+	err := tx.GetContext(ctx, &status, "SELECT status FROM orders WHERE id = $1 FOR UPDATE", orderID)
+	return status, err
+}
+
+func savePayment(ctx context.Context, tx *sqlx.Tx, orderID uuid.UUID, update PaymentUpdate) error {
+	query := `
+		UPDATE payments SET
+			payment_status = $1,
+			midtrans_transaction_id = COALESCE($2, midtrans_transaction_id),
+			payment_method = COALESCE($3, payment_method),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE order_id = $4`
+	_, err := tx.ExecContext(ctx, query, update.Status, update.TransactionID, update.PaymentMethod, orderID)
+	return err
+}
+
+func setOrderStatus(ctx context.Context, tx *sqlx.Tx, orderID uuid.UUID, oldStatus, newStatus int16, note string) error {
+	if _, err := tx.ExecContext(ctx, "UPDATE orders SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2", newStatus, orderID); err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO order_status_logs (order_id, old_status, new_status, note)
+		VALUES ($1, $2, $3, $4)`
+	_, err := tx.ExecContext(ctx, query, orderID, oldStatus, newStatus, note)
+	return err
+}
+
+// setShipBy mengisi batas kirim order yang baru dibayar. Lama proses memakai handling_days shop
+// (shipWindow jika belum diatur), diambil yang terlama jika order memuat beberapa shop. Jika ada
+// shop di order yang sedang libur dengan tanggal kembali, batas kirim dihitung dari tanggal kembali tersebut.
+func setShipBy(ctx context.Context, tx *sqlx.Tx, orderID uuid.UUID, shipWindow time.Duration) error {
+	query := `
+		UPDATE orders SET ship_by = GREATEST(CURRENT_TIMESTAMP, COALESCE((
+			SELECT MAX(s.vacation_ends_at)
+			FROM order_items oi
+			JOIN products p ON p.id = oi.product_id
+			JOIN shop s ON s.id = p.shop_id
+			WHERE oi.order_id = $1 AND s.vacation_active
+		), CURRENT_TIMESTAMP)) + (
+			SELECT MAX(COALESCE(s.handling_days * INTERVAL '1 day', $2::float8 * INTERVAL '1 second'))
+			FROM order_items oi
+			JOIN products p ON p.id = oi.product_id
+			JOIN shop s ON s.id = p.shop_id
+			WHERE oi.order_id = $1
+		)
+		WHERE id = $1`
+	_, err := tx.ExecContext(ctx, query, orderID, shipWindow.Seconds())
+	return err
+}
@@ -0,0 +1,30 @@
+package payment
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSettlementProblem(t *testing.T) {
+	tests := []struct {
+		name         string
+		total, paid  int64
+		missingShops int
+		want         error
+	}{
+		{"exact amount with shipping chosen", 165000, 165000, 0, nil},
+		// Pemenang lelang membayar harga akhir sebelum ongkir ditambahkan ke total
+		{"paid the total from before shipping was added", 165000, 150000, 0, ErrAmountMismatch},
+		{"paid more than the total", 150000, 165000, 0, ErrAmountMismatch},
+		// Pemenang lelang membayar tanpa memilih pengiriman: total masih sama, shipment belum ada
+		{"auction order without a shipment", 150000, 150000, 1, ErrShippingNotChosen},
+		{"amount is checked before shipping", 150000, 100000, 1, ErrAmountMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := settlementProblem(tt.total, tt.paid, tt.missingShops); !errors.Is(got, tt.want) || (tt.want == nil && got != nil) {
+				t.Errorf("settlementProblem(%d, %d, %d) = %v, want %v", tt.total, tt.paid, tt.missingShops, got, tt.want)
+			}
+		})
+	}
+}
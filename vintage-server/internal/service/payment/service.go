@@ -1 +1,110 @@
 package payment
+
+import (
+	"context"
+	"crypto/sha512"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"vintage-server/pkg/apperror"
+
+	"github.com/google/uuid"
+)
+
+// service adalah struct yang akan mengimplementasikan interface Service dari domain.go
+type service struct {
+	repo       Repository
+	serverKey  string
+	shipWindow time.Duration
+}
+
+// NewService adalah constructor untuk service
+// shipWindow adalah lama waktu seller mengirim order sejak dibayar.
+func NewService(repo Repository, midtransServerKey string, shipWindow time.Duration) Service {
+	return &service{
+		repo:       repo,
+		serverKey:  midtransServerKey,
+		shipWindow: shipWindow,
+	}
+}
+
+func (s *service) HandleMidtransNotification(ctx context.Context, req MidtransNotification) error {
+	// 1. Verifikasi signature: SHA512(order_id + status_code + gross_amount + server_key)
+	sum := sha512.Sum512([]byte(req.OrderID + req.StatusCode + req.GrossAmount + s.serverKey))
+	expected := hex.EncodeToString(sum[:])
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(req.SignatureKey)) != 1 {
+		return apperror.New(apperror.ErrCodeUnauthorized, "invalid signature")
+	}
+
+	// midtrans_order_id diisi dengan id order saat checkout
+	orderID, err := uuid.Parse(req.OrderID)
+	if err != nil {
+		return apperror.New(apperror.ErrCodeValidation, "invalid order id")
+	}
+
+	paid, err := parseGrossAmount(req.GrossAmount)
+	if err != nil {
+		return apperror.New(apperror.ErrCodeValidation, "invalid gross amount")
+	}
+
+	update := PaymentUpdate{Status: req.TransactionStatus}
+	if req.TransactionID != "" {
+		update.TransactionID = &req.TransactionID
+	}
+	if req.PaymentType != "" {
+		update.PaymentMethod = &req.PaymentType
+	}
+
+	// 2. Petakan status Midtrans ke aksi
+	switch req.TransactionStatus {
+	case "settlement":
+		err = s.repo.TransactionSettlePayment(ctx, orderID, update, paid, s.shipWindow)
+	case "capture":
+		if req.FraudStatus != "" && req.FraudStatus != "accept" {
+			return nil
+		}
+		err = s.repo.TransactionSettlePayment(ctx, orderID, update, paid, s.shipWindow)
+	case "deny", "cancel", "expire", "failure":
+		err = s.repo.TransactionFailPayment(ctx, orderID, update)
+	default:
+		// pending, refund, dll. tidak mengubah stok
+		return nil
+	}
+
+	if err != nil {
+		if errors.Is(err, ErrRefundRequired) {
+			// Tetap sukses untuk Midtrans agar tidak dikirim ulang; refund ditangani manual
+			log.Printf("Refund required for order %s: %v", orderID, err)
+			return nil
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.New(apperror.ErrCodeNotFound, "order not found")
+		}
+		log.Printf("Error handling payment notification: %v", err)
+		return apperror.New(apperror.ErrCodeInternal, "failed to process notification")
+	}
+	return nil
+}
+
+// parseGrossAmount membaca gross_amount Midtrans ("150000.00"). Rupiah tidak punya sen, jadi
+// bagian desimal harus nol.
+func parseGrossAmount(raw string) (int64, error) {
+	whole, fraction, _ := strings.Cut(raw, ".")
+	if strings.Trim(fraction, "0") != "" {
+		return 0, fmt.Errorf("gross amount %q has a non-zero fraction", raw)
+	}
+	amount, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if amount < 0 {
+		return 0, fmt.Errorf("gross amount %q is negative", raw)
+	}
+	return amount, nil
+}
@@ -0,0 +1,33 @@
+package payment
+
+import "testing"
+
+func TestParseGrossAmount(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    int64
+		wantErr bool
+	}{
+		{raw: "150000.00", want: 150000},
+		{raw: "150000", want: 150000},
+		{raw: "0.00", want: 0},
+		{raw: "150000.50", wantErr: true},
+		{raw: "-1.00", wantErr: true},
+		{raw: "abc", wantErr: true},
+		{raw: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parseGrossAmount(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseGrossAmount(%q) = %d, want an error", tt.raw, got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("parseGrossAmount(%q) = %d, %v, want %d", tt.raw, got, err, tt.want)
+			}
+		})
+	}
+}
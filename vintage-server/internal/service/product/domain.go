@@ -1 +1,272 @@
 package product
+
+// File: internal/service/product/domain.go
+
+import (
+	"context"
+	"mime/multipart"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/audit"
+
+	"github.com/google/uuid"
+)
+
+// =================================================================================
+// KONTRAK UNTUK SERVICE (Logika Bisnis) 🧠
+// =================================================================================
+type Service interface {
+	// --- Catalog ---
+	// Usecase: Customer Search Products (filter kategori ikut menyertakan sub-kategori)
+	// viewerID diisi jika pembeli login, untuk menghitung flag "fits you".
+	SearchProducts(ctx context.Context, filter ProductFilter, viewerID *uuid.UUID) (ProductSearchResponse, error)
+	// Usecase: Customer View Product (listing published & sold, sold tampil dengan badge)
+	GetProduct(ctx context.Context, productID uuid.UUID) (ProductDetail, error)
+	// GetProductBySlug mengembalikan slug terbaru (tanpa detail) jika slug yang diminta
+	// adalah slug lama sebelum produk diganti nama, supaya handler bisa redirect.
+	GetProductBySlug(ctx context.Context, productSlug string) (ProductDetail, string, error)
+	// Usecase: Customer View Breadcrumb (root -> kategori produk)
+	GetProductBreadcrumb(ctx context.Context, productID uuid.UUID) ([]CategoryResponse, error)
+	// Usecase: Customer View Price History (untuk grafik sparkline)
+	GetPriceHistory(ctx context.Context, productID uuid.UUID, filter PriceHistoryFilter) ([]PricePoint, error)
+
+	// --- Shop ---
+	// Usecase: Customer View Shop (lewat id atau slug; slug lama dikembalikan sebagai redirect),
+	// lengkap dengan statistik dari shop_stats dan status follow pengunjung yang login
+	GetShop(ctx context.Context, shopSlug string, viewerID *uuid.UUID) (ShopProfile, string, error)
+
+	// --- Listing (Seller) ---
+	// Usecase: SellerManage Products (produk baru selalu mulai sebagai draft)
+	GetSellerProducts(ctx context.Context, sellerID uuid.UUID, filter SellerProductFilter) (SellerProductPage, error)
+	GetSellerProduct(ctx context.Context, sellerID, productID uuid.UUID) (ProductDetail, error)
+	CreateProduct(ctx context.Context, sellerID uuid.UUID, req ProductRequest) (model.Product, error)
+	UpdateProduct(ctx context.Context, sellerID, productID uuid.UUID, req ProductRequest) (model.Product, error)
+	// Usecase: SellerChange Listing Status (submit, withdraw, archive, mark sold, relist)
+	ChangeListingStatus(ctx context.Context, sellerID, productID uuid.UUID, req ListingStatusRequest) (model.Product, error)
+	// Usecase: SellerDelete Listing (soft delete; riwayat order tetap bisa membaca produknya)
+	DeleteProduct(ctx context.Context, sellerID, productID uuid.UUID) error
+
+	// --- Moderation (Admin) ---
+	// Usecase: AdminReview Listings dari seller baru
+	GetModerationQueue(ctx context.Context, page PageRequest) (ModerationQueuePage, error)
+	ApproveListing(ctx context.Context, actor audit.Actor, productID uuid.UUID) (model.Product, error)
+	RejectListing(ctx context.Context, actor audit.Actor, productID uuid.UUID, req ModerationDecisionRequest) (model.Product, error)
+
+	// --- Wishlist ---
+	// Usecase: CustomerManage Wishlist (hanya listing yang tayang yang bisa ditambahkan)
+	GetWishlist(ctx context.Context, accountID uuid.UUID) ([]WishlistItem, error)
+	AddToWishlist(ctx context.Context, accountID uuid.UUID, req WishlistRequest) ([]WishlistItem, error)
+	RemoveFromWishlist(ctx context.Context, accountID, productID uuid.UUID) ([]WishlistItem, error)
+
+	// --- Saved Search ---
+	// Usecase: CustomerManage Saved Searches (filter sama dengan pencarian katalog)
+	SaveSearch(ctx context.Context, accountID uuid.UUID, filter ProductFilter, req SavedSearchRequest) (model.SavedSearch, error)
+	GetSavedSearches(ctx context.Context, accountID uuid.UUID) ([]model.SavedSearch, error)
+	UpdateSavedSearch(ctx context.Context, accountID, searchID uuid.UUID, req UpdateSavedSearchRequest) (model.SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, accountID, searchID uuid.UUID) error
+	// Usecase: CustomerRun Saved Search
+	RunSavedSearch(ctx context.Context, accountID, searchID uuid.UUID, page PageRequest) (ProductSearchResponse, error)
+	// SendSavedSearchAlerts dipanggil job berkala: listing yang baru tayang dicocokkan ke saved search,
+	// lalu dikirim sebagai notifikasi instant atau digest harian.
+	SendSavedSearchAlerts(ctx context.Context) (int, error)
+
+	// --- Category ---
+	// Usecase: Customer/Client Browse Category Tree
+	GetCategoryTree(ctx context.Context) ([]CategoryNode, error)
+
+	// Usecase: AdminManage Categories
+	CreateCategory(ctx context.Context, actor audit.Actor, req CreateCategoryRequest) (CategoryResponse, error)
+	RenameCategory(ctx context.Context, actor audit.Actor, categoryID int, req RenameCategoryRequest) (CategoryResponse, error)
+	MoveCategory(ctx context.Context, actor audit.Actor, categoryID int, req MoveCategoryRequest) (CategoryResponse, error)
+
+	// --- Reference Data ---
+	// Usecase: Client Build Product Forms (public, di-cache)
+	GetBrands(ctx context.Context) ([]model.Brand, error)
+	GetConditions(ctx context.Context) ([]model.ProductCondition, error)
+	GetSizes(ctx context.Context) ([]model.ProductSize, error)
+	GetCategories(ctx context.Context) ([]CategoryResponse, error)
+
+	// Usecase: AdminManage Brands, Conditions, Sizes
+	CreateBrand(ctx context.Context, actor audit.Actor, req BrandRequest) (model.Brand, error)
+	UpdateBrand(ctx context.Context, actor audit.Actor, brandID int, req BrandRequest) (model.Brand, error)
+	UploadBrandLogo(ctx context.Context, actor audit.Actor, brandID int, file *multipart.FileHeader) (model.Brand, error)
+	CreateCondition(ctx context.Context, actor audit.Actor, req ConditionRequest) (model.ProductCondition, error)
+	UpdateCondition(ctx context.Context, actor audit.Actor, conditionID int, req ConditionRequest) (model.ProductCondition, error)
+	CreateSize(ctx context.Context, actor audit.Actor, req SizeRequest) (model.ProductSize, error)
+	UpdateSize(ctx context.Context, actor audit.Actor, sizeID int, req SizeRequest) (model.ProductSize, error)
+
+	// Usecase: AdminDelete/Merge Reference Data (berlaku untuk brand, condition, size, category)
+	// Delete ditolak jika masih dipakai produk, kecuali MergeTargetID diisi.
+	DeleteReference(ctx context.Context, actor audit.Actor, kind ReferenceKind, id int, req DeleteReferenceRequest) error
+	MergeReference(ctx context.Context, actor audit.Actor, kind ReferenceKind, sourceID int, req MergeReferenceRequest) error
+
+	// --- Vintage Attributes & Tags ---
+	GetLabelTypes(ctx context.Context) ([]model.LabelType, error)
+	GetMaterials(ctx context.Context) ([]model.Material, error)
+	GetProductAttributes(ctx context.Context, productID uuid.UUID) (ProductAttributesResponse, error)
+	// Usecase: SellerManage Product Attributes (era, negara, bahan, label, tag)
+	SetProductAttributes(ctx context.Context, sellerID, productID uuid.UUID, req ProductAttributesRequest) (ProductAttributesResponse, error)
+	// Usecase: Customer Browse Tags (diurutkan dari yang paling banyak dipakai)
+	BrowseTags(ctx context.Context, filter TagFilter) ([]TagSummary, error)
+	// Usecase: AdminCurate Tags
+	MergeTag(ctx context.Context, actor audit.Actor, sourceID int, req MergeTagRequest) error
+	AddTagSynonym(ctx context.Context, actor audit.Actor, tagID int, req TagSynonymRequest) (model.TagSynonym, error)
+	DeleteTagSynonym(ctx context.Context, actor audit.Actor, alias string) error
+
+	// --- Measurement ---
+	// Usecase: Client Build Measurement Form (skema diwarisi dari kategori leluhur terdekat)
+	GetMeasurementFields(ctx context.Context) ([]model.MeasurementField, error)
+	GetCategoryMeasurementSchema(ctx context.Context, categoryID int) ([]MeasurementFieldResponse, error)
+	// Usecase: AdminManage Category Measurement Schema
+	SetCategoryMeasurementSchema(ctx context.Context, actor audit.Actor, categoryID int, req SetMeasurementSchemaRequest) ([]MeasurementFieldResponse, error)
+
+	// Usecase: Customer View Product Measurements (+ flag "fits you")
+	GetProductMeasurements(ctx context.Context, productID uuid.UUID, viewerID *uuid.UUID) (ProductMeasurementsResponse, error)
+	// Usecase: SellerManage Product Measurements
+	SetProductMeasurements(ctx context.Context, sellerID, productID uuid.UUID, req MeasurementsRequest) (ProductMeasurementsResponse, error)
+
+	// Usecase: CustomerManage Own Measurements
+	GetMyMeasurements(ctx context.Context, accountID uuid.UUID) (map[string]float64, error)
+	SetMyMeasurements(ctx context.Context, accountID uuid.UUID, req MeasurementsRequest) (map[string]float64, error)
+}
+
+// =================================================================================
+// KONTRAK UNTUK REPOSITORY (Akses Database) 🚚
+// =================================================================================
+// Repository mendefinisikan semua interaksi ke database yang dibutuhkan oleh Service.
+// Semua method yang mengubah data referensi menerima model.AdminLog dan menulisnya
+// ke admin_logs di dalam transaksi yang sama.
+type Repository interface {
+	// --- Category ---
+	FindAllCategories(ctx context.Context) ([]model.ProductCategory, error)
+	FindCategoryByID(ctx context.Context, id int) (model.ProductCategory, error)
+	// FindCategoryAncestors mengembalikan rantai kategori dari root sampai kategori itu sendiri.
+	FindCategoryAncestors(ctx context.Context, id int) ([]model.ProductCategory, error)
+	IsCategorySlugUsed(ctx context.Context, slug string) (bool, error)
+	SaveCategory(ctx context.Context, category model.ProductCategory, entry model.AdminLog) (model.ProductCategory, error)
+	UpdateCategoryName(ctx context.Context, category model.ProductCategory, entry model.AdminLog) (model.ProductCategory, error)
+	// TransactionMoveCategory mengecek siklus & memindahkan parent dalam satu transaksi.
+	// Mengembalikan ErrCategoryCycle jika newParentID adalah kategori itu sendiri atau turunannya.
+	TransactionMoveCategory(ctx context.Context, categoryID int, newParentID *int, entry model.AdminLog) (model.ProductCategory, error)
+
+	// --- Reference Data ---
+	FindActiveBrands(ctx context.Context) ([]model.Brand, error)
+	FindBrandByID(ctx context.Context, id int) (model.Brand, error)
+	SaveBrand(ctx context.Context, brand model.Brand, entry model.AdminLog) (model.Brand, error)
+	UpdateBrand(ctx context.Context, brand model.Brand, entry model.AdminLog) (model.Brand, error)
+
+	FindActiveConditions(ctx context.Context) ([]model.ProductCondition, error)
+	FindConditionByID(ctx context.Context, id int) (model.ProductCondition, error)
+	SaveCondition(ctx context.Context, condition model.ProductCondition, entry model.AdminLog) (model.ProductCondition, error)
+	UpdateCondition(ctx context.Context, condition model.ProductCondition, entry model.AdminLog) (model.ProductCondition, error)
+
+	FindActiveSizes(ctx context.Context) ([]model.ProductSize, error)
+	FindSizeByID(ctx context.Context, id int) (model.ProductSize, error)
+	SaveSize(ctx context.Context, size model.ProductSize, entry model.AdminLog) (model.ProductSize, error)
+	UpdateSize(ctx context.Context, size model.ProductSize, entry model.AdminLog) (model.ProductSize, error)
+
+	// TransactionSoftDeleteReference mengisi deleted_at, atau ErrReferenceInUse jika masih dipakai
+	// produk (atau, untuk kategori, masih punya sub-kategori aktif).
+	TransactionSoftDeleteReference(ctx context.Context, kind ReferenceKind, id int, entry model.AdminLog) error
+	// TransactionMergeReference memindahkan semua produk (dan sub-kategori) dari source ke target
+	// lalu soft delete source.
+	TransactionMergeReference(ctx context.Context, kind ReferenceKind, sourceID, targetID int, entry model.AdminLog) error
+
+	// --- Vintage Attributes & Tags ---
+	FindLabelTypes(ctx context.Context) ([]model.LabelType, error)
+	FindMaterials(ctx context.Context) ([]model.Material, error)
+	FindProductAttributes(ctx context.Context, productID uuid.UUID) (ProductAttributesResponse, error)
+	// TransactionSetProductAttributes mengganti atribut, bahan & tag produk. tags sudah ternormalisasi;
+	// alias diarahkan ke tag kanonik dan tag baru dibuat otomatis.
+	TransactionSetProductAttributes(ctx context.Context, productID uuid.UUID, req ProductAttributesRequest, tags []string) error
+	FindTags(ctx context.Context, filter TagFilter) ([]TagSummary, error)
+	FindTagByID(ctx context.Context, id int) (model.Tag, error)
+	// TransactionMergeTag memindahkan produk & alias dari source ke target, lalu nama source menjadi alias target.
+	TransactionMergeTag(ctx context.Context, sourceID, targetID int, entry model.AdminLog) error
+	// SaveTagSynonym mengembalikan ErrDuplicateName jika alias sudah dipakai sebagai tag atau alias lain.
+	SaveTagSynonym(ctx context.Context, synonym model.TagSynonym, entry model.AdminLog) (model.TagSynonym, error)
+	DeleteTagSynonym(ctx context.Context, alias string, entry model.AdminLog) error
+
+	// --- Measurement ---
+	FindMeasurementFields(ctx context.Context) ([]model.MeasurementField, error)
+	// FindCategoryMeasurementSchema mengembalikan skema milik kategori, atau milik leluhur terdekat yang punya skema.
+	// Leluhur yang sudah dihapus tidak ikut ditelusuri.
+	FindCategoryMeasurementSchema(ctx context.Context, categoryID int) ([]MeasurementFieldResponse, error)
+	ReplaceCategoryMeasurementSchema(ctx context.Context, categoryID int, fields []model.CategoryMeasurementField, entry model.AdminLog) error
+	FindProductMeasurements(ctx context.Context, productIDs []uuid.UUID) ([]model.ProductMeasurement, error)
+	ReplaceProductMeasurements(ctx context.Context, productID uuid.UUID, values map[string]float64) error
+	FindAccountMeasurements(ctx context.Context, accountID uuid.UUID) ([]model.AccountMeasurement, error)
+	ReplaceAccountMeasurements(ctx context.Context, accountID uuid.UUID, values map[string]float64) error
+
+	// --- Product ---
+	// FindProductCategoryID hanya menemukan produk yang tampil publik (published / sold).
+	FindProductCategoryID(ctx context.Context, productID uuid.UUID) (int, error)
+	// FindProductByID mengambil produk dengan status apa pun, kecuali yang sudah dihapus.
+	FindProductByID(ctx context.Context, productID uuid.UUID) (model.Product, error)
+	FindProductBySlug(ctx context.Context, productSlug string) (model.Product, error)
+	// FindProductSlugRedirect mengembalikan slug terbaru produk pemilik slug lama.
+	FindProductSlugRedirect(ctx context.Context, oldSlug string) (string, error)
+	// FindProductSlugs mengembalikan slug (aktif maupun lama) berawalan base milik produk selain excludeID.
+	FindProductSlugs(ctx context.Context, base string, excludeID uuid.UUID) ([]string, error)
+	FindProductImages(ctx context.Context, productID uuid.UUID) ([]string, error)
+	// FindPriceHistory mengembalikan harga yang berlaku di awal rentang (jika ada) diikuti setiap perubahan sejak since.
+	FindPriceHistory(ctx context.Context, productID uuid.UUID, since time.Time) ([]PricePoint, error)
+	SearchProducts(ctx context.Context, filter ProductFilter) ([]ProductSummary, int64, error)
+
+	// --- Listing ---
+	// FindShopMembership mengambil shop tempat akun menjadi anggota beserta role-nya.
+	FindShopMembership(ctx context.Context, accountID uuid.UUID) (model.ShopMember, error)
+	FindSellerProducts(ctx context.Context, shopID uuid.UUID, filter SellerProductFilter) ([]model.Product, int64, error)
+	// CountPublishedListings menghitung listing shop yang pernah tayang (untuk menentukan seller baru).
+	CountPublishedListings(ctx context.Context, shopID uuid.UUID) (int, error)
+	// FindShopKYCStatus mengambil status verifikasi identitas shop (shop.kyc_status).
+	FindShopKYCStatus(ctx context.Context, shopID uuid.UUID) (string, error)
+	// SaveProduct mengembalikan ErrDuplicateName jika slug keduluan produk lain.
+	SaveProduct(ctx context.Context, product model.Product) (model.Product, error)
+	// UpdateProduct mengembalikan ErrListingChanged jika status di DB sudah bukan product.Status;
+	// jika slug berubah, previousSlug disimpan ke product_slug_history untuk redirect.
+	// product.Stock diabaikan; stok hanya diganti jika stock tidak nil (lihat inventory.SetStock).
+	UpdateProduct(ctx context.Context, product model.Product, previousSlug string, stock *int) (model.Product, error)
+	// TransactionChangeListingStatus mengubah status, mencatat product_status_logs, dan
+	// (jika entry tidak nil) admin_logs di transaksi yang sama.
+	TransactionChangeListingStatus(ctx context.Context, change ListingStatusChange, entry *model.AdminLog) (model.Product, error)
+	// TransactionSoftDeleteProduct mengisi deleted_at, atau ErrListingReserved jika stoknya masih di-hold checkout.
+	TransactionSoftDeleteProduct(ctx context.Context, productID uuid.UUID) error
+	FindModerationQueue(ctx context.Context, page PageRequest) ([]ModerationQueueItem, int64, error)
+
+	// --- Shop ---
+	// FindShop hanya menemukan shop aktif.
+	FindShop(ctx context.Context, shopID uuid.UUID) (ShopProfile, error)
+	FindShopBySlug(ctx context.Context, shopSlug string) (ShopProfile, error)
+	// FindShopSlugRedirect mengembalikan slug terbaru shop pemilik slug lama.
+	FindShopSlugRedirect(ctx context.Context, oldSlug string) (string, error)
+	// FindShopStats mengembalikan sql.ErrNoRows jika statistik shop belum pernah dihitung.
+	FindShopStats(ctx context.Context, shopID uuid.UUID) (ShopStats, error)
+	IsFollowingShop(ctx context.Context, accountID, shopID uuid.UUID) (bool, error)
+
+	// --- Wishlist ---
+	// FindWishlist hanya mengembalikan listing yang tampil publik (published / sold).
+	FindWishlist(ctx context.Context, accountID uuid.UUID) ([]WishlistItem, error)
+	SaveWishlistItem(ctx context.Context, accountID, productID uuid.UUID) error
+	DeleteWishlistItem(ctx context.Context, accountID, productID uuid.UUID) error
+
+	// --- Saved Search ---
+	FindSavedSearches(ctx context.Context, accountID uuid.UUID) ([]model.SavedSearch, error)
+	// FindSavedSearch mengembalikan sql.ErrNoRows jika saved search bukan milik akun tersebut.
+	FindSavedSearch(ctx context.Context, accountID, searchID uuid.UUID) (model.SavedSearch, error)
+	CountSavedSearches(ctx context.Context, accountID uuid.UUID) (int, error)
+	SaveSavedSearch(ctx context.Context, search model.SavedSearch) (model.SavedSearch, error)
+	UpdateSavedSearch(ctx context.Context, search model.SavedSearch) (model.SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, accountID, searchID uuid.UUID) error
+	// TransactionMatchSavedSearches membaca listing yang baru tayang sejak checkpoint terakhir dan
+	// mencocokkannya hanya ke batch itu (bukan seluruh katalog), lalu mengantrikan hasilnya.
+	// Mengembalikan jumlah log status yang diproses (maksimal batchSize).
+	TransactionMatchSavedSearches(ctx context.Context, batchSize int) (int, error)
+	// TransactionDispatchSavedSearchAlerts mengirim antrian saved search instant, dan saved search
+	// daily yang belum menerima alert sejak digestSince, satu notifikasi per saved search lewat build.
+	TransactionDispatchSavedSearchAlerts(ctx context.Context, digestSince time.Time, maxSearches int, build SavedSearchAlertBuilder) (int, error)
+}
+
+// SavedSearchAlertBuilder menyusun notifikasi untuk satu saved search. items adalah sebagian
+// listing yang cocok (terbaru dulu), total adalah jumlah seluruhnya.
+type SavedSearchAlertBuilder func(search model.SavedSearch, items []SavedSearchMatch, total int) (model.Notification, error)
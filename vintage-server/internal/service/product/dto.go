@@ -0,0 +1,403 @@
+package product
+
+import (
+	"errors"
+	"time"
+	"vintage-server/internal/model"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrCategoryCycle dikembalikan repository jika sebuah move/merge akan membuat siklus di tree kategori.
+	ErrCategoryCycle = errors.New("category move would create a cycle")
+	// ErrReferenceInUse dikembalikan repository jika data referensi yang akan dihapus masih dipakai.
+	ErrReferenceInUse = errors.New("reference is still in use")
+	// ErrDuplicateName dikembalikan repository jika nama sudah dipakai data aktif lain.
+	ErrDuplicateName = errors.New("name already used")
+	// ErrListingChanged dikembalikan repository jika status listing berubah sejak dibaca service
+	// (misal di-approve admin saat seller sedang mengedit).
+	ErrListingChanged = errors.New("listing status changed concurrently")
+	// ErrTagNotFound dikembalikan repository jika tag yang dirujuk tidak ada (lagi).
+	ErrTagNotFound = errors.New("tag not found")
+	// ErrListingReserved dikembalikan repository jika listing yang akan dihapus masih di-hold checkout.
+	ErrListingReserved = errors.New("listing is reserved by an ongoing checkout")
+)
+
+// ReferenceKind menandai jenis data referensi produk yang dikelola admin.
+type ReferenceKind string
+
+const (
+	ReferenceBrand     ReferenceKind = "brand"
+	ReferenceCondition ReferenceKind = "condition"
+	ReferenceSize      ReferenceKind = "size"
+	ReferenceCategory  ReferenceKind = "category"
+)
+
+// CategoryResponse adalah bentuk data kategori yang dikirim ke client.
+type CategoryResponse struct {
+	ID       int    `json:"id"`
+	ParentID *int   `json:"parent_id"`
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+}
+
+// CategoryNode adalah satu node di tree kategori beserta anak-anaknya.
+type CategoryNode struct {
+	CategoryResponse
+	Children []CategoryNode `json:"children"`
+}
+
+type CreateCategoryRequest struct {
+	Name     string `json:"name" binding:"required,max=128"`
+	ParentID *int   `json:"parent_id"`
+}
+
+type RenameCategoryRequest struct {
+	Name string `json:"name" binding:"required,max=128"`
+}
+
+// MoveCategoryRequest memindahkan kategori ke parent baru. ParentID nil berarti jadi root.
+type MoveCategoryRequest struct {
+	ParentID *int `json:"parent_id"`
+}
+
+type BrandRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+type ConditionRequest struct {
+	Name string `json:"name" binding:"required,max=32"`
+	// GoogleCondition opsional, default "used" untuk kondisi baru
+	GoogleCondition string `json:"google_condition" binding:"omitempty,oneof=new refurbished used"`
+}
+
+type SizeRequest struct {
+	SizeName string `json:"size_name" binding:"required,max=32"`
+}
+
+// DeleteReferenceRequest dibaca dari query string, misal DELETE /admin/brands/3?merge_target_id=5.
+type DeleteReferenceRequest struct {
+	MergeTargetID *int `form:"merge_target_id"`
+}
+
+type MergeReferenceRequest struct {
+	TargetID int `json:"target_id" binding:"required"`
+}
+
+// ProductFilter adalah kumpulan filter untuk pencarian katalog.
+// Tag json dipakai saat filter disimpan sebagai saved search (tanpa pagination).
+type ProductFilter struct {
+	Query       string `form:"q" json:"q,omitempty"`
+	CategoryID  *int   `form:"category_id" json:"category_id,omitempty"`
+	BrandID     *int   `form:"brand_id" json:"brand_id,omitempty"`
+	SizeID      *int   `form:"size_id" json:"size_id,omitempty"`
+	MinPrice    *int64 `form:"min_price" json:"min_price,omitempty"`
+	MaxPrice    *int64 `form:"max_price" json:"max_price,omitempty"`
+	EraDecade   *int   `form:"era" json:"era,omitempty"`
+	Country     string `form:"country" json:"country,omitempty"`
+	LabelTypeID *int   `form:"label_type_id" json:"label_type_id,omitempty"`
+	MaterialID  *int   `form:"material_id" json:"material_id,omitempty"`
+	// Tags: produk harus punya semua tag yang diminta (?tag=denim&tag=made+in+usa)
+	Tags []string `form:"tag" json:"tags,omitempty"`
+	// IncludeSold ikut menampilkan listing yang sudah terjual (default hanya yang published)
+	IncludeSold bool `form:"include_sold" json:"include_sold,omitempty"`
+	// Measurements diisi handler dari query measurement[chest]=52-56 (nilai dalam cm).
+	Measurements map[string]MeasurementRange `form:"-" json:"measurements,omitempty"`
+	Page         int                         `form:"page" json:"-"`
+	Limit        int                         `form:"limit" json:"-"`
+}
+
+// MeasurementRange adalah rentang ukuran (cm) untuk filter pencarian. Batas nil berarti terbuka.
+type MeasurementRange struct {
+	MinCM *float64 `json:"min_cm,omitempty"`
+	MaxCM *float64 `json:"max_cm,omitempty"`
+}
+
+// ProductSummary adalah data ringkas produk untuk list / hasil pencarian.
+type ProductSummary struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	ShopID     uuid.UUID `json:"shop_id" db:"shop_id"`
+	CategoryID int       `json:"category_id" db:"category_id"`
+	BrandID    *int      `json:"brand_id" db:"brand_id"`
+	Name       string    `json:"name" db:"name"`
+	Price      int64     `json:"price" db:"price"`
+	ImageURL   *string   `json:"image_url" db:"image_url"`
+	// Status 'sold' ditampilkan client sebagai badge terjual
+	Status    string    `json:"status" db:"status"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	// FitsYou hanya diisi jika pembeli login dan punya ukuran yang bisa dibandingkan.
+	FitsYou *bool `json:"fits_you,omitempty" db:"-"`
+}
+
+type ProductSearchResponse struct {
+	Items []ProductSummary `json:"items"`
+	Total int64            `json:"total"`
+	Page  int              `json:"page"`
+	Limit int              `json:"limit"`
+}
+
+// MeasurementFieldResponse adalah satu field di skema ukuran sebuah kategori.
+type MeasurementFieldResponse struct {
+	Key            string  `json:"key" db:"key"`
+	Label          string  `json:"label" db:"label"`
+	MinCM          float64 `json:"min_cm" db:"min_cm"`
+	MaxCM          float64 `json:"max_cm" db:"max_cm"`
+	FitToleranceCM float64 `json:"fit_tolerance_cm" db:"fit_tolerance_cm"`
+	Required       bool    `json:"required" db:"required"`
+}
+
+type MeasurementSchemaField struct {
+	Key      string `json:"key" binding:"required"`
+	Required bool   `json:"required"`
+}
+
+// SetMeasurementSchemaRequest mengganti seluruh skema kategori. Urutan field = urutan tampil.
+type SetMeasurementSchemaRequest struct {
+	Fields []MeasurementSchemaField `json:"fields" binding:"dive"`
+}
+
+// MeasurementsRequest berisi ukuran per field dalam cm, misal {"chest": 54.5, "length": 70}.
+type MeasurementsRequest struct {
+	Measurements map[string]float64 `json:"measurements" binding:"required"`
+}
+
+type ProductMeasurementsResponse struct {
+	Measurements map[string]float64         `json:"measurements"`
+	Schema       []MeasurementFieldResponse `json:"schema"`
+	FitsYou      *bool                      `json:"fits_you,omitempty"`
+}
+
+type ProductMaterialInput struct {
+	MaterialID int    `json:"material_id" binding:"required"`
+	Percentage *int16 `json:"percentage"`
+}
+
+// ProductAttributesRequest mengganti seluruh atribut vintage sebuah produk.
+// Tag dinormalisasi service (huruf kecil, spasi tunggal) dan alias diarahkan ke tag kanonik.
+type ProductAttributesRequest struct {
+	EraDecade       *int16                 `json:"era_decade"`
+	CountryOfOrigin *string                `json:"country_of_origin"`
+	LabelTypeID     *int                   `json:"label_type_id"`
+	Materials       []ProductMaterialInput `json:"materials" binding:"dive"`
+	Tags            []string               `json:"tags"`
+}
+
+type ProductMaterialResponse struct {
+	MaterialID int    `json:"material_id" db:"material_id"`
+	Name       string `json:"name" db:"name"`
+	Percentage *int16 `json:"percentage" db:"percentage"`
+}
+
+type ProductAttributesResponse struct {
+	EraDecade       *int16                    `json:"era_decade" db:"era_decade"`
+	CountryOfOrigin *string                   `json:"country_of_origin" db:"country_of_origin"`
+	LabelTypeID     *int                      `json:"label_type_id" db:"label_type_id"`
+	LabelType       *string                   `json:"label_type" db:"label_type"`
+	Materials       []ProductMaterialResponse `json:"materials" db:"-"`
+	Tags            []string                  `json:"tags" db:"-"`
+}
+
+// TagFilter adalah query untuk browse tag.
+type TagFilter struct {
+	Query string `form:"q"`
+	Limit int    `form:"limit"`
+}
+
+// TagSummary adalah tag beserta jumlah produk yang memakainya.
+type TagSummary struct {
+	ID           int    `json:"id" db:"id"`
+	Name         string `json:"name" db:"name"`
+	ProductCount int64  `json:"product_count" db:"product_count"`
+}
+
+type MergeTagRequest struct {
+	TargetID int `json:"target_id" binding:"required"`
+}
+
+type TagSynonymRequest struct {
+	Alias string `json:"alias" binding:"required"`
+}
+
+// PriceHistoryFilter menentukan rentang riwayat harga yang diambil.
+type PriceHistoryFilter struct {
+	Days int `form:"days"`
+}
+
+// PricePoint adalah satu titik di grafik riwayat harga.
+type PricePoint struct {
+	Price     int64     `json:"price" db:"price"`
+	ChangedAt time.Time `json:"changed_at" db:"changed_at"`
+}
+
+// ListingPolicy mengatur moderasi listing untuk seller baru.
+type ListingPolicy struct {
+	ReviewEnabled bool
+	// TrustedAfter: seller yang sudah punya listing tayang sebanyak ini tidak perlu review lagi.
+	TrustedAfter int
+	// VerifiedPriceFrom: listing dengan harga >= nilai ini hanya bisa tayang dari shop yang lolos
+	// verifikasi KYC (0 = tanpa batas).
+	VerifiedPriceFrom int64
+}
+
+// ProductRequest dipakai untuk membuat draft maupun mengubah produk. Field nil berarti tidak diubah.
+// Kelengkapan data baru divalidasi saat listing disubmit atau sudah tayang.
+type ProductRequest struct {
+	Name        *string `json:"name"`
+	Summary     *string `json:"summary"`
+	Description *string `json:"description"`
+	CategoryID  *int    `json:"category_id"`
+	ConditionID *int16  `json:"condition_id"`
+	SizeID      *int    `json:"size_id"`
+	BrandID     *int    `json:"brand_id"`
+	Price       *int64  `json:"price"`
+	Stock       *int    `json:"stock"`
+}
+
+// ListingStatusRequest adalah perpindahan status yang diminta seller.
+// "published" dari draft berarti submit; bisa berakhir di pending_review jika perlu moderasi.
+type ListingStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+type ModerationDecisionRequest struct {
+	Reason string `json:"reason"`
+}
+
+type SellerProductFilter struct {
+	Status string `form:"status"`
+	Page   int    `form:"page"`
+	Limit  int    `form:"limit"`
+}
+
+type SellerProductPage struct {
+	Items []model.Product `json:"items"`
+	Total int64           `json:"total"`
+	Page  int             `json:"page"`
+	Limit int             `json:"limit"`
+}
+
+// ProductDetail adalah produk lengkap beserta URL gambar (urut image_index).
+type ProductDetail struct {
+	model.Product
+	Images []string `json:"images"`
+	// Diisi di halaman publik jika shop sedang libur: listing tampil tapi tidak bisa dibeli
+	ShopOnVacation  bool       `json:"shop_on_vacation"`
+	VacationMessage *string    `json:"vacation_message,omitempty"`
+	VacationEndsAt  *time.Time `json:"vacation_ends_at,omitempty"`
+}
+
+// ShopProfile adalah informasi publik sebuah shop.
+type ShopProfile struct {
+	ID             uuid.UUID `json:"id" db:"id"`
+	Name           string    `json:"name" db:"name"`
+	Slug           string    `json:"slug" db:"slug"`
+	Summary        *string   `json:"summary" db:"summary"`
+	Description    *string   `json:"description" db:"description"`
+	LogoURL        *string   `json:"logo_url" db:"logo_url"`
+	BannerURL      *string   `json:"banner_url" db:"banner_url"`
+	ShippingPolicy *string   `json:"shipping_policy" db:"shipping_policy"`
+	ReturnPolicy   *string   `json:"return_policy" db:"return_policy"`
+	PaymentPolicy  *string   `json:"payment_policy" db:"payment_policy"`
+	FollowerCount  int       `json:"follower_count" db:"follower_count"`
+	// Pesan & tanggal kembali hanya diisi selama shop sedang libur
+	OnVacation      bool       `json:"on_vacation" db:"vacation_active"`
+	VacationMessage *string    `json:"vacation_message,omitempty" db:"vacation_message"`
+	VacationEndsAt  *time.Time `json:"vacation_ends_at,omitempty" db:"vacation_ends_at"`
+	// MemberSince adalah tanggal akun seller terdaftar
+	MemberSince time.Time `json:"member_since" db:"member_since"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	Stats       ShopStats `json:"stats" db:"-"`
+	// Following hanya diisi jika pengunjung login
+	Following *bool `json:"following,omitempty" db:"-"`
+}
+
+// ShopStats adalah statistik publik shop dari tabel shop_stats (diperbarui berkala oleh shop-service).
+type ShopStats struct {
+	ActiveListings     int      `json:"active_listings" db:"active_listings"`
+	SoldListings       int      `json:"sold_listings" db:"sold_listings"`
+	RatingCount        int      `json:"rating_count" db:"rating_count"`
+	RatingAverage      *float64 `json:"rating_average" db:"rating_average"`
+	RatingDistribution `json:"rating_distribution"`
+	// ResponseRate adalah porsi offer yang dijawab seller (0..1) dalam 90 hari terakhir
+	ResponseRate *float64 `json:"response_rate" db:"response_rate"`
+	// MedianShipHours adalah median jam dari order dibayar sampai dikirim dalam 90 hari terakhir
+	MedianShipHours *float64   `json:"median_ship_hours" db:"median_ship_hours"`
+	RefreshedAt     *time.Time `json:"refreshed_at" db:"refreshed_at"`
+}
+
+// RatingDistribution adalah jumlah review per bintang.
+type RatingDistribution struct {
+	One   int `json:"1" db:"rating_1"`
+	Two   int `json:"2" db:"rating_2"`
+	Three int `json:"3" db:"rating_3"`
+	Four  int `json:"4" db:"rating_4"`
+	Five  int `json:"5" db:"rating_5"`
+}
+
+type PageRequest struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+// ModerationQueueItem adalah listing yang menunggu review admin.
+type ModerationQueueItem struct {
+	ProductID   uuid.UUID `json:"product_id" db:"product_id"`
+	ShopID      uuid.UUID `json:"shop_id" db:"shop_id"`
+	ShopName    string    `json:"shop_name" db:"shop_name"`
+	Name        string    `json:"name" db:"name"`
+	Price       int64     `json:"price" db:"price"`
+	ImageURL    *string   `json:"image_url" db:"image_url"`
+	SubmittedAt time.Time `json:"submitted_at" db:"submitted_at"`
+}
+
+type ModerationQueuePage struct {
+	Items []ModerationQueueItem `json:"items"`
+	Total int64                 `json:"total"`
+	Page  int                   `json:"page"`
+	Limit int                   `json:"limit"`
+}
+
+// ListingStatusChange adalah perubahan status yang akan disimpan repository.
+// From dipakai sebagai guard: update gagal (ErrListingChanged) jika status di DB sudah berbeda.
+type ListingStatusChange struct {
+	ProductID      uuid.UUID
+	From           string
+	To             string
+	ModerationNote *string
+	Reason         *string
+	ActorID        *uuid.UUID
+}
+
+// WishlistItem adalah produk di wishlist. Status dipakai client untuk badge terjual.
+type WishlistItem struct {
+	ProductID uuid.UUID `json:"product_id" db:"product_id"`
+	Name      string    `json:"name" db:"name"`
+	Price     int64     `json:"price" db:"price"`
+	ImageURL  *string   `json:"image_url" db:"image_url"`
+	Status    string    `json:"status" db:"status"`
+	AddedAt   time.Time `json:"added_at" db:"added_at"`
+}
+
+type WishlistRequest struct {
+	ProductID uuid.UUID `json:"product_id" binding:"required"`
+}
+
+// SavedSearchRequest menyimpan filter pencarian katalog yang dikirim lewat query string
+// (format sama dengan GET /products) dengan nama & frekuensi alert di body.
+type SavedSearchRequest struct {
+	Name  string `json:"name" binding:"required,max=100"`
+	Alert string `json:"alert" binding:"omitempty,oneof=instant daily off"`
+}
+
+type UpdateSavedSearchRequest struct {
+	Name  *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Alert *string `json:"alert" binding:"omitempty,oneof=instant daily off"`
+}
+
+// SavedSearchMatch adalah listing baru yang cocok dengan saved search (disimpan di kolom data notifikasi).
+type SavedSearchMatch struct {
+	ProductID uuid.UUID `json:"product_id" db:"product_id"`
+	Name      string    `json:"name" db:"name"`
+	Price     int64     `json:"price" db:"price"`
+}
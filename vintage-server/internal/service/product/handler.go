@@ -1 +1,1073 @@
 package product
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"vintage-server/internal/service/audit"
+	"vintage-server/pkg/middleware"
+	"vintage-server/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// publicCacheControl dipakai endpoint data referensi publik yang jarang berubah.
+const publicCacheControl = "public, max-age=300"
+
+// Handler adalah struct yang memegang dependency ke Service
+type Handler struct {
+	svc Service
+}
+
+// NewHandler adalah constructor untuk handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// --- Catalog ---
+
+// SearchProducts adalah handler untuk pencarian katalog produk
+func (h *Handler) SearchProducts(c *gin.Context) {
+	var filter ProductFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	measurements, err := parseMeasurementRanges(c.QueryMap("measurement"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	filter.Measurements = measurements
+
+	result, err := h.svc.SearchProducts(c.Request.Context(), filter, viewerID(c))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, result)
+}
+
+// GetProductBreadcrumb mengembalikan jalur kategori (root -> leaf) dari sebuah produk
+func (h *Handler) GetProductBreadcrumb(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid product id")
+		return
+	}
+
+	breadcrumb, err := h.svc.GetProductBreadcrumb(c.Request.Context(), productID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, breadcrumb)
+}
+
+// GetPriceHistory mengembalikan riwayat harga produk untuk grafik, ?days= (default 90)
+func (h *Handler) GetPriceHistory(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid product id")
+		return
+	}
+
+	var filter PriceHistoryFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	points, err := h.svc.GetPriceHistory(c.Request.Context(), productID, filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Header("Cache-Control", publicCacheControl)
+	response.Success(c, http.StatusOK, points)
+}
+
+// GetProduct mengembalikan detail produk yang tampil publik (published / sold)
+// Parameter :id boleh berupa UUID atau slug; slug lama diarahkan (301) ke slug terbaru.
+func (h *Handler) GetProduct(c *gin.Context) {
+	if productID, err := uuid.Parse(c.Param("id")); err == nil {
+		detail, err := h.svc.GetProduct(c.Request.Context(), productID)
+		if err != nil {
+			response.FromError(c, err)
+			return
+		}
+		response.Success(c, http.StatusOK, detail)
+		return
+	}
+
+	detail, redirect, err := h.svc.GetProductBySlug(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	if redirect != "" {
+		redirectToSlug(c, redirect)
+		return
+	}
+	response.Success(c, http.StatusOK, detail)
+}
+
+// GetShop mengembalikan profil publik shop lewat UUID atau slug; "following" ikut diisi jika login
+func (h *Handler) GetShop(c *gin.Context) {
+	var viewer *uuid.UUID
+	if id, ok := middleware.GetAccountID(c); ok {
+		viewer = &id
+	}
+
+	shop, redirect, err := h.svc.GetShop(c.Request.Context(), c.Param("slug"), viewer)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	if redirect != "" {
+		redirectToSlug(c, redirect)
+		return
+	}
+	response.Success(c, http.StatusOK, shop)
+}
+
+// redirectToSlug mengganti segmen terakhir path request dengan slug terbaru.
+func redirectToSlug(c *gin.Context, current string) {
+	location := path.Join(path.Dir(c.Request.URL.Path), current)
+	if c.Request.URL.RawQuery != "" {
+		location += "?" + c.Request.URL.RawQuery
+	}
+	c.Redirect(http.StatusMovedPermanently, location)
+}
+
+// --- Listing ---
+
+// GetSellerProducts mengembalikan listing milik seller, bisa difilter ?status=
+func (h *Handler) GetSellerProducts(c *gin.Context) {
+	sellerID, _ := middleware.GetAccountID(c)
+
+	var filter SellerProductFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	page, err := h.svc.GetSellerProducts(c.Request.Context(), sellerID, filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, page)
+}
+
+// GetSellerProduct mengembalikan detail listing milik seller (termasuk draft)
+func (h *Handler) GetSellerProduct(c *gin.Context) {
+	sellerID, _ := middleware.GetAccountID(c)
+
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid product id")
+		return
+	}
+
+	detail, err := h.svc.GetSellerProduct(c.Request.Context(), sellerID, productID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, detail)
+}
+
+// CreateProduct adalah handler seller untuk membuat listing baru (selalu draft)
+func (h *Handler) CreateProduct(c *gin.Context) {
+	sellerID, _ := middleware.GetAccountID(c)
+
+	var req ProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	product, err := h.svc.CreateProduct(c.Request.Context(), sellerID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusCreated, product)
+}
+
+// UpdateProduct adalah handler seller untuk mengubah sebagian field listing
+func (h *Handler) UpdateProduct(c *gin.Context) {
+	sellerID, _ := middleware.GetAccountID(c)
+
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid product id")
+		return
+	}
+
+	var req ProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	product, err := h.svc.UpdateProduct(c.Request.Context(), sellerID, productID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, product)
+}
+
+// ChangeListingStatus adalah handler seller untuk memindahkan status listing
+func (h *Handler) ChangeListingStatus(c *gin.Context) {
+	sellerID, _ := middleware.GetAccountID(c)
+
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid product id")
+		return
+	}
+
+	var req ListingStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	product, err := h.svc.ChangeListingStatus(c.Request.Context(), sellerID, productID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, product)
+}
+
+// DeleteProduct adalah handler seller untuk menghapus listing miliknya
+func (h *Handler) DeleteProduct(c *gin.Context) {
+	sellerID, _ := middleware.GetAccountID(c)
+
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid product id")
+		return
+	}
+
+	if err := h.svc.DeleteProduct(c.Request.Context(), sellerID, productID); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// --- Moderation ---
+
+// GetModerationQueue adalah handler admin untuk melihat listing yang menunggu review
+func (h *Handler) GetModerationQueue(c *gin.Context) {
+	var page PageRequest
+	if err := c.ShouldBindQuery(&page); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	queue, err := h.svc.GetModerationQueue(c.Request.Context(), page)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, queue)
+}
+
+// ApproveListing adalah handler admin untuk menayangkan listing yang sedang direview
+func (h *Handler) ApproveListing(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid product id")
+		return
+	}
+
+	product, err := h.svc.ApproveListing(c.Request.Context(), audit.ActorFromContext(c), productID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, product)
+}
+
+// RejectListing adalah handler admin untuk mengembalikan listing ke draft beserta alasannya
+func (h *Handler) RejectListing(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid product id")
+		return
+	}
+
+	var req ModerationDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	product, err := h.svc.RejectListing(c.Request.Context(), audit.ActorFromContext(c), productID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, product)
+}
+
+// --- Wishlist ---
+
+// GetWishlist mengembalikan wishlist milik user yang login
+func (h *Handler) GetWishlist(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	items, err := h.svc.GetWishlist(c.Request.Context(), accountID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, items)
+}
+
+// AddToWishlist menambahkan produk yang sedang tayang ke wishlist
+func (h *Handler) AddToWishlist(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var req WishlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	items, err := h.svc.AddToWishlist(c.Request.Context(), accountID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, items)
+}
+
+// RemoveFromWishlist menghapus produk dari wishlist
+func (h *Handler) RemoveFromWishlist(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	productID, err := uuid.Parse(c.Param("product_id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid product id")
+		return
+	}
+
+	items, err := h.svc.RemoveFromWishlist(c.Request.Context(), accountID, productID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, items)
+}
+
+// --- Saved Search ---
+
+// GetSavedSearches mengembalikan saved search milik user yang login
+func (h *Handler) GetSavedSearches(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	searches, err := h.svc.GetSavedSearches(c.Request.Context(), accountID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, searches)
+}
+
+// SaveSearch menyimpan filter pencarian. Filter dibaca dari query string (sama persis dengan
+// GET /products), sedangkan nama dan mode alert dari body.
+func (h *Handler) SaveSearch(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var filter ProductFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+	measurements, err := parseMeasurementRanges(c.QueryMap("measurement"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	filter.Measurements = measurements
+
+	var req SavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	search, err := h.svc.SaveSearch(c.Request.Context(), accountID, filter, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusCreated, search)
+}
+
+// UpdateSavedSearch mengubah nama atau mode alert saved search
+func (h *Handler) UpdateSavedSearch(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	searchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid saved search id")
+		return
+	}
+
+	var req UpdateSavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	search, err := h.svc.UpdateSavedSearch(c.Request.Context(), accountID, searchID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, search)
+}
+
+// DeleteSavedSearch menghapus saved search beserta antrian alert-nya
+func (h *Handler) DeleteSavedSearch(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	searchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid saved search id")
+		return
+	}
+
+	if err := h.svc.DeleteSavedSearch(c.Request.Context(), accountID, searchID); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RunSavedSearch menjalankan ulang filter saved search terhadap katalog saat ini
+func (h *Handler) RunSavedSearch(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	searchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid saved search id")
+		return
+	}
+
+	var page PageRequest
+	if err := c.ShouldBindQuery(&page); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.svc.RunSavedSearch(c.Request.Context(), accountID, searchID, page)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, result)
+}
+
+// --- Category ---
+
+// GetCategoryTree mengembalikan seluruh kategori dalam bentuk tree
+func (h *Handler) GetCategoryTree(c *gin.Context) {
+	tree, err := h.svc.GetCategoryTree(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Header("Cache-Control", publicCacheControl)
+	response.Success(c, http.StatusOK, tree)
+}
+
+// CreateCategory adalah handler admin untuk membuat kategori baru
+func (h *Handler) CreateCategory(c *gin.Context) {
+	var req CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	category, err := h.svc.CreateCategory(c.Request.Context(), audit.ActorFromContext(c), req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusCreated, category)
+}
+
+// RenameCategory adalah handler admin untuk mengganti nama kategori
+func (h *Handler) RenameCategory(c *gin.Context) {
+	categoryID, ok := parseIntParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req RenameCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	category, err := h.svc.RenameCategory(c.Request.Context(), audit.ActorFromContext(c), categoryID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, category)
+}
+
+// MoveCategory adalah handler admin untuk memindahkan kategori ke parent lain
+func (h *Handler) MoveCategory(c *gin.Context) {
+	categoryID, ok := parseIntParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req MoveCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	category, err := h.svc.MoveCategory(c.Request.Context(), audit.ActorFromContext(c), categoryID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, category)
+}
+
+// --- Reference Data ---
+
+// GetBrands mengembalikan semua brand aktif (public, di-cache)
+func (h *Handler) GetBrands(c *gin.Context) {
+	brands, err := h.svc.GetBrands(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Header("Cache-Control", publicCacheControl)
+	response.Success(c, http.StatusOK, brands)
+}
+
+// GetConditions mengembalikan semua kondisi produk aktif (public, di-cache)
+func (h *Handler) GetConditions(c *gin.Context) {
+	conditions, err := h.svc.GetConditions(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Header("Cache-Control", publicCacheControl)
+	response.Success(c, http.StatusOK, conditions)
+}
+
+// GetSizes mengembalikan semua ukuran aktif (public, di-cache)
+func (h *Handler) GetSizes(c *gin.Context) {
+	sizes, err := h.svc.GetSizes(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Header("Cache-Control", publicCacheControl)
+	response.Success(c, http.StatusOK, sizes)
+}
+
+// GetCategories mengembalikan semua kategori aktif dalam bentuk list datar (public, di-cache)
+func (h *Handler) GetCategories(c *gin.Context) {
+	categories, err := h.svc.GetCategories(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Header("Cache-Control", publicCacheControl)
+	response.Success(c, http.StatusOK, categories)
+}
+
+// CreateBrand adalah handler admin untuk membuat brand baru
+func (h *Handler) CreateBrand(c *gin.Context) {
+	var req BrandRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	brand, err := h.svc.CreateBrand(c.Request.Context(), audit.ActorFromContext(c), req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusCreated, brand)
+}
+
+// UpdateBrand adalah handler admin untuk mengubah brand
+func (h *Handler) UpdateBrand(c *gin.Context) {
+	brandID, ok := parseIntParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req BrandRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	brand, err := h.svc.UpdateBrand(c.Request.Context(), audit.ActorFromContext(c), brandID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, brand)
+}
+
+// UploadBrandLogo adalah handler admin untuk upload logo brand (multipart field "logo")
+func (h *Handler) UploadBrandLogo(c *gin.Context) {
+	brandID, ok := parseIntParam(c, "id")
+	if !ok {
+		return
+	}
+
+	file, err := c.FormFile("logo")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Logo file is required")
+		return
+	}
+
+	brand, err := h.svc.UploadBrandLogo(c.Request.Context(), audit.ActorFromContext(c), brandID, file)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, brand)
+}
+
+// CreateCondition adalah handler admin untuk membuat kondisi produk baru
+func (h *Handler) CreateCondition(c *gin.Context) {
+	var req ConditionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	condition, err := h.svc.CreateCondition(c.Request.Context(), audit.ActorFromContext(c), req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusCreated, condition)
+}
+
+// UpdateCondition adalah handler admin untuk mengubah kondisi produk
+func (h *Handler) UpdateCondition(c *gin.Context) {
+	conditionID, ok := parseIntParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req ConditionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	condition, err := h.svc.UpdateCondition(c.Request.Context(), audit.ActorFromContext(c), conditionID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, condition)
+}
+
+// CreateSize adalah handler admin untuk membuat ukuran baru
+func (h *Handler) CreateSize(c *gin.Context) {
+	var req SizeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	size, err := h.svc.CreateSize(c.Request.Context(), audit.ActorFromContext(c), req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusCreated, size)
+}
+
+// UpdateSize adalah handler admin untuk mengubah ukuran
+func (h *Handler) UpdateSize(c *gin.Context) {
+	sizeID, ok := parseIntParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req SizeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	size, err := h.svc.UpdateSize(c.Request.Context(), audit.ActorFromContext(c), sizeID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, size)
+}
+
+// DeleteReference membuat handler admin untuk soft delete data referensi jenis tertentu.
+// Query ?merge_target_id= bisa diisi untuk memindahkan produk sebelum dihapus.
+func (h *Handler) DeleteReference(kind ReferenceKind) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseIntParam(c, "id")
+		if !ok {
+			return
+		}
+
+		var req DeleteReferenceRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+			return
+		}
+
+		if err := h.svc.DeleteReference(c.Request.Context(), audit.ActorFromContext(c), kind, id, req); err != nil {
+			response.FromError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// MergeReference membuat handler admin untuk menggabungkan data referensi jenis tertentu ke target.
+func (h *Handler) MergeReference(kind ReferenceKind) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sourceID, ok := parseIntParam(c, "id")
+		if !ok {
+			return
+		}
+
+		var req MergeReferenceRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		if err := h.svc.MergeReference(c.Request.Context(), audit.ActorFromContext(c), kind, sourceID, req); err != nil {
+			response.FromError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// --- Vintage Attributes & Tags ---
+
+// GetLabelTypes mengembalikan daftar jenis label (woven, printed, dst.)
+func (h *Handler) GetLabelTypes(c *gin.Context) {
+	labelTypes, err := h.svc.GetLabelTypes(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Header("Cache-Control", publicCacheControl)
+	response.Success(c, http.StatusOK, labelTypes)
+}
+
+// GetMaterials mengembalikan daftar bahan yang bisa dipilih seller
+func (h *Handler) GetMaterials(c *gin.Context) {
+	materials, err := h.svc.GetMaterials(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Header("Cache-Control", publicCacheControl)
+	response.Success(c, http.StatusOK, materials)
+}
+
+// GetProductAttributes mengembalikan atribut vintage sebuah produk
+func (h *Handler) GetProductAttributes(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid product id")
+		return
+	}
+
+	attrs, err := h.svc.GetProductAttributes(c.Request.Context(), productID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, attrs)
+}
+
+// SetProductAttributes adalah handler seller untuk mengganti atribut vintage produk miliknya
+func (h *Handler) SetProductAttributes(c *gin.Context) {
+	sellerID, _ := middleware.GetAccountID(c)
+
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid product id")
+		return
+	}
+
+	var req ProductAttributesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	attrs, err := h.svc.SetProductAttributes(c.Request.Context(), sellerID, productID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, attrs)
+}
+
+// BrowseTags mengembalikan tag populer, bisa difilter dengan prefix ?q=
+func (h *Handler) BrowseTags(c *gin.Context) {
+	var filter TagFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	tags, err := h.svc.BrowseTags(c.Request.Context(), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, tags)
+}
+
+// MergeTag adalah handler admin untuk menggabungkan tag ke tag lain
+func (h *Handler) MergeTag(c *gin.Context) {
+	sourceID, ok := parseIntParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req MergeTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.svc.MergeTag(c.Request.Context(), audit.ActorFromContext(c), sourceID, req); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AddTagSynonym adalah handler admin untuk menambah alias sebuah tag
+func (h *Handler) AddTagSynonym(c *gin.Context) {
+	tagID, ok := parseIntParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req TagSynonymRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	synonym, err := h.svc.AddTagSynonym(c.Request.Context(), audit.ActorFromContext(c), tagID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusCreated, synonym)
+}
+
+// DeleteTagSynonym adalah handler admin untuk menghapus alias tag
+func (h *Handler) DeleteTagSynonym(c *gin.Context) {
+	if err := h.svc.DeleteTagSynonym(c.Request.Context(), audit.ActorFromContext(c), c.Param("alias")); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// --- Measurement ---
+
+// GetMeasurementFields mengembalikan semua field ukuran yang dikenal beserta batas validasinya
+func (h *Handler) GetMeasurementFields(c *gin.Context) {
+	fields, err := h.svc.GetMeasurementFields(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Header("Cache-Control", publicCacheControl)
+	response.Success(c, http.StatusOK, fields)
+}
+
+// GetCategoryMeasurementSchema mengembalikan field ukuran yang dipakai sebuah kategori
+func (h *Handler) GetCategoryMeasurementSchema(c *gin.Context) {
+	categoryID, ok := parseIntParam(c, "id")
+	if !ok {
+		return
+	}
+
+	schema, err := h.svc.GetCategoryMeasurementSchema(c.Request.Context(), categoryID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, schema)
+}
+
+// SetCategoryMeasurementSchema adalah handler admin untuk mengganti skema ukuran kategori
+func (h *Handler) SetCategoryMeasurementSchema(c *gin.Context) {
+	categoryID, ok := parseIntParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req SetMeasurementSchemaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	schema, err := h.svc.SetCategoryMeasurementSchema(c.Request.Context(), audit.ActorFromContext(c), categoryID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, schema)
+}
+
+// GetProductMeasurements mengembalikan ukuran produk (dan flag fits_you jika pembeli login)
+func (h *Handler) GetProductMeasurements(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid product id")
+		return
+	}
+
+	result, err := h.svc.GetProductMeasurements(c.Request.Context(), productID, viewerID(c))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, result)
+}
+
+// SetProductMeasurements adalah handler seller untuk mengisi ukuran produk miliknya
+func (h *Handler) SetProductMeasurements(c *gin.Context) {
+	sellerID, _ := middleware.GetAccountID(c)
+
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid product id")
+		return
+	}
+
+	var req MeasurementsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.svc.SetProductMeasurements(c.Request.Context(), sellerID, productID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, result)
+}
+
+// GetMyMeasurements mengembalikan ukuran tersimpan milik user yang login
+func (h *Handler) GetMyMeasurements(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	measurements, err := h.svc.GetMyMeasurements(c.Request.Context(), accountID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, measurements)
+}
+
+// SetMyMeasurements mengganti ukuran tersimpan milik user yang login
+func (h *Handler) SetMyMeasurements(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var req MeasurementsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	measurements, err := h.svc.SetMyMeasurements(c.Request.Context(), accountID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, measurements)
+}
+
+// viewerID mengembalikan ID akun jika request membawa token valid (lewat OptionalAuth).
+func viewerID(c *gin.Context) *uuid.UUID {
+	if id, ok := middleware.GetAccountID(c); ok {
+		return &id
+	}
+	return nil
+}
+
+// parseMeasurementRanges mem-parse query measurement[key]=min-max (cm).
+// Salah satu batas boleh kosong: "52-" (minimal 52) atau "-56" (maksimal 56).
+func parseMeasurementRanges(raw map[string]string) (map[string]MeasurementRange, error) {
+	ranges := make(map[string]MeasurementRange, len(raw))
+	for key, value := range raw {
+		minText, maxText, found := strings.Cut(value, "-")
+		if !found || (minText == "" && maxText == "") {
+			return nil, fmt.Errorf("Invalid range for measurement %q", key)
+		}
+
+		var rng MeasurementRange
+		if minText != "" {
+			v, err := strconv.ParseFloat(strings.TrimSpace(minText), 64)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid range for measurement %q", key)
+			}
+			rng.MinCM = &v
+		}
+		if maxText != "" {
+			v, err := strconv.ParseFloat(strings.TrimSpace(maxText), 64)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid range for measurement %q", key)
+			}
+			rng.MaxCM = &v
+		}
+		ranges[key] = rng
+	}
+	return ranges, nil
+}
+
+// parseIntParam membaca path param integer, mengirim 400 jika tidak valid.
+func parseIntParam(c *gin.Context, name string) (int, bool) {
+	value, err := strconv.Atoi(c.Param(name))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid "+name)
+		return 0, false
+	}
+	return value, true
+}
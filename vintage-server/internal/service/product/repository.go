@@ -1 +1,1425 @@
 package product
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/audit"
+	"vintage-server/internal/service/inventory"
+	"vintage-server/internal/service/notification"
+	"vintage-server/internal/service/staff"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// categoryTreeLockKey dipakai sebagai advisory lock agar move/merge kategori berjalan serial.
+// Tanpa ini, dua move bersamaan (A ke bawah B dan B ke bawah A) bisa lolos cek siklus.
+const categoryTreeLockKey = "product_categories_tree"
+
+// savedSearchCheckpoint adalah nama baris di job_checkpoints untuk job pencocokan saved search.
+const savedSearchCheckpoint = "saved_search_alerts"
+
+// maxSavedSearchAlertItems adalah jumlah listing yang dimuat di data satu notifikasi saved search.
+const maxSavedSearchAlertItems = 10
+
+// descendantsCTE mengembalikan id sebuah kategori beserta seluruh turunannya.
+// Placeholder %s diisi dengan parameter posisi (misal "$1").
+const descendantsCTE = `
+	WITH RECURSIVE sub AS (
+		SELECT id FROM product_categories WHERE id = %s
+		UNION ALL
+		SELECT c.id FROM product_categories c JOIN sub ON c.parent_id = sub.id
+		WHERE c.deleted_at IS NULL
+	)
+	SELECT id FROM sub`
+
+// publicStatuses adalah status listing yang boleh dilihat publik. Listing sold tetap tampil dengan badge.
+const publicStatuses = "('published', 'sold')"
+
+// activeShop menyaring listing (alias p) milik shop yang sedang aktif; shop yang belum disetujui
+// atau disuspend tidak tampil di katalog.
+const activeShop = "p.shop_id IN (SELECT id FROM shop WHERE active)"
+
+// referenceTable memetakan jenis data referensi ke tabel & kolom FK-nya di tabel products.
+type referenceTable struct {
+	table         string
+	productColumn string
+}
+
+var referenceTables = map[ReferenceKind]referenceTable{
+	ReferenceBrand:     {table: "brands", productColumn: "brand_id"},
+	ReferenceCondition: {table: "product_conditions", productColumn: "condition_id"},
+	ReferenceSize:      {table: "product_size", productColumn: "size_id"},
+	ReferenceCategory:  {table: "product_categories", productColumn: "category_id"},
+}
+
+// repository adalah struct yang mengimplementasikan kontrak Repository dari domain.go
+type repository struct {
+	db        *sqlx.DB
+	inventory inventory.Repository
+}
+
+// NewRepository adalah constructor untuk implementasi repository
+func NewRepository(db *sqlx.DB, inv inventory.Repository) Repository {
+	return &repository{
+		db:        db,
+		inventory: inv,
+	}
+}
+
+// --- Category ---
+
+func (r *repository) FindAllCategories(ctx context.Context) ([]model.ProductCategory, error) {
+	var categories []model.ProductCategory
+	query := "SELECT * FROM product_categories WHERE deleted_at IS NULL ORDER BY name ASC"
+	err := r.db.SelectContext(ctx, &categories, query)
+	return categories, err
+}
+
+func (r *repository) FindCategoryByID(ctx context.Context, id int) (model.ProductCategory, error) {
+	var category model.ProductCategory
+	query := "SELECT * FROM product_categories WHERE id = $1 AND deleted_at IS NULL"
+	err := r.db.GetContext(ctx, &category, query, id)
+	return category, err
+}
+
+func (r *repository) FindCategoryAncestors(ctx context.Context, id int) ([]model.ProductCategory, error) {
+	var categories []model.ProductCategory
+	// depth dihitung naik dari kategori itu sendiri, lalu dibalik supaya root ada di depan.
+	query := `
+		WITH RECURSIVE chain AS (
+			SELECT c.*, 0 AS depth FROM product_categories c WHERE c.id = $1
+			UNION ALL
+			SELECT p.*, chain.depth + 1 FROM product_categories p JOIN chain ON p.id = chain.parent_id
+		)
+		SELECT id, parent_id, name, slug, created_at, updated_at, deleted_at
+		FROM chain
+		ORDER BY depth DESC`
+	err := r.db.SelectContext(ctx, &categories, query, id)
+	return categories, err
+}
+
+func (r *repository) IsCategorySlugUsed(ctx context.Context, slug string) (bool, error) {
+	var exists bool
+	query := "SELECT EXISTS (SELECT 1 FROM product_categories WHERE slug = $1)"
+	err := r.db.GetContext(ctx, &exists, query, slug)
+	return exists, err
+}
+
+func (r *repository) SaveCategory(ctx context.Context, category model.ProductCategory, entry model.AdminLog) (model.ProductCategory, error) {
+	var saved model.ProductCategory
+	query := `
+		INSERT INTO product_categories (parent_id, name, slug, created_at, updated_at)
+		VALUES (:parent_id, :name, :slug, :created_at, :updated_at)
+		RETURNING *`
+
+	err := r.withAdminLog(ctx, entry, func(tx *sqlx.Tx) error {
+		return namedGet(ctx, tx, &saved, query, category)
+	})
+	return saved, err
+}
+
+func (r *repository) UpdateCategoryName(ctx context.Context, category model.ProductCategory, entry model.AdminLog) (model.ProductCategory, error) {
+	var updated model.ProductCategory
+	query := `
+		UPDATE product_categories SET
+			name = :name,
+			slug = :slug,
+			updated_at = :updated_at
+		WHERE id = :id AND deleted_at IS NULL
+		RETURNING *`
+
+	err := r.withAdminLog(ctx, entry, func(tx *sqlx.Tx) error {
+		return namedGet(ctx, tx, &updated, query, category)
+	})
+	return updated, err
+}
+
+func (r *repository) TransactionMoveCategory(ctx context.Context, categoryID int, newParentID *int, entry model.AdminLog) (model.ProductCategory, error) {
+	var moved model.ProductCategory
+	err := r.withAdminLog(ctx, entry, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", categoryTreeLockKey); err != nil {
+			return err
+		}
+
+		// Parent baru tidak boleh kategori itu sendiri atau salah satu turunannya
+		if newParentID != nil {
+			cycle, err := isDescendant(ctx, tx, categoryID, *newParentID)
+			if err != nil {
+				return err
+			}
+			if cycle {
+				return ErrCategoryCycle
+			}
+		}
+
+		query := `
+			UPDATE product_categories SET parent_id = $1, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $2 AND deleted_at IS NULL
+			RETURNING *`
+		return tx.GetContext(ctx, &moved, query, newParentID, categoryID)
+	})
+	return moved, err
+}
+
+// isDescendant mengecek apakah candidateID adalah rootID itu sendiri atau turunannya.
+func isDescendant(ctx context.Context, tx *sqlx.Tx, rootID, candidateID int) (bool, error) {
+	var exists bool
+	query := fmt.Sprintf("SELECT $2::INT IN (%s)", fmt.Sprintf(descendantsCTE, "$1"))
+	err := tx.GetContext(ctx, &exists, query, rootID, candidateID)
+	return exists, err
+}
+
+// --- Reference Data ---
+
+func (r *repository) FindActiveBrands(ctx context.Context) ([]model.Brand, error) {
+	var brands []model.Brand
+	query := "SELECT * FROM brands WHERE deleted_at IS NULL ORDER BY name ASC"
+	err := r.db.SelectContext(ctx, &brands, query)
+	return brands, err
+}
+
+func (r *repository) FindBrandByID(ctx context.Context, id int) (model.Brand, error) {
+	var brand model.Brand
+	query := "SELECT * FROM brands WHERE id = $1 AND deleted_at IS NULL"
+	err := r.db.GetContext(ctx, &brand, query, id)
+	return brand, err
+}
+
+func (r *repository) SaveBrand(ctx context.Context, brand model.Brand, entry model.AdminLog) (model.Brand, error) {
+	var saved model.Brand
+	query := `
+		INSERT INTO brands (name, logo_url, created_at, updated_at)
+		VALUES (:name, :logo_url, :created_at, :updated_at)
+		RETURNING *`
+
+	err := r.withAdminLog(ctx, entry, func(tx *sqlx.Tx) error {
+		return namedGet(ctx, tx, &saved, query, brand)
+	})
+	return saved, err
+}
+
+func (r *repository) UpdateBrand(ctx context.Context, brand model.Brand, entry model.AdminLog) (model.Brand, error) {
+	var updated model.Brand
+	query := `
+		UPDATE brands SET
+			name = :name,
+			logo_url = :logo_url,
+			updated_at = :updated_at
+		WHERE id = :id AND deleted_at IS NULL
+		RETURNING *`
+
+	err := r.withAdminLog(ctx, entry, func(tx *sqlx.Tx) error {
+		return namedGet(ctx, tx, &updated, query, brand)
+	})
+	return updated, err
+}
+
+func (r *repository) FindActiveConditions(ctx context.Context) ([]model.ProductCondition, error) {
+	var conditions []model.ProductCondition
+	query := "SELECT * FROM product_conditions WHERE deleted_at IS NULL ORDER BY id ASC"
+	err := r.db.SelectContext(ctx, &conditions, query)
+	return conditions, err
+}
+
+func (r *repository) FindConditionByID(ctx context.Context, id int) (model.ProductCondition, error) {
+	var condition model.ProductCondition
+	query := "SELECT * FROM product_conditions WHERE id = $1 AND deleted_at IS NULL"
+	err := r.db.GetContext(ctx, &condition, query, id)
+	return condition, err
+}
+
+func (r *repository) SaveCondition(ctx context.Context, condition model.ProductCondition, entry model.AdminLog) (model.ProductCondition, error) {
+	var saved model.ProductCondition
+	query := `
+		INSERT INTO product_conditions (name, google_condition, created_at, updated_at)
+		VALUES (:name, :google_condition, :created_at, :updated_at)
+		RETURNING *`
+
+	err := r.withAdminLog(ctx, entry, func(tx *sqlx.Tx) error {
+		return namedGet(ctx, tx, &saved, query, condition)
+	})
+	return saved, err
+}
+
+func (r *repository) UpdateCondition(ctx context.Context, condition model.ProductCondition, entry model.AdminLog) (model.ProductCondition, error) {
+	var updated model.ProductCondition
+	query := `
+		UPDATE product_conditions SET
+			name = :name,
+			google_condition = :google_condition,
+			updated_at = :updated_at
+		WHERE id = :id AND deleted_at IS NULL
+		RETURNING *`
+
+	err := r.withAdminLog(ctx, entry, func(tx *sqlx.Tx) error {
+		return namedGet(ctx, tx, &updated, query, condition)
+	})
+	return updated, err
+}
+
+func (r *repository) FindActiveSizes(ctx context.Context) ([]model.ProductSize, error) {
+	var sizes []model.ProductSize
+	query := "SELECT * FROM product_size WHERE deleted_at IS NULL ORDER BY id ASC"
+	err := r.db.SelectContext(ctx, &sizes, query)
+	return sizes, err
+}
+
+func (r *repository) FindSizeByID(ctx context.Context, id int) (model.ProductSize, error) {
+	var size model.ProductSize
+	query := "SELECT * FROM product_size WHERE id = $1 AND deleted_at IS NULL"
+	err := r.db.GetContext(ctx, &size, query, id)
+	return size, err
+}
+
+func (r *repository) SaveSize(ctx context.Context, size model.ProductSize, entry model.AdminLog) (model.ProductSize, error) {
+	var saved model.ProductSize
+	query := `
+		INSERT INTO product_size (size_name, created_at, updated_at)
+		VALUES (:size_name, :created_at, :updated_at)
+		RETURNING *`
+
+	err := r.withAdminLog(ctx, entry, func(tx *sqlx.Tx) error {
+		return namedGet(ctx, tx, &saved, query, size)
+	})
+	return saved, err
+}
+
+func (r *repository) UpdateSize(ctx context.Context, size model.ProductSize, entry model.AdminLog) (model.ProductSize, error) {
+	var updated model.ProductSize
+	query := `
+		UPDATE product_size SET
+			size_name = :size_name,
+			updated_at = :updated_at
+		WHERE id = :id AND deleted_at IS NULL
+		RETURNING *`
+
+	err := r.withAdminLog(ctx, entry, func(tx *sqlx.Tx) error {
+		return namedGet(ctx, tx, &updated, query, size)
+	})
+	return updated, err
+}
+
+func (r *repository) TransactionSoftDeleteReference(ctx context.Context, kind ReferenceKind, id int, entry model.AdminLog) error {
+	ref := referenceTables[kind]
+	return r.withAdminLog(ctx, entry, func(tx *sqlx.Tx) error {
+		// Lock baris referensi: INSERT produk baru yang merujuk baris ini akan menunggu
+		// (FOR KEY SHARE dari FK bentrok dengan FOR UPDATE), jadi hitungan di bawah tetap valid.
+		var locked int
+		lockQuery := fmt.Sprintf("SELECT id FROM %s WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", ref.table)
+		if err := tx.GetContext(ctx, &locked, lockQuery, id); err != nil {
+			return err
+		}
+
+		var inUse bool
+		usageQuery := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM products WHERE %s = $1)", ref.productColumn)
+		if err := tx.GetContext(ctx, &inUse, usageQuery, id); err != nil {
+			return err
+		}
+		if !inUse && kind == ReferenceCategory {
+			childQuery := "SELECT EXISTS (SELECT 1 FROM product_categories WHERE parent_id = $1 AND deleted_at IS NULL)"
+			if err := tx.GetContext(ctx, &inUse, childQuery, id); err != nil {
+				return err
+			}
+		}
+		if inUse {
+			return ErrReferenceInUse
+		}
+
+		deleteQuery := fmt.Sprintf("UPDATE %s SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1", ref.table)
+		_, err := tx.ExecContext(ctx, deleteQuery, id)
+		return err
+	})
+}
+
+func (r *repository) TransactionMergeReference(ctx context.Context, kind ReferenceKind, sourceID, targetID int, entry model.AdminLog) error {
+	ref := referenceTables[kind]
+	return r.withAdminLog(ctx, entry, func(tx *sqlx.Tx) error {
+		if kind == ReferenceCategory {
+			if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", categoryTreeLockKey); err != nil {
+				return err
+			}
+			// Sub-kategori source akan dipindah ke target, jadi target tidak boleh turunan source
+			cycle, err := isDescendant(ctx, tx, sourceID, targetID)
+			if err != nil {
+				return err
+			}
+			if cycle {
+				return ErrCategoryCycle
+			}
+		}
+
+		// Lock source & target supaya tidak ada produk baru yang nyangkut di source
+		var locked []int
+		lockQuery := fmt.Sprintf("SELECT id FROM %s WHERE id IN ($1, $2) AND deleted_at IS NULL FOR UPDATE", ref.table)
+		if err := tx.SelectContext(ctx, &locked, lockQuery, sourceID, targetID); err != nil {
+			return err
+		}
+		if len(locked) != 2 {
+			return sql.ErrNoRows
+		}
+
+		// Query 1: Arahkan semua produk ke target
+		repointQuery := fmt.Sprintf("UPDATE products SET %[1]s = $1, updated_at = CURRENT_TIMESTAMP WHERE %[1]s = $2", ref.productColumn)
+		if _, err := tx.ExecContext(ctx, repointQuery, targetID, sourceID); err != nil {
+			return err
+		}
+
+		// Query 2: Khusus kategori, pindahkan sub-kategori langsung ke bawah target
+		if kind == ReferenceCategory {
+			if _, err := tx.ExecContext(ctx, "UPDATE product_categories SET parent_id = $1, updated_at = CURRENT_TIMESTAMP WHERE parent_id = $2", targetID, sourceID); err != nil {
+				return err
+			}
+		}
+
+		// Query 3: Soft delete source
+		deleteQuery := fmt.Sprintf("UPDATE %s SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1", ref.table)
+		_, err := tx.ExecContext(ctx, deleteQuery, sourceID)
+		return err
+	})
+}
+
+// withAdminLog menjalankan fn di dalam transaksi lalu mencatat entry ke admin_logs di transaksi yang sama.
+// Pelanggaran unique constraint diterjemahkan menjadi ErrDuplicateName.
+func (r *repository) withAdminLog(ctx context.Context, entry model.AdminLog, fn func(tx *sqlx.Tx) error) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return ErrDuplicateName
+		}
+		return err
+	}
+	if err := audit.SaveAdminLog(ctx, tx, entry); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// namedGet menjalankan query ber-parameter nama di dalam transaksi dan men-scan satu baris hasil.
+func namedGet(ctx context.Context, tx *sqlx.Tx, dest any, query string, arg any) error {
+	bound, args, err := tx.BindNamed(query, arg)
+	if err != nil {
+		return err
+	}
+	return tx.GetContext(ctx, dest, bound, args...)
+}
+
+// --- Vintage Attributes & Tags ---
+
+func (r *repository) FindLabelTypes(ctx context.Context) ([]model.LabelType, error) {
+	var labelTypes []model.LabelType
+	query := "SELECT id, name, created_at FROM label_types ORDER BY id"
+	err := r.db.SelectContext(ctx, &labelTypes, query)
+	return labelTypes, err
+}
+
+func (r *repository) FindMaterials(ctx context.Context) ([]model.Material, error) {
+	var materials []model.Material
+	query := "SELECT id, name, created_at FROM materials ORDER BY name"
+	err := r.db.SelectContext(ctx, &materials, query)
+	return materials, err
+}
+
+func (r *repository) FindProductAttributes(ctx context.Context, productID uuid.UUID) (ProductAttributesResponse, error) {
+	var attrs ProductAttributesResponse
+
+	// Query 1: Kolom atribut di tabel products
+	query := `
+		SELECT p.era_decade, p.country_of_origin, p.label_type_id, lt.name AS label_type
+		FROM products p
+		LEFT JOIN label_types lt ON lt.id = p.label_type_id
+		WHERE p.id = $1`
+	if err := r.db.GetContext(ctx, &attrs, query, productID); err != nil {
+		return attrs, err
+	}
+
+	// Query 2: Komposisi bahan, persentase terbesar di depan
+	attrs.Materials = []ProductMaterialResponse{}
+	materialsQuery := `
+		SELECT pm.material_id, m.name, pm.percentage
+		FROM product_materials pm
+		JOIN materials m ON m.id = pm.material_id
+		WHERE pm.product_id = $1
+		ORDER BY pm.percentage DESC NULLS LAST, m.name`
+	if err := r.db.SelectContext(ctx, &attrs.Materials, materialsQuery, productID); err != nil {
+		return attrs, err
+	}
+
+	// Query 3: Tag
+	attrs.Tags = []string{}
+	tagsQuery := `
+		SELECT t.name FROM product_tags pt
+		JOIN tags t ON t.id = pt.tag_id
+		WHERE pt.product_id = $1
+		ORDER BY t.name`
+	err := r.db.SelectContext(ctx, &attrs.Tags, tagsQuery, productID)
+	return attrs, err
+}
+
+func (r *repository) TransactionSetProductAttributes(ctx context.Context, productID uuid.UUID, req ProductAttributesRequest, tags []string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Kolom atribut
+	query := `
+		UPDATE products
+		SET era_decade = $2, country_of_origin = $3, label_type_id = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`
+	result, err := tx.ExecContext(ctx, query, productID, req.EraDecade, req.CountryOfOrigin, req.LabelTypeID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	// Query 2: Ganti komposisi bahan
+	if _, err := tx.ExecContext(ctx, "DELETE FROM product_materials WHERE product_id = $1", productID); err != nil {
+		return err
+	}
+	for _, m := range req.Materials {
+		insertMaterial := "INSERT INTO product_materials (product_id, material_id, percentage) VALUES ($1, $2, $3)"
+		if _, err := tx.ExecContext(ctx, insertMaterial, productID, m.MaterialID, m.Percentage); err != nil {
+			return err
+		}
+	}
+
+	// Query 3: Ganti tag. Alias diarahkan ke tag kanonik, nama baru dibuat sebagai tag baru.
+	if _, err := tx.ExecContext(ctx, "DELETE FROM product_tags WHERE product_id = $1", productID); err != nil {
+		return err
+	}
+	resolveTag := `
+		WITH synonym AS (
+			SELECT tag_id AS id FROM tag_synonyms WHERE alias = $1
+		), created AS (
+			INSERT INTO tags (name)
+			SELECT $1 WHERE NOT EXISTS (SELECT 1 FROM synonym)
+			ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+			RETURNING id
+		)
+		SELECT id FROM synonym UNION ALL SELECT id FROM created`
+	for _, name := range tags {
+		var tagID int
+		if err := tx.GetContext(ctx, &tagID, resolveTag, name); err != nil {
+			return err
+		}
+		insertTag := "INSERT INTO product_tags (product_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING"
+		if _, err := tx.ExecContext(ctx, insertTag, productID, tagID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *repository) FindTags(ctx context.Context, filter TagFilter) ([]TagSummary, error) {
+	tags := []TagSummary{}
+	// Prefix juga dicocokkan ke alias, sehingga "single stitched" menemukan tag "single stitch".
+	query := `
+		SELECT t.id, t.name, COUNT(pt.product_id) AS product_count
+		FROM tags t
+		LEFT JOIN product_tags pt ON pt.tag_id = t.id
+		WHERE $1 = ''
+			OR t.name LIKE $1 || '%'
+			OR t.id IN (SELECT tag_id FROM tag_synonyms WHERE alias LIKE $1 || '%')
+		GROUP BY t.id
+		ORDER BY product_count DESC, t.name
+		LIMIT $2`
+	err := r.db.SelectContext(ctx, &tags, query, escapeLike(filter.Query), filter.Limit)
+	return tags, err
+}
+
+func (r *repository) FindTagByID(ctx context.Context, id int) (model.Tag, error) {
+	var tag model.Tag
+	query := "SELECT id, name, created_at FROM tags WHERE id = $1"
+	err := r.db.GetContext(ctx, &tag, query, id)
+	return tag, err
+}
+
+func (r *repository) TransactionMergeTag(ctx context.Context, sourceID, targetID int, entry model.AdminLog) error {
+	return r.withAdminLog(ctx, entry, func(tx *sqlx.Tx) error {
+		// Query 1: Kunci kedua tag supaya tidak ada merge lain yang berjalan bersamaan
+		var locked []model.Tag
+		lockQuery := "SELECT id, name, created_at FROM tags WHERE id IN ($1, $2) ORDER BY id FOR UPDATE"
+		if err := tx.SelectContext(ctx, &locked, lockQuery, sourceID, targetID); err != nil {
+			return err
+		}
+		if len(locked) != 2 {
+			return ErrTagNotFound
+		}
+		source := locked[0]
+		if source.ID != sourceID {
+			source = locked[1]
+		}
+
+		// Query 2: Pindahkan produk & alias ke target
+		moveProducts := `
+			INSERT INTO product_tags (product_id, tag_id)
+			SELECT product_id, $2 FROM product_tags WHERE tag_id = $1
+			ON CONFLICT DO NOTHING`
+		if _, err := tx.ExecContext(ctx, moveProducts, sourceID, targetID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE tag_synonyms SET tag_id = $2 WHERE tag_id = $1", sourceID, targetID); err != nil {
+			return err
+		}
+
+		// Query 3: Hapus source, namanya menjadi alias target
+		if _, err := tx.ExecContext(ctx, "DELETE FROM tags WHERE id = $1", sourceID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "INSERT INTO tag_synonyms (alias, tag_id) VALUES ($1, $2)", source.Name, targetID)
+		return err
+	})
+}
+
+func (r *repository) SaveTagSynonym(ctx context.Context, synonym model.TagSynonym, entry model.AdminLog) (model.TagSynonym, error) {
+	var saved model.TagSynonym
+	err := r.withAdminLog(ctx, entry, func(tx *sqlx.Tx) error {
+		// Alias tidak boleh sama dengan nama tag yang ada, gunakan merge untuk kasus itu.
+		query := `
+			INSERT INTO tag_synonyms (alias, tag_id)
+			SELECT CAST(:alias AS VARCHAR), CAST(:tag_id AS INT)
+			WHERE NOT EXISTS (SELECT 1 FROM tags WHERE name = :alias)
+			RETURNING alias, tag_id, created_at`
+		err := namedGet(ctx, tx, &saved, query, synonym)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrDuplicateName
+		}
+		return err
+	})
+	return saved, err
+}
+
+func (r *repository) DeleteTagSynonym(ctx context.Context, alias string, entry model.AdminLog) error {
+	return r.withAdminLog(ctx, entry, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, "DELETE FROM tag_synonyms WHERE alias = $1", alias)
+		if err != nil {
+			return err
+		}
+		if affected, err := result.RowsAffected(); err != nil {
+			return err
+		} else if affected == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// escapeLike meng-escape karakter wildcard LIKE dari input user.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// --- Measurement ---
+
+func (r *repository) FindMeasurementFields(ctx context.Context) ([]model.MeasurementField, error) {
+	var fields []model.MeasurementField
+	query := "SELECT key, label, min_cm, max_cm, fit_tolerance_cm FROM measurement_fields ORDER BY key"
+	err := r.db.SelectContext(ctx, &fields, query)
+	return fields, err
+}
+
+func (r *repository) FindCategoryMeasurementSchema(ctx context.Context, categoryID int) ([]MeasurementFieldResponse, error) {
+	fields := []MeasurementFieldResponse{}
+	// Naik ke leluhur satu per satu, ambil skema dari kategori terdekat yang punya skema.
+	// Leluhur yang sudah dihapus memutus rantai, jadi skemanya (dan skema di atasnya) tidak diwariskan.
+	query := `
+		WITH RECURSIVE chain AS (
+			SELECT id, parent_id, 0 AS depth FROM product_categories WHERE id = $1
+			UNION ALL
+			SELECT c.id, c.parent_id, chain.depth + 1
+			FROM product_categories c JOIN chain ON c.id = chain.parent_id
+			WHERE c.deleted_at IS NULL
+		),
+		owner AS (
+			SELECT chain.id FROM chain
+			WHERE EXISTS (SELECT 1 FROM category_measurement_fields cmf WHERE cmf.category_id = chain.id)
+			ORDER BY chain.depth
+			LIMIT 1
+		)
+		SELECT mf.key, mf.label, mf.min_cm, mf.max_cm, mf.fit_tolerance_cm, cmf.required
+		FROM category_measurement_fields cmf
+		JOIN owner ON cmf.category_id = owner.id
+		JOIN measurement_fields mf ON mf.key = cmf.field_key
+		ORDER BY cmf.position, mf.key`
+	err := r.db.SelectContext(ctx, &fields, query, categoryID)
+	return fields, err
+}
+
+func (r *repository) ReplaceCategoryMeasurementSchema(ctx context.Context, categoryID int, fields []model.CategoryMeasurementField, entry model.AdminLog) error {
+	return r.withAdminLog(ctx, entry, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM category_measurement_fields WHERE category_id = $1", categoryID); err != nil {
+			return err
+		}
+		query := `
+			INSERT INTO category_measurement_fields (category_id, field_key, required, position)
+			VALUES (:category_id, :field_key, :required, :position)`
+		for _, field := range fields {
+			if _, err := tx.NamedExecContext(ctx, query, field); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *repository) FindProductMeasurements(ctx context.Context, productIDs []uuid.UUID) ([]model.ProductMeasurement, error) {
+	measurements := []model.ProductMeasurement{}
+	if len(productIDs) == 0 {
+		return measurements, nil
+	}
+	query := "SELECT product_id, field_key, value_cm FROM product_measurements WHERE product_id = ANY($1::uuid[])"
+	err := r.db.SelectContext(ctx, &measurements, query, pq.Array(productIDs))
+	return measurements, err
+}
+
+func (r *repository) ReplaceProductMeasurements(ctx context.Context, productID uuid.UUID, values map[string]float64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM product_measurements WHERE product_id = $1", productID); err != nil {
+		return err
+	}
+	query := "INSERT INTO product_measurements (product_id, field_key, value_cm) VALUES ($1, $2, $3)"
+	for key, value := range values {
+		if _, err := tx.ExecContext(ctx, query, productID, key, value); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *repository) FindAccountMeasurements(ctx context.Context, accountID uuid.UUID) ([]model.AccountMeasurement, error) {
+	measurements := []model.AccountMeasurement{}
+	query := "SELECT account_id, field_key, value_cm, updated_at FROM account_measurements WHERE account_id = $1"
+	err := r.db.SelectContext(ctx, &measurements, query, accountID)
+	return measurements, err
+}
+
+func (r *repository) ReplaceAccountMeasurements(ctx context.Context, accountID uuid.UUID, values map[string]float64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM account_measurements WHERE account_id = $1", accountID); err != nil {
+		return err
+	}
+	query := "INSERT INTO account_measurements (account_id, field_key, value_cm) VALUES ($1, $2, $3)"
+	for key, value := range values {
+		if _, err := tx.ExecContext(ctx, query, accountID, key, value); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// --- Product ---
+
+func (r *repository) FindProductCategoryID(ctx context.Context, productID uuid.UUID) (int, error) {
+	var categoryID int
+	query := "SELECT category_id FROM products p WHERE id = $1 AND deleted_at IS NULL AND status IN " + publicStatuses + " AND " + activeShop
+	err := r.db.GetContext(ctx, &categoryID, query, productID)
+	return categoryID, err
+}
+
+func (r *repository) FindProductByID(ctx context.Context, productID uuid.UUID) (model.Product, error) {
+	var product model.Product
+	query := "SELECT * FROM products WHERE id = $1 AND deleted_at IS NULL"
+	err := r.db.GetContext(ctx, &product, query, productID)
+	return product, err
+}
+
+func (r *repository) FindProductBySlug(ctx context.Context, productSlug string) (model.Product, error) {
+	var product model.Product
+	query := "SELECT * FROM products WHERE slug = $1 AND deleted_at IS NULL"
+	err := r.db.GetContext(ctx, &product, query, productSlug)
+	return product, err
+}
+
+func (r *repository) FindProductSlugRedirect(ctx context.Context, oldSlug string) (string, error) {
+	var current string
+	query := `
+		SELECT p.slug FROM product_slug_history h
+		JOIN products p ON p.id = h.product_id
+		WHERE h.slug = $1 AND p.deleted_at IS NULL`
+	err := r.db.GetContext(ctx, &current, query, oldSlug)
+	return current, err
+}
+
+func (r *repository) FindProductSlugs(ctx context.Context, base string, excludeID uuid.UUID) ([]string, error) {
+	slugs := []string{}
+	// base hanya berisi [a-z0-9-] sehingga aman dipakai sebagai pola LIKE
+	query := `
+		SELECT slug FROM products WHERE (slug = $1 OR slug LIKE $1 || '-%') AND id <> $2
+		UNION
+		SELECT slug FROM product_slug_history WHERE (slug = $1 OR slug LIKE $1 || '-%') AND product_id <> $2`
+	err := r.db.SelectContext(ctx, &slugs, query, base, excludeID)
+	return slugs, err
+}
+
+func (r *repository) FindProductImages(ctx context.Context, productID uuid.UUID) ([]string, error) {
+	images := []string{}
+	query := "SELECT url FROM product_images WHERE product_id = $1 ORDER BY image_index"
+	err := r.db.SelectContext(ctx, &images, query, productID)
+	return images, err
+}
+
+func (r *repository) FindPriceHistory(ctx context.Context, productID uuid.UUID, since time.Time) ([]PricePoint, error) {
+	points := []PricePoint{}
+	// Harga terakhir sebelum rentang ditaruh di titik awal rentang supaya grafik tidak kosong
+	// untuk produk yang harganya jarang berubah.
+	query := `
+		(
+			SELECT new_price AS price, $2::TIMESTAMPTZ AS changed_at
+			FROM product_price_history
+			WHERE product_id = $1 AND changed_at < $2
+			ORDER BY changed_at DESC, id DESC
+			LIMIT 1
+		)
+		UNION ALL
+		(
+			SELECT new_price AS price, changed_at
+			FROM product_price_history
+			WHERE product_id = $1 AND changed_at >= $2
+		)
+		ORDER BY changed_at`
+	err := r.db.SelectContext(ctx, &points, query, productID, since)
+	return points, err
+}
+
+func (r *repository) SearchProducts(ctx context.Context, filter ProductFilter) ([]ProductSummary, int64, error) {
+	where, args := buildProductFilter(filter)
+
+	var total int64
+	countQuery := "SELECT COUNT(*) FROM products p WHERE " + where
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, err
+	}
+
+	items := []ProductSummary{}
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := fmt.Sprintf(`
+		SELECT
+			p.id, p.shop_id, p.category_id, p.brand_id, p.name, p.price,
+			pi.url AS image_url,
+			p.status, p.created_at
+		FROM products p
+		LEFT JOIN product_images pi ON p.id = pi.product_id AND pi.image_index = 0
+		WHERE %s
+		ORDER BY p.created_at DESC
+		LIMIT $%d OFFSET $%d`, where, len(args)-1, len(args))
+	err := r.db.SelectContext(ctx, &items, query, args...)
+	return items, total, err
+}
+
+// buildProductFilter menyusun klausa WHERE (alias tabel 'p') beserta argumennya.
+func buildProductFilter(filter ProductFilter) (string, []any) {
+	conditions := []string{"p.status = 'published'", "p.deleted_at IS NULL", activeShop}
+	if filter.IncludeSold {
+		conditions[0] = "p.status IN " + publicStatuses
+	}
+	args := []any{}
+	next := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Query != "" {
+		conditions = append(conditions, "p.name ILIKE "+next("%"+filter.Query+"%"))
+	}
+	if filter.CategoryID != nil {
+		conditions = append(conditions, fmt.Sprintf("p.category_id IN (%s)", fmt.Sprintf(descendantsCTE, next(*filter.CategoryID))))
+	}
+	if filter.BrandID != nil {
+		conditions = append(conditions, "p.brand_id = "+next(*filter.BrandID))
+	}
+	if filter.SizeID != nil {
+		conditions = append(conditions, "p.size_id = "+next(*filter.SizeID))
+	}
+	if filter.MinPrice != nil {
+		conditions = append(conditions, "p.price >= "+next(*filter.MinPrice))
+	}
+	if filter.MaxPrice != nil {
+		conditions = append(conditions, "p.price <= "+next(*filter.MaxPrice))
+	}
+	if filter.EraDecade != nil {
+		conditions = append(conditions, "p.era_decade = "+next(*filter.EraDecade))
+	}
+	if filter.Country != "" {
+		conditions = append(conditions, "p.country_of_origin = "+next(filter.Country))
+	}
+	if filter.LabelTypeID != nil {
+		conditions = append(conditions, "p.label_type_id = "+next(*filter.LabelTypeID))
+	}
+	if filter.MaterialID != nil {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM product_materials pmat WHERE pmat.product_id = p.id AND pmat.material_id = "+next(*filter.MaterialID)+")")
+	}
+	for _, tag := range filter.Tags {
+		// Tag dicari lewat nama kanonik atau alias-nya
+		ph := next(tag)
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM product_tags pt WHERE pt.product_id = p.id AND pt.tag_id IN (
+				SELECT id FROM tags WHERE name = %[1]s
+				UNION SELECT tag_id FROM tag_synonyms WHERE alias = %[1]s))`, ph))
+	}
+	for key, rng := range filter.Measurements {
+		clause := "pm.field_key = " + next(key)
+		if rng.MinCM != nil {
+			clause += " AND pm.value_cm >= " + next(*rng.MinCM)
+		}
+		if rng.MaxCM != nil {
+			clause += " AND pm.value_cm <= " + next(*rng.MaxCM)
+		}
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM product_measurements pm WHERE pm.product_id = p.id AND "+clause+")")
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// --- Listing ---
+
+func (r *repository) FindShopMembership(ctx context.Context, accountID uuid.UUID) (model.ShopMember, error) {
+	return staff.FindMembership(ctx, r.db, accountID)
+}
+
+func (r *repository) FindSellerProducts(ctx context.Context, shopID uuid.UUID, filter SellerProductFilter) ([]model.Product, int64, error) {
+	var total int64
+	countQuery := "SELECT COUNT(*) FROM products WHERE shop_id = $1 AND deleted_at IS NULL AND ($2 = '' OR status = $2)"
+	if err := r.db.GetContext(ctx, &total, countQuery, shopID, filter.Status); err != nil {
+		return nil, 0, err
+	}
+
+	products := []model.Product{}
+	query := `
+		SELECT * FROM products
+		WHERE shop_id = $1 AND deleted_at IS NULL AND ($2 = '' OR status = $2)
+		ORDER BY updated_at DESC
+		LIMIT $3 OFFSET $4`
+	err := r.db.SelectContext(ctx, &products, query, shopID, filter.Status, filter.Limit, (filter.Page-1)*filter.Limit)
+	return products, total, err
+}
+
+func (r *repository) CountPublishedListings(ctx context.Context, shopID uuid.UUID) (int, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM products WHERE shop_id = $1 AND published_at IS NOT NULL AND deleted_at IS NULL"
+	err := r.db.GetContext(ctx, &count, query, shopID)
+	return count, err
+}
+
+func (r *repository) FindShopKYCStatus(ctx context.Context, shopID uuid.UUID) (string, error) {
+	var status string
+	err := r.db.GetContext(ctx, &status, "SELECT kyc_status FROM shop WHERE id = $1", shopID)
+	return status, err
+}
+
+func (r *repository) SaveProduct(ctx context.Context, product model.Product) (model.Product, error) {
+	var saved model.Product
+	query := `
+		INSERT INTO products (
+			shop_id, condition_id, category_id, size_id, brand_id, name, slug, summary, description,
+			price, stock, status, created_at, updated_at
+		) VALUES (
+			:shop_id, :condition_id, :category_id, :size_id, :brand_id, :name, :slug, :summary, :description,
+			:price, :stock, :status, :created_at, :updated_at
+		)
+		RETURNING *`
+	rows, err := r.db.NamedQueryContext(ctx, query, product)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return saved, ErrDuplicateName
+		}
+		return saved, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return saved, sql.ErrNoRows
+	}
+	err = rows.StructScan(&saved)
+	return saved, err
+}
+
+func (r *repository) UpdateProduct(ctx context.Context, product model.Product, previousSlug string, stock *int) (model.Product, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return model.Product{}, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Stok hanya diubah jika diminta, lewat inventory supaya tidak menimpa pengurangan
+	// dari penjualan yang berjalan bersamaan dan tidak turun di bawah stok yang di-hold
+	if stock != nil {
+		if err := r.inventory.SetStock(ctx, tx, product.ID, *stock); err != nil {
+			return model.Product{}, err
+		}
+	}
+
+	// Query 2: Update produk, gagal jika status sudah diubah proses lain
+	var updated model.Product
+	query := `
+		UPDATE products SET
+			condition_id = :condition_id,
+			category_id = :category_id,
+			size_id = :size_id,
+			brand_id = :brand_id,
+			name = :name,
+			slug = :slug,
+			summary = :summary,
+			description = :description,
+			price = :price,
+			updated_at = :updated_at
+		WHERE id = :id AND status = :status AND deleted_at IS NULL
+		RETURNING *`
+	if err := namedGet(ctx, tx, &updated, query, product); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Product{}, ErrListingChanged
+		}
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return model.Product{}, ErrDuplicateName
+		}
+		return model.Product{}, err
+	}
+
+	if previousSlug != product.Slug {
+		// Query 3: Slug baru bisa saja slug lama produk ini sendiri (nama dikembalikan)
+		query := "DELETE FROM product_slug_history WHERE slug = $1 AND product_id = $2"
+		if _, err := tx.ExecContext(ctx, query, product.Slug, product.ID); err != nil {
+			return model.Product{}, err
+		}
+
+		// Query 4: Simpan slug lama untuk redirect
+		query = "INSERT INTO product_slug_history (slug, product_id) VALUES ($1, $2) ON CONFLICT (slug) DO NOTHING"
+		if _, err := tx.ExecContext(ctx, query, previousSlug, product.ID); err != nil {
+			return model.Product{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Product{}, err
+	}
+	return updated, nil
+}
+
+func (r *repository) TransactionChangeListingStatus(ctx context.Context, change ListingStatusChange, entry *model.AdminLog) (model.Product, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return model.Product{}, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Ubah status, hanya jika status di DB masih sama dengan yang dibaca service
+	var product model.Product
+	query := `
+		UPDATE products SET
+			status = $3,
+			moderation_note = $4,
+			submitted_at = CASE WHEN $3 = 'pending_review' THEN CURRENT_TIMESTAMP ELSE submitted_at END,
+			published_at = CASE WHEN $3 = 'published' THEN COALESCE(published_at, CURRENT_TIMESTAMP) ELSE published_at END,
+			sold_at = CASE WHEN $3 = 'sold' THEN CURRENT_TIMESTAMP ELSE sold_at END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = $2 AND deleted_at IS NULL
+		RETURNING *`
+	err = tx.GetContext(ctx, &product, query, change.ProductID, change.From, change.To, change.ModerationNote)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Product{}, ErrListingChanged
+	}
+	if err != nil {
+		return model.Product{}, err
+	}
+
+	// Query 2: Riwayat status
+	logQuery := `
+		INSERT INTO product_status_logs (product_id, from_status, to_status, reason, created_by)
+		VALUES ($1, $2, $3, $4, $5)`
+	if _, err := tx.ExecContext(ctx, logQuery, change.ProductID, change.From, change.To, change.Reason, change.ActorID); err != nil {
+		return model.Product{}, err
+	}
+
+	// Query 3: Keputusan moderasi dicatat juga di admin_logs
+	if entry != nil {
+		if err := audit.SaveAdminLog(ctx, tx, *entry); err != nil {
+			return model.Product{}, err
+		}
+	}
+
+	return product, tx.Commit()
+}
+
+func (r *repository) TransactionSoftDeleteProduct(ctx context.Context, productID uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Kunci baris produk supaya tidak ada checkout baru yang meng-hold stoknya
+	var id uuid.UUID
+	if err := tx.GetContext(ctx, &id, "SELECT id FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", productID); err != nil {
+		return err
+	}
+
+	// Query 2: Listing yang sedang di-checkout pembeli tidak boleh hilang di tengah jalan
+	var reserved bool
+	queryHolds := `
+		SELECT EXISTS (
+			SELECT 1 FROM inventory_holds
+			WHERE product_id = $1 AND status = 'active' AND expires_at > CURRENT_TIMESTAMP
+		)`
+	if err := tx.GetContext(ctx, &reserved, queryHolds, productID); err != nil {
+		return err
+	}
+	if reserved {
+		return ErrListingReserved
+	}
+
+	// Query 3: Soft delete
+	query := "UPDATE products SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = $1"
+	if _, err := tx.ExecContext(ctx, query, productID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *repository) FindModerationQueue(ctx context.Context, page PageRequest) ([]ModerationQueueItem, int64, error) {
+	var total int64
+	countQuery := "SELECT COUNT(*) FROM products WHERE status = 'pending_review' AND deleted_at IS NULL"
+	if err := r.db.GetContext(ctx, &total, countQuery); err != nil {
+		return nil, 0, err
+	}
+
+	// Antrian diproses dari yang paling lama menunggu
+	items := []ModerationQueueItem{}
+	query := `
+		SELECT
+			p.id AS product_id, p.shop_id, s.name AS shop_name, p.name, p.price,
+			pi.url AS image_url, p.submitted_at
+		FROM products p
+		JOIN shop s ON s.id = p.shop_id
+		LEFT JOIN product_images pi ON p.id = pi.product_id AND pi.image_index = 0
+		WHERE p.status = 'pending_review' AND p.deleted_at IS NULL
+		ORDER BY p.submitted_at ASC
+		LIMIT $1 OFFSET $2`
+	err := r.db.SelectContext(ctx, &items, query, page.Limit, (page.Page-1)*page.Limit)
+	return items, total, err
+}
+
+// --- Shop ---
+
+const shopProfileSelect = `
+	SELECT
+		s.id, s.name, s.slug, s.summary, s.description, s.logo_url, s.banner_url,
+		s.shipping_policy, s.return_policy, s.payment_policy, s.follower_count, s.vacation_active,
+		CASE WHEN s.vacation_active THEN s.vacation_message END AS vacation_message,
+		CASE WHEN s.vacation_active THEN s.vacation_ends_at END AS vacation_ends_at,
+		a.created_at AS member_since, s.created_at
+	FROM shop s
+	JOIN accounts a ON a.id = s.account_id`
+
+func (r *repository) FindShop(ctx context.Context, shopID uuid.UUID) (ShopProfile, error) {
+	var shop ShopProfile
+	query := shopProfileSelect + " WHERE s.id = $1 AND s.active AND s.deleted_at IS NULL"
+	err := r.db.GetContext(ctx, &shop, query, shopID)
+	return shop, err
+}
+
+func (r *repository) FindShopBySlug(ctx context.Context, shopSlug string) (ShopProfile, error) {
+	var shop ShopProfile
+	query := shopProfileSelect + " WHERE s.slug = $1 AND s.active AND s.deleted_at IS NULL"
+	err := r.db.GetContext(ctx, &shop, query, shopSlug)
+	return shop, err
+}
+
+func (r *repository) FindShopSlugRedirect(ctx context.Context, oldSlug string) (string, error) {
+	var current string
+	query := `
+		SELECT s.slug FROM shop_slug_history h
+		JOIN shop s ON s.id = h.shop_id
+		WHERE h.slug = $1 AND s.active AND s.deleted_at IS NULL`
+	err := r.db.GetContext(ctx, &current, query, oldSlug)
+	return current, err
+}
+
+func (r *repository) IsFollowingShop(ctx context.Context, accountID, shopID uuid.UUID) (bool, error) {
+	var following bool
+	query := "SELECT EXISTS (SELECT 1 FROM shop_followers WHERE account_id = $1 AND shop_id = $2)"
+	err := r.db.GetContext(ctx, &following, query, accountID, shopID)
+	return following, err
+}
+
+func (r *repository) FindShopStats(ctx context.Context, shopID uuid.UUID) (ShopStats, error) {
+	var stats ShopStats
+	query := `
+		SELECT
+			active_listings, sold_listings, rating_count, rating_average,
+			rating_1, rating_2, rating_3, rating_4, rating_5,
+			response_rate, median_ship_hours, refreshed_at
+		FROM shop_stats WHERE shop_id = $1`
+	err := r.db.GetContext(ctx, &stats, query, shopID)
+	return stats, err
+}
+
+// --- Wishlist ---
+
+func (r *repository) FindWishlist(ctx context.Context, accountID uuid.UUID) ([]WishlistItem, error) {
+	items := []WishlistItem{}
+	// Listing yang ditarik (draft / pending / archived) disembunyikan, yang sold tetap tampil
+	query := `
+		SELECT
+			p.id AS product_id, p.name, p.price, pi.url AS image_url, p.status,
+			w.created_at AS added_at
+		FROM wishlist w
+		JOIN products p ON p.id = w.product_id
+		LEFT JOIN product_images pi ON p.id = pi.product_id AND pi.image_index = 0
+		WHERE w.account_id = $1 AND p.deleted_at IS NULL AND p.status IN ` + publicStatuses + ` AND ` + activeShop + `
+		ORDER BY w.created_at DESC`
+	err := r.db.SelectContext(ctx, &items, query, accountID)
+	return items, err
+}
+
+func (r *repository) SaveWishlistItem(ctx context.Context, accountID, productID uuid.UUID) error {
+	query := `
+		INSERT INTO wishlist (account_id, product_id, created_at, updated_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		ON CONFLICT (account_id, product_id) DO NOTHING`
+	_, err := r.db.ExecContext(ctx, query, accountID, productID)
+	return err
+}
+
+func (r *repository) DeleteWishlistItem(ctx context.Context, accountID, productID uuid.UUID) error {
+	query := "DELETE FROM wishlist WHERE account_id = $1 AND product_id = $2"
+	_, err := r.db.ExecContext(ctx, query, accountID, productID)
+	return err
+}
+
+// --- Saved Search ---
+
+func (r *repository) FindSavedSearches(ctx context.Context, accountID uuid.UUID) ([]model.SavedSearch, error) {
+	searches := []model.SavedSearch{}
+	query := "SELECT * FROM saved_searches WHERE account_id = $1 ORDER BY created_at DESC"
+	err := r.db.SelectContext(ctx, &searches, query, accountID)
+	return searches, err
+}
+
+func (r *repository) FindSavedSearch(ctx context.Context, accountID, searchID uuid.UUID) (model.SavedSearch, error) {
+	var search model.SavedSearch
+	query := "SELECT * FROM saved_searches WHERE id = $1 AND account_id = $2"
+	err := r.db.GetContext(ctx, &search, query, searchID, accountID)
+	return search, err
+}
+
+func (r *repository) CountSavedSearches(ctx context.Context, accountID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, "SELECT COUNT(*) FROM saved_searches WHERE account_id = $1", accountID)
+	return count, err
+}
+
+func (r *repository) SaveSavedSearch(ctx context.Context, search model.SavedSearch) (model.SavedSearch, error) {
+	var saved model.SavedSearch
+	query := `
+		INSERT INTO saved_searches (account_id, name, filter, alert, brand_id, size_id)
+		VALUES (:account_id, :name, :filter, :alert, :brand_id, :size_id)
+		RETURNING *`
+	rows, err := r.db.NamedQueryContext(ctx, query, search)
+	if err != nil {
+		return model.SavedSearch{}, err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		err = rows.StructScan(&saved)
+	}
+	return saved, err
+}
+
+func (r *repository) UpdateSavedSearch(ctx context.Context, search model.SavedSearch) (model.SavedSearch, error) {
+	var updated model.SavedSearch
+	query := `
+		UPDATE saved_searches SET name = $3, alert = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND account_id = $2
+		RETURNING *`
+	err := r.db.GetContext(ctx, &updated, query, search.ID, search.AccountID, search.Name, search.Alert)
+	return updated, err
+}
+
+func (r *repository) DeleteSavedSearch(ctx context.Context, accountID, searchID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM saved_searches WHERE id = $1 AND account_id = $2", searchID, accountID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *repository) TransactionMatchSavedSearches(ctx context.Context, batchSize int) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Kunci checkpoint supaya dua instance job tidak memproses batch yang sama
+	var name string
+	if err := tx.GetContext(ctx, &name, "SELECT name FROM job_checkpoints WHERE name = $1 FOR UPDATE", savedSearchCheckpoint); err != nil {
+		return 0, err
+	}
+
+	// Query 2: Listing yang berpindah ke 'published' sejak checkpoint, hanya dari transaksi yang
+	// sudah selesai (lihat migrasi 000030) supaya publish yang commit belakangan tidak terlewat
+	var published []struct {
+		ID        int64     `db:"id"`
+		ProductID uuid.UUID `db:"product_id"`
+	}
+	queryLogs := `
+		SELECT l.id, l.product_id FROM product_status_logs l
+		JOIN job_checkpoints jc ON jc.name = $1
+		WHERE (l.created_xid, l.id) > (jc.last_xid, jc.last_id)
+		  AND l.created_xid < pg_snapshot_xmin(pg_current_snapshot())
+		  AND l.to_status = 'published'
+		ORDER BY l.created_xid, l.id
+		LIMIT $2`
+	if err := tx.SelectContext(ctx, &published, queryLogs, savedSearchCheckpoint, batchSize); err != nil {
+		return 0, err
+	}
+	if len(published) == 0 {
+		return 0, nil
+	}
+	productIDs := make([]uuid.UUID, 0, len(published))
+	for _, row := range published {
+		productIDs = append(productIDs, row.ProductID)
+	}
+
+	// Query 3: Hanya saved search yang brand & size-nya mungkin cocok dengan batch ini
+	var searches []model.SavedSearch
+	querySearches := `
+		SELECT * FROM saved_searches ss
+		WHERE ss.alert <> 'off'
+		  AND (ss.brand_id IS NULL OR ss.brand_id IN (SELECT brand_id FROM products WHERE id = ANY($1)))
+		  AND (ss.size_id IS NULL OR ss.size_id IN (SELECT size_id FROM products WHERE id = ANY($1)))`
+	if err := tx.SelectContext(ctx, &searches, querySearches, pq.Array(productIDs)); err != nil {
+		return 0, err
+	}
+
+	// Query 4: Jalankan filter setiap saved search terhadap batch produk baru saja
+	for _, search := range searches {
+		var filter ProductFilter
+		if err := json.Unmarshal(search.Filter, &filter); err != nil {
+			return 0, fmt.Errorf("saved search %s: %w", search.ID, err)
+		}
+		where, args := buildProductFilter(filter)
+		args = append(args, pq.Array(productIDs), search.ID, search.AccountID)
+		queryMatch := fmt.Sprintf(`
+			INSERT INTO saved_search_matches (saved_search_id, product_id)
+			SELECT $%[2]d, p.id FROM products p
+			JOIN shop s ON s.id = p.shop_id
+			WHERE %[1]s AND p.id = ANY($%[3]d) AND s.account_id <> $%[4]d
+			ON CONFLICT (saved_search_id, product_id) DO NOTHING`, where, len(args)-1, len(args)-2, len(args))
+		if _, err := tx.ExecContext(ctx, queryMatch, args...); err != nil {
+			return 0, err
+		}
+	}
+
+	// Query 5: Maju-kan checkpoint
+	updateCheckpoint := `
+		UPDATE job_checkpoints jc SET last_xid = l.created_xid, last_id = l.id, updated_at = CURRENT_TIMESTAMP
+		FROM product_status_logs l
+		WHERE jc.name = $1 AND l.id = $2`
+	if _, err := tx.ExecContext(ctx, updateCheckpoint, savedSearchCheckpoint, published[len(published)-1].ID); err != nil {
+		return 0, err
+	}
+
+	return len(published), tx.Commit()
+}
+
+func (r *repository) TransactionDispatchSavedSearchAlerts(ctx context.Context, digestSince time.Time, maxSearches int, build SavedSearchAlertBuilder) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Saved search dengan antrian yang jatuh tempo (instant: kapan saja, daily: sekali sehari)
+	var searches []model.SavedSearch
+	querySearches := `
+		SELECT ss.* FROM saved_searches ss
+		WHERE (ss.alert = 'instant' OR (ss.alert = 'daily' AND (ss.last_alerted_at IS NULL OR ss.last_alerted_at <= $1)))
+		  AND EXISTS (SELECT 1 FROM saved_search_matches m WHERE m.saved_search_id = ss.id AND m.alerted_at IS NULL)
+		LIMIT $2
+		FOR UPDATE OF ss SKIP LOCKED`
+	if err := tx.SelectContext(ctx, &searches, querySearches, digestSince, maxSearches); err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, search := range searches {
+		// Query 2: Listing yang cocok dan masih tayang, terbaru dulu
+		var total int
+		queryCount := `
+			SELECT COUNT(*) FROM saved_search_matches m
+			JOIN products p ON p.id = m.product_id
+			WHERE m.saved_search_id = $1 AND m.alerted_at IS NULL AND p.status = 'published' AND p.deleted_at IS NULL AND ` + activeShop
+		if err := tx.GetContext(ctx, &total, queryCount, search.ID); err != nil {
+			return 0, err
+		}
+		var items []SavedSearchMatch
+		queryItems := `
+			SELECT p.id AS product_id, p.name, p.price FROM saved_search_matches m
+			JOIN products p ON p.id = m.product_id
+			WHERE m.saved_search_id = $1 AND m.alerted_at IS NULL AND p.status = 'published' AND p.deleted_at IS NULL AND ` + activeShop + `
+			ORDER BY m.matched_at DESC
+			LIMIT $2`
+		if err := tx.SelectContext(ctx, &items, queryItems, search.ID, maxSavedSearchAlertItems); err != nil {
+			return 0, err
+		}
+
+		// Query 3: Kirim notifikasi (jika masih ada yang tayang) lalu kosongkan antrian
+		if len(items) > 0 {
+			n, err := build(search, items, total)
+			if err != nil {
+				return 0, err
+			}
+			if err := notification.SaveNotification(ctx, tx, n); err != nil {
+				return 0, err
+			}
+			sent++
+		}
+		queryAlerted := "UPDATE saved_search_matches SET alerted_at = CURRENT_TIMESTAMP WHERE saved_search_id = $1 AND alerted_at IS NULL"
+		if _, err := tx.ExecContext(ctx, queryAlerted, search.ID); err != nil {
+			return 0, err
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE saved_searches SET last_alerted_at = CURRENT_TIMESTAMP WHERE id = $1", search.ID); err != nil {
+			return 0, err
+		}
+	}
+
+	return sent, tx.Commit()
+}
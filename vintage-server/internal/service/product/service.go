@@ -1 +1,1987 @@
 package product
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/audit"
+	"vintage-server/internal/service/inventory"
+	"vintage-server/internal/service/staff"
+	"vintage-server/pkg/apperror"
+	"vintage-server/pkg/cache"
+	"vintage-server/pkg/slug"
+	"vintage-server/pkg/storage"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+
+	// maxLogoSize adalah batas ukuran file logo brand (2 MB).
+	maxLogoSize = 2 << 20
+
+	maxTagsPerProduct = 20
+	maxTagLength      = 64
+	defaultTagLimit   = 50
+	maxTagLimit       = 200
+	minEraDecade      = 1900
+
+	defaultPriceHistoryDays = 90
+	maxPriceHistoryDays     = 365
+
+	// maxProductSlugBase menyisakan ruang suffix "-N" di kolom products.slug (160)
+	maxProductSlugBase = 140
+
+	maxSavedSearches = 20
+	// savedSearchBatchSize adalah jumlah log status listing yang dicocokkan per putaran job.
+	savedSearchBatchSize = 1000
+	// maxSavedSearchAlertsPerRun membatasi jumlah notifikasi saved search per putaran job.
+	maxSavedSearchAlertsPerRun = 500
+	// savedSearchDigestInterval adalah jarak minimal antar digest untuk saved search 'daily'.
+	savedSearchDigestInterval = 24 * time.Hour
+)
+
+// Key cache untuk data referensi publik. Dihapus setiap kali admin mengubah datanya.
+const (
+	cacheKeyBrands       = "brands"
+	cacheKeyConditions   = "conditions"
+	cacheKeySizes        = "sizes"
+	cacheKeyCategories   = "categories"
+	cacheKeyCategoryTree = "category_tree"
+	cacheKeyMeasurements = "measurement_fields"
+	cacheKeyLabelTypes   = "label_types"
+	cacheKeyMaterials    = "materials"
+)
+
+// logoExtensions adalah content type logo yang diterima beserta ekstensi file-nya.
+var logoExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/webp": ".webp",
+}
+
+// service adalah struct yang akan mengimplementasikan interface Service dari domain.go
+type service struct {
+	repo    Repository
+	storage storage.Storage
+	cache   *cache.Cache
+	listing ListingPolicy
+}
+
+// NewService adalah constructor untuk service
+func NewService(repo Repository, store storage.Storage, refCache *cache.Cache, listing ListingPolicy) Service {
+	return &service{
+		repo:    repo,
+		storage: store,
+		cache:   refCache,
+		listing: listing,
+	}
+}
+
+// listingTransitions adalah perpindahan status yang boleh dilakukan lewat API.
+// published -> sold juga terjadi otomatis di inventory saat stok habis.
+var listingTransitions = map[string][]string{
+	model.ListingStatusDraft:         {model.ListingStatusPendingReview, model.ListingStatusPublished},
+	model.ListingStatusPendingReview: {model.ListingStatusPublished, model.ListingStatusDraft},
+	model.ListingStatusPublished:     {model.ListingStatusSold, model.ListingStatusArchived, model.ListingStatusDraft},
+	model.ListingStatusSold:          {model.ListingStatusPublished, model.ListingStatusArchived},
+	model.ListingStatusArchived:      {model.ListingStatusDraft},
+}
+
+// --- Catalog ---
+
+func (s *service) SearchProducts(ctx context.Context, filter ProductFilter, viewerID *uuid.UUID) (ProductSearchResponse, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = defaultPageLimit
+	}
+	if filter.Limit > maxPageLimit {
+		filter.Limit = maxPageLimit
+	}
+	filter, err := s.normalizeFilter(ctx, filter)
+	if err != nil {
+		return ProductSearchResponse{}, err
+	}
+
+	items, total, err := s.repo.SearchProducts(ctx, filter)
+	if err != nil {
+		log.Printf("Error searching products: %v", err)
+		return ProductSearchResponse{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	if viewerID != nil && len(items) > 0 {
+		// Flag "fits you" hanya pelengkap, kegagalan di sini tidak menggagalkan pencarian.
+		if err := s.attachFit(ctx, items, *viewerID); err != nil {
+			log.Printf("Error computing fit flags: %v", err)
+		}
+	}
+
+	return ProductSearchResponse{
+		Items: items,
+		Total: total,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+	}, nil
+}
+
+// normalizeFilter merapikan input pencarian (spasi, huruf besar negara, tag) dan memvalidasi
+// filter ukuran. Dipakai pencarian katalog dan saat menyimpan saved search.
+func (s *service) normalizeFilter(ctx context.Context, filter ProductFilter) (ProductFilter, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	filter.Country = strings.ToUpper(strings.TrimSpace(filter.Country))
+	tags := make([]string, 0, len(filter.Tags))
+	for _, tag := range filter.Tags {
+		if name := normalizeTag(tag); name != "" {
+			tags = append(tags, name)
+		}
+	}
+	filter.Tags = tags
+
+	if len(filter.Measurements) > 0 {
+		fields, err := s.measurementFieldIndex(ctx)
+		if err != nil {
+			return ProductFilter{}, err
+		}
+		for key, rng := range filter.Measurements {
+			if _, ok := fields[key]; !ok {
+				return ProductFilter{}, apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("unknown measurement %q", key))
+			}
+			if rng.MinCM != nil && rng.MaxCM != nil && *rng.MinCM > *rng.MaxCM {
+				return ProductFilter{}, apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("invalid range for measurement %q", key))
+			}
+		}
+	}
+	return filter, nil
+}
+
+func (s *service) GetProduct(ctx context.Context, productID uuid.UUID) (ProductDetail, error) {
+	product, err := s.repo.FindProductByID(ctx, productID)
+	if err != nil {
+		return ProductDetail{}, s.referenceReadError(err, "product")
+	}
+	if product.Status != model.ListingStatusPublished && product.Status != model.ListingStatusSold {
+		return ProductDetail{}, apperror.New(apperror.ErrCodeNotFound, "product not found")
+	}
+	// Listing milik shop yang belum aktif / disuspend disembunyikan
+	shop, err := s.repo.FindShop(ctx, product.ShopID)
+	if err != nil {
+		return ProductDetail{}, s.referenceReadError(err, "product")
+	}
+
+	// Catatan moderasi hanya untuk seller
+	product.ModerationNote = nil
+	detail, err := s.productDetail(ctx, product)
+	if err != nil {
+		return ProductDetail{}, err
+	}
+	detail.ShopOnVacation = shop.OnVacation
+	detail.VacationMessage, detail.VacationEndsAt = shop.VacationMessage, shop.VacationEndsAt
+	return detail, nil
+}
+
+func (s *service) GetProductBySlug(ctx context.Context, productSlug string) (ProductDetail, string, error) {
+	product, err := s.repo.FindProductBySlug(ctx, productSlug)
+	if errors.Is(err, sql.ErrNoRows) {
+		current, err := s.repo.FindProductSlugRedirect(ctx, productSlug)
+		if err != nil {
+			return ProductDetail{}, "", s.referenceReadError(err, "product")
+		}
+		return ProductDetail{}, current, nil
+	}
+	if err != nil {
+		return ProductDetail{}, "", s.referenceReadError(err, "product")
+	}
+
+	detail, err := s.GetProduct(ctx, product.ID)
+	return detail, "", err
+}
+
+func (s *service) GetShop(ctx context.Context, shopSlug string, viewerID *uuid.UUID) (ShopProfile, string, error) {
+	if shopID, err := uuid.Parse(shopSlug); err == nil {
+		shop, err := s.repo.FindShop(ctx, shopID)
+		if err != nil {
+			return ShopProfile{}, "", s.referenceReadError(err, "shop")
+		}
+		return s.withShopStats(ctx, shop, viewerID)
+	}
+
+	shop, err := s.repo.FindShopBySlug(ctx, shopSlug)
+	if errors.Is(err, sql.ErrNoRows) {
+		current, err := s.repo.FindShopSlugRedirect(ctx, shopSlug)
+		if err != nil {
+			return ShopProfile{}, "", s.referenceReadError(err, "shop")
+		}
+		return ShopProfile{}, current, nil
+	}
+	if err != nil {
+		return ShopProfile{}, "", s.referenceReadError(err, "shop")
+	}
+	return s.withShopStats(ctx, shop, viewerID)
+}
+
+// withShopStats melengkapi profil dengan statistik shop dan status follow pengunjung. Shop baru
+// yang belum tersentuh job statistik ditampilkan dengan angka nol.
+func (s *service) withShopStats(ctx context.Context, shop ShopProfile, viewerID *uuid.UUID) (ShopProfile, string, error) {
+	stats, err := s.repo.FindShopStats(ctx, shop.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Error finding shop stats: %v", err)
+		return ShopProfile{}, "", apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	shop.Stats = stats
+
+	if viewerID != nil {
+		following, err := s.repo.IsFollowingShop(ctx, *viewerID, shop.ID)
+		if err != nil {
+			log.Printf("Error checking shop follow: %v", err)
+			return ShopProfile{}, "", apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+		}
+		shop.Following = &following
+	}
+	return shop, "", nil
+}
+
+func (s *service) GetProductBreadcrumb(ctx context.Context, productID uuid.UUID) ([]CategoryResponse, error) {
+	categoryID, err := s.repo.FindProductCategoryID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.New(apperror.ErrCodeNotFound, "product not found")
+		}
+		log.Printf("Error finding product category: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	chain, err := s.repo.FindCategoryAncestors(ctx, categoryID)
+	if err != nil {
+		log.Printf("Error finding category ancestors: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	breadcrumb := make([]CategoryResponse, 0, len(chain))
+	for _, c := range chain {
+		breadcrumb = append(breadcrumb, toCategoryResponse(c))
+	}
+	return breadcrumb, nil
+}
+
+func (s *service) GetPriceHistory(ctx context.Context, productID uuid.UUID, filter PriceHistoryFilter) ([]PricePoint, error) {
+	if filter.Days < 1 {
+		filter.Days = defaultPriceHistoryDays
+	}
+	if filter.Days > maxPriceHistoryDays {
+		filter.Days = maxPriceHistoryDays
+	}
+
+	if _, err := s.repo.FindProductCategoryID(ctx, productID); err != nil {
+		return nil, s.referenceReadError(err, "product")
+	}
+
+	since := time.Now().AddDate(0, 0, -filter.Days)
+	points, err := s.repo.FindPriceHistory(ctx, productID, since)
+	if err != nil {
+		log.Printf("Error finding price history: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return points, nil
+}
+
+// --- Listing (Seller) ---
+
+func (s *service) GetSellerProducts(ctx context.Context, sellerID uuid.UUID, filter SellerProductFilter) (SellerProductPage, error) {
+	shopID, err := s.sellerShopID(ctx, sellerID)
+	if err != nil {
+		return SellerProductPage{}, err
+	}
+	if filter.Status != "" {
+		if _, ok := listingTransitions[filter.Status]; !ok {
+			return SellerProductPage{}, apperror.New(apperror.ErrCodeValidation, "unknown listing status")
+		}
+	}
+	filter.Page, filter.Limit = normalizePage(filter.Page, filter.Limit)
+
+	items, total, err := s.repo.FindSellerProducts(ctx, shopID, filter)
+	if err != nil {
+		log.Printf("Error finding seller products: %v", err)
+		return SellerProductPage{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return SellerProductPage{Items: items, Total: total, Page: filter.Page, Limit: filter.Limit}, nil
+}
+
+func (s *service) GetSellerProduct(ctx context.Context, sellerID, productID uuid.UUID) (ProductDetail, error) {
+	product, err := s.findOwnedProduct(ctx, sellerID, productID)
+	if err != nil {
+		return ProductDetail{}, err
+	}
+	return s.productDetail(ctx, product)
+}
+
+func (s *service) CreateProduct(ctx context.Context, sellerID uuid.UUID, req ProductRequest) (model.Product, error) {
+	shopID, err := s.sellerShopID(ctx, sellerID)
+	if err != nil {
+		return model.Product{}, err
+	}
+
+	now := time.Now()
+	product := model.Product{
+		ShopID:    shopID,
+		Stock:     1,
+		Status:    model.ListingStatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.applyProductRequest(ctx, &product, req); err != nil {
+		return model.Product{}, err
+	}
+	if product.Name == "" {
+		return model.Product{}, apperror.New(apperror.ErrCodeValidation, "product name is required")
+	}
+	if product.Slug, err = s.uniqueProductSlug(ctx, product.Name, uuid.Nil); err != nil {
+		return model.Product{}, err
+	}
+
+	saved, err := s.repo.SaveProduct(ctx, product)
+	if err != nil {
+		if errors.Is(err, ErrDuplicateName) {
+			return model.Product{}, apperror.New(apperror.ErrCodeConflict, "another listing with the same name was just created, try again")
+		}
+		log.Printf("Error saving product: %v", err)
+		return model.Product{}, apperror.New(apperror.ErrCodeInternal, "failed to save product")
+	}
+	return saved, nil
+}
+
+func (s *service) UpdateProduct(ctx context.Context, sellerID, productID uuid.UUID, req ProductRequest) (model.Product, error) {
+	product, err := s.findOwnedProduct(ctx, sellerID, productID)
+	if err != nil {
+		return model.Product{}, err
+	}
+	previousName, previousSlug := product.Name, product.Slug
+	if err := s.applyProductRequest(ctx, &product, req); err != nil {
+		return model.Product{}, err
+	}
+	if product.Name == "" {
+		return model.Product{}, apperror.New(apperror.ErrCodeValidation, "product name is required")
+	}
+	// Slug mengikuti nama; slug lama disimpan untuk redirect
+	if product.Name != previousName {
+		if product.Slug, err = s.uniqueProductSlug(ctx, product.Name, product.ID); err != nil {
+			return model.Product{}, err
+		}
+	}
+	// Draft boleh belum lengkap; selain itu listing harus tetap memenuhi validasi ketat
+	if product.Status != model.ListingStatusDraft {
+		if err := s.validateListing(ctx, product); err != nil {
+			return model.Product{}, err
+		}
+		if err := s.requireVerifiedShop(ctx, product); err != nil {
+			return model.Product{}, err
+		}
+	}
+	product.UpdatedAt = time.Now()
+
+	updated, err := s.repo.UpdateProduct(ctx, product, previousSlug, req.Stock)
+	if err != nil {
+		return model.Product{}, listingWriteError(err)
+	}
+	return updated, nil
+}
+
+func (s *service) ChangeListingStatus(ctx context.Context, sellerID, productID uuid.UUID, req ListingStatusRequest) (model.Product, error) {
+	product, err := s.findOwnedProduct(ctx, sellerID, productID)
+	if err != nil {
+		return model.Product{}, err
+	}
+
+	target := req.Status
+	// Moderasi hanya diputuskan admin; seller cukup minta "published"
+	if target == model.ListingStatusPendingReview {
+		return model.Product{}, apperror.New(apperror.ErrCodeValidation, `request "published" to submit a listing`)
+	}
+	if !canTransition(product.Status, target) {
+		return model.Product{}, apperror.New(apperror.ErrCodeConflict, fmt.Sprintf("cannot change listing from %s to %s", product.Status, target))
+	}
+	if product.Status == model.ListingStatusPendingReview && target == model.ListingStatusPublished {
+		return model.Product{}, apperror.New(apperror.ErrCodeConflict, "listing is waiting for review")
+	}
+
+	note := product.ModerationNote
+	if target == model.ListingStatusPublished {
+		if err := s.validateListing(ctx, product); err != nil {
+			return model.Product{}, err
+		}
+		if err := s.requireVerifiedShop(ctx, product); err != nil {
+			return model.Product{}, err
+		}
+		if product.Status == model.ListingStatusDraft {
+			needsReview, err := s.needsReview(ctx, product.ShopID)
+			if err != nil {
+				return model.Product{}, err
+			}
+			if needsReview {
+				target = model.ListingStatusPendingReview
+			}
+		}
+		note = nil
+	}
+
+	updated, err := s.repo.TransactionChangeListingStatus(ctx, ListingStatusChange{
+		ProductID:      productID,
+		From:           product.Status,
+		To:             target,
+		ModerationNote: note,
+		ActorID:        &sellerID,
+	}, nil)
+	if err != nil {
+		return model.Product{}, listingWriteError(err)
+	}
+	return updated, nil
+}
+
+func (s *service) DeleteProduct(ctx context.Context, sellerID, productID uuid.UUID) error {
+	if _, err := s.findOwnedProduct(ctx, sellerID, productID); err != nil {
+		return err
+	}
+
+	if err := s.repo.TransactionSoftDeleteProduct(ctx, productID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.New(apperror.ErrCodeNotFound, "product not found")
+		}
+		if errors.Is(err, ErrListingReserved) {
+			return apperror.New(apperror.ErrCodeConflict, "listing is being checked out by a buyer, try again later")
+		}
+		log.Printf("Error deleting listing: %v", err)
+		return apperror.New(apperror.ErrCodeInternal, "failed to delete listing")
+	}
+	return nil
+}
+
+// --- Moderation (Admin) ---
+
+func (s *service) GetModerationQueue(ctx context.Context, page PageRequest) (ModerationQueuePage, error) {
+	page.Page, page.Limit = normalizePage(page.Page, page.Limit)
+
+	items, total, err := s.repo.FindModerationQueue(ctx, page)
+	if err != nil {
+		log.Printf("Error finding moderation queue: %v", err)
+		return ModerationQueuePage{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return ModerationQueuePage{Items: items, Total: total, Page: page.Page, Limit: page.Limit}, nil
+}
+
+func (s *service) ApproveListing(ctx context.Context, actor audit.Actor, productID uuid.UUID) (model.Product, error) {
+	product, err := s.findPendingListing(ctx, productID)
+	if err != nil {
+		return model.Product{}, err
+	}
+	if err := s.validateListing(ctx, product); err != nil {
+		return model.Product{}, err
+	}
+
+	entry := actor.Entry("listing.approve", fmt.Sprintf("approved listing %s (%q)", productID, product.Name))
+	updated, err := s.repo.TransactionChangeListingStatus(ctx, ListingStatusChange{
+		ProductID: productID,
+		From:      model.ListingStatusPendingReview,
+		To:        model.ListingStatusPublished,
+		ActorID:   &actor.AdminID,
+	}, &entry)
+	if err != nil {
+		return model.Product{}, listingWriteError(err)
+	}
+	return updated, nil
+}
+
+func (s *service) RejectListing(ctx context.Context, actor audit.Actor, productID uuid.UUID, req ModerationDecisionRequest) (model.Product, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return model.Product{}, apperror.New(apperror.ErrCodeValidation, "a rejection reason is required")
+	}
+	product, err := s.findPendingListing(ctx, productID)
+	if err != nil {
+		return model.Product{}, err
+	}
+
+	// Listing yang ditolak kembali ke draft supaya seller bisa memperbaiki lalu submit ulang
+	entry := actor.Entry("listing.reject", fmt.Sprintf("rejected listing %s (%q): %s", productID, product.Name, reason))
+	updated, err := s.repo.TransactionChangeListingStatus(ctx, ListingStatusChange{
+		ProductID:      productID,
+		From:           model.ListingStatusPendingReview,
+		To:             model.ListingStatusDraft,
+		ModerationNote: &reason,
+		Reason:         &reason,
+		ActorID:        &actor.AdminID,
+	}, &entry)
+	if err != nil {
+		return model.Product{}, listingWriteError(err)
+	}
+	return updated, nil
+}
+
+// --- Wishlist ---
+
+func (s *service) GetWishlist(ctx context.Context, accountID uuid.UUID) ([]WishlistItem, error) {
+	items, err := s.repo.FindWishlist(ctx, accountID)
+	if err != nil {
+		log.Printf("Error finding wishlist: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return items, nil
+}
+
+func (s *service) AddToWishlist(ctx context.Context, accountID uuid.UUID, req WishlistRequest) ([]WishlistItem, error) {
+	product, err := s.repo.FindProductByID(ctx, req.ProductID)
+	if err != nil {
+		return nil, s.referenceReadError(err, "product")
+	}
+	if product.Status != model.ListingStatusPublished {
+		return nil, apperror.New(apperror.ErrCodeConflict, "only available listings can be wishlisted")
+	}
+
+	if err := s.repo.SaveWishlistItem(ctx, accountID, req.ProductID); err != nil {
+		log.Printf("Error saving wishlist item: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "failed to update wishlist")
+	}
+	return s.GetWishlist(ctx, accountID)
+}
+
+func (s *service) RemoveFromWishlist(ctx context.Context, accountID, productID uuid.UUID) ([]WishlistItem, error) {
+	if err := s.repo.DeleteWishlistItem(ctx, accountID, productID); err != nil {
+		log.Printf("Error deleting wishlist item: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "failed to update wishlist")
+	}
+	return s.GetWishlist(ctx, accountID)
+}
+
+// --- Saved Search ---
+
+func (s *service) SaveSearch(ctx context.Context, accountID uuid.UUID, filter ProductFilter, req SavedSearchRequest) (model.SavedSearch, error) {
+	filter, err := s.normalizeFilter(ctx, filter)
+	if err != nil {
+		return model.SavedSearch{}, err
+	}
+	if req.Alert == "" {
+		req.Alert = model.SavedSearchAlertInstant
+	}
+
+	count, err := s.repo.CountSavedSearches(ctx, accountID)
+	if err != nil {
+		log.Printf("Error counting saved searches: %v", err)
+		return model.SavedSearch{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if count >= maxSavedSearches {
+		return model.SavedSearch{}, apperror.New(apperror.ErrCodeConflict, fmt.Sprintf("you can save up to %d searches", maxSavedSearches))
+	}
+
+	encoded, err := json.Marshal(filter)
+	if err != nil {
+		log.Printf("Error encoding saved search filter: %v", err)
+		return model.SavedSearch{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	search := model.SavedSearch{
+		AccountID: accountID,
+		Name:      strings.TrimSpace(req.Name),
+		Filter:    encoded,
+		Alert:     req.Alert,
+		BrandID:   filter.BrandID,
+		SizeID:    filter.SizeID,
+	}
+	saved, err := s.repo.SaveSavedSearch(ctx, search)
+	if err != nil {
+		log.Printf("Error saving search: %v", err)
+		return model.SavedSearch{}, apperror.New(apperror.ErrCodeInternal, "failed to save search")
+	}
+	return saved, nil
+}
+
+func (s *service) GetSavedSearches(ctx context.Context, accountID uuid.UUID) ([]model.SavedSearch, error) {
+	searches, err := s.repo.FindSavedSearches(ctx, accountID)
+	if err != nil {
+		log.Printf("Error finding saved searches: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return searches, nil
+}
+
+func (s *service) UpdateSavedSearch(ctx context.Context, accountID, searchID uuid.UUID, req UpdateSavedSearchRequest) (model.SavedSearch, error) {
+	search, err := s.findSavedSearch(ctx, accountID, searchID)
+	if err != nil {
+		return model.SavedSearch{}, err
+	}
+	if req.Name != nil {
+		search.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Alert != nil {
+		search.Alert = *req.Alert
+	}
+
+	updated, err := s.repo.UpdateSavedSearch(ctx, search)
+	if err != nil {
+		log.Printf("Error updating saved search: %v", err)
+		return model.SavedSearch{}, apperror.New(apperror.ErrCodeInternal, "failed to update saved search")
+	}
+	return updated, nil
+}
+
+func (s *service) DeleteSavedSearch(ctx context.Context, accountID, searchID uuid.UUID) error {
+	if err := s.repo.DeleteSavedSearch(ctx, accountID, searchID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.New(apperror.ErrCodeNotFound, "saved search not found")
+		}
+		log.Printf("Error deleting saved search: %v", err)
+		return apperror.New(apperror.ErrCodeInternal, "failed to delete saved search")
+	}
+	return nil
+}
+
+func (s *service) RunSavedSearch(ctx context.Context, accountID, searchID uuid.UUID, page PageRequest) (ProductSearchResponse, error) {
+	search, err := s.findSavedSearch(ctx, accountID, searchID)
+	if err != nil {
+		return ProductSearchResponse{}, err
+	}
+
+	var filter ProductFilter
+	if err := json.Unmarshal(search.Filter, &filter); err != nil {
+		log.Printf("Error decoding saved search filter: %v", err)
+		return ProductSearchResponse{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	filter.Page, filter.Limit = page.Page, page.Limit
+	return s.SearchProducts(ctx, filter, &accountID)
+}
+
+func (s *service) SendSavedSearchAlerts(ctx context.Context) (int, error) {
+	// Cocokkan semua listing yang baru tayang sebelum mengirim
+	for {
+		processed, err := s.repo.TransactionMatchSavedSearches(ctx, savedSearchBatchSize)
+		if err != nil {
+			return 0, fmt.Errorf("match saved searches: %w", err)
+		}
+		if processed < savedSearchBatchSize {
+			break
+		}
+	}
+
+	sent, err := s.repo.TransactionDispatchSavedSearchAlerts(ctx, time.Now().Add(-savedSearchDigestInterval), maxSavedSearchAlertsPerRun, buildSavedSearchNotification)
+	if err != nil {
+		return 0, fmt.Errorf("dispatch saved search alerts: %w", err)
+	}
+	return sent, nil
+}
+
+// findSavedSearch mengambil saved search milik akun, 404 jika tidak ada.
+func (s *service) findSavedSearch(ctx context.Context, accountID, searchID uuid.UUID) (model.SavedSearch, error) {
+	search, err := s.repo.FindSavedSearch(ctx, accountID, searchID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.SavedSearch{}, apperror.New(apperror.ErrCodeNotFound, "saved search not found")
+		}
+		log.Printf("Error finding saved search: %v", err)
+		return model.SavedSearch{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return search, nil
+}
+
+// buildSavedSearchNotification menyusun satu notifikasi untuk semua listing baru yang cocok.
+func buildSavedSearchNotification(search model.SavedSearch, items []SavedSearchMatch, total int) (model.Notification, error) {
+	data, err := json.Marshal(map[string]any{
+		"saved_search_id": search.ID,
+		"items":           items,
+		"total":           total,
+	})
+	if err != nil {
+		return model.Notification{}, err
+	}
+
+	title := fmt.Sprintf("New listing for %q", search.Name)
+	body := fmt.Sprintf("%s (%d) matches your saved search.", items[0].Name, items[0].Price)
+	if total > 1 {
+		title = fmt.Sprintf("%d new listings for %q", total, search.Name)
+		body = fmt.Sprintf("%s (%d) and %d more match your saved search.", items[0].Name, items[0].Price, total-1)
+	}
+
+	return model.Notification{
+		AccountID: search.AccountID,
+		Type:      model.NotificationTypeSavedSearch,
+		Title:     title,
+		Body:      body,
+		Data:      data,
+	}, nil
+}
+
+// StartSavedSearchAlertJob menjalankan SendSavedSearchAlerts secara berkala sampai ctx dibatalkan.
+func StartSavedSearchAlertJob(ctx context.Context, svc Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sent, err := svc.SendSavedSearchAlerts(ctx)
+			if err != nil {
+				log.Printf("Error sending saved search alerts: %v", err)
+				continue
+			}
+			if sent > 0 {
+				log.Printf("Saved search job sent %d notification(s)", sent)
+			}
+		}
+	}
+}
+
+// sellerShopID mengambil shop tempat seller menjadi anggota, 403 jika akun belum punya shop
+// atau role-nya tidak boleh mengelola listing.
+func (s *service) sellerShopID(ctx context.Context, sellerID uuid.UUID) (uuid.UUID, error) {
+	member, err := s.repo.FindShopMembership(ctx, sellerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, apperror.New(apperror.ErrCodeForbidden, "open a shop before listing products")
+		}
+		log.Printf("Error finding seller shop: %v", err)
+		return uuid.Nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if !staff.Can(member.Role, staff.PermissionListings) {
+		return uuid.Nil, apperror.New(apperror.ErrCodeForbidden, "your shop role does not allow managing listings")
+	}
+	return member.ShopID, nil
+}
+
+// findOwnedProduct mengambil produk dan memastikan produk itu milik shop seller.
+func (s *service) findOwnedProduct(ctx context.Context, sellerID, productID uuid.UUID) (model.Product, error) {
+	shopID, err := s.sellerShopID(ctx, sellerID)
+	if err != nil {
+		return model.Product{}, err
+	}
+	product, err := s.repo.FindProductByID(ctx, productID)
+	if err != nil {
+		return model.Product{}, s.referenceReadError(err, "product")
+	}
+	if product.ShopID != shopID {
+		return model.Product{}, apperror.New(apperror.ErrCodeForbidden, "you do not own this product")
+	}
+	return product, nil
+}
+
+func (s *service) findPendingListing(ctx context.Context, productID uuid.UUID) (model.Product, error) {
+	product, err := s.repo.FindProductByID(ctx, productID)
+	if err != nil {
+		return model.Product{}, s.referenceReadError(err, "product")
+	}
+	if product.Status != model.ListingStatusPendingReview {
+		return model.Product{}, apperror.New(apperror.ErrCodeConflict, "listing is not waiting for review")
+	}
+	return product, nil
+}
+
+func (s *service) productDetail(ctx context.Context, product model.Product) (ProductDetail, error) {
+	images, err := s.repo.FindProductImages(ctx, product.ID)
+	if err != nil {
+		log.Printf("Error finding product images: %v", err)
+		return ProductDetail{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return ProductDetail{Product: product, Images: images}, nil
+}
+
+// needsReview menentukan apakah listing baru dari shop ini harus masuk antrian moderasi.
+func (s *service) needsReview(ctx context.Context, shopID uuid.UUID) (bool, error) {
+	if !s.listing.ReviewEnabled {
+		return false, nil
+	}
+	published, err := s.repo.CountPublishedListings(ctx, shopID)
+	if err != nil {
+		log.Printf("Error counting published listings: %v", err)
+		return false, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return published < s.listing.TrustedAfter, nil
+}
+
+// requireVerifiedShop menolak listing bernilai tinggi dari shop yang belum lolos verifikasi KYC.
+func (s *service) requireVerifiedShop(ctx context.Context, product model.Product) error {
+	if s.listing.VerifiedPriceFrom <= 0 || product.Price == nil || *product.Price < s.listing.VerifiedPriceFrom {
+		return nil
+	}
+	status, err := s.repo.FindShopKYCStatus(ctx, product.ShopID)
+	if err != nil {
+		log.Printf("Error finding shop kyc status: %v", err)
+		return apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if status != model.ShopKYCVerified {
+		return apperror.New(apperror.ErrCodeForbidden, fmt.Sprintf("listings priced at %d or more require a verified shop", s.listing.VerifiedPriceFrom))
+	}
+	return nil
+}
+
+// applyProductRequest menyalin field yang diisi ke product, dengan validasi ringan yang
+// berlaku juga untuk draft (nilai tidak negatif, referensi harus ada).
+func (s *service) applyProductRequest(ctx context.Context, product *model.Product, req ProductRequest) error {
+	if req.Name != nil {
+		product.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Summary != nil {
+		product.Summary = req.Summary
+	}
+	if req.Description != nil {
+		product.Description = req.Description
+	}
+	if req.Price != nil {
+		if *req.Price < 0 {
+			return apperror.New(apperror.ErrCodeValidation, "price cannot be negative")
+		}
+		product.Price = req.Price
+	}
+	if req.Stock != nil {
+		if *req.Stock < 0 {
+			return apperror.New(apperror.ErrCodeValidation, "stock cannot be negative")
+		}
+		product.Stock = *req.Stock
+	}
+	if req.CategoryID != nil {
+		if _, err := s.findCategory(ctx, *req.CategoryID); err != nil {
+			return err
+		}
+		product.CategoryID = req.CategoryID
+	}
+	if req.ConditionID != nil {
+		if _, err := s.repo.FindConditionByID(ctx, int(*req.ConditionID)); err != nil {
+			return s.referenceReadError(err, "condition")
+		}
+		product.ConditionID = req.ConditionID
+	}
+	if req.SizeID != nil {
+		if _, err := s.repo.FindSizeByID(ctx, *req.SizeID); err != nil {
+			return s.referenceReadError(err, "size")
+		}
+		product.SizeID = req.SizeID
+	}
+	if req.BrandID != nil {
+		if _, err := s.repo.FindBrandByID(ctx, *req.BrandID); err != nil {
+			return s.referenceReadError(err, "brand")
+		}
+		product.BrandID = req.BrandID
+	}
+	return nil
+}
+
+// validateListing adalah validasi ketat untuk listing yang disubmit / sudah tayang.
+func (s *service) validateListing(ctx context.Context, product model.Product) error {
+	var missing []string
+	if product.CategoryID == nil {
+		missing = append(missing, "category_id")
+	}
+	if product.ConditionID == nil {
+		missing = append(missing, "condition_id")
+	}
+	if product.SizeID == nil {
+		missing = append(missing, "size_id")
+	}
+	if product.Price == nil || *product.Price <= 0 {
+		missing = append(missing, "price")
+	}
+	if len(missing) > 0 {
+		return apperror.New(apperror.ErrCodeValidation, "listing is incomplete, missing: "+strings.Join(missing, ", "))
+	}
+	if product.Stock < 1 && product.Status != model.ListingStatusSold {
+		return apperror.New(apperror.ErrCodeValidation, "stock must be at least 1, mark the listing as sold instead")
+	}
+
+	// Ukuran wajib dari skema kategori
+	schema, err := s.categorySchema(ctx, *product.CategoryID)
+	if err != nil {
+		return err
+	}
+	measurements, err := s.repo.FindProductMeasurements(ctx, []uuid.UUID{product.ID})
+	if err != nil {
+		log.Printf("Error finding product measurements: %v", err)
+		return apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	filled := make(map[string]bool, len(measurements))
+	for _, m := range measurements {
+		filled[m.FieldKey] = true
+	}
+	for _, f := range schema {
+		if f.Required && !filled[f.Key] {
+			missing = append(missing, f.Key)
+		}
+	}
+	if len(missing) > 0 {
+		return apperror.New(apperror.ErrCodeValidation, "listing is missing required measurements: "+strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func canTransition(from, to string) bool {
+	for _, allowed := range listingTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// listingWriteError memetakan error saat menyimpan perubahan listing.
+func listingWriteError(err error) error {
+	if errors.Is(err, ErrListingChanged) {
+		return apperror.New(apperror.ErrCodeConflict, "listing was changed by someone else, reload and try again")
+	}
+	if errors.Is(err, ErrDuplicateName) {
+		return apperror.New(apperror.ErrCodeConflict, "another listing with the same name was just saved, try again")
+	}
+	if errors.Is(err, inventory.ErrStockBelowHeld) {
+		return apperror.New(apperror.ErrCodeConflict, "stock cannot be lower than the quantity reserved by ongoing checkouts")
+	}
+	log.Printf("Error saving listing: %v", err)
+	return apperror.New(apperror.ErrCodeInternal, "failed to save listing")
+}
+
+func normalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	return page, limit
+}
+
+// --- Category ---
+
+func (s *service) GetCategoryTree(ctx context.Context) ([]CategoryNode, error) {
+	if cached, ok := s.cache.Get(cacheKeyCategoryTree); ok {
+		return cached.([]CategoryNode), nil
+	}
+
+	categories, err := s.repo.FindAllCategories(ctx)
+	if err != nil {
+		log.Printf("Error finding categories: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	tree := buildCategoryTree(categories)
+	s.cache.Set(cacheKeyCategoryTree, tree)
+	return tree, nil
+}
+
+func (s *service) CreateCategory(ctx context.Context, actor audit.Actor, req CreateCategoryRequest) (CategoryResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return CategoryResponse{}, apperror.New(apperror.ErrCodeValidation, "category name is required")
+	}
+
+	if req.ParentID != nil {
+		if _, err := s.findCategory(ctx, *req.ParentID); err != nil {
+			return CategoryResponse{}, err
+		}
+	}
+
+	categorySlug, err := s.uniqueCategorySlug(ctx, name, "")
+	if err != nil {
+		return CategoryResponse{}, err
+	}
+
+	saved, err := s.repo.SaveCategory(ctx, model.ProductCategory{
+		ParentID:  req.ParentID,
+		Name:      name,
+		Slug:      categorySlug,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, actor.Entry("category.create", fmt.Sprintf("created category %q", name)))
+	if err != nil {
+		log.Printf("Error saving category: %v", err)
+		return CategoryResponse{}, apperror.New(apperror.ErrCodeInternal, "failed to create category")
+	}
+
+	s.invalidateReference(ReferenceCategory)
+	return toCategoryResponse(saved), nil
+}
+
+func (s *service) RenameCategory(ctx context.Context, actor audit.Actor, categoryID int, req RenameCategoryRequest) (CategoryResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return CategoryResponse{}, apperror.New(apperror.ErrCodeValidation, "category name is required")
+	}
+
+	category, err := s.findCategory(ctx, categoryID)
+	if err != nil {
+		return CategoryResponse{}, err
+	}
+
+	category.Slug, err = s.uniqueCategorySlug(ctx, name, category.Slug)
+	if err != nil {
+		return CategoryResponse{}, err
+	}
+	entry := actor.Entry("category.rename", fmt.Sprintf("renamed category %d from %q to %q", categoryID, category.Name, name))
+	category.Name = name
+	category.UpdatedAt = time.Now()
+
+	updated, err := s.repo.UpdateCategoryName(ctx, category, entry)
+	if err != nil {
+		log.Printf("Error renaming category: %v", err)
+		return CategoryResponse{}, apperror.New(apperror.ErrCodeInternal, "failed to rename category")
+	}
+
+	s.invalidateReference(ReferenceCategory)
+	return toCategoryResponse(updated), nil
+}
+
+func (s *service) MoveCategory(ctx context.Context, actor audit.Actor, categoryID int, req MoveCategoryRequest) (CategoryResponse, error) {
+	if _, err := s.findCategory(ctx, categoryID); err != nil {
+		return CategoryResponse{}, err
+	}
+	if req.ParentID != nil {
+		if _, err := s.findCategory(ctx, *req.ParentID); err != nil {
+			return CategoryResponse{}, err
+		}
+	}
+
+	description := fmt.Sprintf("moved category %d to root", categoryID)
+	if req.ParentID != nil {
+		description = fmt.Sprintf("moved category %d under %d", categoryID, *req.ParentID)
+	}
+
+	moved, err := s.repo.TransactionMoveCategory(ctx, categoryID, req.ParentID, actor.Entry("category.move", description))
+	if err != nil {
+		if errors.Is(err, ErrCategoryCycle) {
+			return CategoryResponse{}, apperror.New(apperror.ErrCodeConflict, "category cannot be moved under itself or its descendants")
+		}
+		log.Printf("Error moving category: %v", err)
+		return CategoryResponse{}, apperror.New(apperror.ErrCodeInternal, "failed to move category")
+	}
+
+	s.invalidateReference(ReferenceCategory)
+	return toCategoryResponse(moved), nil
+}
+
+// --- Reference Data ---
+
+func (s *service) GetBrands(ctx context.Context) ([]model.Brand, error) {
+	if cached, ok := s.cache.Get(cacheKeyBrands); ok {
+		return cached.([]model.Brand), nil
+	}
+	brands, err := s.repo.FindActiveBrands(ctx)
+	if err != nil {
+		log.Printf("Error finding brands: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	s.cache.Set(cacheKeyBrands, brands)
+	return brands, nil
+}
+
+func (s *service) GetConditions(ctx context.Context) ([]model.ProductCondition, error) {
+	if cached, ok := s.cache.Get(cacheKeyConditions); ok {
+		return cached.([]model.ProductCondition), nil
+	}
+	conditions, err := s.repo.FindActiveConditions(ctx)
+	if err != nil {
+		log.Printf("Error finding conditions: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	s.cache.Set(cacheKeyConditions, conditions)
+	return conditions, nil
+}
+
+func (s *service) GetSizes(ctx context.Context) ([]model.ProductSize, error) {
+	if cached, ok := s.cache.Get(cacheKeySizes); ok {
+		return cached.([]model.ProductSize), nil
+	}
+	sizes, err := s.repo.FindActiveSizes(ctx)
+	if err != nil {
+		log.Printf("Error finding sizes: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	s.cache.Set(cacheKeySizes, sizes)
+	return sizes, nil
+}
+
+func (s *service) GetCategories(ctx context.Context) ([]CategoryResponse, error) {
+	if cached, ok := s.cache.Get(cacheKeyCategories); ok {
+		return cached.([]CategoryResponse), nil
+	}
+	categories, err := s.repo.FindAllCategories(ctx)
+	if err != nil {
+		log.Printf("Error finding categories: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	result := make([]CategoryResponse, 0, len(categories))
+	for _, c := range categories {
+		result = append(result, toCategoryResponse(c))
+	}
+	s.cache.Set(cacheKeyCategories, result)
+	return result, nil
+}
+
+func (s *service) CreateBrand(ctx context.Context, actor audit.Actor, req BrandRequest) (model.Brand, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return model.Brand{}, apperror.New(apperror.ErrCodeValidation, "brand name is required")
+	}
+
+	saved, err := s.repo.SaveBrand(ctx, model.Brand{
+		Name:      name,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, actor.Entry("brand.create", fmt.Sprintf("created brand %q", name)))
+	if err != nil {
+		return model.Brand{}, s.referenceWriteError(err, "brand")
+	}
+
+	s.invalidateReference(ReferenceBrand)
+	return saved, nil
+}
+
+func (s *service) UpdateBrand(ctx context.Context, actor audit.Actor, brandID int, req BrandRequest) (model.Brand, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return model.Brand{}, apperror.New(apperror.ErrCodeValidation, "brand name is required")
+	}
+
+	brand, err := s.repo.FindBrandByID(ctx, brandID)
+	if err != nil {
+		return model.Brand{}, s.referenceReadError(err, "brand")
+	}
+
+	entry := actor.Entry("brand.update", fmt.Sprintf("renamed brand %d from %q to %q", brandID, brand.Name, name))
+	brand.Name = name
+	brand.UpdatedAt = time.Now()
+
+	updated, err := s.repo.UpdateBrand(ctx, brand, entry)
+	if err != nil {
+		return model.Brand{}, s.referenceWriteError(err, "brand")
+	}
+
+	s.invalidateReference(ReferenceBrand)
+	return updated, nil
+}
+
+func (s *service) UploadBrandLogo(ctx context.Context, actor audit.Actor, brandID int, file *multipart.FileHeader) (model.Brand, error) {
+	if file.Size > maxLogoSize {
+		return model.Brand{}, apperror.New(apperror.ErrCodeValidation, "logo must be at most 2 MB")
+	}
+
+	brand, err := s.repo.FindBrandByID(ctx, brandID)
+	if err != nil {
+		return model.Brand{}, s.referenceReadError(err, "brand")
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return model.Brand{}, apperror.New(apperror.ErrCodeValidation, "failed to read logo file")
+	}
+	defer src.Close()
+
+	// Tentukan tipe file dari isinya, bukan dari nama file / header yang dikirim client
+	head := make([]byte, 512)
+	n, _ := src.Read(head)
+	ext, ok := logoExtensions[http.DetectContentType(head[:n])]
+	if !ok {
+		return model.Brand{}, apperror.New(apperror.ErrCodeValidation, "logo must be a PNG, JPEG or WebP image")
+	}
+	if _, err := src.Seek(0, 0); err != nil {
+		return model.Brand{}, apperror.New(apperror.ErrCodeInternal, "failed to read logo file")
+	}
+
+	key := path.Join("brands", fmt.Sprintf("%d-%s%s", brandID, uuid.NewString(), ext))
+	url, err := s.storage.Save(ctx, key, src)
+	if err != nil {
+		log.Printf("Error storing brand logo: %v", err)
+		return model.Brand{}, apperror.New(apperror.ErrCodeInternal, "failed to store logo")
+	}
+
+	brand.LogoURL = &url
+	brand.UpdatedAt = time.Now()
+	updated, err := s.repo.UpdateBrand(ctx, brand, actor.Entry("brand.logo", fmt.Sprintf("uploaded logo for brand %d: %s", brandID, url)))
+	if err != nil {
+		s.storage.Delete(ctx, key)
+		return model.Brand{}, s.referenceWriteError(err, "brand")
+	}
+
+	s.invalidateReference(ReferenceBrand)
+	return updated, nil
+}
+
+func (s *service) CreateCondition(ctx context.Context, actor audit.Actor, req ConditionRequest) (model.ProductCondition, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return model.ProductCondition{}, apperror.New(apperror.ErrCodeValidation, "condition name is required")
+	}
+
+	googleCondition := req.GoogleCondition
+	if googleCondition == "" {
+		googleCondition = "used"
+	}
+
+	saved, err := s.repo.SaveCondition(ctx, model.ProductCondition{
+		Name:            name,
+		GoogleCondition: googleCondition,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}, actor.Entry("condition.create", fmt.Sprintf("created condition %q", name)))
+	if err != nil {
+		return model.ProductCondition{}, s.referenceWriteError(err, "condition")
+	}
+
+	s.invalidateReference(ReferenceCondition)
+	return saved, nil
+}
+
+func (s *service) UpdateCondition(ctx context.Context, actor audit.Actor, conditionID int, req ConditionRequest) (model.ProductCondition, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return model.ProductCondition{}, apperror.New(apperror.ErrCodeValidation, "condition name is required")
+	}
+
+	condition, err := s.repo.FindConditionByID(ctx, conditionID)
+	if err != nil {
+		return model.ProductCondition{}, s.referenceReadError(err, "condition")
+	}
+
+	entry := actor.Entry("condition.update", fmt.Sprintf("renamed condition %d from %q to %q", conditionID, condition.Name, name))
+	condition.Name = name
+	if req.GoogleCondition != "" {
+		condition.GoogleCondition = req.GoogleCondition
+	}
+	condition.UpdatedAt = time.Now()
+
+	updated, err := s.repo.UpdateCondition(ctx, condition, entry)
+	if err != nil {
+		return model.ProductCondition{}, s.referenceWriteError(err, "condition")
+	}
+
+	s.invalidateReference(ReferenceCondition)
+	return updated, nil
+}
+
+func (s *service) CreateSize(ctx context.Context, actor audit.Actor, req SizeRequest) (model.ProductSize, error) {
+	name := strings.TrimSpace(req.SizeName)
+	if name == "" {
+		return model.ProductSize{}, apperror.New(apperror.ErrCodeValidation, "size name is required")
+	}
+
+	saved, err := s.repo.SaveSize(ctx, model.ProductSize{
+		SizeName:  name,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, actor.Entry("size.create", fmt.Sprintf("created size %q", name)))
+	if err != nil {
+		return model.ProductSize{}, s.referenceWriteError(err, "size")
+	}
+
+	s.invalidateReference(ReferenceSize)
+	return saved, nil
+}
+
+func (s *service) UpdateSize(ctx context.Context, actor audit.Actor, sizeID int, req SizeRequest) (model.ProductSize, error) {
+	name := strings.TrimSpace(req.SizeName)
+	if name == "" {
+		return model.ProductSize{}, apperror.New(apperror.ErrCodeValidation, "size name is required")
+	}
+
+	size, err := s.repo.FindSizeByID(ctx, sizeID)
+	if err != nil {
+		return model.ProductSize{}, s.referenceReadError(err, "size")
+	}
+
+	entry := actor.Entry("size.update", fmt.Sprintf("renamed size %d from %q to %q", sizeID, size.SizeName, name))
+	size.SizeName = name
+	size.UpdatedAt = time.Now()
+
+	updated, err := s.repo.UpdateSize(ctx, size, entry)
+	if err != nil {
+		return model.ProductSize{}, s.referenceWriteError(err, "size")
+	}
+
+	s.invalidateReference(ReferenceSize)
+	return updated, nil
+}
+
+func (s *service) DeleteReference(ctx context.Context, actor audit.Actor, kind ReferenceKind, id int, req DeleteReferenceRequest) error {
+	// Delete dengan target berarti merge: produk dipindah dulu, baru source dihapus
+	if req.MergeTargetID != nil {
+		return s.MergeReference(ctx, actor, kind, id, MergeReferenceRequest{TargetID: *req.MergeTargetID})
+	}
+
+	entry := actor.Entry(string(kind)+".delete", fmt.Sprintf("deleted %s %d", kind, id))
+	if err := s.repo.TransactionSoftDeleteReference(ctx, kind, id, entry); err != nil {
+		if errors.Is(err, ErrReferenceInUse) {
+			return apperror.New(apperror.ErrCodeConflict, fmt.Sprintf("%s is still in use, provide merge_target_id to re-point it first", kind))
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.New(apperror.ErrCodeNotFound, fmt.Sprintf("%s not found", kind))
+		}
+		log.Printf("Error deleting %s: %v", kind, err)
+		return apperror.New(apperror.ErrCodeInternal, fmt.Sprintf("failed to delete %s", kind))
+	}
+
+	s.invalidateReference(kind)
+	return nil
+}
+
+func (s *service) MergeReference(ctx context.Context, actor audit.Actor, kind ReferenceKind, sourceID int, req MergeReferenceRequest) error {
+	if sourceID == req.TargetID {
+		return apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("cannot merge a %s into itself", kind))
+	}
+
+	entry := actor.Entry(string(kind)+".merge", fmt.Sprintf("merged %s %d into %d", kind, sourceID, req.TargetID))
+	if err := s.repo.TransactionMergeReference(ctx, kind, sourceID, req.TargetID, entry); err != nil {
+		if errors.Is(err, ErrCategoryCycle) {
+			return apperror.New(apperror.ErrCodeConflict, "cannot merge a category into one of its descendants")
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.New(apperror.ErrCodeNotFound, fmt.Sprintf("%s not found", kind))
+		}
+		log.Printf("Error merging %s: %v", kind, err)
+		return apperror.New(apperror.ErrCodeInternal, fmt.Sprintf("failed to merge %s", kind))
+	}
+
+	s.invalidateReference(kind)
+	return nil
+}
+
+// --- Vintage Attributes & Tags ---
+
+func (s *service) GetLabelTypes(ctx context.Context) ([]model.LabelType, error) {
+	if cached, ok := s.cache.Get(cacheKeyLabelTypes); ok {
+		return cached.([]model.LabelType), nil
+	}
+
+	labelTypes, err := s.repo.FindLabelTypes(ctx)
+	if err != nil {
+		log.Printf("Error finding label types: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	s.cache.Set(cacheKeyLabelTypes, labelTypes)
+	return labelTypes, nil
+}
+
+func (s *service) GetMaterials(ctx context.Context) ([]model.Material, error) {
+	if cached, ok := s.cache.Get(cacheKeyMaterials); ok {
+		return cached.([]model.Material), nil
+	}
+
+	materials, err := s.repo.FindMaterials(ctx)
+	if err != nil {
+		log.Printf("Error finding materials: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	s.cache.Set(cacheKeyMaterials, materials)
+	return materials, nil
+}
+
+func (s *service) GetProductAttributes(ctx context.Context, productID uuid.UUID) (ProductAttributesResponse, error) {
+	if _, err := s.repo.FindProductCategoryID(ctx, productID); err != nil {
+		return ProductAttributesResponse{}, s.referenceReadError(err, "product")
+	}
+	return s.productAttributes(ctx, productID)
+}
+
+func (s *service) productAttributes(ctx context.Context, productID uuid.UUID) (ProductAttributesResponse, error) {
+	attrs, err := s.repo.FindProductAttributes(ctx, productID)
+	if err != nil {
+		return ProductAttributesResponse{}, s.referenceReadError(err, "product")
+	}
+	return attrs, nil
+}
+
+func (s *service) SetProductAttributes(ctx context.Context, sellerID, productID uuid.UUID, req ProductAttributesRequest) (ProductAttributesResponse, error) {
+	if _, err := s.findOwnedProduct(ctx, sellerID, productID); err != nil {
+		return ProductAttributesResponse{}, err
+	}
+
+	if req.EraDecade != nil {
+		latest := int16(time.Now().Year() / 10 * 10)
+		if *req.EraDecade%10 != 0 || *req.EraDecade < minEraDecade || *req.EraDecade > latest {
+			return ProductAttributesResponse{}, apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("era_decade must be a decade between %d and %d, e.g. 1980", minEraDecade, latest))
+		}
+	}
+	if req.CountryOfOrigin != nil {
+		country := strings.ToUpper(strings.TrimSpace(*req.CountryOfOrigin))
+		if !isCountryCode(country) {
+			return ProductAttributesResponse{}, apperror.New(apperror.ErrCodeValidation, "country_of_origin must be an ISO 3166-1 alpha-2 code, e.g. US")
+		}
+		req.CountryOfOrigin = &country
+	}
+	if err := s.validateLabelType(ctx, req.LabelTypeID); err != nil {
+		return ProductAttributesResponse{}, err
+	}
+	if err := s.validateMaterials(ctx, req.Materials); err != nil {
+		return ProductAttributesResponse{}, err
+	}
+
+	tags := make([]string, 0, len(req.Tags))
+	seen := make(map[string]bool, len(req.Tags))
+	for _, raw := range req.Tags {
+		name := normalizeTag(raw)
+		if name == "" || seen[name] {
+			continue
+		}
+		if len([]rune(name)) > maxTagLength {
+			return ProductAttributesResponse{}, apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("tag %q is longer than %d characters", name, maxTagLength))
+		}
+		seen[name] = true
+		tags = append(tags, name)
+	}
+	if len(tags) > maxTagsPerProduct {
+		return ProductAttributesResponse{}, apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("a product can have at most %d tags", maxTagsPerProduct))
+	}
+
+	if err := s.repo.TransactionSetProductAttributes(ctx, productID, req, tags); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ProductAttributesResponse{}, apperror.New(apperror.ErrCodeNotFound, "product not found")
+		}
+		log.Printf("Error saving product attributes: %v", err)
+		return ProductAttributesResponse{}, apperror.New(apperror.ErrCodeInternal, "failed to save product attributes")
+	}
+	return s.productAttributes(ctx, productID)
+}
+
+func (s *service) BrowseTags(ctx context.Context, filter TagFilter) ([]TagSummary, error) {
+	filter.Query = normalizeTag(filter.Query)
+	if filter.Limit < 1 {
+		filter.Limit = defaultTagLimit
+	}
+	if filter.Limit > maxTagLimit {
+		filter.Limit = maxTagLimit
+	}
+
+	tags, err := s.repo.FindTags(ctx, filter)
+	if err != nil {
+		log.Printf("Error finding tags: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return tags, nil
+}
+
+func (s *service) MergeTag(ctx context.Context, actor audit.Actor, sourceID int, req MergeTagRequest) error {
+	if sourceID == req.TargetID {
+		return apperror.New(apperror.ErrCodeValidation, "a tag cannot be merged into itself")
+	}
+	source, err := s.repo.FindTagByID(ctx, sourceID)
+	if err != nil {
+		return s.referenceReadError(err, "tag")
+	}
+	target, err := s.repo.FindTagByID(ctx, req.TargetID)
+	if err != nil {
+		return s.referenceReadError(err, "target tag")
+	}
+
+	entry := actor.Entry("tag.merge", fmt.Sprintf("merged tag %q into %q", source.Name, target.Name))
+	if err := s.repo.TransactionMergeTag(ctx, sourceID, req.TargetID, entry); err != nil {
+		if errors.Is(err, ErrTagNotFound) {
+			return apperror.New(apperror.ErrCodeNotFound, "tag not found")
+		}
+		log.Printf("Error merging tag: %v", err)
+		return apperror.New(apperror.ErrCodeInternal, "failed to merge tag")
+	}
+	return nil
+}
+
+func (s *service) AddTagSynonym(ctx context.Context, actor audit.Actor, tagID int, req TagSynonymRequest) (model.TagSynonym, error) {
+	alias := normalizeTag(req.Alias)
+	if alias == "" || len([]rune(alias)) > maxTagLength {
+		return model.TagSynonym{}, apperror.New(apperror.ErrCodeValidation, "invalid alias")
+	}
+	tag, err := s.repo.FindTagByID(ctx, tagID)
+	if err != nil {
+		return model.TagSynonym{}, s.referenceReadError(err, "tag")
+	}
+
+	entry := actor.Entry("tag.synonym.create", fmt.Sprintf("added alias %q for tag %q", alias, tag.Name))
+	saved, err := s.repo.SaveTagSynonym(ctx, model.TagSynonym{Alias: alias, TagID: tagID}, entry)
+	if err != nil {
+		if errors.Is(err, ErrDuplicateName) {
+			return model.TagSynonym{}, apperror.New(apperror.ErrCodeConflict, "alias is already a tag or an alias, merge the tags instead")
+		}
+		log.Printf("Error saving tag synonym: %v", err)
+		return model.TagSynonym{}, apperror.New(apperror.ErrCodeInternal, "failed to save alias")
+	}
+	return saved, nil
+}
+
+func (s *service) DeleteTagSynonym(ctx context.Context, actor audit.Actor, alias string) error {
+	alias = normalizeTag(alias)
+	entry := actor.Entry("tag.synonym.delete", fmt.Sprintf("removed alias %q", alias))
+	if err := s.repo.DeleteTagSynonym(ctx, alias, entry); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.New(apperror.ErrCodeNotFound, "alias not found")
+		}
+		log.Printf("Error deleting tag synonym: %v", err)
+		return apperror.New(apperror.ErrCodeInternal, "failed to delete alias")
+	}
+	return nil
+}
+
+func (s *service) validateLabelType(ctx context.Context, labelTypeID *int) error {
+	if labelTypeID == nil {
+		return nil
+	}
+	labelTypes, err := s.GetLabelTypes(ctx)
+	if err != nil {
+		return err
+	}
+	for _, lt := range labelTypes {
+		if lt.ID == *labelTypeID {
+			return nil
+		}
+	}
+	return apperror.New(apperror.ErrCodeValidation, "unknown label_type_id")
+}
+
+// validateMaterials memastikan bahan dikenal, tidak dobel, dan total persentasenya tidak lebih dari 100.
+func (s *service) validateMaterials(ctx context.Context, inputs []ProductMaterialInput) error {
+	if len(inputs) == 0 {
+		return nil
+	}
+	materials, err := s.GetMaterials(ctx)
+	if err != nil {
+		return err
+	}
+	known := make(map[int]bool, len(materials))
+	for _, m := range materials {
+		known[m.ID] = true
+	}
+
+	seen := make(map[int]bool, len(inputs))
+	total := 0
+	for _, in := range inputs {
+		if !known[in.MaterialID] {
+			return apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("unknown material_id %d", in.MaterialID))
+		}
+		if seen[in.MaterialID] {
+			return apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("material_id %d listed twice", in.MaterialID))
+		}
+		seen[in.MaterialID] = true
+		if in.Percentage != nil {
+			if *in.Percentage < 1 || *in.Percentage > 100 {
+				return apperror.New(apperror.ErrCodeValidation, "material percentage must be between 1 and 100")
+			}
+			total += int(*in.Percentage)
+		}
+	}
+	if total > 100 {
+		return apperror.New(apperror.ErrCodeValidation, "material percentages add up to more than 100")
+	}
+	return nil
+}
+
+// normalizeTag menyeragamkan penulisan tag: huruf kecil, tanpa '#' di depan, spasi tunggal.
+func normalizeTag(raw string) string {
+	name := strings.ToLower(strings.TrimSpace(raw))
+	name = strings.TrimLeft(name, "#")
+	return strings.Join(strings.Fields(name), " ")
+}
+
+func isCountryCode(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// --- Measurement ---
+
+func (s *service) GetMeasurementFields(ctx context.Context) ([]model.MeasurementField, error) {
+	if cached, ok := s.cache.Get(cacheKeyMeasurements); ok {
+		return cached.([]model.MeasurementField), nil
+	}
+
+	fields, err := s.repo.FindMeasurementFields(ctx)
+	if err != nil {
+		log.Printf("Error finding measurement fields: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	s.cache.Set(cacheKeyMeasurements, fields)
+	return fields, nil
+}
+
+func (s *service) GetCategoryMeasurementSchema(ctx context.Context, categoryID int) ([]MeasurementFieldResponse, error) {
+	if _, err := s.findCategory(ctx, categoryID); err != nil {
+		return nil, err
+	}
+	return s.categorySchema(ctx, categoryID)
+}
+
+func (s *service) SetCategoryMeasurementSchema(ctx context.Context, actor audit.Actor, categoryID int, req SetMeasurementSchemaRequest) ([]MeasurementFieldResponse, error) {
+	if _, err := s.findCategory(ctx, categoryID); err != nil {
+		return nil, err
+	}
+	known, err := s.measurementFieldIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]model.CategoryMeasurementField, 0, len(req.Fields))
+	keys := make([]string, 0, len(req.Fields))
+	seen := make(map[string]bool, len(req.Fields))
+	for i, f := range req.Fields {
+		if _, ok := known[f.Key]; !ok {
+			return nil, apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("unknown measurement %q", f.Key))
+		}
+		if seen[f.Key] {
+			return nil, apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("measurement %q listed twice", f.Key))
+		}
+		seen[f.Key] = true
+		keys = append(keys, f.Key)
+		fields = append(fields, model.CategoryMeasurementField{
+			CategoryID: categoryID,
+			FieldKey:   f.Key,
+			Required:   f.Required,
+			Position:   int16(i),
+		})
+	}
+
+	entry := actor.Entry("category.measurement_schema", fmt.Sprintf("set measurement schema of category %d to [%s]", categoryID, strings.Join(keys, ", ")))
+	if err := s.repo.ReplaceCategoryMeasurementSchema(ctx, categoryID, fields, entry); err != nil {
+		log.Printf("Error saving category measurement schema: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "failed to save measurement schema")
+	}
+	return s.categorySchema(ctx, categoryID)
+}
+
+func (s *service) GetProductMeasurements(ctx context.Context, productID uuid.UUID, viewerID *uuid.UUID) (ProductMeasurementsResponse, error) {
+	categoryID, err := s.repo.FindProductCategoryID(ctx, productID)
+	if err != nil {
+		return ProductMeasurementsResponse{}, s.referenceReadError(err, "product")
+	}
+	return s.productMeasurements(ctx, productID, categoryID, viewerID)
+}
+
+func (s *service) SetProductMeasurements(ctx context.Context, sellerID, productID uuid.UUID, req MeasurementsRequest) (ProductMeasurementsResponse, error) {
+	product, err := s.findOwnedProduct(ctx, sellerID, productID)
+	if err != nil {
+		return ProductMeasurementsResponse{}, err
+	}
+	if product.CategoryID == nil {
+		return ProductMeasurementsResponse{}, apperror.New(apperror.ErrCodeValidation, "set the product category first")
+	}
+
+	schema, err := s.categorySchema(ctx, *product.CategoryID)
+	if err != nil {
+		return ProductMeasurementsResponse{}, err
+	}
+	if len(schema) == 0 {
+		return ProductMeasurementsResponse{}, apperror.New(apperror.ErrCodeValidation, "this category has no measurement schema")
+	}
+
+	allowed := make(map[string]MeasurementFieldResponse, len(schema))
+	for _, f := range schema {
+		allowed[f.Key] = f
+		if _, ok := req.Measurements[f.Key]; f.Required && !ok {
+			return ProductMeasurementsResponse{}, apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("measurement %q is required", f.Key))
+		}
+	}
+	for key, value := range req.Measurements {
+		f, ok := allowed[key]
+		if !ok {
+			return ProductMeasurementsResponse{}, apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("measurement %q is not part of this category", key))
+		}
+		if err := validateMeasurement(f.Key, f.MinCM, f.MaxCM, value); err != nil {
+			return ProductMeasurementsResponse{}, err
+		}
+	}
+
+	if err := s.repo.ReplaceProductMeasurements(ctx, productID, req.Measurements); err != nil {
+		log.Printf("Error saving product measurements: %v", err)
+		return ProductMeasurementsResponse{}, apperror.New(apperror.ErrCodeInternal, "failed to save measurements")
+	}
+	return s.productMeasurements(ctx, productID, *product.CategoryID, nil)
+}
+
+func (s *service) GetMyMeasurements(ctx context.Context, accountID uuid.UUID) (map[string]float64, error) {
+	measurements, err := s.repo.FindAccountMeasurements(ctx, accountID)
+	if err != nil {
+		log.Printf("Error finding account measurements: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	values := make(map[string]float64, len(measurements))
+	for _, m := range measurements {
+		values[m.FieldKey] = m.ValueCM
+	}
+	return values, nil
+}
+
+func (s *service) SetMyMeasurements(ctx context.Context, accountID uuid.UUID, req MeasurementsRequest) (map[string]float64, error) {
+	known, err := s.measurementFieldIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range req.Measurements {
+		f, ok := known[key]
+		if !ok {
+			return nil, apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("unknown measurement %q", key))
+		}
+		if err := validateMeasurement(f.Key, f.MinCM, f.MaxCM, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.ReplaceAccountMeasurements(ctx, accountID, req.Measurements); err != nil {
+		log.Printf("Error saving account measurements: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "failed to save measurements")
+	}
+	return req.Measurements, nil
+}
+
+// measurementFieldIndex mengembalikan daftar field ukuran (dari cache) dalam bentuk map per key.
+func (s *service) measurementFieldIndex(ctx context.Context) (map[string]model.MeasurementField, error) {
+	fields, err := s.GetMeasurementFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]model.MeasurementField, len(fields))
+	for _, f := range fields {
+		index[f.Key] = f
+	}
+	return index, nil
+}
+
+func (s *service) categorySchema(ctx context.Context, categoryID int) ([]MeasurementFieldResponse, error) {
+	schema, err := s.repo.FindCategoryMeasurementSchema(ctx, categoryID)
+	if err != nil {
+		log.Printf("Error finding category measurement schema: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return schema, nil
+}
+
+func (s *service) productMeasurements(ctx context.Context, productID uuid.UUID, categoryID int, viewerID *uuid.UUID) (ProductMeasurementsResponse, error) {
+	schema, err := s.categorySchema(ctx, categoryID)
+	if err != nil {
+		return ProductMeasurementsResponse{}, err
+	}
+	measurements, err := s.repo.FindProductMeasurements(ctx, []uuid.UUID{productID})
+	if err != nil {
+		log.Printf("Error finding product measurements: %v", err)
+		return ProductMeasurementsResponse{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	result := ProductMeasurementsResponse{
+		Measurements: make(map[string]float64, len(measurements)),
+		Schema:       schema,
+	}
+	for _, m := range measurements {
+		result.Measurements[m.FieldKey] = m.ValueCM
+	}
+
+	if viewerID != nil {
+		buyer, err := s.GetMyMeasurements(ctx, *viewerID)
+		if err != nil {
+			return ProductMeasurementsResponse{}, err
+		}
+		known, err := s.measurementFieldIndex(ctx)
+		if err != nil {
+			return ProductMeasurementsResponse{}, err
+		}
+		result.FitsYou = fitsBuyer(result.Measurements, buyer, known)
+	}
+	return result, nil
+}
+
+// attachFit mengisi flag FitsYou di setiap item berdasarkan ukuran tersimpan milik pembeli.
+func (s *service) attachFit(ctx context.Context, items []ProductSummary, accountID uuid.UUID) error {
+	buyer, err := s.repo.FindAccountMeasurements(ctx, accountID)
+	if err != nil || len(buyer) == 0 {
+		return err
+	}
+	known, err := s.measurementFieldIndex(ctx)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	measurements, err := s.repo.FindProductMeasurements(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	buyerValues := make(map[string]float64, len(buyer))
+	for _, m := range buyer {
+		buyerValues[m.FieldKey] = m.ValueCM
+	}
+	perProduct := make(map[uuid.UUID]map[string]float64)
+	for _, m := range measurements {
+		if perProduct[m.ProductID] == nil {
+			perProduct[m.ProductID] = make(map[string]float64)
+		}
+		perProduct[m.ProductID][m.FieldKey] = m.ValueCM
+	}
+	for i := range items {
+		items[i].FitsYou = fitsBuyer(perProduct[items[i].ID], buyerValues, known)
+	}
+	return nil
+}
+
+// fitsBuyer membandingkan ukuran produk dengan ukuran pembeli pada field yang dimiliki keduanya.
+// Hasil nil berarti tidak ada field yang bisa dibandingkan.
+func fitsBuyer(product, buyer map[string]float64, fields map[string]model.MeasurementField) *bool {
+	compared := 0
+	fits := true
+	for key, value := range product {
+		mine, ok := buyer[key]
+		if !ok {
+			continue
+		}
+		compared++
+		if math.Abs(value-mine) > fields[key].FitToleranceCM {
+			fits = false
+		}
+	}
+	if compared == 0 {
+		return nil
+	}
+	return &fits
+}
+
+func validateMeasurement(key string, minCM, maxCM, value float64) error {
+	if value < minCM || value > maxCM {
+		return apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("measurement %q must be between %g and %g cm", key, minCM, maxCM))
+	}
+	return nil
+}
+
+// invalidateReference menghapus cache publik untuk jenis data referensi yang berubah.
+func (s *service) invalidateReference(kind ReferenceKind) {
+	switch kind {
+	case ReferenceBrand:
+		s.cache.Delete(cacheKeyBrands)
+	case ReferenceCondition:
+		s.cache.Delete(cacheKeyConditions)
+	case ReferenceSize:
+		s.cache.Delete(cacheKeySizes)
+	case ReferenceCategory:
+		s.cache.Delete(cacheKeyCategories, cacheKeyCategoryTree)
+	}
+}
+
+// referenceReadError memetakan error saat membaca data referensi.
+func (s *service) referenceReadError(err error, label string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperror.New(apperror.ErrCodeNotFound, label+" not found")
+	}
+	log.Printf("Error finding %s: %v", label, err)
+	return apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+}
+
+// referenceWriteError memetakan error saat menyimpan data referensi.
+func (s *service) referenceWriteError(err error, label string) error {
+	if errors.Is(err, ErrDuplicateName) {
+		return apperror.New(apperror.ErrCodeConflict, label+" name already exists")
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperror.New(apperror.ErrCodeNotFound, label+" not found")
+	}
+	log.Printf("Error saving %s: %v", label, err)
+	return apperror.New(apperror.ErrCodeInternal, "failed to save "+label)
+}
+
+// findCategory mengambil kategori dan memetakan sql.ErrNoRows menjadi 404.
+func (s *service) findCategory(ctx context.Context, id int) (model.ProductCategory, error) {
+	category, err := s.repo.FindCategoryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ProductCategory{}, apperror.New(apperror.ErrCodeNotFound, "category not found")
+		}
+		log.Printf("Error finding category: %v", err)
+		return model.ProductCategory{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return category, nil
+}
+
+// uniqueProductSlug membuat slug dari nama produk, diberi suffix angka jika sudah dipakai produk lain
+// (termasuk slug lama yang masih dipakai untuk redirect). productID adalah produk itu sendiri (uuid.Nil saat create).
+func (s *service) uniqueProductSlug(ctx context.Context, name string, productID uuid.UUID) (string, error) {
+	base := slug.Truncate(slug.Make(name), maxProductSlugBase)
+	if base == "" {
+		base = "product"
+	}
+
+	taken, err := s.repo.FindProductSlugs(ctx, base, productID)
+	if err != nil {
+		log.Printf("Error finding product slugs: %v", err)
+		return "", apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return slug.Unique(base, taken), nil
+}
+
+// uniqueCategorySlug membuat slug dari nama, menambahkan suffix angka jika sudah dipakai.
+// currentSlug adalah slug milik kategori itu sendiri (kosong saat create) dan tidak dianggap bentrok.
+func (s *service) uniqueCategorySlug(ctx context.Context, name, currentSlug string) (string, error) {
+	base := slug.Make(name)
+	if base == "" {
+		return "", apperror.New(apperror.ErrCodeValidation, "category name must contain letters or digits")
+	}
+
+	candidate := base
+	for i := 2; ; i++ {
+		if candidate == currentSlug {
+			return candidate, nil
+		}
+		used, err := s.repo.IsCategorySlugUsed(ctx, candidate)
+		if err != nil {
+			log.Printf("Error checking category slug: %v", err)
+			return "", apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+		}
+		if !used {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+func toCategoryResponse(c model.ProductCategory) CategoryResponse {
+	return CategoryResponse{
+		ID:       c.ID,
+		ParentID: c.ParentID,
+		Name:     c.Name,
+		Slug:     c.Slug,
+	}
+}
+
+// buildCategoryTree menyusun list datar kategori menjadi tree berdasarkan parent_id.
+func buildCategoryTree(categories []model.ProductCategory) []CategoryNode {
+	children := make(map[int][]model.ProductCategory)
+	var roots []model.ProductCategory
+	for _, c := range categories {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+			continue
+		}
+		children[*c.ParentID] = append(children[*c.ParentID], c)
+	}
+
+	var build func(list []model.ProductCategory) []CategoryNode
+	build = func(list []model.ProductCategory) []CategoryNode {
+		nodes := make([]CategoryNode, 0, len(list))
+		for _, c := range list {
+			nodes = append(nodes, CategoryNode{
+				CategoryResponse: toCategoryResponse(c),
+				Children:         build(children[c.ID]),
+			})
+		}
+		return nodes
+	}
+	return build(roots)
+}
@@ -0,0 +1,57 @@
+package recentlyviewed
+
+// File: internal/service/recentlyviewed/domain.go
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// =================================================================================
+// KONTRAK UNTUK SERVICE (Logika Bisnis) 🧠
+// =================================================================================
+type Service interface {
+	// Usecase: Storefront RecordView
+	// RecordView menaruh produk di urutan teratas riwayat owner (akun atau sesi anonim).
+	RecordView(ctx context.Context, owner string, productID uuid.UUID) error
+
+	// Usecase: Storefront Recently Viewed
+	// Listing yang sudah sold, diturunkan, atau dihapus tidak ditampilkan.
+	GetRecentlyViewed(ctx context.Context, owner string, filter RecentlyViewedFilter) ([]RecentProduct, error)
+	ClearRecentlyViewed(ctx context.Context, owner string) error
+
+	// Usecase: Customer Login
+	// MergeSession memindahkan riwayat sesi anonim ke akun yang baru login.
+	MergeSession(ctx context.Context, accountID uuid.UUID, sessionID string) error
+
+	// PurgeSessions membuang riwayat sesi anonim yang sudah lama; dipanggil job berkala.
+	PurgeSessions(ctx context.Context) (int, error)
+}
+
+// =================================================================================
+// KONTRAK UNTUK STORE (Penyimpanan Riwayat) 🗃️
+// =================================================================================
+// Store menyimpan riwayat per owner sebagai daftar terbatas yang terurut dari yang terbaru.
+// Implementasi: Postgres (default) dan server yang kompatibel dengan Redis.
+type Store interface {
+	// Add memperbarui waktu view produk lalu memangkas riwayat menjadi paling banyak limit entry.
+	Add(ctx context.Context, owner string, entry Entry, limit int) error
+	List(ctx context.Context, owner string, limit int) ([]Entry, error)
+	// Merge menggabungkan riwayat from ke to (waktu view terbaru yang menang), lalu menghapus from.
+	Merge(ctx context.Context, from, to string, limit int) error
+	Clear(ctx context.Context, owner string) error
+	// PurgeSessions menghapus entry sesi anonim yang lebih lama dari before. Store yang
+	// memakai TTL per key boleh selalu mengembalikan 0.
+	PurgeSessions(ctx context.Context, before time.Time) (int, error)
+}
+
+// =================================================================================
+// KONTRAK UNTUK REPOSITORY (Akses Database) 🚚
+// =================================================================================
+// Repository mendefinisikan semua interaksi ke database yang dibutuhkan oleh Service.
+type Repository interface {
+	// FindVisibleProducts mengambil produk yang masih tayang dari ids; urutan tidak dijamin.
+	FindVisibleProducts(ctx context.Context, ids []uuid.UUID) ([]RecentProduct, error)
+}
@@ -0,0 +1,28 @@
+package recentlyviewed
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Entry adalah satu produk di riwayat beserta waktu terakhir dilihat.
+type Entry struct {
+	ProductID uuid.UUID `db:"product_id"`
+	ViewedAt  time.Time `db:"viewed_at"`
+}
+
+type RecentlyViewedFilter struct {
+	Limit int `form:"limit"`
+}
+
+type RecentProduct struct {
+	ID       uuid.UUID `json:"id" db:"id"`
+	ShopID   uuid.UUID `json:"shop_id" db:"shop_id"`
+	ShopName string    `json:"shop_name" db:"shop_name"`
+	Name     string    `json:"name" db:"name"`
+	Slug     string    `json:"slug" db:"slug"`
+	Price    int64     `json:"price" db:"price"`
+	ImageURL *string   `json:"image_url" db:"image_url"`
+	ViewedAt time.Time `json:"viewed_at" db:"-"`
+}
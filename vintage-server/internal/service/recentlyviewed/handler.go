@@ -0,0 +1,86 @@
+package recentlyviewed
+
+import (
+	"net/http"
+	"vintage-server/pkg/middleware"
+	"vintage-server/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// sessionHeader adalah id sesi anonim yang dikirim storefront (sama dengan deduplikasi view trending)
+const sessionHeader = "X-Session-ID"
+
+// Handler adalah struct yang memegang dependency ke Service
+type Handler struct {
+	svc Service
+}
+
+// NewHandler adalah constructor untuk handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// TrackView mencatat produk ke riwayat pengunjung lalu meneruskan request ke handler berikutnya
+// di route yang sama (pencatat view trending). Tidak pernah menulis response: gagal mencatat
+// riwayat tidak boleh menggagalkan pencatatan view.
+func (h *Handler) TrackView(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return
+	}
+	if owner := ownerKey(c); owner != "" {
+		h.svc.RecordView(c.Request.Context(), owner, productID)
+	}
+}
+
+// GetRecentlyViewed mengembalikan riwayat listing yang dilihat akun yang login, atau sesi anonim
+func (h *Handler) GetRecentlyViewed(c *gin.Context) {
+	var filter RecentlyViewedFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	items, err := h.svc.GetRecentlyViewed(c.Request.Context(), ownerKey(c), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "private, no-store")
+	response.Success(c, http.StatusOK, items)
+}
+
+// ClearRecentlyViewed menghapus seluruh riwayat akun yang login, atau sesi anonim
+func (h *Handler) ClearRecentlyViewed(c *gin.Context) {
+	if err := h.svc.ClearRecentlyViewed(c.Request.Context(), ownerKey(c)); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// MergeSession dipanggil storefront tepat setelah login dengan header X-Session-ID sesi anonim
+// sebelumnya, supaya riwayat yang dilihat sebelum login pindah ke akun.
+func (h *Handler) MergeSession(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	if err := h.svc.MergeSession(c.Request.Context(), accountID, c.GetHeader(sessionHeader)); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ownerKey mengidentifikasi pemilik riwayat: akun jika login, lalu X-Session-ID.
+// Kosong jika keduanya tidak ada (tanpa fallback IP, riwayat bersifat personal).
+func ownerKey(c *gin.Context) string {
+	if accountID, ok := middleware.GetAccountID(c); ok {
+		return AccountOwner(accountID)
+	}
+	if session := c.GetHeader(sessionHeader); session != "" && len(session) <= maxSessionIDLength {
+		return SessionOwner(session)
+	}
+	return ""
+}
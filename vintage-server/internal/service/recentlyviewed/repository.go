@@ -0,0 +1,33 @@
+package recentlyviewed
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// repository adalah struct yang mengimplementasikan kontrak Repository dari domain.go
+type repository struct {
+	db *sqlx.DB
+}
+
+// NewRepository adalah constructor untuk implementasi repository
+func NewRepository(db *sqlx.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) FindVisibleProducts(ctx context.Context, ids []uuid.UUID) ([]RecentProduct, error) {
+	products := []RecentProduct{}
+	query := `
+		SELECT p.id, p.shop_id, s.name AS shop_name, p.name, p.slug, p.price, pi.url AS image_url
+		FROM products p
+		JOIN shop s ON s.id = p.shop_id
+		LEFT JOIN product_images pi ON pi.product_id = p.id AND pi.image_index = 0
+		WHERE p.id = ANY($1) AND p.status = 'published' AND p.deleted_at IS NULL AND s.active AND s.deleted_at IS NULL`
+	err := r.db.SelectContext(ctx, &products, query, pq.Array(ids))
+	return products, err
+}
@@ -0,0 +1,165 @@
+package recentlyviewed
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"vintage-server/pkg/apperror"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxEntries adalah panjang riwayat per owner; view yang lebih lama terbuang
+	maxEntries = 50
+
+	defaultLimit = 20
+
+	// SessionTTL adalah umur riwayat sesi anonim yang tidak pernah di-merge ke akun
+	SessionTTL = 30 * 24 * time.Hour
+
+	maxSessionIDLength = 128
+)
+
+// service adalah struct yang akan mengimplementasikan interface Service dari domain.go
+type service struct {
+	store Store
+	repo  Repository
+}
+
+// NewService adalah constructor untuk service
+func NewService(store Store, repo Repository) Service {
+	return &service{store: store, repo: repo}
+}
+
+// AccountOwner adalah owner key riwayat milik akun.
+func AccountOwner(accountID uuid.UUID) string {
+	return "a:" + accountID.String()
+}
+
+// SessionOwner adalah owner key riwayat sesi anonim (header X-Session-ID dari storefront).
+func SessionOwner(sessionID string) string {
+	return "s:" + sessionID
+}
+
+func isSession(owner string) bool {
+	return strings.HasPrefix(owner, "s:")
+}
+
+func (s *service) RecordView(ctx context.Context, owner string, productID uuid.UUID) error {
+	if owner == "" {
+		return apperror.New(apperror.ErrCodeValidation, "session is required")
+	}
+	if err := s.store.Add(ctx, owner, Entry{ProductID: productID, ViewedAt: time.Now()}, maxEntries); err != nil {
+		log.Printf("Error recording recently viewed product: %v", err)
+		return apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return nil
+}
+
+func (s *service) GetRecentlyViewed(ctx context.Context, owner string, filter RecentlyViewedFilter) ([]RecentProduct, error) {
+	limit := filter.Limit
+	if limit < 1 {
+		limit = defaultLimit
+	}
+	if limit > maxEntries {
+		limit = maxEntries
+	}
+	if owner == "" {
+		return []RecentProduct{}, nil
+	}
+
+	// Ambil seluruh riwayat (maksimal maxEntries) supaya limit tetap terpenuhi setelah
+	// listing yang tidak tayang disaring
+	entries, err := s.store.List(ctx, owner, maxEntries)
+	if err != nil {
+		log.Printf("Error listing recently viewed products: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if len(entries) == 0 {
+		return []RecentProduct{}, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.ProductID)
+	}
+	products, err := s.repo.FindVisibleProducts(ctx, ids)
+	if err != nil {
+		log.Printf("Error finding recently viewed products: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	byID := make(map[uuid.UUID]RecentProduct, len(products))
+	for _, product := range products {
+		byID[product.ID] = product
+	}
+
+	items := make([]RecentProduct, 0, limit)
+	for _, entry := range entries {
+		product, ok := byID[entry.ProductID]
+		if !ok {
+			continue
+		}
+		product.ViewedAt = entry.ViewedAt
+		items = append(items, product)
+		if len(items) == limit {
+			break
+		}
+	}
+	return items, nil
+}
+
+func (s *service) ClearRecentlyViewed(ctx context.Context, owner string) error {
+	if owner == "" {
+		return nil
+	}
+	if err := s.store.Clear(ctx, owner); err != nil {
+		log.Printf("Error clearing recently viewed products: %v", err)
+		return apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return nil
+}
+
+func (s *service) MergeSession(ctx context.Context, accountID uuid.UUID, sessionID string) error {
+	sessionID = strings.TrimSpace(sessionID)
+	if sessionID == "" || len(sessionID) > maxSessionIDLength {
+		return apperror.New(apperror.ErrCodeValidation, "a valid X-Session-ID header is required")
+	}
+	if err := s.store.Merge(ctx, SessionOwner(sessionID), AccountOwner(accountID), maxEntries); err != nil {
+		log.Printf("Error merging recently viewed session: %v", err)
+		return apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return nil
+}
+
+func (s *service) PurgeSessions(ctx context.Context) (int, error) {
+	purged, err := s.store.PurgeSessions(ctx, time.Now().Add(-SessionTTL))
+	if err != nil {
+		return 0, fmt.Errorf("purge recently viewed sessions: %w", err)
+	}
+	return purged, nil
+}
+
+// StartPurgeJob menjalankan PurgeSessions secara berkala sampai ctx dibatalkan.
+func StartPurgeJob(ctx context.Context, svc Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := svc.PurgeSessions(ctx)
+			if err != nil {
+				log.Printf("Error purging recently viewed sessions: %v", err)
+				continue
+			}
+			if purged > 0 {
+				log.Printf("Recently viewed job purged %d session entries", purged)
+			}
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package recentlyviewed
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+	"vintage-server/pkg/redis"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// --- Postgres ---
+
+// postgresStore menyimpan riwayat di tabel recently_viewed, satu baris per owner per produk.
+type postgresStore struct {
+	db *sqlx.DB
+}
+
+// NewPostgresStore adalah constructor untuk Store berbasis tabel recently_viewed.
+func NewPostgresStore(db *sqlx.DB) Store {
+	return &postgresStore{db: db}
+}
+
+// trimQuery membuang entry owner $1 di luar limit $2 terbaru.
+const trimQuery = `
+	DELETE FROM recently_viewed
+	WHERE owner_key = $1 AND product_id IN (
+		SELECT product_id FROM recently_viewed WHERE owner_key = $1
+		ORDER BY viewed_at DESC
+		OFFSET $2)`
+
+func (s *postgresStore) Add(ctx context.Context, owner string, entry Entry, limit int) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Upsert waktu view; produk yang tidak ada diabaikan tanpa error FK
+	upsert := `
+		INSERT INTO recently_viewed (owner_key, product_id, viewed_at)
+		SELECT $1, $2, $3 WHERE EXISTS (SELECT 1 FROM products WHERE id = $2)
+		ON CONFLICT (owner_key, product_id) DO UPDATE SET viewed_at = GREATEST(recently_viewed.viewed_at, EXCLUDED.viewed_at)`
+	result, err := tx.ExecContext(ctx, upsert, owner, entry.ProductID, entry.ViewedAt)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil
+	}
+
+	// Query 2: Pangkas riwayat ke limit entry terbaru
+	if _, err := tx.ExecContext(ctx, trimQuery, owner, limit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *postgresStore) List(ctx context.Context, owner string, limit int) ([]Entry, error) {
+	entries := []Entry{}
+	query := "SELECT product_id, viewed_at FROM recently_viewed WHERE owner_key = $1 ORDER BY viewed_at DESC LIMIT $2"
+	err := s.db.SelectContext(ctx, &entries, query, owner, limit)
+	return entries, err
+}
+
+func (s *postgresStore) Merge(ctx context.Context, from, to string, limit int) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Pindahkan riwayat sesi; untuk produk yang sama, waktu view terbaru yang dipakai
+	move := `
+		INSERT INTO recently_viewed (owner_key, product_id, viewed_at)
+		SELECT $2, product_id, viewed_at FROM recently_viewed WHERE owner_key = $1
+		ON CONFLICT (owner_key, product_id) DO UPDATE SET viewed_at = GREATEST(recently_viewed.viewed_at, EXCLUDED.viewed_at)`
+	result, err := tx.ExecContext(ctx, move, from, to)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil
+	}
+
+	// Query 2: Hapus riwayat sesi dan pangkas riwayat akun
+	if _, err := tx.ExecContext(ctx, "DELETE FROM recently_viewed WHERE owner_key = $1", from); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, trimQuery, to, limit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *postgresStore) Clear(ctx context.Context, owner string) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM recently_viewed WHERE owner_key = $1", owner)
+	return err
+}
+
+func (s *postgresStore) PurgeSessions(ctx context.Context, before time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM recently_viewed WHERE owner_key LIKE 's:%' AND viewed_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
+
+// --- Redis ---
+
+// redisStore menyimpan riwayat per owner sebagai sorted set (member = product id, score = waktu
+// view dalam milidetik). Riwayat sesi anonim diberi TTL sehingga tidak perlu dibersihkan job.
+type redisStore struct {
+	client     *redis.Client
+	prefix     string
+	sessionTTL time.Duration
+}
+
+// NewRedisStore adalah constructor untuk Store berbasis server yang kompatibel dengan Redis.
+func NewRedisStore(client *redis.Client, sessionTTL time.Duration) Store {
+	return &redisStore{client: client, prefix: "recently_viewed:", sessionTTL: sessionTTL}
+}
+
+func (s *redisStore) Add(ctx context.Context, owner string, entry Entry, limit int) error {
+	key := s.prefix + owner
+	cmds := [][]any{
+		{"MULTI"},
+		// GT: waktu view lama (misal dari retry) tidak menimpa yang lebih baru
+		{"ZADD", key, "GT", entry.ViewedAt.UnixMilli(), entry.ProductID.String()},
+		{"ZREMRANGEBYRANK", key, 0, -(limit + 1)},
+	}
+	if isSession(owner) {
+		cmds = append(cmds, []any{"PEXPIRE", key, s.sessionTTL.Milliseconds()})
+	}
+	cmds = append(cmds, []any{"EXEC"})
+	return s.exec(ctx, cmds)
+}
+
+func (s *redisStore) List(ctx context.Context, owner string, limit int) ([]Entry, error) {
+	reply, err := s.client.Do(ctx, "ZREVRANGE", s.prefix+owner, 0, limit-1, "WITHSCORES")
+	if err != nil {
+		return nil, err
+	}
+	items, _ := reply.([]any)
+
+	entries := make([]Entry, 0, len(items)/2)
+	for i := 0; i+1 < len(items); i += 2 {
+		member, _ := items[i].(string)
+		score, _ := items[i+1].(string)
+		productID, err := uuid.Parse(member)
+		if err != nil {
+			continue
+		}
+		millis, err := strconv.ParseFloat(score, 64)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, Entry{ProductID: productID, ViewedAt: time.UnixMilli(int64(millis))})
+	}
+	return entries, nil
+}
+
+func (s *redisStore) Merge(ctx context.Context, from, to string, limit int) error {
+	fromKey, toKey := s.prefix+from, s.prefix+to
+	return s.exec(ctx, [][]any{
+		{"MULTI"},
+		{"ZUNIONSTORE", toKey, 2, toKey, fromKey, "AGGREGATE", "MAX"},
+		{"ZREMRANGEBYRANK", toKey, 0, -(limit + 1)},
+		{"DEL", fromKey},
+		{"EXEC"},
+	})
+}
+
+func (s *redisStore) Clear(ctx context.Context, owner string) error {
+	_, err := s.client.Do(ctx, "DEL", s.prefix+owner)
+	return err
+}
+
+func (s *redisStore) PurgeSessions(ctx context.Context, before time.Time) (int, error) {
+	// Key sesi sudah kedaluwarsa lewat PEXPIRE
+	return 0, nil
+}
+
+// exec menjalankan pipeline MULTI/EXEC dan memeriksa error per perintah di balasan EXEC.
+func (s *redisStore) exec(ctx context.Context, cmds [][]any) error {
+	replies, err := s.client.Pipeline(ctx, cmds...)
+	if err != nil {
+		return err
+	}
+	results, ok := replies[len(replies)-1].([]any)
+	if !ok {
+		return fmt.Errorf("recently viewed: transaction aborted")
+	}
+	for _, result := range results {
+		if replyErr, ok := result.(redis.Error); ok {
+			return replyErr
+		}
+	}
+	return nil
+}
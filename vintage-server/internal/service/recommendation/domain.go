@@ -0,0 +1,37 @@
+package recommendation
+
+// File: internal/service/recommendation/domain.go
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// =================================================================================
+// KONTRAK UNTUK SERVICE (Logika Bisnis) 🧠
+// =================================================================================
+type Service interface {
+	// Usecase: CustomerView Recommendations (halaman detail produk)
+	GetRecommendations(ctx context.Context, productID uuid.UUID, filter RecommendationFilter) (Recommendations, error)
+
+	// RebuildNeighbors dipanggil job berkala untuk menghitung ulang seluruh product_neighbors.
+	RebuildNeighbors(ctx context.Context) error
+}
+
+// =================================================================================
+// KONTRAK UNTUK REPOSITORY (Akses Database) 🚚
+// =================================================================================
+// Repository mendefinisikan semua interaksi ke database yang dibutuhkan oleh Service.
+type Repository interface {
+	// FindProductStatus mengembalikan status listing, sql.ErrNoRows jika produk tidak ada.
+	FindProductStatus(ctx context.Context, productID uuid.UUID) (string, error)
+	// FindNeighbors membaca daftar hasil job. Tetangga yang sudah tidak published dilewati.
+	FindNeighbors(ctx context.Context, productID uuid.UUID, kind string, limit int) ([]Recommendation, error)
+	// FindSimilarOnline menghitung "similar items" langsung untuk listing yang belum diproses job.
+	FindSimilarOnline(ctx context.Context, productID uuid.UUID, limit int) ([]Recommendation, error)
+	// TransactionRebuildNeighbors mengganti seluruh baris kind dengan hasil hitung baru,
+	// maksimal perProduct tetangga per produk. Mengembalikan jumlah baris yang disimpan.
+	TransactionRebuildNeighbors(ctx context.Context, kind string, perProduct int, computedAt time.Time) (int64, error)
+}
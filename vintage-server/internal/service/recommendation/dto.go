@@ -0,0 +1,23 @@
+package recommendation
+
+import "github.com/google/uuid"
+
+type RecommendationFilter struct {
+	Limit int `form:"limit"`
+}
+
+// Recommendation adalah satu listing yang direkomendasikan beserta skornya.
+type Recommendation struct {
+	ID       uuid.UUID `json:"id" db:"id"`
+	ShopID   uuid.UUID `json:"shop_id" db:"shop_id"`
+	Name     string    `json:"name" db:"name"`
+	Price    int64     `json:"price" db:"price"`
+	ImageURL *string   `json:"image_url" db:"image_url"`
+	Score    float64   `json:"score" db:"score"`
+}
+
+type Recommendations struct {
+	Similar        []Recommendation `json:"similar"`
+	AlsoWishlisted []Recommendation `json:"also_wishlisted"`
+	BoughtTogether []Recommendation `json:"bought_together"`
+}
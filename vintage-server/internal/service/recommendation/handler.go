@@ -0,0 +1,42 @@
+package recommendation
+
+import (
+	"net/http"
+	"vintage-server/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Handler adalah struct yang memegang dependency ke Service
+type Handler struct {
+	svc Service
+}
+
+// NewHandler adalah constructor untuk handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// GetRecommendations mengembalikan "similar items", "also wishlisted", dan "bought together" sebuah produk
+func (h *Handler) GetRecommendations(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid product id")
+		return
+	}
+
+	var filter RecommendationFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.svc.GetRecommendations(c.Request.Context(), productID, filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=300")
+	response.Success(c, http.StatusOK, result)
+}
@@ -0,0 +1,150 @@
+package recommendation
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"vintage-server/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// similarScore memberi bobot kemiripan listing n terhadap listing p.
+// Harga dianggap satu "band" jika selisihnya maksimal 30%.
+const similarScore = `(
+	CASE WHEN n.category_id = p.category_id THEN 4 ELSE 0 END
+	+ CASE WHEN n.brand_id = p.brand_id THEN 3 ELSE 0 END
+	+ CASE WHEN n.era_decade = p.era_decade THEN 2 ELSE 0 END
+	+ CASE WHEN n.size_id = p.size_id THEN 2 ELSE 0 END
+	+ CASE WHEN n.price BETWEEN p.price * 0.7 AND p.price * 1.3 THEN 1 ELSE 0 END)`
+
+// similarCandidates membatasi pasangan yang dinilai ke listing tayang dengan kategori atau brand yang sama.
+const similarCandidates = `
+	JOIN products n ON n.id <> p.id AND n.status = 'published' AND n.deleted_at IS NULL
+		AND (n.category_id = p.category_id OR n.brand_id = p.brand_id)`
+
+// rebuildQueries berisi query SELECT (product_id, neighbor_id, score) untuk tiap jenis tetangga.
+// Semua sumber yang tampil publik ikut dihitung supaya listing sold tetap punya rekomendasi.
+var rebuildQueries = map[string]string{
+	model.NeighborKindSimilar: `
+		SELECT p.id AS product_id, n.id AS neighbor_id, ` + similarScore + ` AS score, n.published_at
+		FROM products p` + similarCandidates + `
+		WHERE p.status IN ('published', 'sold') AND p.deleted_at IS NULL`,
+
+	// Kemiripan cosine: jumlah akun yang me-wishlist keduanya dibagi akar perkalian popularitasnya
+	model.NeighborKindAlsoWishlisted: `
+		SELECT w1.product_id, w2.product_id AS neighbor_id,
+			COUNT(*) / SQRT(MAX(c1.total) * MAX(c2.total)) AS score, MAX(n.published_at) AS published_at
+		FROM wishlist w1
+		JOIN wishlist w2 ON w2.account_id = w1.account_id AND w2.product_id <> w1.product_id
+		JOIN products n ON n.id = w2.product_id AND n.status = 'published' AND n.deleted_at IS NULL
+		JOIN (SELECT product_id, COUNT(*) AS total FROM wishlist GROUP BY product_id) c1 ON c1.product_id = w1.product_id
+		JOIN (SELECT product_id, COUNT(*) AS total FROM wishlist GROUP BY product_id) c2 ON c2.product_id = w2.product_id
+		GROUP BY w1.product_id, w2.product_id`,
+
+	// Pesanan dengan status di $4 (dibayar / dikirim / selesai) saja yang dihitung
+	model.NeighborKindBoughtTogether: `
+		SELECT i1.product_id, i2.product_id AS neighbor_id, COUNT(DISTINCT i1.order_id) AS score, MAX(n.published_at) AS published_at
+		FROM order_items i1
+		JOIN orders o ON o.id = i1.order_id AND o.status = ANY($4)
+		JOIN order_items i2 ON i2.order_id = i1.order_id AND i2.product_id <> i1.product_id
+		JOIN products n ON n.id = i2.product_id AND n.status = 'published' AND n.deleted_at IS NULL
+		GROUP BY i1.product_id, i2.product_id`,
+}
+
+// neighborColumns adalah kolom Recommendation (alias tabel 'n' untuk listing yang direkomendasikan).
+const neighborColumns = `n.id, n.shop_id, n.name, n.price, pi.url AS image_url`
+
+// repository adalah struct yang mengimplementasikan kontrak Repository dari domain.go
+type repository struct {
+	db *sqlx.DB
+}
+
+// NewRepository adalah constructor untuk implementasi repository
+func NewRepository(db *sqlx.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) FindProductStatus(ctx context.Context, productID uuid.UUID) (string, error) {
+	var status string
+	err := r.db.GetContext(ctx, &status, "SELECT status FROM products WHERE id = $1 AND deleted_at IS NULL AND shop_id IN (SELECT id FROM shop WHERE active)", productID)
+	return status, err
+}
+
+func (r *repository) FindNeighbors(ctx context.Context, productID uuid.UUID, kind string, limit int) ([]Recommendation, error) {
+	items := []Recommendation{}
+	query := `
+		SELECT ` + neighborColumns + `, pn.score
+		FROM product_neighbors pn
+		JOIN products n ON n.id = pn.neighbor_id AND n.status = 'published' AND n.deleted_at IS NULL
+			AND n.shop_id IN (SELECT id FROM shop WHERE active)
+		LEFT JOIN product_images pi ON pi.product_id = n.id AND pi.image_index = 0
+		WHERE pn.product_id = $1 AND pn.kind = $2
+		ORDER BY pn.rank
+		LIMIT $3`
+	err := r.db.SelectContext(ctx, &items, query, productID, kind, limit)
+	return items, err
+}
+
+func (r *repository) FindSimilarOnline(ctx context.Context, productID uuid.UUID, limit int) ([]Recommendation, error) {
+	items := []Recommendation{}
+	query := `
+		SELECT ` + neighborColumns + `, ` + similarScore + ` AS score
+		FROM products p` + similarCandidates + `
+		LEFT JOIN product_images pi ON pi.product_id = n.id AND pi.image_index = 0
+		WHERE p.id = $1
+		ORDER BY score DESC, n.published_at DESC
+		LIMIT $2`
+	err := r.db.SelectContext(ctx, &items, query, productID, limit)
+	return items, err
+}
+
+func (r *repository) TransactionRebuildNeighbors(ctx context.Context, kind string, perProduct int, computedAt time.Time) (int64, error) {
+	source, ok := rebuildQueries[kind]
+	if !ok {
+		return 0, fmt.Errorf("unknown neighbor kind %q", kind)
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Hapus hasil lama, pembaca tetap melihat data lama sampai commit
+	if _, err := tx.ExecContext(ctx, "DELETE FROM product_neighbors WHERE kind = $1", kind); err != nil {
+		return 0, err
+	}
+
+	// Query 2: Simpan perProduct tetangga terbaik per produk
+	query := fmt.Sprintf(`
+		WITH scored AS (%s),
+		ranked AS (
+			SELECT product_id, neighbor_id, score,
+				ROW_NUMBER() OVER (PARTITION BY product_id ORDER BY score DESC, published_at DESC, neighbor_id) AS rank
+			FROM scored
+			WHERE score > 0
+		)
+		INSERT INTO product_neighbors (product_id, kind, neighbor_id, score, rank, computed_at)
+		SELECT product_id, $2, neighbor_id, score, rank, $3
+		FROM ranked
+		WHERE rank <= $1`, source)
+	args := []any{perProduct, kind, computedAt}
+	if kind == model.NeighborKindBoughtTogether {
+		args = append(args, pq.Array([]int{model.OrderStatusPaid, model.OrderStatusShipped, model.OrderStatusCompleted}))
+	}
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	saved, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return saved, tx.Commit()
+}
@@ -0,0 +1,121 @@
+package recommendation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/pkg/apperror"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultLimit = 12
+	maxLimit     = 24
+
+	// neighborsPerProduct adalah jumlah tetangga yang disimpan job per produk per jenis.
+	neighborsPerProduct = maxLimit
+)
+
+// neighborKinds adalah urutan jenis tetangga yang dihitung ulang oleh job.
+var neighborKinds = []string{
+	model.NeighborKindSimilar,
+	model.NeighborKindAlsoWishlisted,
+	model.NeighborKindBoughtTogether,
+}
+
+// service adalah struct yang akan mengimplementasikan interface Service dari domain.go
+type service struct {
+	repo Repository
+}
+
+// NewService adalah constructor untuk service
+func NewService(repo Repository) Service {
+	return &service{
+		repo: repo,
+	}
+}
+
+func (s *service) GetRecommendations(ctx context.Context, productID uuid.UUID, filter RecommendationFilter) (Recommendations, error) {
+	limit := filter.Limit
+	if limit < 1 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	// Listing sold tetap punya rekomendasi supaya pembeli bisa lanjut browsing
+	status, err := s.repo.FindProductStatus(ctx, productID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Error finding product status: %v", err)
+		return Recommendations{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if errors.Is(err, sql.ErrNoRows) || (status != model.ListingStatusPublished && status != model.ListingStatusSold) {
+		return Recommendations{}, apperror.New(apperror.ErrCodeNotFound, "product not found")
+	}
+
+	var result Recommendations
+	lists := map[string]*[]Recommendation{
+		model.NeighborKindSimilar:        &result.Similar,
+		model.NeighborKindAlsoWishlisted: &result.AlsoWishlisted,
+		model.NeighborKindBoughtTogether: &result.BoughtTogether,
+	}
+	for _, kind := range neighborKinds {
+		items, err := s.repo.FindNeighbors(ctx, productID, kind, limit)
+		if err != nil {
+			log.Printf("Error finding %s neighbors: %v", kind, err)
+			return Recommendations{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+		}
+		*lists[kind] = items
+	}
+
+	// Listing baru belum diproses job: hitung "similar" langsung untuk produk ini saja.
+	// Wishlist / order belum ada datanya, jadi dua daftar lain memang kosong.
+	if len(result.Similar) == 0 {
+		items, err := s.repo.FindSimilarOnline(ctx, productID, limit)
+		if err != nil {
+			log.Printf("Error finding similar items online: %v", err)
+			return Recommendations{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+		}
+		result.Similar = items
+	}
+	return result, nil
+}
+
+func (s *service) RebuildNeighbors(ctx context.Context) error {
+	computedAt := time.Now()
+	for _, kind := range neighborKinds {
+		saved, err := s.repo.TransactionRebuildNeighbors(ctx, kind, neighborsPerProduct, computedAt)
+		if err != nil {
+			return fmt.Errorf("rebuild %s neighbors: %w", kind, err)
+		}
+		log.Printf("Recommendation job stored %d %s neighbor(s)", saved, kind)
+	}
+	return nil
+}
+
+// StartNeighborJob menjalankan RebuildNeighbors sekali saat start, lalu berkala sampai ctx dibatalkan.
+func StartNeighborJob(ctx context.Context, svc Service, interval time.Duration) {
+	if err := svc.RebuildNeighbors(ctx); err != nil {
+		log.Printf("Error rebuilding product neighbors: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := svc.RebuildNeighbors(ctx); err != nil {
+				log.Printf("Error rebuilding product neighbors: %v", err)
+			}
+		}
+	}
+}
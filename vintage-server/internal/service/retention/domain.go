@@ -0,0 +1,46 @@
+package retention
+
+// File: internal/service/retention/domain.go
+
+import (
+	"context"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/audit"
+
+	"github.com/google/uuid"
+)
+
+// =================================================================================
+// KONTRAK UNTUK SERVICE (Logika Bisnis) 🧠
+// =================================================================================
+type Service interface {
+	// Usecase: AdminView Deleted Records (produk, shop, akun yang menunggu purge)
+	GetDeleted(ctx context.Context, kind Kind, filter DeletedFilter) (DeletedPage, error)
+	// Usecase: AdminDelete Record (soft delete; shop ikut menghapus listing-nya, akun ikut menghapus shop-nya)
+	Delete(ctx context.Context, actor audit.Actor, kind Kind, id uuid.UUID) error
+	// Usecase: AdminRestore Record (data turunan yang terhapus bersamaan ikut dikembalikan)
+	Restore(ctx context.Context, actor audit.Actor, kind Kind, id uuid.UUID) error
+
+	// PurgeExpired menghapus permanen data yang masa retensinya sudah lewat; dipanggil job berkala.
+	PurgeExpired(ctx context.Context) (PurgeResult, error)
+}
+
+// =================================================================================
+// KONTRAK UNTUK REPOSITORY (Akses Database) 🚚
+// =================================================================================
+// Repository mendefinisikan semua interaksi ke database yang dibutuhkan oleh Service.
+// Method yang dipakai admin menerima model.AdminLog dan menulisnya di transaksi yang sama.
+type Repository interface {
+	FindDeleted(ctx context.Context, kind Kind, limit, offset int) ([]DeletedRecord, int64, error)
+	// TransactionSoftDelete mengisi deleted_at baris beserta turunannya dengan waktu yang sama.
+	// Mengembalikan sql.ErrNoRows jika baris tidak ada / sudah terhapus, atau ErrReserved
+	// jika ada listing terdampak yang stoknya masih di-hold checkout.
+	TransactionSoftDelete(ctx context.Context, kind Kind, id uuid.UUID, deletedAt time.Time, entry model.AdminLog) error
+	// TransactionRestore mengosongkan deleted_at baris beserta turunan yang terhapus bersamaan.
+	// Mengembalikan sql.ErrNoRows jika baris tidak sedang terhapus, atau ErrParentDeleted.
+	TransactionRestore(ctx context.Context, kind Kind, id uuid.UUID, entry model.AdminLog) error
+	// PurgeDeleted menghapus permanen paling banyak limit baris yang terhapus sebelum before
+	// dan tidak lagi dirujuk riwayat transaksi. Mengembalikan jumlah baris yang dihapus.
+	PurgeDeleted(ctx context.Context, kind Kind, before time.Time, limit int) (int, error)
+}
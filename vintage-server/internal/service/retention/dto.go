@@ -0,0 +1,59 @@
+package retention
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrParentDeleted dikembalikan repository saat restore data yang shop / akun pemiliknya masih terhapus.
+	ErrParentDeleted = errors.New("parent record is still deleted")
+	// ErrReserved dikembalikan repository jika listing yang akan dihapus masih di-hold checkout.
+	ErrReserved = errors.New("listing is reserved by an ongoing checkout")
+)
+
+// Kind menandai jenis data yang mendukung soft delete.
+type Kind string
+
+const (
+	KindProduct Kind = "product"
+	KindShop    Kind = "shop"
+	KindAccount Kind = "account"
+)
+
+// purgeOrder adalah urutan purge: anak lebih dulu supaya induknya tidak lagi dirujuk.
+var purgeOrder = []Kind{KindProduct, KindShop, KindAccount}
+
+type DeletedFilter struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+// DeletedRecord adalah satu baris yang sudah di-soft-delete.
+// Name berisi nama produk / shop, atau username untuk akun.
+type DeletedRecord struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	Name       string    `json:"name" db:"name"`
+	DeletedAt  time.Time `json:"deleted_at" db:"deleted_at"`
+	PurgeAfter time.Time `json:"purge_after" db:"-"`
+}
+
+type DeletedPage struct {
+	Items []DeletedRecord `json:"items"`
+	Total int64           `json:"total"`
+	Page  int             `json:"page"`
+	Limit int             `json:"limit"`
+}
+
+// PurgeResult adalah jumlah baris yang dihapus permanen per jenis dalam satu putaran job.
+type PurgeResult struct {
+	Products int
+	Shops    int
+	Accounts int
+}
+
+func (r PurgeResult) total() int {
+	return r.Products + r.Shops + r.Accounts
+}
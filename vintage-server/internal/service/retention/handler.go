@@ -0,0 +1,81 @@
+package retention
+
+import (
+	"fmt"
+	"net/http"
+	"vintage-server/internal/service/audit"
+	"vintage-server/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Handler adalah struct yang memegang dependency ke Service
+type Handler struct {
+	svc Service
+}
+
+// NewHandler adalah constructor untuk handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// GetDeleted membuat handler admin untuk melihat data jenis tertentu yang sudah dihapus
+func (h *Handler) GetDeleted(kind Kind) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var filter DeletedFilter
+		if err := c.ShouldBindQuery(&filter); err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+			return
+		}
+
+		records, err := h.svc.GetDeleted(c.Request.Context(), kind, filter)
+		if err != nil {
+			response.FromError(c, err)
+			return
+		}
+		response.Success(c, http.StatusOK, records)
+	}
+}
+
+// Delete membuat handler admin untuk soft delete data jenis tertentu
+func (h *Handler) Delete(kind Kind) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseID(c, kind)
+		if !ok {
+			return
+		}
+
+		if err := h.svc.Delete(c.Request.Context(), audit.ActorFromContext(c), kind, id); err != nil {
+			response.FromError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// Restore membuat handler admin untuk memulihkan data jenis tertentu sebelum di-purge
+func (h *Handler) Restore(kind Kind) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseID(c, kind)
+		if !ok {
+			return
+		}
+
+		if err := h.svc.Restore(c.Request.Context(), audit.ActorFromContext(c), kind, id); err != nil {
+			response.FromError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// parseID membaca path param "id" dan langsung membalas 400 jika bukan UUID.
+func parseID(c *gin.Context, kind Kind) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid %s id", kind))
+		return uuid.Nil, false
+	}
+	return id, true
+}
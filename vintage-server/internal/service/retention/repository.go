@@ -0,0 +1,256 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/audit"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type kindTable struct {
+	table      string
+	nameColumn string
+	// productScope memilih listing (alias p) yang ikut terhapus / dipulihkan bersama baris $1.
+	productScope string
+	// parentDeleted bernilai true jika induk baris $1 masih terhapus; kosong untuk akun.
+	parentDeleted string
+	// unreferenced adalah syarat purge (alias t): baris tidak lagi dirujuk riwayat transaksi.
+	unreferenced string
+}
+
+var kindTables = map[Kind]kindTable{
+	KindProduct: {
+		table:        "products",
+		nameColumn:   "name",
+		productScope: "p.id = $1",
+		parentDeleted: `
+			SELECT s.deleted_at IS NOT NULL FROM products p
+			JOIN shop s ON s.id = p.shop_id
+			WHERE p.id = $1`,
+		unreferenced: `
+			NOT EXISTS (SELECT 1 FROM order_items oi WHERE oi.product_id = t.id)
+			AND NOT EXISTS (SELECT 1 FROM reviews rv WHERE rv.product_id = t.id)`,
+	},
+	KindShop: {
+		table:        "shop",
+		nameColumn:   "name",
+		productScope: "p.shop_id = $1",
+		parentDeleted: `
+			SELECT a.deleted_at IS NOT NULL FROM shop s
+			JOIN accounts a ON a.id = s.account_id
+			WHERE s.id = $1`,
+		unreferenced: "NOT EXISTS (SELECT 1 FROM products p WHERE p.shop_id = t.id)",
+	},
+	KindAccount: {
+		table:        "accounts",
+		nameColumn:   "username",
+		productScope: "p.shop_id IN (SELECT id FROM shop WHERE account_id = $1)",
+		unreferenced: `
+			NOT EXISTS (SELECT 1 FROM orders o WHERE o.account_id = t.id)
+			AND NOT EXISTS (SELECT 1 FROM reviews rv WHERE rv.account_id = t.id)
+			AND NOT EXISTS (SELECT 1 FROM shop s WHERE s.account_id = t.id)
+			AND NOT EXISTS (SELECT 1 FROM admin_logs al WHERE al.admin_id = t.id)`,
+	},
+}
+
+// repository adalah struct yang mengimplementasikan kontrak Repository dari domain.go
+type repository struct {
+	db *sqlx.DB
+}
+
+// NewRepository adalah constructor untuk implementasi repository
+func NewRepository(db *sqlx.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) FindDeleted(ctx context.Context, kind Kind, limit, offset int) ([]DeletedRecord, int64, error) {
+	t := kindTables[kind]
+
+	var total int64
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE deleted_at IS NOT NULL", t.table)
+	if err := r.db.GetContext(ctx, &total, countQuery); err != nil {
+		return nil, 0, err
+	}
+
+	records := []DeletedRecord{}
+	query := fmt.Sprintf(`
+		SELECT id, %s AS name, deleted_at FROM %s
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id
+		LIMIT $1 OFFSET $2`, t.nameColumn, t.table)
+	err := r.db.SelectContext(ctx, &records, query, limit, offset)
+	return records, total, err
+}
+
+func (r *repository) TransactionSoftDelete(ctx context.Context, kind Kind, id uuid.UUID, deletedAt time.Time, entry model.AdminLog) error {
+	t := kindTables[kind]
+	return r.withAdminLog(ctx, entry, func(tx *sqlx.Tx) error {
+		// Query 1: Kunci baris yang dihapus
+		var locked uuid.UUID
+		lockQuery := fmt.Sprintf("SELECT id FROM %s WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", t.table)
+		if err := tx.GetContext(ctx, &locked, lockQuery, id); err != nil {
+			return err
+		}
+
+		// Query 2: Kunci listing terdampak supaya tidak ada checkout baru yang meng-hold stoknya
+		var productIDs []uuid.UUID
+		lockProducts := "SELECT p.id FROM products p WHERE " + t.productScope + " AND p.deleted_at IS NULL FOR UPDATE"
+		if err := tx.SelectContext(ctx, &productIDs, lockProducts, id); err != nil {
+			return err
+		}
+
+		// Query 3: Listing yang sedang di-checkout pembeli tidak boleh hilang di tengah jalan
+		var reserved bool
+		reservedQuery := `
+			SELECT EXISTS (
+				SELECT 1 FROM inventory_holds h
+				JOIN products p ON p.id = h.product_id
+				WHERE ` + t.productScope + ` AND p.deleted_at IS NULL
+				  AND h.status = 'active' AND h.expires_at > CURRENT_TIMESTAMP
+			)`
+		if err := tx.GetContext(ctx, &reserved, reservedQuery, id); err != nil {
+			return err
+		}
+		if reserved {
+			return ErrReserved
+		}
+
+		// Query 4: Soft delete listing, shop (untuk akun), lalu barisnya sendiri dengan waktu yang sama,
+		// supaya restore bisa mengenali turunan yang terhapus bersamaan
+		productQuery := `
+			UPDATE products p SET deleted_at = $2, updated_at = CURRENT_TIMESTAMP
+			WHERE ` + t.productScope + ` AND p.deleted_at IS NULL`
+		if _, err := tx.ExecContext(ctx, productQuery, id, deletedAt); err != nil {
+			return err
+		}
+		if kind == KindAccount {
+			shopQuery := "UPDATE shop SET deleted_at = $2, updated_at = CURRENT_TIMESTAMP WHERE account_id = $1 AND deleted_at IS NULL"
+			if _, err := tx.ExecContext(ctx, shopQuery, id, deletedAt); err != nil {
+				return err
+			}
+		}
+		if kind != KindProduct {
+			query := fmt.Sprintf("UPDATE %s SET deleted_at = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1", t.table)
+			if _, err := tx.ExecContext(ctx, query, id, deletedAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *repository) TransactionRestore(ctx context.Context, kind Kind, id uuid.UUID, entry model.AdminLog) error {
+	t := kindTables[kind]
+	return r.withAdminLog(ctx, entry, func(tx *sqlx.Tx) error {
+		// Query 1: Kunci baris yang dipulihkan
+		var deletedAt time.Time
+		lockQuery := fmt.Sprintf("SELECT deleted_at FROM %s WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE", t.table)
+		if err := tx.GetContext(ctx, &deletedAt, lockQuery, id); err != nil {
+			return err
+		}
+
+		// Query 2: Induk harus dipulihkan lebih dulu
+		if t.parentDeleted != "" {
+			var parentDeleted bool
+			if err := tx.GetContext(ctx, &parentDeleted, t.parentDeleted, id); err != nil {
+				return err
+			}
+			if parentDeleted {
+				return ErrParentDeleted
+			}
+		}
+
+		// Query 3: Pulihkan hanya turunan yang terhapus bersamaan; yang dihapus lebih dulu tetap terhapus
+		productQuery := `
+			UPDATE products p SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+			WHERE ` + t.productScope + ` AND p.deleted_at = $2`
+		if _, err := tx.ExecContext(ctx, productQuery, id, deletedAt); err != nil {
+			return err
+		}
+		if kind == KindAccount {
+			shopQuery := "UPDATE shop SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE account_id = $1 AND deleted_at = $2"
+			if _, err := tx.ExecContext(ctx, shopQuery, id, deletedAt); err != nil {
+				return err
+			}
+		}
+		if kind != KindProduct {
+			query := fmt.Sprintf("UPDATE %s SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = $1", t.table)
+			if _, err := tx.ExecContext(ctx, query, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *repository) PurgeDeleted(ctx context.Context, kind Kind, before time.Time, limit int) (int, error) {
+	t := kindTables[kind]
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Ambil kandidat; SKIP LOCKED supaya tidak menunggu restore yang sedang berjalan
+	var ids []uuid.UUID
+	candidates := fmt.Sprintf(`
+		SELECT t.id FROM %s t
+		WHERE t.deleted_at < $1 AND %s
+		ORDER BY t.deleted_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`, t.table, t.unreferenced)
+	if err := tx.SelectContext(ctx, &ids, candidates, before, limit); err != nil {
+		return 0, err
+	}
+
+	// Query 2: Hapus satu per satu. Baris yang ternyata masih dirujuk tabel lain (FK violation)
+	// dilewati lewat savepoint, supaya satu baris tidak menggagalkan seluruh batch.
+	deleteQuery := fmt.Sprintf("DELETE FROM %s WHERE id = $1", t.table)
+	purged := 0
+	for _, id := range ids {
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT purge_row"); err != nil {
+			return 0, err
+		}
+		if _, err := tx.ExecContext(ctx, deleteQuery, id); err != nil {
+			var pqErr *pq.Error
+			if !errors.As(err, &pqErr) || pqErr.Code != "23503" {
+				return 0, err
+			}
+			if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT purge_row"); err != nil {
+				return 0, err
+			}
+		} else {
+			purged++
+		}
+		if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT purge_row"); err != nil {
+			return 0, err
+		}
+	}
+
+	return purged, tx.Commit()
+}
+
+// withAdminLog menjalankan fn di dalam transaksi lalu mencatat entry ke admin_logs di transaksi yang sama.
+func (r *repository) withAdminLog(ctx context.Context, entry model.AdminLog, fn func(tx *sqlx.Tx) error) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := audit.SaveAdminLog(ctx, tx, entry); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,137 @@
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"vintage-server/internal/service/audit"
+	"vintage-server/pkg/apperror"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+	// purgeBatchSize membatasi jumlah baris per jenis yang dihapus dalam satu putaran job
+	purgeBatchSize = 500
+)
+
+// service adalah struct yang akan mengimplementasikan interface Service dari domain.go
+type service struct {
+	repo   Repository
+	period time.Duration
+}
+
+// NewService adalah constructor untuk service.
+// period adalah masa retensi sebelum data yang di-soft-delete dihapus permanen.
+func NewService(repo Repository, period time.Duration) Service {
+	return &service{
+		repo:   repo,
+		period: period,
+	}
+}
+
+func (s *service) GetDeleted(ctx context.Context, kind Kind, filter DeletedFilter) (DeletedPage, error) {
+	page, limit := normalizePage(filter.Page, filter.Limit)
+	records, total, err := s.repo.FindDeleted(ctx, kind, limit, (page-1)*limit)
+	if err != nil {
+		log.Printf("Error finding deleted %s records: %v", kind, err)
+		return DeletedPage{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	for i := range records {
+		records[i].PurgeAfter = records[i].DeletedAt.Add(s.period)
+	}
+	return DeletedPage{Items: records, Total: total, Page: page, Limit: limit}, nil
+}
+
+func (s *service) Delete(ctx context.Context, actor audit.Actor, kind Kind, id uuid.UUID) error {
+	entry := actor.Entry(string(kind)+".delete", fmt.Sprintf("deleted %s %s", kind, id))
+	if err := s.repo.TransactionSoftDelete(ctx, kind, id, time.Now(), entry); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.New(apperror.ErrCodeNotFound, fmt.Sprintf("%s not found", kind))
+		}
+		if errors.Is(err, ErrReserved) {
+			return apperror.New(apperror.ErrCodeConflict, "a listing is being checked out by a buyer, try again later")
+		}
+		log.Printf("Error deleting %s: %v", kind, err)
+		return apperror.New(apperror.ErrCodeInternal, fmt.Sprintf("failed to delete %s", kind))
+	}
+	return nil
+}
+
+func (s *service) Restore(ctx context.Context, actor audit.Actor, kind Kind, id uuid.UUID) error {
+	entry := actor.Entry(string(kind)+".restore", fmt.Sprintf("restored %s %s", kind, id))
+	if err := s.repo.TransactionRestore(ctx, kind, id, entry); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.New(apperror.ErrCodeNotFound, fmt.Sprintf("deleted %s not found", kind))
+		}
+		if errors.Is(err, ErrParentDeleted) {
+			return apperror.New(apperror.ErrCodeConflict, fmt.Sprintf("the owner of this %s is deleted, restore it first", kind))
+		}
+		log.Printf("Error restoring %s: %v", kind, err)
+		return apperror.New(apperror.ErrCodeInternal, fmt.Sprintf("failed to restore %s", kind))
+	}
+	return nil
+}
+
+func (s *service) PurgeExpired(ctx context.Context) (PurgeResult, error) {
+	before := time.Now().Add(-s.period)
+	var result PurgeResult
+	counters := map[Kind]*int{
+		KindProduct: &result.Products,
+		KindShop:    &result.Shops,
+		KindAccount: &result.Accounts,
+	}
+	for _, kind := range purgeOrder {
+		purged, err := s.repo.PurgeDeleted(ctx, kind, before, purgeBatchSize)
+		if err != nil {
+			return result, fmt.Errorf("purge %s: %w", kind, err)
+		}
+		*counters[kind] = purged
+	}
+	return result, nil
+}
+
+func normalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	return page, limit
+}
+
+// StartPurgeJob menjalankan PurgeExpired sekali saat start lalu secara berkala sampai ctx dibatalkan.
+func StartPurgeJob(ctx context.Context, svc Service, interval time.Duration) {
+	purge := func() {
+		result, err := svc.PurgeExpired(ctx)
+		if err != nil {
+			log.Printf("Error purging deleted records: %v", err)
+			return
+		}
+		if result.total() > 0 {
+			log.Printf("Retention job purged %d product(s), %d shop(s), %d account(s)", result.Products, result.Shops, result.Accounts)
+		}
+	}
+
+	purge()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purge()
+		}
+	}
+}
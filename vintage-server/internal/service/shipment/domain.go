@@ -1 +1,148 @@
 package shipment
+
+// File: internal/service/shipment/domain.go
+
+import (
+	"context"
+	"errors"
+	"vintage-server/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	// ErrOriginNotSet dikembalikan jika shop belum mengisi alamat asal pengiriman.
+	ErrOriginNotSet = errors.New("shop has not set up shipping yet")
+	// ErrServiceNotEnabled dikembalikan jika layanan kurir yang dipilih tidak diaktifkan shop.
+	ErrServiceNotEnabled = errors.New("courier service is not offered by the shop")
+	// ErrPickupNotAvailable dikembalikan jika pembeli memilih ambil sendiri di shop yang tidak menyediakannya.
+	ErrPickupNotAvailable = errors.New("shop does not offer self-pickup")
+	// ErrDestinationRequired dikembalikan jika pengiriman kurir dipilih tanpa alamat tujuan.
+	ErrDestinationRequired = errors.New("a delivery address is required")
+	// ErrOrderNotPaid dikembalikan jika seller mengirim shipment dari order yang belum dibayar / sudah batal.
+	ErrOrderNotPaid = errors.New("order has not been paid")
+	// ErrShipmentNotPending dikembalikan jika shipment sudah dikirim sebelumnya.
+	ErrShipmentNotPending = errors.New("shipment has already been shipped")
+	// ErrShipmentNotShipped dikembalikan jika nomor resi diubah sebelum shipment dikirim.
+	ErrShipmentNotShipped = errors.New("shipment has not been shipped yet")
+)
+
+// Shipment ambil sendiri disimpan dengan kurir & layanan ini (tanpa alamat tujuan).
+const (
+	CourierPickup = "pickup"
+	ServicePickup = "pickup"
+)
+
+// Courier adalah kurir yang didukung beserta layanannya.
+type Courier struct {
+	Code     string           `json:"code"`
+	Name     string           `json:"name"`
+	Services []CourierService `json:"services"`
+}
+
+type CourierService struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// Couriers adalah katalog kurir & layanan yang bisa diaktifkan shop. Kode-nya disimpan di
+// shop_couriers dan shipments (courier VARCHAR(10), service VARCHAR(50)).
+var Couriers = []Courier{
+	{Code: "jne", Name: "JNE", Services: []CourierService{{"REG", "Reguler"}, {"YES", "Yakin Esok Sampai"}, {"OKE", "Ongkos Kirim Ekonomis"}}},
+	{Code: "jnt", Name: "J&T Express", Services: []CourierService{{"EZ", "Reguler"}}},
+	{Code: "sicepat", Name: "SiCepat", Services: []CourierService{{"REG", "Reguler"}, {"BEST", "Besok Sampai Tujuan"}}},
+	{Code: "anteraja", Name: "AnterAja", Services: []CourierService{{"REG", "Reguler"}, {"ND", "Next Day"}}},
+	{Code: "pos", Name: "Pos Indonesia", Services: []CourierService{{"REG", "Pos Reguler"}, {"EXP", "Pos Nextday"}}},
+	{Code: "tiki", Name: "TIKI", Services: []CourierService{{"REG", "Reguler"}, {"ONS", "Over Night Service"}}},
+}
+
+// Location adalah wilayah asal / tujuan pengiriman (id tabel districts, regencies dan provinces).
+type Location struct {
+	DistrictID string `json:"district_id" db:"district_id"`
+	RegencyID  string `json:"regency_id" db:"regency_id"`
+	ProvinceID string `json:"province_id" db:"province_id"`
+}
+
+// Settings adalah pengaturan pengiriman satu shop.
+type Settings struct {
+	ShopID   uuid.UUID
+	ShopName string
+	// Origin nil jika alamat asal belum diisi; shop seperti ini belum bisa mengirim pesanan
+	Origin       *Location
+	OriginCity   *string
+	HandlingDays *int16
+	SelfPickup   bool
+	Services     []model.ShopCourier
+}
+
+// Choice adalah pilihan pengiriman pembeli untuk satu shop.
+type Choice struct {
+	Courier    string
+	Service    string
+	SelfPickup bool
+}
+
+// Option adalah satu layanan pengiriman yang bisa dipilih pembeli beserta ongkirnya.
+type Option struct {
+	Courier string `json:"courier"`
+	Service string `json:"service"`
+	Cost    int64  `json:"cost"`
+}
+
+// RateRequest adalah permintaan ongkir satu layanan kurir dari origin ke destination.
+type RateRequest struct {
+	Origin      Location
+	Destination Location
+	Courier     string
+	Service     string
+}
+
+// RateProvider menghitung ongkir. Implementasi lain (API agregator kurir) cukup memenuhi interface ini;
+// pemanggil tidak memakainya di dalam transaksi DB, jadi implementasi boleh melakukan network I/O.
+type RateProvider interface {
+	Rate(ctx context.Context, req RateRequest) (int64, error)
+}
+
+// =================================================================================
+// KONTRAK UNTUK SERVICE (Logika Bisnis) 🧠
+// =================================================================================
+type Service interface {
+	// --- Seller ---
+	// Usecase: SellerShip Order (anggota shop dengan akses fulfillment). Shipment kurir wajib
+	// disertai nomor resi; shipment ambil sendiri ditandai sudah diserahkan ke pembeli.
+	// Order berpindah ke 'shipped' setelah semua shipment-nya dikirim.
+	ShipShipment(ctx context.Context, accountID, shipmentID uuid.UUID, req ShipRequest) (model.Shipment, error)
+	// Usecase: SellerUpdate Tracking Number (koreksi nomor resi shipment yang sudah dikirim)
+	UpdateTrackingNumber(ctx context.Context, accountID, shipmentID uuid.UUID, req TrackingRequest) (model.Shipment, error)
+}
+
+// =================================================================================
+// KONTRAK UNTUK REPOSITORY (Akses Database) 🚚
+// =================================================================================
+// Method yang menerima q / tx dipakai service lain (checkout, order) di koneksi / transaksi
+// mereka sendiri; sisanya dipakai Service milik package ini.
+type Repository interface {
+	// FindSettings mengambil pengaturan pengiriman shop-shop tersebut; shop yang tidak ada tidak
+	// ikut di map.
+	FindSettings(ctx context.Context, q sqlx.QueryerContext, shopIDs []uuid.UUID) (map[uuid.UUID]Settings, error)
+	// FindAddressLocation mengambil wilayah alamat milik accountID (sql.ErrNoRows jika bukan miliknya).
+	FindAddressLocation(ctx context.Context, q sqlx.QueryerContext, accountID uuid.UUID, addressID int64) (Location, error)
+	SaveShipment(ctx context.Context, tx *sqlx.Tx, shipment model.Shipment) (model.Shipment, error)
+	FindOrderShipments(ctx context.Context, q sqlx.QueryerContext, orderID uuid.UUID) ([]model.Shipment, error)
+
+	// --- Seller ---
+	// FindShopMembership mengambil shop tempat akun menjadi anggota beserta role-nya.
+	FindShopMembership(ctx context.Context, accountID uuid.UUID) (model.ShopMember, error)
+	// FindShopShipment mengambil shipment milik shopID (sql.ErrNoRows jika milik shop lain).
+	FindShopShipment(ctx context.Context, shopID, shipmentID uuid.UUID) (model.Shipment, error)
+	// TransactionShipShipment mengunci order, menandai shipment 'shipped' dengan nomor resinya,
+	// memindah order ke 'shipped' jika tidak ada lagi shipment yang menunggu, lalu memberi tahu pembeli.
+	// Mengembalikan ErrOrderNotPaid atau ErrShipmentNotPending.
+	TransactionShipShipment(ctx context.Context, shopID, shipmentID uuid.UUID, trackingNumber *string, by uuid.UUID, notify ShippedNotifier) (model.Shipment, error)
+	// UpdateTrackingNumber mengganti nomor resi shipment yang sudah dikirim (ErrShipmentNotShipped jika belum).
+	UpdateTrackingNumber(ctx context.Context, shopID, shipmentID uuid.UUID, trackingNumber string) (model.Shipment, error)
+}
+
+// ShippedNotifier menyusun notifikasi pembeli untuk shipment yang baru dikirim.
+type ShippedNotifier func(shipment model.Shipment, buyerID uuid.UUID) (model.Notification, error)
@@ -0,0 +1,10 @@
+package shipment
+
+// ShipRequest: TrackingNumber wajib untuk shipment kurir, diabaikan untuk ambil sendiri.
+type ShipRequest struct {
+	TrackingNumber string `json:"tracking_number" binding:"max=100"`
+}
+
+type TrackingRequest struct {
+	TrackingNumber string `json:"tracking_number" binding:"required,max=100"`
+}
@@ -1 +1,68 @@
 package shipment
+
+import (
+	"net/http"
+	"vintage-server/pkg/middleware"
+	"vintage-server/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Handler adalah struct yang memegang dependency ke Service
+type Handler struct {
+	svc Service
+}
+
+// NewHandler adalah constructor untuk handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// --- Seller ---
+
+// ShipShipment menandai shipment shop sudah dikirim beserta nomor resinya
+func (h *Handler) ShipShipment(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid shipment id")
+		return
+	}
+	var req ShipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	shipment, err := h.svc.ShipShipment(c.Request.Context(), accountID, shipmentID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, shipment)
+}
+
+// UpdateTrackingNumber mengoreksi nomor resi shipment yang sudah dikirim
+func (h *Handler) UpdateTrackingNumber(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	shipmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid shipment id")
+		return
+	}
+	var req TrackingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	shipment, err := h.svc.UpdateTrackingNumber(c.Request.Context(), accountID, shipmentID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, shipment)
+}
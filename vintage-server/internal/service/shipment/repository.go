@@ -1 +1,200 @@
 package shipment
+
+import (
+	"context"
+	"database/sql"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/notification"
+	"vintage-server/internal/service/staff"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// repository adalah struct yang mengimplementasikan kontrak Repository dari domain.go.
+// Pengaturan pengiriman dan shipment dibaca / ditulis sebagai bagian dari checkout dan order,
+// karena itu sebagian query memakai q / tx milik service pemanggil; db hanya dipakai untuk
+// aksi seller di shipment-service.
+type repository struct {
+	db *sqlx.DB
+}
+
+// NewRepository adalah constructor untuk implementasi repository
+func NewRepository(db *sqlx.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) FindSettings(ctx context.Context, q sqlx.QueryerContext, shopIDs []uuid.UUID) (map[uuid.UUID]Settings, error) {
+	var shops []struct {
+		ID               uuid.UUID `db:"id"`
+		Name             string    `db:"name"`
+		OriginDistrictID *string   `db:"origin_district_id"`
+		OriginRegencyID  *string   `db:"origin_regency_id"`
+		OriginProvinceID *string   `db:"origin_province_id"`
+		OriginCity       *string   `db:"origin_city"`
+		HandlingDays     *int16    `db:"handling_days"`
+		SelfPickup       bool      `db:"self_pickup"`
+	}
+	query := `
+		SELECT s.id, s.name, s.origin_district_id, s.origin_regency_id, s.origin_province_id,
+			r.name AS origin_city, s.handling_days, s.self_pickup
+		FROM shop s
+		LEFT JOIN regencies r ON r.id = s.origin_regency_id
+		WHERE s.id = ANY($1) AND s.deleted_at IS NULL`
+	if err := sqlx.SelectContext(ctx, q, &shops, query, pq.Array(shopIDs)); err != nil {
+		return nil, err
+	}
+
+	settings := make(map[uuid.UUID]Settings, len(shops))
+	for _, shop := range shops {
+		entry := Settings{
+			ShopID:       shop.ID,
+			ShopName:     shop.Name,
+			OriginCity:   shop.OriginCity,
+			HandlingDays: shop.HandlingDays,
+			SelfPickup:   shop.SelfPickup,
+		}
+		if shop.OriginDistrictID != nil {
+			entry.Origin = &Location{DistrictID: *shop.OriginDistrictID, RegencyID: *shop.OriginRegencyID, ProvinceID: *shop.OriginProvinceID}
+		}
+		settings[shop.ID] = entry
+	}
+
+	var services []model.ShopCourier
+	servicesQuery := "SELECT * FROM shop_couriers WHERE shop_id = ANY($1) ORDER BY courier, service"
+	if err := sqlx.SelectContext(ctx, q, &services, servicesQuery, pq.Array(shopIDs)); err != nil {
+		return nil, err
+	}
+	for _, service := range services {
+		if entry, ok := settings[service.ShopID]; ok {
+			entry.Services = append(entry.Services, service)
+			settings[service.ShopID] = entry
+		}
+	}
+	return settings, nil
+}
+
+func (r *repository) FindAddressLocation(ctx context.Context, q sqlx.QueryerContext, accountID uuid.UUID, addressID int64) (Location, error) {
+	var location Location
+	query := "SELECT district_id, regency_id, province_id FROM addresses WHERE id = $1 AND account_id = $2"
+	err := sqlx.GetContext(ctx, q, &location, query, addressID, accountID)
+	return location, err
+}
+
+func (r *repository) SaveShipment(ctx context.Context, tx *sqlx.Tx, shipment model.Shipment) (model.Shipment, error) {
+	var saved model.Shipment
+	query := `
+		INSERT INTO shipments (order_id, shop_id, address_id, courier, service, shipping_cost, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING *`
+	err := tx.GetContext(ctx, &saved, query, shipment.OrderID, shipment.ShopID, shipment.AddressID,
+		shipment.Courier, shipment.Service, shipment.ShippingCost, shipment.Status)
+	return saved, err
+}
+
+func (r *repository) FindOrderShipments(ctx context.Context, q sqlx.QueryerContext, orderID uuid.UUID) ([]model.Shipment, error) {
+	shipments := []model.Shipment{}
+	query := "SELECT * FROM shipments WHERE order_id = $1 ORDER BY created_at, id"
+	err := sqlx.SelectContext(ctx, q, &shipments, query, orderID)
+	return shipments, err
+}
+
+// --- Seller ---
+
+func (r *repository) FindShopMembership(ctx context.Context, accountID uuid.UUID) (model.ShopMember, error) {
+	return staff.FindMembership(ctx, r.db, accountID)
+}
+
+func (r *repository) FindShopShipment(ctx context.Context, shopID, shipmentID uuid.UUID) (model.Shipment, error) {
+	var shipment model.Shipment
+	err := r.db.GetContext(ctx, &shipment, "SELECT * FROM shipments WHERE id = $1 AND shop_id = $2", shipmentID, shopID)
+	return shipment, err
+}
+
+func (r *repository) TransactionShipShipment(ctx context.Context, shopID, shipmentID uuid.UUID, trackingNumber *string, by uuid.UUID, notify ShippedNotifier) (model.Shipment, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return model.Shipment{}, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Kunci order supaya status order & shipment lain di order yang sama konsisten
+	var order struct {
+		ID        uuid.UUID `db:"id"`
+		AccountID uuid.UUID `db:"account_id"`
+		Status    int16     `db:"status"`
+	}
+	queryLock := `
+		SELECT o.id, o.account_id, o.status FROM shipments sh
+		JOIN orders o ON o.id = sh.order_id
+		WHERE sh.id = $1 AND sh.shop_id = $2
+		FOR UPDATE OF o`
+	if err := tx.GetContext(ctx, &order, queryLock, shipmentID, shopID); err != nil {
+		return model.Shipment{}, err
+	}
+	if order.Status != model.OrderStatusPaid {
+		return model.Shipment{}, ErrOrderNotPaid
+	}
+
+	// Query 2: Tandai shipment terkirim
+	var shipment model.Shipment
+	queryShip := `
+		UPDATE shipments SET status = $2, tracking_number = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = $4
+		RETURNING *`
+	err = tx.GetContext(ctx, &shipment, queryShip, shipmentID, model.ShipmentStatusShipped, trackingNumber, model.ShipmentStatusPending)
+	if err == sql.ErrNoRows {
+		return model.Shipment{}, ErrShipmentNotPending
+	}
+	if err != nil {
+		return model.Shipment{}, err
+	}
+
+	// Query 3: Order dianggap terkirim setelah semua shop di dalamnya mengirim
+	var remaining int
+	queryRemaining := "SELECT COUNT(*) FROM shipments WHERE order_id = $1 AND status = $2"
+	if err := tx.GetContext(ctx, &remaining, queryRemaining, order.ID, model.ShipmentStatusPending); err != nil {
+		return model.Shipment{}, err
+	}
+	if remaining == 0 {
+		if _, err := tx.ExecContext(ctx, "UPDATE orders SET status = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1", order.ID, model.OrderStatusShipped); err != nil {
+			return model.Shipment{}, err
+		}
+		queryLog := `
+			INSERT INTO order_status_logs (order_id, old_status, new_status, note, created_by)
+			VALUES ($1, $2, $3, 'all shipments shipped', $4)`
+		if _, err := tx.ExecContext(ctx, queryLog, order.ID, order.Status, model.OrderStatusShipped, by); err != nil {
+			return model.Shipment{}, err
+		}
+	}
+
+	// Query 4: Notifikasi pembeli
+	n, err := notify(shipment, order.AccountID)
+	if err != nil {
+		return model.Shipment{}, err
+	}
+	if err := notification.SaveNotification(ctx, tx, n); err != nil {
+		return model.Shipment{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Shipment{}, err
+	}
+	return shipment, nil
+}
+
+func (r *repository) UpdateTrackingNumber(ctx context.Context, shopID, shipmentID uuid.UUID, trackingNumber string) (model.Shipment, error) {
+	var shipment model.Shipment
+	query := `
+		UPDATE shipments SET tracking_number = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND shop_id = $2 AND status = $4
+		RETURNING *`
+	err := r.db.GetContext(ctx, &shipment, query, shipmentID, shopID, trackingNumber, model.ShipmentStatusShipped)
+	if err == sql.ErrNoRows {
+		return model.Shipment{}, ErrShipmentNotShipped
+	}
+	return shipment, err
+}
@@ -1 +1,303 @@
 package shipment
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/staff"
+	"vintage-server/pkg/apperror"
+
+	"github.com/google/uuid"
+)
+
+// service adalah struct yang akan mengimplementasikan interface Service dari domain.go
+type service struct {
+	repo Repository
+}
+
+// NewService adalah constructor untuk service
+func NewService(repo Repository) Service {
+	return &service{
+		repo: repo,
+	}
+}
+
+// --- Seller ---
+
+func (s *service) ShipShipment(ctx context.Context, accountID, shipmentID uuid.UUID, req ShipRequest) (model.Shipment, error) {
+	shopID, err := s.fulfillmentShopID(ctx, accountID)
+	if err != nil {
+		return model.Shipment{}, err
+	}
+	current, err := s.findShopShipment(ctx, shopID, shipmentID)
+	if err != nil {
+		return model.Shipment{}, err
+	}
+
+	// Ambil sendiri tidak punya resi; pengiriman kurir wajib ada resinya
+	var trackingNumber *string
+	if current.Courier != CourierPickup {
+		tracking := strings.TrimSpace(req.TrackingNumber)
+		if tracking == "" {
+			return model.Shipment{}, apperror.New(apperror.ErrCodeValidation, "tracking number is required")
+		}
+		trackingNumber = &tracking
+	}
+
+	shipment, err := s.repo.TransactionShipShipment(ctx, shopID, shipmentID, trackingNumber, accountID, buildShippedNotification)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Shipment{}, apperror.New(apperror.ErrCodeNotFound, "shipment not found")
+		}
+		if errors.Is(err, ErrOrderNotPaid) || errors.Is(err, ErrShipmentNotPending) {
+			return model.Shipment{}, apperror.New(apperror.ErrCodeConflict, err.Error())
+		}
+		log.Printf("Error shipping shipment: %v", err)
+		return model.Shipment{}, apperror.New(apperror.ErrCodeInternal, "failed to update shipment")
+	}
+	return shipment, nil
+}
+
+func (s *service) UpdateTrackingNumber(ctx context.Context, accountID, shipmentID uuid.UUID, req TrackingRequest) (model.Shipment, error) {
+	shopID, err := s.fulfillmentShopID(ctx, accountID)
+	if err != nil {
+		return model.Shipment{}, err
+	}
+	current, err := s.findShopShipment(ctx, shopID, shipmentID)
+	if err != nil {
+		return model.Shipment{}, err
+	}
+	if current.Courier == CourierPickup {
+		return model.Shipment{}, apperror.New(apperror.ErrCodeValidation, "self-pickup shipments have no tracking number")
+	}
+	tracking := strings.TrimSpace(req.TrackingNumber)
+	if tracking == "" {
+		return model.Shipment{}, apperror.New(apperror.ErrCodeValidation, "tracking number is required")
+	}
+
+	shipment, err := s.repo.UpdateTrackingNumber(ctx, shopID, shipmentID, tracking)
+	if err != nil {
+		if errors.Is(err, ErrShipmentNotShipped) {
+			return model.Shipment{}, apperror.New(apperror.ErrCodeConflict, err.Error())
+		}
+		log.Printf("Error updating tracking number: %v", err)
+		return model.Shipment{}, apperror.New(apperror.ErrCodeInternal, "failed to update shipment")
+	}
+	return shipment, nil
+}
+
+// fulfillmentShopID mengambil shop tempat akun menjadi anggota, 403 jika akun belum punya shop
+// atau role-nya tidak boleh memproses order.
+func (s *service) fulfillmentShopID(ctx context.Context, accountID uuid.UUID) (uuid.UUID, error) {
+	member, err := s.repo.FindShopMembership(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, apperror.New(apperror.ErrCodeForbidden, "you do not have a shop yet")
+		}
+		log.Printf("Error finding shop membership: %v", err)
+		return uuid.Nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if !staff.Can(member.Role, staff.PermissionFulfillment) {
+		return uuid.Nil, apperror.New(apperror.ErrCodeForbidden, "your shop role does not allow handling orders")
+	}
+	return member.ShopID, nil
+}
+
+func (s *service) findShopShipment(ctx context.Context, shopID, shipmentID uuid.UUID) (model.Shipment, error) {
+	shipment, err := s.repo.FindShopShipment(ctx, shopID, shipmentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Shipment{}, apperror.New(apperror.ErrCodeNotFound, "shipment not found")
+		}
+		log.Printf("Error finding shipment: %v", err)
+		return model.Shipment{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return shipment, nil
+}
+
+// buildShippedNotification memberi tahu pembeli bahwa paket dari satu shop sudah dikirim / diserahkan.
+func buildShippedNotification(shipment model.Shipment, buyerID uuid.UUID) (model.Notification, error) {
+	data, err := json.Marshal(map[string]any{
+		"order_id":        shipment.OrderID,
+		"shipment_id":     shipment.ID,
+		"courier":         shipment.Courier,
+		"service":         shipment.Service,
+		"tracking_number": shipment.TrackingNumber,
+	})
+	if err != nil {
+		return model.Notification{}, err
+	}
+
+	title, body := "Your order has shipped", fmt.Sprintf("Your package is on its way with %s.", strings.ToUpper(shipment.Courier))
+	if shipment.TrackingNumber != nil {
+		body = fmt.Sprintf("Your package is on its way with %s, tracking number %s.", strings.ToUpper(shipment.Courier), *shipment.TrackingNumber)
+	}
+	if shipment.Courier == CourierPickup {
+		title, body = "Order picked up", "The seller has marked your order as picked up."
+	}
+	return model.Notification{
+		AccountID: buyerID,
+		Type:      model.NotificationTypeOrder,
+		Title:     title,
+		Body:      body,
+		Data:      data,
+	}, nil
+}
+
+// ZoneRates adalah ongkir satu paket untuk tiga zona tujuan dari alamat asal shop: satu kota /
+// kabupaten, satu provinsi, atau antarprovinsi.
+type ZoneRates struct {
+	SameRegency   int64
+	SameProvince  int64
+	OtherProvince int64
+}
+
+// For mengembalikan tarif zona untuk rute origin -> destination.
+func (z ZoneRates) For(origin, destination Location) int64 {
+	switch {
+	case origin.RegencyID == destination.RegencyID:
+		return z.SameRegency
+	case origin.ProvinceID == destination.ProvinceID:
+		return z.SameProvince
+	}
+	return z.OtherProvince
+}
+
+// ConfigRates adalah RateProvider dari tarif yang dikonfigurasi per layanan kurir, dengan key
+// "kurir/LAYANAN" (misal "jne/YES"); layanan yang tidak dikonfigurasi memakai Default.
+// Produk belum menyimpan berat, jadi tarif berlaku per paket dan sebaiknya diisi sesuai
+// paket pakaian biasa (sekitar 1 kg). Tidak ada I/O, aman dipanggil kapan saja.
+type ConfigRates struct {
+	Default  ZoneRates
+	Services map[string]ZoneRates
+}
+
+func (c ConfigRates) Rate(ctx context.Context, req RateRequest) (int64, error) {
+	rates, ok := c.Services[req.Courier+"/"+req.Service]
+	if !ok {
+		rates = c.Default
+	}
+	return rates.For(req.Origin, req.Destination), nil
+}
+
+// ParseServiceRates membaca tarif per layanan berformat
+// "jne/REG=10000:18000:30000,jne/YES=18000:28000:45000", yaitu kurir/LAYANAN=satu kota:satu provinsi:antarprovinsi.
+// String kosong menghasilkan map kosong. Layanan harus ada di katalog Couriers.
+func ParseServiceRates(spec string) (map[string]ZoneRates, error) {
+	rates := make(map[string]ZoneRates)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, values, ok := strings.Cut(entry, "=")
+		courier, service, okKey := strings.Cut(strings.TrimSpace(key), "/")
+		zones := strings.Split(values, ":")
+		if !ok || !okKey || len(zones) != 3 {
+			return nil, fmt.Errorf("shipping rate %q: want courier/SERVICE=same_regency:same_province:other_province", entry)
+		}
+		if !IsSupported(courier, service) {
+			return nil, fmt.Errorf("shipping rate %q: unknown courier service", entry)
+		}
+
+		var amounts [3]int64
+		for i, zone := range zones {
+			amount, err := strconv.ParseInt(strings.TrimSpace(zone), 10, 64)
+			if err != nil || amount < 0 {
+				return nil, fmt.Errorf("shipping rate %q: invalid amount %q", entry, zone)
+			}
+			amounts[i] = amount
+		}
+		rates[courier+"/"+service] = ZoneRates{SameRegency: amounts[0], SameProvince: amounts[1], OtherProvince: amounts[2]}
+	}
+	return rates, nil
+}
+
+// IsSupported memeriksa apakah pasangan kurir & layanan ada di katalog Couriers.
+func IsSupported(courier, service string) bool {
+	for _, c := range Couriers {
+		if c.Code != courier {
+			continue
+		}
+		for _, s := range c.Services {
+			if s.Code == service {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Offers memeriksa apakah shop mengaktifkan layanan kurir tersebut.
+func (s Settings) Offers(courier, service string) bool {
+	for _, enabled := range s.Services {
+		if enabled.Courier == courier && enabled.Service == service {
+			return true
+		}
+	}
+	return false
+}
+
+// Quote memvalidasi pilihan pembeli terhadap pengaturan shop lalu menyusun shipment-nya (belum
+// terikat order). destination nil hanya boleh untuk ambil sendiri.
+func Quote(ctx context.Context, rates RateProvider, settings Settings, destination *Location, addressID *int64, choice Choice) (model.Shipment, error) {
+	if settings.Origin == nil {
+		return model.Shipment{}, ErrOriginNotSet
+	}
+	shipment := model.Shipment{ShopID: settings.ShopID, Status: model.ShipmentStatusPending}
+
+	if choice.SelfPickup {
+		if !settings.SelfPickup {
+			return model.Shipment{}, ErrPickupNotAvailable
+		}
+		shipment.Courier, shipment.Service = CourierPickup, ServicePickup
+		return shipment, nil
+	}
+
+	if !settings.Offers(choice.Courier, choice.Service) {
+		return model.Shipment{}, ErrServiceNotEnabled
+	}
+	if destination == nil {
+		return model.Shipment{}, ErrDestinationRequired
+	}
+	cost, err := rates.Rate(ctx, RateRequest{
+		Origin:      *settings.Origin,
+		Destination: *destination,
+		Courier:     choice.Courier,
+		Service:     choice.Service,
+	})
+	if err != nil {
+		return model.Shipment{}, err
+	}
+	shipment.AddressID = addressID
+	shipment.Courier, shipment.Service, shipment.ShippingCost = choice.Courier, choice.Service, cost
+	return shipment, nil
+}
+
+// Options menghitung ongkir setiap layanan yang diaktifkan shop ke destination. Shop tanpa alamat
+// asal tidak punya opsi.
+func Options(ctx context.Context, rates RateProvider, settings Settings, destination Location) ([]Option, error) {
+	options := []Option{}
+	if settings.Origin == nil {
+		return options, nil
+	}
+	for _, enabled := range settings.Services {
+		cost, err := rates.Rate(ctx, RateRequest{
+			Origin:      *settings.Origin,
+			Destination: destination,
+			Courier:     enabled.Courier,
+			Service:     enabled.Service,
+		})
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, Option{Courier: enabled.Courier, Service: enabled.Service, Cost: cost})
+	}
+	return options, nil
+}
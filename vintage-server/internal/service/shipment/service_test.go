@@ -0,0 +1,75 @@
+package shipment
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseServiceRates(t *testing.T) {
+	rates, err := ParseServiceRates(" jne/YES=18000:28000:45000, sicepat/BEST=16000:26000:42000 ,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]ZoneRates{
+		"jne/YES":      {SameRegency: 18000, SameProvince: 28000, OtherProvince: 45000},
+		"sicepat/BEST": {SameRegency: 16000, SameProvince: 26000, OtherProvince: 42000},
+	}
+	if len(rates) != len(want) {
+		t.Fatalf("rates = %+v, want %+v", rates, want)
+	}
+	for key, zones := range want {
+		if rates[key] != zones {
+			t.Errorf("rates[%s] = %+v, want %+v", key, rates[key], zones)
+		}
+	}
+
+	if rates, err := ParseServiceRates(""); err != nil || len(rates) != 0 {
+		t.Errorf("empty spec = %+v, %v; want empty map", rates, err)
+	}
+
+	for _, spec := range []string{
+		"jne/YES",
+		"jne=1:2:3",
+		"jne/YES=1:2",
+		"jne/YES=1:x:3",
+		"jne/YES=1:-2:3",
+		"jne/FAST=1:2:3",
+	} {
+		if _, err := ParseServiceRates(spec); err == nil {
+			t.Errorf("ParseServiceRates(%q) succeeded, want error", spec)
+		}
+	}
+}
+
+func TestConfigRates(t *testing.T) {
+	rates := ConfigRates{
+		Default:  ZoneRates{SameRegency: 10000, SameProvince: 18000, OtherProvince: 30000},
+		Services: map[string]ZoneRates{"jne/YES": {SameRegency: 18000, SameProvince: 28000, OtherProvince: 45000}},
+	}
+	origin := Location{DistrictID: "3171010", RegencyID: "3171", ProvinceID: "31"}
+
+	tests := []struct {
+		name        string
+		courier     string
+		service     string
+		destination Location
+		want        int64
+	}{
+		{"configured service, same regency", "jne", "YES", Location{RegencyID: "3171", ProvinceID: "31"}, 18000},
+		{"configured service, same province", "jne", "YES", Location{RegencyID: "3172", ProvinceID: "31"}, 28000},
+		{"configured service, other province", "jne", "YES", Location{RegencyID: "3273", ProvinceID: "32"}, 45000},
+		{"default, same regency", "jne", "REG", Location{RegencyID: "3171", ProvinceID: "31"}, 10000},
+		{"default, other province", "jnt", "EZ", Location{RegencyID: "3273", ProvinceID: "32"}, 30000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rates.Rate(context.Background(), RateRequest{Origin: origin, Destination: tt.destination, Courier: tt.courier, Service: tt.service})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("rate = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
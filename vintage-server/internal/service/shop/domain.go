@@ -1 +1,140 @@
 package shop
+
+// File: internal/service/shop/domain.go
+
+import (
+	"context"
+	"mime/multipart"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/audit"
+
+	"github.com/google/uuid"
+)
+
+// =================================================================================
+// KONTRAK UNTUK SERVICE (Logika Bisnis) 🧠
+// =================================================================================
+type Service interface {
+	// --- Seller ---
+	// Usecase: SellerCreate Shop (satu shop per akun, menunggu review admin)
+	CreateShop(ctx context.Context, accountID uuid.UUID, req CreateShopRequest) (model.Shop, error)
+	GetMyShop(ctx context.Context, accountID uuid.UUID) (MyShop, error)
+	// Usecase: SellerConfigure Shop (nama, profil, kebijakan; slug lama disimpan untuk redirect)
+	UpdateShop(ctx context.Context, accountID uuid.UUID, req UpdateShopRequest) (model.Shop, error)
+	UploadShopImage(ctx context.Context, accountID uuid.UUID, kind ImageKind, file *multipart.FileHeader) (model.Shop, error)
+
+	// --- Admin ---
+	// Usecase: AdminReview Shops
+	GetShops(ctx context.Context, filter AdminShopFilter) (ShopPage, error)
+	GetShop(ctx context.Context, shopID uuid.UUID) (ShopDetail, error)
+	// Usecase: AdminApprove / Activate / Suspend / Reinstate Shop
+	ChangeShopStatus(ctx context.Context, actor audit.Actor, shopID uuid.UUID, action Action, req ShopDecisionRequest) (model.Shop, error)
+
+	// --- Staff ---
+	// Usecase: SellerManage Staff (owner mengundang lewat email, mengubah role dan mengeluarkan anggota)
+	GetMembers(ctx context.Context, accountID uuid.UUID) ([]ShopMemberDetail, error)
+	UpdateMemberRole(ctx context.Context, accountID, memberID uuid.UUID, req UpdateMemberRequest) (ShopMemberDetail, error)
+	// RemoveMember juga dipakai anggota untuk keluar dari shop (memberID = accountID); owner tidak bisa dikeluarkan.
+	RemoveMember(ctx context.Context, accountID, memberID uuid.UUID) error
+	InviteMember(ctx context.Context, accountID uuid.UUID, req InviteMemberRequest) (model.ShopInvitation, error)
+	GetInvitations(ctx context.Context, accountID uuid.UUID) ([]model.ShopInvitation, error)
+	RevokeInvitation(ctx context.Context, accountID, invitationID uuid.UUID) error
+	// Usecase: CustomerRespond Shop Invitation (undangan yang ditujukan ke email akun yang login)
+	GetMyInvitations(ctx context.Context, accountID uuid.UUID) ([]InvitationDetail, error)
+	AcceptInvitation(ctx context.Context, accountID, invitationID uuid.UUID) (MyShop, error)
+	DeclineInvitation(ctx context.Context, accountID, invitationID uuid.UUID) error
+
+	// --- Libur ---
+	// Usecase: SellerSet Vacation (langsung atau terjadwal, beserta perlakuan order yang belum dikirim)
+	SetVacation(ctx context.Context, accountID uuid.UUID, req VacationRequest) (model.Shop, error)
+	EndVacation(ctx context.Context, accountID uuid.UUID) (model.Shop, error)
+	// Usecase: SystemRun Vacation Schedule (dipanggil job berkala); mengembalikan jumlah shop yang berubah
+	RunVacationSchedule(ctx context.Context) (int, error)
+
+	// --- Pengiriman ---
+	// Usecase: SellerConfigure Shipping (alamat asal, kurir & layanan, lama proses, ambil sendiri);
+	// dibaca checkout untuk opsi dan ongkir pengiriman
+	GetShipping(ctx context.Context, accountID uuid.UUID) (ShippingSettings, error)
+	UpdateShipping(ctx context.Context, accountID uuid.UUID, req ShippingRequest) (ShippingSettings, error)
+
+	// --- Statistik ---
+	// Usecase: SystemRefresh Shop Stats (dipanggil job berkala, dibaca profil publik shop)
+	RefreshShopStats(ctx context.Context) error
+}
+
+// =================================================================================
+// KONTRAK UNTUK REPOSITORY (Akses Database) 🚚
+// =================================================================================
+// Repository mendefinisikan semua interaksi ke database yang dibutuhkan oleh Service.
+type Repository interface {
+	// FindShopMembership mengambil shop tempat akun menjadi anggota beserta role-nya.
+	FindShopMembership(ctx context.Context, accountID uuid.UUID) (model.ShopMember, error)
+	FindShopByID(ctx context.Context, shopID uuid.UUID) (model.Shop, error)
+	// FindShopSlugs mengembalikan slug (aktif maupun lama) yang sama dengan base atau berawalan base-,
+	// kecuali milik shop excludeID.
+	FindShopSlugs(ctx context.Context, base string, excludeID uuid.UUID) ([]string, error)
+	// TransactionCreateShop menyimpan shop beserta akun pembuatnya sebagai owner. Mengembalikan
+	// ErrShopExists (akun sudah menjadi anggota shop) atau ErrDuplicateName jika melanggar constraint unik.
+	TransactionCreateShop(ctx context.Context, shop model.Shop) (model.Shop, error)
+	// TransactionUpdateShop menyimpan profil shop; jika slug berubah, previousSlug disimpan ke
+	// shop_slug_history untuk redirect.
+	TransactionUpdateShop(ctx context.Context, shop model.Shop, previousSlug string) (model.Shop, error)
+
+	FindShops(ctx context.Context, filter AdminShopFilter) ([]model.Shop, int64, error)
+	FindShopStatusLogs(ctx context.Context, shopID uuid.UUID) ([]model.ShopStatusLog, error)
+	// TransactionChangeShopStatus mengubah status hanya jika status di DB masih change.From
+	// (ErrShopChanged jika tidak), lalu menulis riwayat, admin_logs dan notifikasi seller.
+	TransactionChangeShopStatus(ctx context.Context, change StatusChange, entry model.AdminLog, notice model.Notification) (model.Shop, error)
+
+	FindMembers(ctx context.Context, shopID uuid.UUID) ([]ShopMemberDetail, error)
+	FindMember(ctx context.Context, shopID, accountID uuid.UUID) (ShopMemberDetail, error)
+	// UpdateMemberRole dan DeleteMember tidak pernah mengubah owner (sql.ErrNoRows).
+	UpdateMemberRole(ctx context.Context, shopID, accountID uuid.UUID, role string) error
+	DeleteMember(ctx context.Context, shopID, accountID uuid.UUID) error
+	FindAccountEmail(ctx context.Context, accountID uuid.UUID) (string, error)
+	// TransactionSaveInvitation membuat undangan, atau memperbarui undangan yang masih terbuka untuk
+	// email yang sama. ErrAlreadyMember jika email itu sudah anggota shop. Jika email terdaftar,
+	// pemiliknya dinotifikasi (build).
+	TransactionSaveInvitation(ctx context.Context, invitation model.ShopInvitation, build func(inviteeID uuid.UUID) (model.Notification, error)) (model.ShopInvitation, error)
+	FindOpenInvitations(ctx context.Context, shopID uuid.UUID) ([]model.ShopInvitation, error)
+	RevokeInvitation(ctx context.Context, shopID, invitationID uuid.UUID) error
+	// FindInvitationsForEmail mengembalikan undangan terbuka yang belum kedaluwarsa untuk email.
+	FindInvitationsForEmail(ctx context.Context, email string, now time.Time) ([]InvitationDetail, error)
+	// TransactionAcceptInvitation menjadikan accountID anggota shop pengundang dan memberi tahu owner
+	// (build). sql.ErrNoRows jika undangan tidak ada / sudah ditutup / kedaluwarsa, ErrAlreadyMember
+	// jika akun sudah menjadi anggota shop.
+	TransactionAcceptInvitation(ctx context.Context, accountID uuid.UUID, email string, invitationID uuid.UUID, now time.Time, build func(model.Shop) (model.Notification, error)) (model.ShopMember, error)
+	DeclineInvitation(ctx context.Context, email string, invitationID uuid.UUID) error
+
+	// SaveVacationSchedule menyimpan libur yang baru akan dimulai di settings.StartsAt; libur yang
+	// sedang berjalan dihentikan.
+	SaveVacationSchedule(ctx context.Context, shopID uuid.UUID, settings VacationSettings) (model.Shop, error)
+	// TransactionStartVacation menyalakan libur. Order shop yang belum dikirim membuat libur ditolak
+	// (ErrOrdersAwaitingShipment) untuk kebijakan block, atau batas kirimnya digeser sampai
+	// settings.EndsAt dan pembelinya dinotifikasi (buildDelay) untuk kebijakan extend.
+	// Jika scheduled, libur hanya dinyalakan jika jadwal di DB masih settings.StartsAt
+	// (ErrVacationChanged jika tidak) dan notice dikirim ke seller.
+	TransactionStartVacation(ctx context.Context, shopID uuid.UUID, settings VacationSettings, scheduled bool, buildDelay func(DelayedOrder) (model.Notification, error), notice *model.Notification) (model.Shop, error)
+	// MarkVacationBlocked menandai libur terjadwal yang tertahan order; notice hanya dikirim saat
+	// pertama kali tertahan.
+	MarkVacationBlocked(ctx context.Context, shopID uuid.UUID, notice model.Notification) error
+	// ClearVacation mematikan libur dan menghapus jadwalnya.
+	ClearVacation(ctx context.Context, shopID uuid.UUID) (model.Shop, error)
+	// FindDueVacationStarts mengembalikan shop dengan jadwal libur yang sudah waktunya dimulai.
+	FindDueVacationStarts(ctx context.Context, now time.Time, limit int) ([]model.Shop, error)
+	// TransactionEndDueVacations mematikan libur yang vacation_ends_at-nya sudah lewat dan
+	// menotifikasi seller-nya (build).
+	TransactionEndDueVacations(ctx context.Context, now time.Time, build func(EndedVacation) (model.Notification, error)) (int, error)
+
+	// FindRegion mengambil district beserta regency dan province-nya (sql.ErrNoRows jika tidak ada).
+	FindRegion(ctx context.Context, districtID string) (ShippingOrigin, error)
+	FindShopCouriers(ctx context.Context, shopID uuid.UUID) ([]model.ShopCourier, error)
+	// TransactionSaveShipping menyimpan alamat asal, lama proses dan opsi ambil sendiri dari shop,
+	// lalu mengganti seluruh layanan kurir shop dengan couriers.
+	TransactionSaveShipping(ctx context.Context, shop model.Shop, couriers []model.ShopCourier) (model.Shop, error)
+
+	// RefreshShopStats menghitung ulang shop_stats untuk semua shop. Response rate dan waktu kirim
+	// hanya memperhitungkan aktivitas sejak since.
+	RefreshShopStats(ctx context.Context, since time.Time) error
+}
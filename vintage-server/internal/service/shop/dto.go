@@ -0,0 +1,206 @@
+package shop
+
+import (
+	"errors"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/staff"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrShopExists dikembalikan repository jika akun sudah memiliki shop.
+	ErrShopExists = errors.New("account already has a shop")
+	// ErrDuplicateName dikembalikan repository jika nama shop sudah dipakai shop lain.
+	ErrDuplicateName = errors.New("shop name already taken")
+	// ErrShopChanged dikembalikan repository jika status shop berubah sejak dibaca service.
+	ErrShopChanged = errors.New("shop status changed concurrently")
+	// ErrOrdersAwaitingShipment dikembalikan repository jika libur dengan kebijakan block
+	// dimulai saat shop masih punya order yang belum dikirim.
+	ErrOrdersAwaitingShipment = errors.New("shop has orders awaiting shipment")
+	// ErrVacationChanged dikembalikan repository jika jadwal libur berubah sejak dibaca job.
+	ErrVacationChanged = errors.New("shop vacation changed concurrently")
+	// ErrAlreadyMember dikembalikan repository jika akun yang diundang / menerima undangan sudah
+	// menjadi anggota sebuah shop.
+	ErrAlreadyMember = errors.New("account already belongs to a shop")
+)
+
+// Action adalah keputusan admin atas status shop.
+type Action string
+
+const (
+	// ActionApprove: pengajuan shop baru lolos review (pending -> approved)
+	ActionApprove Action = "approve"
+	// ActionActivate: shop yang sudah disetujui mulai tayang (approved -> active)
+	ActionActivate Action = "activate"
+	// ActionSuspend: shop diturunkan sementara, listing-nya hilang dari katalog (active -> suspended)
+	ActionSuspend Action = "suspend"
+	// ActionReinstate: shop yang disuspend tayang kembali (suspended -> active)
+	ActionReinstate Action = "reinstate"
+)
+
+// ImageKind adalah jenis gambar profil shop; nilainya juga nama field form upload.
+type ImageKind string
+
+const (
+	ImageLogo   ImageKind = "logo"
+	ImageBanner ImageKind = "banner"
+)
+
+type CreateShopRequest struct {
+	Name           string  `json:"name" binding:"required,max=64"`
+	Summary        *string `json:"summary" binding:"omitempty,max=255"`
+	Description    *string `json:"description" binding:"omitempty,max=5000"`
+	ShippingPolicy *string `json:"shipping_policy" binding:"omitempty,max=5000"`
+	ReturnPolicy   *string `json:"return_policy" binding:"omitempty,max=5000"`
+	PaymentPolicy  *string `json:"payment_policy" binding:"omitempty,max=5000"`
+}
+
+// UpdateShopRequest: field yang tidak dikirim tidak diubah, string kosong mengosongkan field opsional.
+type UpdateShopRequest struct {
+	Name           *string `json:"name" binding:"omitempty,min=1,max=64"`
+	Summary        *string `json:"summary" binding:"omitempty,max=255"`
+	Description    *string `json:"description" binding:"omitempty,max=5000"`
+	ShippingPolicy *string `json:"shipping_policy" binding:"omitempty,max=5000"`
+	ReturnPolicy   *string `json:"return_policy" binding:"omitempty,max=5000"`
+	PaymentPolicy  *string `json:"payment_policy" binding:"omitempty,max=5000"`
+}
+
+// InviteMemberRequest mengundang akun dengan email tersebut; role owner tidak bisa diundang.
+type InviteMemberRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+	Role  string `json:"role" binding:"required,oneof=manager lister fulfillment"`
+}
+
+type UpdateMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=manager lister fulfillment"`
+}
+
+// ShopDecisionRequest adalah alasan keputusan admin; wajib untuk suspend dan reinstate.
+type ShopDecisionRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// VacationRequest menyalakan mode libur. Tanpa starts_at (atau starts_at yang sudah lewat) libur
+// langsung aktif; tanpa ends_at libur berlangsung sampai dimatikan seller.
+type VacationRequest struct {
+	Message  *string    `json:"message" binding:"omitempty,max=500"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+	// PendingOrders: block (default) atau extend, lihat model.VacationPendingOrders*
+	PendingOrders string `json:"pending_orders" binding:"omitempty,oneof=block extend"`
+}
+
+// ShippingRequest mengganti seluruh pengaturan pengiriman shop. Kurir & layanan mengikuti katalog
+// shipment.Couriers; minimal satu layanan kurir atau ambil sendiri harus aktif.
+type ShippingRequest struct {
+	DistrictID string `json:"district_id" binding:"required,max=10"`
+	Street     string `json:"street" binding:"required,max=500"`
+	PostalCode string `json:"postal_code" binding:"required,max=10"`
+	// HandlingDays nil berarti memakai batas kirim default platform
+	HandlingDays *int16                  `json:"handling_days" binding:"omitempty,min=1,max=14"`
+	SelfPickup   bool                    `json:"self_pickup"`
+	Couriers     []CourierServiceRequest `json:"couriers" binding:"dive"`
+}
+
+type CourierServiceRequest struct {
+	Courier string `json:"courier" binding:"required"`
+	Service string `json:"service" binding:"required"`
+}
+
+type AdminShopFilter struct {
+	Status string `form:"status"`
+	// Query mencari berdasarkan nama atau slug shop
+	Query string `form:"q"`
+	Page  int    `form:"page"`
+	Limit int    `form:"limit"`
+}
+
+type ShopPage struct {
+	Items []model.Shop `json:"items"`
+	Total int64        `json:"total"`
+	Page  int          `json:"page"`
+	Limit int          `json:"limit"`
+}
+
+// MyShop adalah shop tempat akun yang login menjadi anggota, beserta role dan hak aksesnya.
+type MyShop struct {
+	model.Shop
+	Role        string             `json:"role"`
+	Permissions []staff.Permission `json:"permissions"`
+}
+
+// ShopMemberDetail adalah anggota shop beserta profil akunnya.
+type ShopMemberDetail struct {
+	model.ShopMember
+	Username    string             `json:"username" db:"username"`
+	Firstname   string             `json:"firstname" db:"firstname"`
+	Lastname    *string            `json:"lastname" db:"lastname"`
+	Email       string             `json:"email" db:"email"`
+	Permissions []staff.Permission `json:"permissions" db:"-"`
+}
+
+// InvitationDetail adalah undangan yang dilihat calon anggota, beserta shop yang mengundang.
+type InvitationDetail struct {
+	model.ShopInvitation
+	ShopName string `json:"shop_name" db:"shop_name"`
+	ShopSlug string `json:"shop_slug" db:"shop_slug"`
+}
+
+// ShippingOrigin adalah alamat asal pengiriman shop beserta nama wilayahnya.
+type ShippingOrigin struct {
+	DistrictID   string `json:"district_id" db:"district_id"`
+	DistrictName string `json:"district_name" db:"district_name"`
+	RegencyID    string `json:"regency_id" db:"regency_id"`
+	RegencyName  string `json:"regency_name" db:"regency_name"`
+	ProvinceID   string `json:"province_id" db:"province_id"`
+	ProvinceName string `json:"province_name" db:"province_name"`
+	Street       string `json:"street" db:"-"`
+	PostalCode   string `json:"postal_code" db:"-"`
+}
+
+// ShippingSettings adalah pengaturan pengiriman shop; Origin nil jika belum diisi.
+type ShippingSettings struct {
+	Origin       *ShippingOrigin     `json:"origin"`
+	HandlingDays *int16              `json:"handling_days"`
+	SelfPickup   bool                `json:"self_pickup"`
+	Couriers     []model.ShopCourier `json:"couriers"`
+}
+
+// ShopDetail adalah shop beserta riwayat keputusan admin, untuk halaman review.
+type ShopDetail struct {
+	model.Shop
+	StatusLogs []model.ShopStatusLog `json:"status_logs"`
+}
+
+// StatusChange adalah perpindahan status shop yang ditulis repository beserta riwayatnya.
+type StatusChange struct {
+	ShopID  uuid.UUID
+	From    string
+	To      string
+	Reason  *string
+	ActorID uuid.UUID
+}
+
+// VacationSettings adalah pengaturan libur yang ditulis repository. StartsAt nil berarti mulai sekarang.
+type VacationSettings struct {
+	Message       *string
+	StartsAt      *time.Time
+	EndsAt        *time.Time
+	PendingOrders string
+}
+
+// DelayedOrder adalah order yang batas kirimnya digeser karena shop libur.
+type DelayedOrder struct {
+	OrderID   uuid.UUID `db:"id"`
+	AccountID uuid.UUID `db:"account_id"`
+	ShipBy    time.Time `db:"ship_by"`
+}
+
+// EndedVacation adalah shop yang jadwal liburnya selesai; WasActive false berarti libur tidak
+// pernah berjalan karena tertahan order.
+type EndedVacation struct {
+	model.Shop
+	WasActive bool `db:"was_active"`
+}
@@ -1 +1,361 @@
 package shop
+
+import (
+	"net/http"
+	"vintage-server/internal/service/audit"
+	"vintage-server/internal/service/shipment"
+	"vintage-server/pkg/middleware"
+	"vintage-server/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Handler adalah struct yang memegang dependency ke Service
+type Handler struct {
+	svc Service
+}
+
+// NewHandler adalah constructor untuk handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// --- Seller ---
+
+// CreateShop membuat shop untuk akun yang login; shop menunggu review admin sebelum tayang
+func (h *Handler) CreateShop(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var req CreateShopRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	shop, err := h.svc.CreateShop(c.Request.Context(), accountID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusCreated, shop)
+}
+
+// GetMyShop mengembalikan shop tempat akun yang login menjadi anggota, termasuk status review dan role-nya
+func (h *Handler) GetMyShop(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	shop, err := h.svc.GetMyShop(c.Request.Context(), accountID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, shop)
+}
+
+// UpdateShop mengubah profil dan kebijakan shop milik akun yang login
+func (h *Handler) UpdateShop(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var req UpdateShopRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	shop, err := h.svc.UpdateShop(c.Request.Context(), accountID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, shop)
+}
+
+// UploadImage mengembalikan handler upload logo / banner shop (field form sama dengan kind)
+func (h *Handler) UploadImage(kind ImageKind) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID, _ := middleware.GetAccountID(c)
+
+		file, err := c.FormFile(string(kind))
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Image file is required")
+			return
+		}
+
+		shop, err := h.svc.UploadShopImage(c.Request.Context(), accountID, kind, file)
+		if err != nil {
+			response.FromError(c, err)
+			return
+		}
+		response.Success(c, http.StatusOK, shop)
+	}
+}
+
+func (h *Handler) SetVacation(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var req VacationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	shop, err := h.svc.SetVacation(c.Request.Context(), accountID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, shop)
+}
+
+func (h *Handler) EndVacation(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	shop, err := h.svc.EndVacation(c.Request.Context(), accountID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, shop)
+}
+
+// --- Pengiriman ---
+
+// GetShipping mengembalikan alamat asal, kurir & layanan, lama proses dan opsi ambil sendiri shop
+func (h *Handler) GetShipping(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	settings, err := h.svc.GetShipping(c.Request.Context(), accountID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, settings)
+}
+
+// UpdateShipping mengganti seluruh pengaturan pengiriman shop
+func (h *Handler) UpdateShipping(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var req ShippingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	settings, err := h.svc.UpdateShipping(c.Request.Context(), accountID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, settings)
+}
+
+// GetCouriers mengembalikan katalog kurir & layanan yang bisa diaktifkan shop
+func (h *Handler) GetCouriers(c *gin.Context) {
+	response.Success(c, http.StatusOK, shipment.Couriers)
+}
+
+// --- Staff ---
+
+// GetMembers mengembalikan anggota shop beserta role dan hak aksesnya
+func (h *Handler) GetMembers(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	members, err := h.svc.GetMembers(c.Request.Context(), accountID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, members)
+}
+
+func (h *Handler) UpdateMemberRole(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	memberID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid member id")
+		return
+	}
+	var req UpdateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	member, err := h.svc.UpdateMemberRole(c.Request.Context(), accountID, memberID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, member)
+}
+
+// RemoveMember mengeluarkan anggota shop; anggota bisa keluar sendiri dengan id akunnya
+func (h *Handler) RemoveMember(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	memberID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid member id")
+		return
+	}
+
+	if err := h.svc.RemoveMember(c.Request.Context(), accountID, memberID); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// InviteMember mengundang staff lewat email; undangan yang masih terbuka untuk email yang sama diperbarui
+func (h *Handler) InviteMember(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	var req InviteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	invitation, err := h.svc.InviteMember(c.Request.Context(), accountID, req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusCreated, invitation)
+}
+
+func (h *Handler) GetInvitations(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	invitations, err := h.svc.GetInvitations(c.Request.Context(), accountID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, invitations)
+}
+
+func (h *Handler) RevokeInvitation(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	invitationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid invitation id")
+		return
+	}
+
+	if err := h.svc.RevokeInvitation(c.Request.Context(), accountID, invitationID); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetMyInvitations mengembalikan undangan staff yang ditujukan ke email akun yang login
+func (h *Handler) GetMyInvitations(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	invitations, err := h.svc.GetMyInvitations(c.Request.Context(), accountID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, invitations)
+}
+
+func (h *Handler) AcceptInvitation(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	invitationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid invitation id")
+		return
+	}
+
+	shop, err := h.svc.AcceptInvitation(c.Request.Context(), accountID, invitationID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, shop)
+}
+
+func (h *Handler) DeclineInvitation(c *gin.Context) {
+	accountID, _ := middleware.GetAccountID(c)
+
+	invitationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid invitation id")
+		return
+	}
+
+	if err := h.svc.DeclineInvitation(c.Request.Context(), accountID, invitationID); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// --- Admin ---
+
+// GetShops adalah handler admin untuk daftar shop, misal antrian review (?status=pending)
+func (h *Handler) GetShops(c *gin.Context) {
+	var filter AdminShopFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.svc.GetShops(c.Request.Context(), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, result)
+}
+
+// GetShop adalah handler admin untuk detail shop beserta riwayat keputusannya
+func (h *Handler) GetShop(c *gin.Context) {
+	shopID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid shop id")
+		return
+	}
+
+	detail, err := h.svc.GetShop(c.Request.Context(), shopID)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, detail)
+}
+
+// ChangeStatus mengembalikan handler admin untuk satu keputusan status shop (approve, suspend, dll)
+func (h *Handler) ChangeStatus(action Action) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		shopID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid shop id")
+			return
+		}
+
+		// Body boleh kosong untuk keputusan yang tidak mewajibkan alasan
+		var req ShopDecisionRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				response.Error(c, http.StatusBadRequest, "Invalid request body")
+				return
+			}
+		}
+
+		shop, err := h.svc.ChangeShopStatus(c.Request.Context(), audit.ActorFromContext(c), shopID, action, req)
+		if err != nil {
+			response.FromError(c, err)
+			return
+		}
+		response.Success(c, http.StatusOK, shop)
+	}
+}
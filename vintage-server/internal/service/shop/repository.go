@@ -1 +1,779 @@
 package shop
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/audit"
+	"vintage-server/internal/service/notification"
+	"vintage-server/internal/service/staff"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// repository adalah struct yang mengimplementasikan kontrak Repository dari domain.go
+type repository struct {
+	db *sqlx.DB
+}
+
+// NewRepository adalah constructor untuk implementasi repository
+func NewRepository(db *sqlx.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// --- Seller ---
+
+func (r *repository) FindShopMembership(ctx context.Context, accountID uuid.UUID) (model.ShopMember, error) {
+	return staff.FindMembership(ctx, r.db, accountID)
+}
+
+func (r *repository) FindShopByID(ctx context.Context, shopID uuid.UUID) (model.Shop, error) {
+	var shop model.Shop
+	query := "SELECT * FROM shop WHERE id = $1 AND deleted_at IS NULL"
+	err := r.db.GetContext(ctx, &shop, query, shopID)
+	return shop, err
+}
+
+func (r *repository) FindShopSlugs(ctx context.Context, base string, excludeID uuid.UUID) ([]string, error) {
+	slugs := []string{}
+	// base hanya berisi [a-z0-9-] sehingga aman dipakai sebagai pola LIKE
+	query := `
+		SELECT slug FROM shop WHERE (slug = $1 OR slug LIKE $1 || '-%') AND id <> $2
+		UNION
+		SELECT slug FROM shop_slug_history WHERE (slug = $1 OR slug LIKE $1 || '-%') AND shop_id <> $2`
+	err := r.db.SelectContext(ctx, &slugs, query, base, excludeID)
+	return slugs, err
+}
+
+func (r *repository) TransactionCreateShop(ctx context.Context, shop model.Shop) (model.Shop, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return model.Shop{}, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Simpan shop
+	var created model.Shop
+	query := `
+		INSERT INTO shop (account_id, name, slug, summary, description, shipping_policy, return_policy, payment_policy, status, active)
+		VALUES (:account_id, :name, :slug, :summary, :description, :shipping_policy, :return_policy, :payment_policy, :status, :active)
+		RETURNING *`
+	bound, args, err := tx.BindNamed(query, shop)
+	if err != nil {
+		return model.Shop{}, err
+	}
+	if err := tx.GetContext(ctx, &created, bound, args...); err != nil {
+		return model.Shop{}, uniqueViolation(err)
+	}
+
+	// Query 2: Pembuat shop menjadi owner
+	memberQuery := "INSERT INTO shop_members (shop_id, account_id, role) VALUES ($1, $2, $3)"
+	if _, err := tx.ExecContext(ctx, memberQuery, created.ID, created.AccountID, model.ShopRoleOwner); err != nil {
+		return model.Shop{}, uniqueViolation(err)
+	}
+
+	return created, tx.Commit()
+}
+
+func (r *repository) TransactionUpdateShop(ctx context.Context, shop model.Shop, previousSlug string) (model.Shop, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return model.Shop{}, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Simpan profil
+	var updated model.Shop
+	query := `
+		UPDATE shop SET
+			name = :name,
+			slug = :slug,
+			summary = :summary,
+			description = :description,
+			logo_url = :logo_url,
+			banner_url = :banner_url,
+			shipping_policy = :shipping_policy,
+			return_policy = :return_policy,
+			payment_policy = :payment_policy,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = :id AND deleted_at IS NULL
+		RETURNING *`
+	bound, args, err := tx.BindNamed(query, shop)
+	if err != nil {
+		return model.Shop{}, err
+	}
+	if err := tx.GetContext(ctx, &updated, bound, args...); err != nil {
+		return model.Shop{}, uniqueViolation(err)
+	}
+
+	if previousSlug != shop.Slug {
+		// Query 2: Slug baru bisa saja slug lama shop ini sendiri (nama dikembalikan)
+		query := "DELETE FROM shop_slug_history WHERE slug = $1 AND shop_id = $2"
+		if _, err := tx.ExecContext(ctx, query, shop.Slug, shop.ID); err != nil {
+			return model.Shop{}, err
+		}
+
+		// Query 3: Simpan slug lama untuk redirect
+		query = "INSERT INTO shop_slug_history (slug, shop_id) VALUES ($1, $2) ON CONFLICT (slug) DO NOTHING"
+		if _, err := tx.ExecContext(ctx, query, previousSlug, shop.ID); err != nil {
+			return model.Shop{}, err
+		}
+	}
+
+	return updated, tx.Commit()
+}
+
+// uniqueViolation menerjemahkan pelanggaran constraint unik tabel shop ke error sentinel.
+func uniqueViolation(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		switch pqErr.Constraint {
+		case "shop_account_id_key", "shop_members_account_key", "shop_members_pkey":
+			return ErrShopExists
+		case "shop_name_key":
+			return ErrDuplicateName
+		}
+	}
+	return err
+}
+
+// --- Admin ---
+
+func (r *repository) FindShops(ctx context.Context, filter AdminShopFilter) ([]model.Shop, int64, error) {
+	where := "deleted_at IS NULL AND ($1 = '' OR status = $1) AND ($2 = '' OR name ILIKE '%' || $2 || '%' OR slug LIKE '%' || $2 || '%')"
+
+	var total int64
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM shop WHERE "+where, filter.Status, filter.Query); err != nil {
+		return nil, 0, err
+	}
+
+	// Antrian review paling lama di atas
+	shops := []model.Shop{}
+	query := "SELECT * FROM shop WHERE " + where + " ORDER BY created_at ASC, id LIMIT $3 OFFSET $4"
+	err := r.db.SelectContext(ctx, &shops, query, filter.Status, filter.Query, filter.Limit, (filter.Page-1)*filter.Limit)
+	return shops, total, err
+}
+
+func (r *repository) FindShopStatusLogs(ctx context.Context, shopID uuid.UUID) ([]model.ShopStatusLog, error) {
+	logs := []model.ShopStatusLog{}
+	query := "SELECT * FROM shop_status_logs WHERE shop_id = $1 ORDER BY created_at DESC, id DESC"
+	err := r.db.SelectContext(ctx, &logs, query, shopID)
+	return logs, err
+}
+
+func (r *repository) TransactionChangeShopStatus(ctx context.Context, change StatusChange, entry model.AdminLog, notice model.Notification) (model.Shop, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return model.Shop{}, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Ubah status, hanya jika status di DB masih sama dengan yang dibaca service
+	var shop model.Shop
+	query := `
+		UPDATE shop SET
+			status = $3,
+			active = ($3 = 'active'),
+			status_reason = $4,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = $2 AND deleted_at IS NULL
+		RETURNING *`
+	err = tx.GetContext(ctx, &shop, query, change.ShopID, change.From, change.To, change.Reason)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Shop{}, ErrShopChanged
+	}
+	if err != nil {
+		return model.Shop{}, err
+	}
+
+	// Query 2: Riwayat status
+	logQuery := `
+		INSERT INTO shop_status_logs (shop_id, from_status, to_status, reason, created_by)
+		VALUES ($1, $2, $3, $4, $5)`
+	if _, err := tx.ExecContext(ctx, logQuery, change.ShopID, change.From, change.To, change.Reason, change.ActorID); err != nil {
+		return model.Shop{}, err
+	}
+
+	// Query 3: Keputusan dicatat di admin_logs dan diberitahukan ke seller
+	if err := audit.SaveAdminLog(ctx, tx, entry); err != nil {
+		return model.Shop{}, err
+	}
+	if err := notification.SaveNotification(ctx, tx, notice); err != nil {
+		return model.Shop{}, err
+	}
+
+	return shop, tx.Commit()
+}
+
+// --- Staff ---
+
+// memberDetailSelect membentuk ShopMemberDetail dari shop_members (alias m) dan accounts (alias a).
+const memberDetailSelect = `
+	SELECT m.*, a.username, a.firstname, a.lastname, a.email
+	FROM shop_members m
+	JOIN accounts a ON a.id = m.account_id`
+
+// openInvitation adalah kondisi undangan (alias i) yang belum diterima, ditolak maupun dicabut.
+const openInvitation = "i.accepted_at IS NULL AND i.declined_at IS NULL AND i.revoked_at IS NULL"
+
+func (r *repository) FindMembers(ctx context.Context, shopID uuid.UUID) ([]ShopMemberDetail, error) {
+	members := []ShopMemberDetail{}
+	// Owner di atas, lalu anggota terlama
+	query := memberDetailSelect + " WHERE m.shop_id = $1 ORDER BY m.role = 'owner' DESC, m.created_at, m.account_id"
+	err := r.db.SelectContext(ctx, &members, query, shopID)
+	return members, err
+}
+
+func (r *repository) FindMember(ctx context.Context, shopID, accountID uuid.UUID) (ShopMemberDetail, error) {
+	var member ShopMemberDetail
+	query := memberDetailSelect + " WHERE m.shop_id = $1 AND m.account_id = $2"
+	err := r.db.GetContext(ctx, &member, query, shopID, accountID)
+	return member, err
+}
+
+func (r *repository) UpdateMemberRole(ctx context.Context, shopID, accountID uuid.UUID, role string) error {
+	query := `
+		UPDATE shop_members SET role = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE shop_id = $1 AND account_id = $2 AND role <> 'owner'`
+	result, err := r.db.ExecContext(ctx, query, shopID, accountID, role)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+func (r *repository) DeleteMember(ctx context.Context, shopID, accountID uuid.UUID) error {
+	query := "DELETE FROM shop_members WHERE shop_id = $1 AND account_id = $2 AND role <> 'owner'"
+	result, err := r.db.ExecContext(ctx, query, shopID, accountID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+func (r *repository) FindAccountEmail(ctx context.Context, accountID uuid.UUID) (string, error) {
+	var email string
+	err := r.db.GetContext(ctx, &email, "SELECT email FROM accounts WHERE id = $1 AND deleted_at IS NULL", accountID)
+	return email, err
+}
+
+func (r *repository) TransactionSaveInvitation(ctx context.Context, invitation model.ShopInvitation, build func(inviteeID uuid.UUID) (model.Notification, error)) (model.ShopInvitation, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return model.ShopInvitation{}, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Akun terdaftar dengan email tersebut (jika ada) tidak boleh sudah anggota shop ini
+	var invitee struct {
+		ID     uuid.UUID  `db:"id"`
+		ShopID *uuid.UUID `db:"shop_id"`
+	}
+	inviteeQuery := `
+		SELECT a.id, m.shop_id FROM accounts a
+		LEFT JOIN shop_members m ON m.account_id = a.id
+		WHERE lower(a.email) = lower($1) AND a.deleted_at IS NULL`
+	err = tx.GetContext(ctx, &invitee, inviteeQuery, invitation.Email)
+	registered := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return model.ShopInvitation{}, err
+	}
+	if registered && invitee.ShopID != nil && *invitee.ShopID == invitation.ShopID {
+		return model.ShopInvitation{}, ErrAlreadyMember
+	}
+
+	// Query 2: Undangan terbuka untuk email yang sama diperbarui, bukan diduplikasi
+	var saved model.ShopInvitation
+	query := `
+		INSERT INTO shop_invitations (shop_id, email, role, invited_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (shop_id, lower(email)) WHERE accepted_at IS NULL AND declined_at IS NULL AND revoked_at IS NULL
+		DO UPDATE SET
+			role = EXCLUDED.role,
+			invited_by = EXCLUDED.invited_by,
+			expires_at = EXCLUDED.expires_at,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING *`
+	err = tx.GetContext(ctx, &saved, query, invitation.ShopID, invitation.Email, invitation.Role, invitation.InvitedBy, invitation.ExpiresAt)
+	if err != nil {
+		return model.ShopInvitation{}, err
+	}
+
+	// Query 3: Beri tahu calon anggota yang sudah punya akun
+	if registered {
+		n, err := build(invitee.ID)
+		if err != nil {
+			return model.ShopInvitation{}, err
+		}
+		if err := notification.SaveNotification(ctx, tx, n); err != nil {
+			return model.ShopInvitation{}, err
+		}
+	}
+
+	return saved, tx.Commit()
+}
+
+func (r *repository) FindOpenInvitations(ctx context.Context, shopID uuid.UUID) ([]model.ShopInvitation, error) {
+	invitations := []model.ShopInvitation{}
+	query := "SELECT i.* FROM shop_invitations i WHERE i.shop_id = $1 AND " + openInvitation + " ORDER BY i.created_at DESC, i.id"
+	err := r.db.SelectContext(ctx, &invitations, query, shopID)
+	return invitations, err
+}
+
+func (r *repository) RevokeInvitation(ctx context.Context, shopID, invitationID uuid.UUID) error {
+	query := `
+		UPDATE shop_invitations i SET revoked_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE i.id = $1 AND i.shop_id = $2 AND ` + openInvitation
+	result, err := r.db.ExecContext(ctx, query, invitationID, shopID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+func (r *repository) FindInvitationsForEmail(ctx context.Context, email string, now time.Time) ([]InvitationDetail, error) {
+	invitations := []InvitationDetail{}
+	query := `
+		SELECT i.*, s.name AS shop_name, s.slug AS shop_slug
+		FROM shop_invitations i
+		JOIN shop s ON s.id = i.shop_id
+		WHERE lower(i.email) = lower($1) AND i.expires_at > $2 AND s.deleted_at IS NULL AND ` + openInvitation + `
+		ORDER BY i.created_at DESC, i.id`
+	err := r.db.SelectContext(ctx, &invitations, query, email, now)
+	return invitations, err
+}
+
+func (r *repository) TransactionAcceptInvitation(ctx context.Context, accountID uuid.UUID, email string, invitationID uuid.UUID, now time.Time, build func(model.Shop) (model.Notification, error)) (model.ShopMember, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return model.ShopMember{}, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Kunci undangan yang masih terbuka untuk email akun ini
+	var invitation model.ShopInvitation
+	lockQuery := `
+		SELECT i.* FROM shop_invitations i
+		WHERE i.id = $1 AND lower(i.email) = lower($2) AND i.expires_at > $3 AND ` + openInvitation + `
+		FOR UPDATE`
+	if err := tx.GetContext(ctx, &invitation, lockQuery, invitationID, email, now); err != nil {
+		return model.ShopMember{}, err
+	}
+
+	// Query 2: Jadikan anggota; satu akun hanya bisa menjadi anggota satu shop
+	var member model.ShopMember
+	memberQuery := `
+		INSERT INTO shop_members (shop_id, account_id, role, invited_by)
+		SELECT s.id, $2, $3, $4 FROM shop s WHERE s.id = $1 AND s.deleted_at IS NULL
+		RETURNING *`
+	err = tx.GetContext(ctx, &member, memberQuery, invitation.ShopID, accountID, invitation.Role, invitation.InvitedBy)
+	if err != nil {
+		if errors.Is(uniqueViolation(err), ErrShopExists) {
+			return model.ShopMember{}, ErrAlreadyMember
+		}
+		return model.ShopMember{}, err
+	}
+
+	// Query 3: Tutup undangan dan beri tahu owner
+	acceptQuery := "UPDATE shop_invitations SET accepted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = $1"
+	if _, err := tx.ExecContext(ctx, acceptQuery, invitationID); err != nil {
+		return model.ShopMember{}, err
+	}
+	var shop model.Shop
+	if err := tx.GetContext(ctx, &shop, "SELECT * FROM shop WHERE id = $1", invitation.ShopID); err != nil {
+		return model.ShopMember{}, err
+	}
+	n, err := build(shop)
+	if err != nil {
+		return model.ShopMember{}, err
+	}
+	if err := notification.SaveNotification(ctx, tx, n); err != nil {
+		return model.ShopMember{}, err
+	}
+
+	return member, tx.Commit()
+}
+
+func (r *repository) DeclineInvitation(ctx context.Context, email string, invitationID uuid.UUID) error {
+	query := `
+		UPDATE shop_invitations i SET declined_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE i.id = $1 AND lower(i.email) = lower($2) AND ` + openInvitation
+	result, err := r.db.ExecContext(ctx, query, invitationID, email)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// requireAffected mengubah UPDATE / DELETE yang tidak mengenai baris apa pun menjadi sql.ErrNoRows.
+func requireAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// --- Libur ---
+
+// clearVacationColumns mengosongkan semua kolom mode libur shop.
+const clearVacationColumns = `
+	vacation_active = FALSE,
+	vacation_message = NULL,
+	vacation_starts_at = NULL,
+	vacation_ends_at = NULL,
+	vacation_pending_orders = 'block',
+	vacation_blocked_at = NULL,
+	updated_at = CURRENT_TIMESTAMP`
+
+// shopOrdersFilter memilih order yang memuat produk shop $1.
+const shopOrdersFilter = `
+	EXISTS (
+		SELECT 1 FROM order_items oi
+		JOIN products p ON p.id = oi.product_id
+		WHERE oi.order_id = o.id AND p.shop_id = $1
+	)`
+
+func (r *repository) SaveVacationSchedule(ctx context.Context, shopID uuid.UUID, settings VacationSettings) (model.Shop, error) {
+	var shop model.Shop
+	query := `
+		UPDATE shop SET
+			vacation_active = FALSE,
+			vacation_message = $2,
+			vacation_starts_at = $3,
+			vacation_ends_at = $4,
+			vacation_pending_orders = $5,
+			vacation_blocked_at = NULL,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING *`
+	err := r.db.GetContext(ctx, &shop, query, shopID, settings.Message, settings.StartsAt, settings.EndsAt, settings.PendingOrders)
+	return shop, err
+}
+
+func (r *repository) TransactionStartVacation(ctx context.Context, shopID uuid.UUID, settings VacationSettings, scheduled bool, buildDelay func(DelayedOrder) (model.Notification, error), notice *model.Notification) (model.Shop, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return model.Shop{}, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Kunci shop; job hanya melanjutkan jika jadwal belum diubah / dimulai seller
+	var current struct {
+		Active   bool       `db:"vacation_active"`
+		StartsAt *time.Time `db:"vacation_starts_at"`
+	}
+	lockQuery := "SELECT vacation_active, vacation_starts_at FROM shop WHERE id = $1 AND deleted_at IS NULL FOR UPDATE"
+	if err := tx.GetContext(ctx, &current, lockQuery, shopID); err != nil {
+		return model.Shop{}, err
+	}
+	if scheduled && (current.Active || current.StartsAt == nil || settings.StartsAt == nil || !current.StartsAt.Equal(*settings.StartsAt)) {
+		return model.Shop{}, ErrVacationChanged
+	}
+
+	// Query 2: Order shop yang belum dikirim (menunggu pembayaran atau sudah dibayar)
+	var pending int
+	pendingQuery := `
+		SELECT COUNT(*) FROM (
+			SELECT o.id FROM orders o
+			WHERE o.status IN ($2, $3) AND` + shopOrdersFilter + `
+			FOR UPDATE
+		) pending`
+	if err := tx.GetContext(ctx, &pending, pendingQuery, shopID, model.OrderStatusPendingPayment, model.OrderStatusPaid); err != nil {
+		return model.Shop{}, err
+	}
+
+	if pending > 0 {
+		if settings.PendingOrders != model.VacationPendingOrdersExtend {
+			return model.Shop{}, ErrOrdersAwaitingShipment
+		}
+
+		// Query 3: Geser batas kirim order yang sudah dibayar sepanjang sisa libur. Order yang belum
+		// dibayar mendapat batas kirim dari tanggal kembali saat pembayarannya masuk.
+		var delayed []DelayedOrder
+		extendQuery := `
+			UPDATE orders o SET
+				ship_by = COALESCE(o.ship_by, CURRENT_TIMESTAMP) + ($3::timestamptz - CURRENT_TIMESTAMP),
+				updated_at = CURRENT_TIMESTAMP
+			WHERE o.status = $2 AND` + shopOrdersFilter + `
+			RETURNING o.id, o.account_id, o.ship_by`
+		if err := tx.SelectContext(ctx, &delayed, extendQuery, shopID, model.OrderStatusPaid, settings.EndsAt); err != nil {
+			return model.Shop{}, err
+		}
+		for _, order := range delayed {
+			n, err := buildDelay(order)
+			if err != nil {
+				return model.Shop{}, err
+			}
+			if err := notification.SaveNotification(ctx, tx, n); err != nil {
+				return model.Shop{}, err
+			}
+		}
+	}
+
+	// Query 4: Nyalakan libur
+	var shop model.Shop
+	query := `
+		UPDATE shop SET
+			vacation_active = TRUE,
+			vacation_message = $2,
+			vacation_starts_at = COALESCE($3, CURRENT_TIMESTAMP),
+			vacation_ends_at = $4,
+			vacation_pending_orders = $5,
+			vacation_blocked_at = NULL,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING *`
+	if err := tx.GetContext(ctx, &shop, query, shopID, settings.Message, settings.StartsAt, settings.EndsAt, settings.PendingOrders); err != nil {
+		return model.Shop{}, err
+	}
+
+	if notice != nil {
+		if err := notification.SaveNotification(ctx, tx, *notice); err != nil {
+			return model.Shop{}, err
+		}
+	}
+
+	return shop, tx.Commit()
+}
+
+func (r *repository) MarkVacationBlocked(ctx context.Context, shopID uuid.UUID, notice model.Notification) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Tandai tertahan, hanya sekali per jadwal
+	query := `
+		UPDATE shop SET vacation_blocked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND NOT vacation_active AND vacation_blocked_at IS NULL`
+	result, err := tx.ExecContext(ctx, query, shopID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return err
+	}
+
+	// Query 2: Beri tahu seller bahwa libur belum bisa dimulai
+	if err := notification.SaveNotification(ctx, tx, notice); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *repository) ClearVacation(ctx context.Context, shopID uuid.UUID) (model.Shop, error) {
+	var shop model.Shop
+	query := "UPDATE shop SET" + clearVacationColumns + " WHERE id = $1 AND deleted_at IS NULL RETURNING *"
+	err := r.db.GetContext(ctx, &shop, query, shopID)
+	return shop, err
+}
+
+func (r *repository) FindDueVacationStarts(ctx context.Context, now time.Time, limit int) ([]model.Shop, error) {
+	shops := []model.Shop{}
+	// Jadwal yang belum pernah tertahan didahulukan agar tidak kalah antre oleh shop yang masih menunggu order dikirim
+	query := `
+		SELECT * FROM shop
+		WHERE NOT vacation_active AND vacation_starts_at <= $1 AND deleted_at IS NULL
+		ORDER BY vacation_blocked_at NULLS FIRST, vacation_starts_at
+		LIMIT $2`
+	err := r.db.SelectContext(ctx, &shops, query, now, limit)
+	return shops, err
+}
+
+func (r *repository) TransactionEndDueVacations(ctx context.Context, now time.Time, build func(EndedVacation) (model.Notification, error)) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Matikan libur yang sudah lewat tanggal kembalinya
+	var ended []EndedVacation
+	query := `
+		WITH due AS (
+			SELECT id, vacation_active AS was_active FROM shop
+			WHERE vacation_ends_at <= $1 AND deleted_at IS NULL
+			FOR UPDATE
+		)
+		UPDATE shop s SET` + clearVacationColumns + `
+		FROM due
+		WHERE s.id = due.id
+		RETURNING s.*, due.was_active`
+	if err := tx.SelectContext(ctx, &ended, query, now); err != nil {
+		return 0, err
+	}
+
+	// Query 2: Beri tahu seller
+	for _, shop := range ended {
+		n, err := build(shop)
+		if err != nil {
+			return 0, err
+		}
+		if err := notification.SaveNotification(ctx, tx, n); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(ended), tx.Commit()
+}
+
+// --- Pengiriman ---
+
+func (r *repository) FindRegion(ctx context.Context, districtID string) (ShippingOrigin, error) {
+	var origin ShippingOrigin
+	query := `
+		SELECT d.id AS district_id, d.name AS district_name, r.id AS regency_id, r.name AS regency_name,
+			p.id AS province_id, p.name AS province_name
+		FROM districts d
+		JOIN regencies r ON r.id = d.regency_id
+		JOIN provinces p ON p.id = r.province_id
+		WHERE d.id = $1`
+	err := r.db.GetContext(ctx, &origin, query, districtID)
+	return origin, err
+}
+
+func (r *repository) FindShopCouriers(ctx context.Context, shopID uuid.UUID) ([]model.ShopCourier, error) {
+	couriers := []model.ShopCourier{}
+	query := "SELECT * FROM shop_couriers WHERE shop_id = $1 ORDER BY courier, service"
+	err := r.db.SelectContext(ctx, &couriers, query, shopID)
+	return couriers, err
+}
+
+func (r *repository) TransactionSaveShipping(ctx context.Context, shop model.Shop, couriers []model.ShopCourier) (model.Shop, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return model.Shop{}, err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Alamat asal, lama proses dan opsi ambil sendiri
+	var saved model.Shop
+	query := `
+		UPDATE shop SET
+			origin_province_id = $2,
+			origin_regency_id = $3,
+			origin_district_id = $4,
+			origin_street = $5,
+			origin_postal_code = $6,
+			handling_days = $7,
+			self_pickup = $8,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING *`
+	err = tx.GetContext(ctx, &saved, query, shop.ID, shop.OriginProvinceID, shop.OriginRegencyID, shop.OriginDistrictID,
+		shop.OriginStreet, shop.OriginPostalCode, shop.HandlingDays, shop.SelfPickup)
+	if err != nil {
+		return model.Shop{}, err
+	}
+
+	// Query 2: Ganti seluruh layanan kurir
+	if _, err := tx.ExecContext(ctx, "DELETE FROM shop_couriers WHERE shop_id = $1", shop.ID); err != nil {
+		return model.Shop{}, err
+	}
+	insertQuery := "INSERT INTO shop_couriers (shop_id, courier, service) VALUES ($1, $2, $3)"
+	for _, courier := range couriers {
+		if _, err := tx.ExecContext(ctx, insertQuery, shop.ID, courier.Courier, courier.Service); err != nil {
+			return model.Shop{}, err
+		}
+	}
+
+	return saved, tx.Commit()
+}
+
+// --- Statistik ---
+
+func (r *repository) RefreshShopStats(ctx context.Context, since time.Time) error {
+	// Listing & rating dihitung dari seluruh riwayat. Offer yang masih menunggu seller atau ditarik
+	// pembeli sebelum dijawab tidak dihitung ke response rate. Waktu kirim diukur dari log status
+	// order paid -> shipped per order yang memuat produk shop tersebut.
+	query := `
+		INSERT INTO shop_stats (
+			shop_id, active_listings, sold_listings, rating_count, rating_average,
+			rating_1, rating_2, rating_3, rating_4, rating_5,
+			response_rate, median_ship_hours, refreshed_at
+		)
+		SELECT
+			s.id, COALESCE(l.active, 0), COALESCE(l.sold, 0), COALESCE(rv.total, 0), rv.average,
+			COALESCE(rv.r1, 0), COALESCE(rv.r2, 0), COALESCE(rv.r3, 0), COALESCE(rv.r4, 0), COALESCE(rv.r5, 0),
+			o.rate, sh.median, NOW()
+		FROM shop s
+		LEFT JOIN (
+			SELECT shop_id,
+				COUNT(*) FILTER (WHERE status = 'published' AND deleted_at IS NULL) AS active,
+				COUNT(*) FILTER (WHERE status = 'sold') AS sold
+			FROM products
+			GROUP BY shop_id
+		) l ON l.shop_id = s.id
+		LEFT JOIN (
+			SELECT p.shop_id, COUNT(*) AS total, ROUND(AVG(r.rating), 2) AS average,
+				COUNT(*) FILTER (WHERE r.rating = 1) AS r1,
+				COUNT(*) FILTER (WHERE r.rating = 2) AS r2,
+				COUNT(*) FILTER (WHERE r.rating = 3) AS r3,
+				COUNT(*) FILTER (WHERE r.rating = 4) AS r4,
+				COUNT(*) FILTER (WHERE r.rating = 5) AS r5
+			FROM reviews r
+			JOIN products p ON p.id = r.product_id
+			GROUP BY p.shop_id
+		) rv ON rv.shop_id = s.id
+		LEFT JOIN (
+			SELECT shop_id, ROUND(AVG(responded::int), 4) AS rate
+			FROM (
+				SELECT o.shop_id, o.status, EXISTS (
+					SELECT 1 FROM offer_events e
+					WHERE e.offer_id = o.id AND e.actor_id <> o.buyer_id
+					  AND e.action IN ('counter', 'accept', 'decline')
+				) AS responded
+				FROM offers o
+				WHERE o.created_at >= $1
+			) x
+			WHERE responded OR status NOT IN ('pending', 'withdrawn')
+			GROUP BY shop_id
+		) o ON o.shop_id = s.id
+		LEFT JOIN (
+			SELECT shop_id, ROUND(percentile_cont(0.5) WITHIN GROUP (ORDER BY hours)::numeric, 2) AS median
+			FROM (
+				SELECT DISTINCT p.shop_id, shipped.order_id,
+					EXTRACT(EPOCH FROM shipped.at - paid.at) / 3600 AS hours
+				FROM (
+					SELECT order_id, MIN(created_at) AS at FROM order_status_logs
+					WHERE new_status = $3 AND created_at >= $1
+					GROUP BY order_id
+				) shipped
+				JOIN (
+					SELECT order_id, MIN(created_at) AS at FROM order_status_logs
+					WHERE new_status = $2
+					GROUP BY order_id
+				) paid ON paid.order_id = shipped.order_id
+				JOIN order_items oi ON oi.order_id = shipped.order_id
+				JOIN products p ON p.id = oi.product_id
+			) t
+			GROUP BY shop_id
+		) sh ON sh.shop_id = s.id
+		WHERE s.deleted_at IS NULL
+		ON CONFLICT (shop_id) DO UPDATE SET
+			active_listings = EXCLUDED.active_listings,
+			sold_listings = EXCLUDED.sold_listings,
+			rating_count = EXCLUDED.rating_count,
+			rating_average = EXCLUDED.rating_average,
+			rating_1 = EXCLUDED.rating_1,
+			rating_2 = EXCLUDED.rating_2,
+			rating_3 = EXCLUDED.rating_3,
+			rating_4 = EXCLUDED.rating_4,
+			rating_5 = EXCLUDED.rating_5,
+			response_rate = EXCLUDED.response_rate,
+			median_ship_hours = EXCLUDED.median_ship_hours,
+			refreshed_at = EXCLUDED.refreshed_at`
+	_, err := r.db.ExecContext(ctx, query, since, model.OrderStatusPaid, model.OrderStatusShipped)
+	return err
+}
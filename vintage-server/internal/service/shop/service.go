@@ -1 +1,936 @@
 package shop
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/audit"
+	"vintage-server/internal/service/shipment"
+	"vintage-server/internal/service/staff"
+	"vintage-server/pkg/apperror"
+	"vintage-server/pkg/slug"
+	"vintage-server/pkg/storage"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxShopSlugBase menyisakan ruang untuk suffix angka di kolom slug (VARCHAR(80))
+	maxShopSlugBase = 64
+
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+
+	// invitationTTL adalah lama undangan staff bisa diterima
+	invitationTTL = 7 * 24 * time.Hour
+
+	// vacationBatchSize adalah jumlah jadwal libur yang dimulai per putaran job
+	vacationBatchSize = 100
+
+	// statsWindow adalah jendela aktivitas untuk response rate dan waktu kirim di shop_stats
+	statsWindow = 90 * 24 * time.Hour
+)
+
+// maxImageSizes adalah batas ukuran file per jenis gambar shop.
+var maxImageSizes = map[ImageKind]int64{
+	ImageLogo:   2 << 20,
+	ImageBanner: 5 << 20,
+}
+
+// imageExtensions adalah content type gambar yang diterima beserta ekstensi file-nya.
+var imageExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/webp": ".webp",
+}
+
+// statusTransition adalah perpindahan status untuk satu Action admin.
+type statusTransition struct {
+	from           string
+	to             string
+	reasonRequired bool
+}
+
+var statusTransitions = map[Action]statusTransition{
+	ActionApprove:   {from: model.ShopStatusPending, to: model.ShopStatusApproved},
+	ActionActivate:  {from: model.ShopStatusApproved, to: model.ShopStatusActive},
+	ActionSuspend:   {from: model.ShopStatusActive, to: model.ShopStatusSuspended, reasonRequired: true},
+	ActionReinstate: {from: model.ShopStatusSuspended, to: model.ShopStatusActive, reasonRequired: true},
+}
+
+// statusNotices adalah judul notifikasi seller untuk setiap Action.
+var statusNotices = map[Action]string{
+	ActionApprove:   "Your shop has been approved",
+	ActionActivate:  "Your shop is now live",
+	ActionSuspend:   "Your shop has been suspended",
+	ActionReinstate: "Your shop has been reinstated",
+}
+
+// service adalah struct yang akan mengimplementasikan interface Service dari domain.go
+type service struct {
+	repo    Repository
+	storage storage.Storage
+}
+
+// NewService adalah constructor untuk service
+func NewService(repo Repository, storage storage.Storage) Service {
+	return &service{repo: repo, storage: storage}
+}
+
+// --- Seller ---
+
+func (s *service) CreateShop(ctx context.Context, accountID uuid.UUID, req CreateShopRequest) (model.Shop, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return model.Shop{}, apperror.New(apperror.ErrCodeValidation, "shop name is required")
+	}
+	shopSlug, err := s.uniqueShopSlug(ctx, name, uuid.Nil)
+	if err != nil {
+		return model.Shop{}, err
+	}
+
+	// Shop baru belum tayang sampai disetujui dan diaktifkan admin
+	created, err := s.repo.TransactionCreateShop(ctx, model.Shop{
+		AccountID:      accountID,
+		Name:           name,
+		Slug:           shopSlug,
+		Summary:        optionalText(req.Summary),
+		Description:    optionalText(req.Description),
+		ShippingPolicy: optionalText(req.ShippingPolicy),
+		ReturnPolicy:   optionalText(req.ReturnPolicy),
+		PaymentPolicy:  optionalText(req.PaymentPolicy),
+		Status:         model.ShopStatusPending,
+		Active:         false,
+	})
+	if err != nil {
+		return model.Shop{}, shopWriteError(err)
+	}
+	return created, nil
+}
+
+func (s *service) GetMyShop(ctx context.Context, accountID uuid.UUID) (MyShop, error) {
+	member, err := s.findSellerMember(ctx, accountID, "")
+	if err != nil {
+		return MyShop{}, err
+	}
+	shop, err := s.findShop(ctx, member.ShopID)
+	if err != nil {
+		return MyShop{}, err
+	}
+	return MyShop{Shop: shop, Role: member.Role, Permissions: staff.Permissions(member.Role)}, nil
+}
+
+func (s *service) UpdateShop(ctx context.Context, accountID uuid.UUID, req UpdateShopRequest) (model.Shop, error) {
+	shop, err := s.findSellerShop(ctx, accountID, staff.PermissionSettings)
+	if err != nil {
+		return model.Shop{}, err
+	}
+	previousSlug := shop.Slug
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return model.Shop{}, apperror.New(apperror.ErrCodeValidation, "shop name is required")
+		}
+		if name != shop.Name {
+			shop.Name = name
+			if shop.Slug, err = s.uniqueShopSlug(ctx, name, shop.ID); err != nil {
+				return model.Shop{}, err
+			}
+		}
+	}
+	if req.Summary != nil {
+		shop.Summary = optionalText(req.Summary)
+	}
+	if req.Description != nil {
+		shop.Description = optionalText(req.Description)
+	}
+	if req.ShippingPolicy != nil {
+		shop.ShippingPolicy = optionalText(req.ShippingPolicy)
+	}
+	if req.ReturnPolicy != nil {
+		shop.ReturnPolicy = optionalText(req.ReturnPolicy)
+	}
+	if req.PaymentPolicy != nil {
+		shop.PaymentPolicy = optionalText(req.PaymentPolicy)
+	}
+
+	updated, err := s.repo.TransactionUpdateShop(ctx, shop, previousSlug)
+	if err != nil {
+		return model.Shop{}, shopWriteError(err)
+	}
+	return updated, nil
+}
+
+func (s *service) UploadShopImage(ctx context.Context, accountID uuid.UUID, kind ImageKind, file *multipart.FileHeader) (model.Shop, error) {
+	maxSize, ok := maxImageSizes[kind]
+	if !ok {
+		return model.Shop{}, apperror.New(apperror.ErrCodeValidation, "unknown image type")
+	}
+	if file.Size > maxSize {
+		return model.Shop{}, apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("%s must be at most %d MB", kind, maxSize>>20))
+	}
+
+	shop, err := s.findSellerShop(ctx, accountID, staff.PermissionSettings)
+	if err != nil {
+		return model.Shop{}, err
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return model.Shop{}, apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("failed to read %s file", kind))
+	}
+	defer src.Close()
+
+	// Tentukan tipe file dari isinya, bukan dari nama file / header yang dikirim client
+	head := make([]byte, 512)
+	n, _ := src.Read(head)
+	ext, ok := imageExtensions[http.DetectContentType(head[:n])]
+	if !ok {
+		return model.Shop{}, apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("%s must be a PNG, JPEG or WebP image", kind))
+	}
+	if _, err := src.Seek(0, 0); err != nil {
+		return model.Shop{}, apperror.New(apperror.ErrCodeInternal, fmt.Sprintf("failed to read %s file", kind))
+	}
+
+	key := path.Join("shops", shop.ID.String(), fmt.Sprintf("%s-%s%s", kind, uuid.NewString(), ext))
+	url, err := s.storage.Save(ctx, key, src)
+	if err != nil {
+		log.Printf("Error storing shop %s: %v", kind, err)
+		return model.Shop{}, apperror.New(apperror.ErrCodeInternal, fmt.Sprintf("failed to store %s", kind))
+	}
+
+	if kind == ImageLogo {
+		shop.LogoURL = &url
+	} else {
+		shop.BannerURL = &url
+	}
+	updated, err := s.repo.TransactionUpdateShop(ctx, shop, shop.Slug)
+	if err != nil {
+		s.storage.Delete(ctx, key)
+		return model.Shop{}, shopWriteError(err)
+	}
+	return updated, nil
+}
+
+// --- Admin ---
+
+func (s *service) GetShops(ctx context.Context, filter AdminShopFilter) (ShopPage, error) {
+	if filter.Status != "" && !isShopStatus(filter.Status) {
+		return ShopPage{}, apperror.New(apperror.ErrCodeValidation, "unknown shop status")
+	}
+	filter.Query = strings.TrimSpace(filter.Query)
+	filter.Page, filter.Limit = normalizePage(filter.Page, filter.Limit)
+
+	shops, total, err := s.repo.FindShops(ctx, filter)
+	if err != nil {
+		log.Printf("Error finding shops: %v", err)
+		return ShopPage{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return ShopPage{Items: shops, Total: total, Page: filter.Page, Limit: filter.Limit}, nil
+}
+
+func (s *service) GetShop(ctx context.Context, shopID uuid.UUID) (ShopDetail, error) {
+	shop, err := s.findShop(ctx, shopID)
+	if err != nil {
+		return ShopDetail{}, err
+	}
+
+	logs, err := s.repo.FindShopStatusLogs(ctx, shopID)
+	if err != nil {
+		log.Printf("Error finding shop status logs: %v", err)
+		return ShopDetail{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return ShopDetail{Shop: shop, StatusLogs: logs}, nil
+}
+
+func (s *service) ChangeShopStatus(ctx context.Context, actor audit.Actor, shopID uuid.UUID, action Action, req ShopDecisionRequest) (model.Shop, error) {
+	transition, ok := statusTransitions[action]
+	if !ok {
+		return model.Shop{}, apperror.New(apperror.ErrCodeValidation, "unknown shop action")
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if transition.reasonRequired && reason == "" {
+		return model.Shop{}, apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("a reason is required to %s a shop", action))
+	}
+
+	shop, err := s.findShop(ctx, shopID)
+	if err != nil {
+		return model.Shop{}, err
+	}
+	if shop.Status != transition.from {
+		return model.Shop{}, apperror.New(apperror.ErrCodeConflict, fmt.Sprintf("cannot %s a shop that is %s", action, shop.Status))
+	}
+
+	description := fmt.Sprintf("%s shop %s (%q)", action, shopID, shop.Name)
+	if reason != "" {
+		description += ": " + reason
+	}
+	notice, err := buildStatusNotification(shop, action, reason)
+	if err != nil {
+		log.Printf("Error building shop notification: %v", err)
+		return model.Shop{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	updated, err := s.repo.TransactionChangeShopStatus(ctx, StatusChange{
+		ShopID:  shopID,
+		From:    transition.from,
+		To:      transition.to,
+		Reason:  optionalText(&reason),
+		ActorID: actor.AdminID,
+	}, actor.Entry("shop."+string(action), description), notice)
+	if err != nil {
+		return model.Shop{}, shopWriteError(err)
+	}
+	return updated, nil
+}
+
+// --- Staff ---
+
+func (s *service) GetMembers(ctx context.Context, accountID uuid.UUID) ([]ShopMemberDetail, error) {
+	member, err := s.findSellerMember(ctx, accountID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.FindMembers(ctx, member.ShopID)
+	if err != nil {
+		log.Printf("Error finding shop members: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	for i := range members {
+		members[i].Permissions = staff.Permissions(members[i].Role)
+	}
+	return members, nil
+}
+
+func (s *service) UpdateMemberRole(ctx context.Context, accountID, memberID uuid.UUID, req UpdateMemberRequest) (ShopMemberDetail, error) {
+	member, err := s.findSellerMember(ctx, accountID, staff.PermissionStaff)
+	if err != nil {
+		return ShopMemberDetail{}, err
+	}
+	if memberID == accountID {
+		return ShopMemberDetail{}, apperror.New(apperror.ErrCodeValidation, "you cannot change your own role")
+	}
+
+	if err := s.repo.UpdateMemberRole(ctx, member.ShopID, memberID, req.Role); err != nil {
+		return ShopMemberDetail{}, memberWriteError(err)
+	}
+	updated, err := s.repo.FindMember(ctx, member.ShopID, memberID)
+	if err != nil {
+		return ShopMemberDetail{}, memberWriteError(err)
+	}
+	updated.Permissions = staff.Permissions(updated.Role)
+	return updated, nil
+}
+
+func (s *service) RemoveMember(ctx context.Context, accountID, memberID uuid.UUID) error {
+	// Anggota selalu boleh keluar sendiri; mengeluarkan anggota lain butuh akses staff
+	permission := staff.PermissionStaff
+	if memberID == accountID {
+		permission = ""
+	}
+	member, err := s.findSellerMember(ctx, accountID, permission)
+	if err != nil {
+		return err
+	}
+	if memberID == accountID && member.Role == model.ShopRoleOwner {
+		return apperror.New(apperror.ErrCodeConflict, "the shop owner cannot leave the shop")
+	}
+
+	if err := s.repo.DeleteMember(ctx, member.ShopID, memberID); err != nil {
+		return memberWriteError(err)
+	}
+	return nil
+}
+
+func (s *service) InviteMember(ctx context.Context, accountID uuid.UUID, req InviteMemberRequest) (model.ShopInvitation, error) {
+	member, err := s.findSellerMember(ctx, accountID, staff.PermissionStaff)
+	if err != nil {
+		return model.ShopInvitation{}, err
+	}
+	shop, err := s.findShop(ctx, member.ShopID)
+	if err != nil {
+		return model.ShopInvitation{}, err
+	}
+
+	invitation := model.ShopInvitation{
+		ShopID:    shop.ID,
+		Email:     strings.ToLower(strings.TrimSpace(req.Email)),
+		Role:      req.Role,
+		InvitedBy: &accountID,
+		ExpiresAt: time.Now().Add(invitationTTL),
+	}
+	build := func(inviteeID uuid.UUID) (model.Notification, error) {
+		return buildInvitationNotification(inviteeID, shop, invitation)
+	}
+	saved, err := s.repo.TransactionSaveInvitation(ctx, invitation, build)
+	if err != nil {
+		if errors.Is(err, ErrAlreadyMember) {
+			return model.ShopInvitation{}, apperror.New(apperror.ErrCodeConflict, "this account is already a member of your shop")
+		}
+		log.Printf("Error saving shop invitation: %v", err)
+		return model.ShopInvitation{}, apperror.New(apperror.ErrCodeInternal, "failed to send invitation")
+	}
+	return saved, nil
+}
+
+func (s *service) GetInvitations(ctx context.Context, accountID uuid.UUID) ([]model.ShopInvitation, error) {
+	member, err := s.findSellerMember(ctx, accountID, staff.PermissionStaff)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.repo.FindOpenInvitations(ctx, member.ShopID)
+	if err != nil {
+		log.Printf("Error finding shop invitations: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return invitations, nil
+}
+
+func (s *service) RevokeInvitation(ctx context.Context, accountID, invitationID uuid.UUID) error {
+	member, err := s.findSellerMember(ctx, accountID, staff.PermissionStaff)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.RevokeInvitation(ctx, member.ShopID, invitationID); err != nil {
+		return invitationWriteError(err)
+	}
+	return nil
+}
+
+func (s *service) GetMyInvitations(ctx context.Context, accountID uuid.UUID) ([]InvitationDetail, error) {
+	email, err := s.accountEmail(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.repo.FindInvitationsForEmail(ctx, email, time.Now())
+	if err != nil {
+		log.Printf("Error finding invitations for account: %v", err)
+		return nil, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return invitations, nil
+}
+
+func (s *service) AcceptInvitation(ctx context.Context, accountID, invitationID uuid.UUID) (MyShop, error) {
+	email, err := s.accountEmail(ctx, accountID)
+	if err != nil {
+		return MyShop{}, err
+	}
+
+	build := func(shop model.Shop) (model.Notification, error) {
+		return buildShopNotification(shop, "A new member joined your shop",
+			fmt.Sprintf("%s accepted the invitation to join %s.", email, shop.Name))
+	}
+	member, err := s.repo.TransactionAcceptInvitation(ctx, accountID, email, invitationID, time.Now(), build)
+	if err != nil {
+		if errors.Is(err, ErrAlreadyMember) {
+			return MyShop{}, apperror.New(apperror.ErrCodeConflict, "you already belong to a shop; leave it before joining another")
+		}
+		return MyShop{}, invitationWriteError(err)
+	}
+
+	shop, err := s.findShop(ctx, member.ShopID)
+	if err != nil {
+		return MyShop{}, err
+	}
+	return MyShop{Shop: shop, Role: member.Role, Permissions: staff.Permissions(member.Role)}, nil
+}
+
+func (s *service) DeclineInvitation(ctx context.Context, accountID, invitationID uuid.UUID) error {
+	email, err := s.accountEmail(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeclineInvitation(ctx, email, invitationID); err != nil {
+		return invitationWriteError(err)
+	}
+	return nil
+}
+
+// --- Libur ---
+
+func (s *service) SetVacation(ctx context.Context, accountID uuid.UUID, req VacationRequest) (model.Shop, error) {
+	now := time.Now()
+	if req.EndsAt != nil && !req.EndsAt.After(now) {
+		return model.Shop{}, apperror.New(apperror.ErrCodeValidation, "ends_at must be in the future")
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return model.Shop{}, apperror.New(apperror.ErrCodeValidation, "ends_at must be after starts_at")
+	}
+	settings := VacationSettings{
+		Message:       optionalText(req.Message),
+		StartsAt:      req.StartsAt,
+		EndsAt:        req.EndsAt,
+		PendingOrders: req.PendingOrders,
+	}
+	if settings.PendingOrders == "" {
+		settings.PendingOrders = model.VacationPendingOrdersBlock
+	}
+	// Batas kirim baru hanya bisa dihitung jika tanggal kembali diketahui
+	if settings.PendingOrders == model.VacationPendingOrdersExtend && settings.EndsAt == nil {
+		return model.Shop{}, apperror.New(apperror.ErrCodeValidation, "ends_at is required to extend pending orders")
+	}
+
+	shop, err := s.findSellerShop(ctx, accountID, staff.PermissionSettings)
+	if err != nil {
+		return model.Shop{}, err
+	}
+
+	if settings.StartsAt != nil && settings.StartsAt.After(now) {
+		scheduled, err := s.repo.SaveVacationSchedule(ctx, shop.ID, settings)
+		if err != nil {
+			return model.Shop{}, shopWriteError(err)
+		}
+		return scheduled, nil
+	}
+
+	settings.StartsAt = nil
+	started, err := s.repo.TransactionStartVacation(ctx, shop.ID, settings, false, buildDelayNotification, nil)
+	if err != nil {
+		return model.Shop{}, vacationError(err)
+	}
+	return started, nil
+}
+
+func (s *service) EndVacation(ctx context.Context, accountID uuid.UUID) (model.Shop, error) {
+	shop, err := s.findSellerShop(ctx, accountID, staff.PermissionSettings)
+	if err != nil {
+		return model.Shop{}, err
+	}
+	if !shop.VacationActive && shop.VacationStartsAt == nil {
+		return shop, nil
+	}
+
+	updated, err := s.repo.ClearVacation(ctx, shop.ID)
+	if err != nil {
+		return model.Shop{}, shopWriteError(err)
+	}
+	return updated, nil
+}
+
+func (s *service) RunVacationSchedule(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	// Akhiri dulu libur yang sudah selesai, agar jadwal yang sudah lewat seluruhnya tidak sempat dimulai
+	ended, err := s.repo.TransactionEndDueVacations(ctx, now, buildVacationEndedNotification)
+	if err != nil {
+		return 0, fmt.Errorf("end due vacations: %w", err)
+	}
+
+	due, err := s.repo.FindDueVacationStarts(ctx, now, vacationBatchSize)
+	if err != nil {
+		return ended, fmt.Errorf("find due vacation starts: %w", err)
+	}
+
+	started := 0
+	for _, shop := range due {
+		settings := VacationSettings{
+			Message:       shop.VacationMessage,
+			StartsAt:      shop.VacationStartsAt,
+			EndsAt:        shop.VacationEndsAt,
+			PendingOrders: shop.VacationPendingOrders,
+		}
+		notice, err := buildShopNotification(shop, "Your shop is now on vacation",
+			fmt.Sprintf("%s is on vacation. Listings stay visible but cannot be bought until you return.", shop.Name))
+		if err != nil {
+			return ended + started, fmt.Errorf("build vacation notification: %w", err)
+		}
+
+		_, err = s.repo.TransactionStartVacation(ctx, shop.ID, settings, true, buildDelayNotification, &notice)
+		switch {
+		case err == nil:
+			started++
+		case errors.Is(err, ErrVacationChanged):
+			continue
+		case errors.Is(err, ErrOrdersAwaitingShipment):
+			// Jadwal tetap disimpan dan dicoba lagi di putaran berikutnya setelah order dikirim
+			blocked, err := buildShopNotification(shop, "Your vacation has not started",
+				fmt.Sprintf("%s still has orders awaiting shipment. Ship them to start your vacation, or change it to extend their shipping deadline.", shop.Name))
+			if err != nil {
+				return ended + started, fmt.Errorf("build vacation notification: %w", err)
+			}
+			if err := s.repo.MarkVacationBlocked(ctx, shop.ID, blocked); err != nil {
+				return ended + started, fmt.Errorf("mark vacation blocked for shop %s: %w", shop.ID, err)
+			}
+		default:
+			return ended + started, fmt.Errorf("start vacation for shop %s: %w", shop.ID, err)
+		}
+	}
+	return ended + started, nil
+}
+
+// StartVacationJob menjalankan RunVacationSchedule sekali saat start, lalu berkala sampai ctx dibatalkan.
+func StartVacationJob(ctx context.Context, svc Service, interval time.Duration) {
+	run := func() {
+		if _, err := svc.RunVacationSchedule(ctx); err != nil {
+			log.Printf("Error running shop vacation schedule: %v", err)
+		}
+	}
+	run()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
+
+// --- Pengiriman ---
+
+func (s *service) GetShipping(ctx context.Context, accountID uuid.UUID) (ShippingSettings, error) {
+	shop, err := s.findSellerShop(ctx, accountID, "")
+	if err != nil {
+		return ShippingSettings{}, err
+	}
+	return s.shippingSettings(ctx, shop)
+}
+
+func (s *service) UpdateShipping(ctx context.Context, accountID uuid.UUID, req ShippingRequest) (ShippingSettings, error) {
+	street, postalCode := strings.TrimSpace(req.Street), strings.TrimSpace(req.PostalCode)
+	if street == "" || postalCode == "" {
+		return ShippingSettings{}, apperror.New(apperror.ErrCodeValidation, "origin street and postal code are required")
+	}
+	var couriers []model.ShopCourier
+	enabled := make(map[string]bool, len(req.Couriers))
+	for _, c := range req.Couriers {
+		courier, service := strings.ToLower(strings.TrimSpace(c.Courier)), strings.ToUpper(strings.TrimSpace(c.Service))
+		if !shipment.IsSupported(courier, service) {
+			return ShippingSettings{}, apperror.New(apperror.ErrCodeValidation, fmt.Sprintf("unsupported courier service %s %s", c.Courier, c.Service))
+		}
+		if key := courier + "/" + service; !enabled[key] {
+			enabled[key] = true
+			couriers = append(couriers, model.ShopCourier{Courier: courier, Service: service})
+		}
+	}
+	if len(couriers) == 0 && !req.SelfPickup {
+		return ShippingSettings{}, apperror.New(apperror.ErrCodeValidation, "enable at least one courier service or self-pickup")
+	}
+
+	shop, err := s.findSellerShop(ctx, accountID, staff.PermissionSettings)
+	if err != nil {
+		return ShippingSettings{}, err
+	}
+	region, err := s.repo.FindRegion(ctx, req.DistrictID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ShippingSettings{}, apperror.New(apperror.ErrCodeValidation, "district not found")
+		}
+		log.Printf("Error finding district: %v", err)
+		return ShippingSettings{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+
+	// Regency dan province selalu diturunkan dari district supaya tidak saling bertentangan
+	shop.OriginDistrictID = &region.DistrictID
+	shop.OriginRegencyID = &region.RegencyID
+	shop.OriginProvinceID = &region.ProvinceID
+	shop.OriginStreet = &street
+	shop.OriginPostalCode = &postalCode
+	shop.HandlingDays = req.HandlingDays
+	shop.SelfPickup = req.SelfPickup
+
+	updated, err := s.repo.TransactionSaveShipping(ctx, shop, couriers)
+	if err != nil {
+		return ShippingSettings{}, shopWriteError(err)
+	}
+	return s.shippingSettings(ctx, updated)
+}
+
+// --- Statistik ---
+
+func (s *service) RefreshShopStats(ctx context.Context) error {
+	if err := s.repo.RefreshShopStats(ctx, time.Now().Add(-statsWindow)); err != nil {
+		return fmt.Errorf("refresh shop stats: %w", err)
+	}
+	return nil
+}
+
+// StartStatsJob menjalankan RefreshShopStats sekali saat start, lalu berkala sampai ctx dibatalkan.
+func StartStatsJob(ctx context.Context, svc Service, interval time.Duration) {
+	if err := svc.RefreshShopStats(ctx); err != nil {
+		log.Printf("Error refreshing shop stats: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := svc.RefreshShopStats(ctx); err != nil {
+				log.Printf("Error refreshing shop stats: %v", err)
+			}
+		}
+	}
+}
+
+// --- Helper ---
+
+// findSellerMember mengambil keanggotaan shop akun, 404 jika akun belum punya shop dan 403 jika
+// role-nya tidak memiliki permission (permission kosong berarti semua anggota boleh).
+func (s *service) findSellerMember(ctx context.Context, accountID uuid.UUID, permission staff.Permission) (model.ShopMember, error) {
+	member, err := s.repo.FindShopMembership(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ShopMember{}, apperror.New(apperror.ErrCodeNotFound, "you do not have a shop yet")
+		}
+		log.Printf("Error finding shop membership: %v", err)
+		return model.ShopMember{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if permission != "" && !staff.Can(member.Role, permission) {
+		return model.ShopMember{}, apperror.New(apperror.ErrCodeForbidden, fmt.Sprintf("your shop role does not have %s access", permission))
+	}
+	return member, nil
+}
+
+// findSellerShop mengambil shop tempat akun menjadi anggota dengan permission tersebut.
+func (s *service) findSellerShop(ctx context.Context, accountID uuid.UUID, permission staff.Permission) (model.Shop, error) {
+	member, err := s.findSellerMember(ctx, accountID, permission)
+	if err != nil {
+		return model.Shop{}, err
+	}
+	return s.findShop(ctx, member.ShopID)
+}
+
+func (s *service) findShop(ctx context.Context, shopID uuid.UUID) (model.Shop, error) {
+	shop, err := s.repo.FindShopByID(ctx, shopID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Shop{}, apperror.New(apperror.ErrCodeNotFound, "shop not found")
+		}
+		log.Printf("Error finding shop: %v", err)
+		return model.Shop{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return shop, nil
+}
+
+// uniqueShopSlug membuat slug dari nama shop, diberi suffix angka jika sudah dipakai shop lain
+// (termasuk slug lama yang masih dipakai untuk redirect). shopID adalah shop itu sendiri (uuid.Nil saat create).
+func (s *service) uniqueShopSlug(ctx context.Context, name string, shopID uuid.UUID) (string, error) {
+	base := slug.Truncate(slug.Make(name), maxShopSlugBase)
+	if base == "" {
+		base = "shop"
+	}
+
+	taken, err := s.repo.FindShopSlugs(ctx, base, shopID)
+	if err != nil {
+		log.Printf("Error finding shop slugs: %v", err)
+		return "", apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return slug.Unique(base, taken), nil
+}
+
+func shopWriteError(err error) error {
+	switch {
+	case errors.Is(err, ErrShopExists):
+		return apperror.New(apperror.ErrCodeConflict, "you already belong to a shop")
+	case errors.Is(err, ErrDuplicateName):
+		return apperror.New(apperror.ErrCodeConflict, "shop name is already taken")
+	case errors.Is(err, ErrShopChanged):
+		return apperror.New(apperror.ErrCodeConflict, "shop status has changed, please reload")
+	case errors.Is(err, sql.ErrNoRows):
+		return apperror.New(apperror.ErrCodeNotFound, "shop not found")
+	}
+	log.Printf("Error saving shop: %v", err)
+	return apperror.New(apperror.ErrCodeInternal, "failed to save shop")
+}
+
+// accountEmail mengambil email akun yang login; undangan staff dicocokkan dengan email ini.
+func (s *service) accountEmail(ctx context.Context, accountID uuid.UUID) (string, error) {
+	email, err := s.repo.FindAccountEmail(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", apperror.New(apperror.ErrCodeNotFound, "account not found")
+		}
+		log.Printf("Error finding account email: %v", err)
+		return "", apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return email, nil
+}
+
+// shippingSettings menyusun pengaturan pengiriman shop beserta nama wilayah asalnya.
+func (s *service) shippingSettings(ctx context.Context, shop model.Shop) (ShippingSettings, error) {
+	settings := ShippingSettings{HandlingDays: shop.HandlingDays, SelfPickup: shop.SelfPickup}
+	if shop.OriginDistrictID != nil {
+		origin, err := s.repo.FindRegion(ctx, *shop.OriginDistrictID)
+		if err != nil {
+			log.Printf("Error finding shop origin region: %v", err)
+			return ShippingSettings{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+		}
+		origin.Street, origin.PostalCode = *shop.OriginStreet, *shop.OriginPostalCode
+		settings.Origin = &origin
+	}
+
+	couriers, err := s.repo.FindShopCouriers(ctx, shop.ID)
+	if err != nil {
+		log.Printf("Error finding shop couriers: %v", err)
+		return ShippingSettings{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	settings.Couriers = couriers
+	return settings, nil
+}
+
+func memberWriteError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperror.New(apperror.ErrCodeNotFound, "member not found")
+	}
+	log.Printf("Error saving shop member: %v", err)
+	return apperror.New(apperror.ErrCodeInternal, "failed to save shop member")
+}
+
+func invitationWriteError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperror.New(apperror.ErrCodeNotFound, "invitation not found or no longer open")
+	}
+	log.Printf("Error saving shop invitation: %v", err)
+	return apperror.New(apperror.ErrCodeInternal, "failed to save invitation")
+}
+
+// buildInvitationNotification memberi tahu akun terdaftar bahwa ia diundang menjadi staff shop.
+func buildInvitationNotification(inviteeID uuid.UUID, shop model.Shop, invitation model.ShopInvitation) (model.Notification, error) {
+	data, err := json.Marshal(map[string]any{
+		"shop_id": shop.ID,
+		"role":    invitation.Role,
+	})
+	if err != nil {
+		return model.Notification{}, err
+	}
+	return model.Notification{
+		AccountID: inviteeID,
+		Type:      model.NotificationTypeShop,
+		Title:     "You have been invited to join a shop",
+		Body:      fmt.Sprintf("%s invited you to join as %s.", shop.Name, invitation.Role),
+		Data:      data,
+	}, nil
+}
+
+func vacationError(err error) error {
+	switch {
+	case errors.Is(err, ErrOrdersAwaitingShipment):
+		return apperror.New(apperror.ErrCodeConflict, "you have orders awaiting shipment; ship them first or choose to extend their shipping deadline")
+	case errors.Is(err, sql.ErrNoRows):
+		return apperror.New(apperror.ErrCodeNotFound, "shop not found")
+	}
+	log.Printf("Error starting shop vacation: %v", err)
+	return apperror.New(apperror.ErrCodeInternal, "failed to start vacation")
+}
+
+// buildVacationNotification menyusun notifikasi seller tentang mode libur shop-nya.
+func buildShopNotification(shop model.Shop, title, body string) (model.Notification, error) {
+	data, err := json.Marshal(map[string]any{"shop_id": shop.ID})
+	if err != nil {
+		return model.Notification{}, err
+	}
+	return model.Notification{
+		AccountID: shop.AccountID,
+		Type:      model.NotificationTypeShop,
+		Title:     title,
+		Body:      body,
+		Data:      data,
+	}, nil
+}
+
+// buildVacationEndedNotification memberi tahu seller bahwa jadwal liburnya selesai.
+func buildVacationEndedNotification(ended EndedVacation) (model.Notification, error) {
+	if !ended.WasActive {
+		return buildShopNotification(ended.Shop, "Your vacation was skipped",
+			fmt.Sprintf("The scheduled vacation for %s ended before it could start because orders were still awaiting shipment.", ended.Name))
+	}
+	return buildShopNotification(ended.Shop, "Welcome back",
+		fmt.Sprintf("Your vacation has ended and %s is open for orders again.", ended.Name))
+}
+
+// buildDelayNotification memberi tahu pembeli bahwa batas kirim order-nya digeser karena seller libur.
+func buildDelayNotification(order DelayedOrder) (model.Notification, error) {
+	data, err := json.Marshal(map[string]any{
+		"order_id": order.OrderID,
+		"ship_by":  order.ShipBy,
+	})
+	if err != nil {
+		return model.Notification{}, err
+	}
+	return model.Notification{
+		AccountID: order.AccountID,
+		Type:      model.NotificationTypeOrder,
+		Title:     "Your order will ship later",
+		Body:      fmt.Sprintf("The seller is on vacation. Your order will now ship by %s.", order.ShipBy.Format("2 Jan 2006")),
+		Data:      data,
+	}, nil
+}
+
+// buildStatusNotification menyusun notifikasi seller untuk keputusan admin.
+func buildStatusNotification(shop model.Shop, action Action, reason string) (model.Notification, error) {
+	data, err := json.Marshal(map[string]any{
+		"shop_id": shop.ID,
+		"action":  action,
+	})
+	if err != nil {
+		return model.Notification{}, err
+	}
+
+	body := fmt.Sprintf("%s is now %s.", shop.Name, statusTransitions[action].to)
+	if reason != "" {
+		body += " Reason: " + reason
+	}
+	return model.Notification{
+		AccountID: shop.AccountID,
+		Type:      model.NotificationTypeShop,
+		Title:     statusNotices[action],
+		Body:      body,
+		Data:      data,
+	}, nil
+}
+
+// optionalText merapikan teks opsional; string kosong disimpan sebagai NULL.
+func optionalText(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+func isShopStatus(status string) bool {
+	switch status {
+	case model.ShopStatusPending, model.ShopStatusApproved, model.ShopStatusActive, model.ShopStatusSuspended:
+		return true
+	}
+	return false
+}
+
+func normalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	return page, limit
+}
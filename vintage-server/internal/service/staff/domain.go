@@ -0,0 +1,48 @@
+package staff
+
+// File: internal/service/staff/domain.go
+
+import "vintage-server/internal/model"
+
+// Permission adalah hak akses anggota shop atas satu area fitur seller.
+// Service lain membaca keanggotaan lewat FindMembership lalu memeriksa Can sebelum bertindak atas nama shop.
+type Permission string
+
+const (
+	// PermissionListings: membuat, mengubah dan menghapus listing, termasuk import, offer dan lelang
+	PermissionListings Permission = "listings"
+	// PermissionFulfillment: melihat dan memproses order yang berisi produk shop
+	PermissionFulfillment Permission = "fulfillment"
+	// PermissionSettings: profil, kebijakan, gambar dan mode libur shop
+	PermissionSettings Permission = "settings"
+	// PermissionStaff: mengundang, mengubah role dan mengeluarkan anggota shop
+	PermissionStaff Permission = "staff"
+)
+
+var rolePermissions = map[string][]Permission{
+	model.ShopRoleOwner:       {PermissionListings, PermissionFulfillment, PermissionSettings, PermissionStaff},
+	model.ShopRoleManager:     {PermissionListings, PermissionFulfillment, PermissionSettings},
+	model.ShopRoleLister:      {PermissionListings},
+	model.ShopRoleFulfillment: {PermissionFulfillment},
+}
+
+// Can melaporkan apakah role memiliki permission tersebut. Role yang tidak dikenal tidak punya akses apa pun.
+func Can(role string, permission Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Permissions mengembalikan semua permission sebuah role, untuk ditampilkan ke anggota shop.
+func Permissions(role string) []Permission {
+	return append([]Permission{}, rolePermissions[role]...)
+}
+
+// IsRole melaporkan apakah role adalah role anggota shop yang dikenal.
+func IsRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
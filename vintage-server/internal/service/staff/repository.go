@@ -0,0 +1,21 @@
+package staff
+
+import (
+	"context"
+	"vintage-server/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// FindMembership mengambil keanggotaan shop sebuah akun (sql.ErrNoRows jika akun bukan anggota
+// shop yang belum dihapus). q bisa berupa *sqlx.DB atau *sqlx.Tx.
+func FindMembership(ctx context.Context, q sqlx.QueryerContext, accountID uuid.UUID) (model.ShopMember, error) {
+	var member model.ShopMember
+	query := `
+		SELECT m.* FROM shop_members m
+		JOIN shop s ON s.id = m.shop_id
+		WHERE m.account_id = $1 AND s.deleted_at IS NULL`
+	err := sqlx.GetContext(ctx, q, &member, query, accountID)
+	return member, err
+}
@@ -0,0 +1,43 @@
+package syndication
+
+// File: internal/service/syndication/domain.go
+
+import (
+	"context"
+	"vintage-server/internal/model"
+)
+
+// =================================================================================
+// KONTRAK UNTUK SERVICE (Logika Bisnis) 🧠
+// =================================================================================
+type Service interface {
+	// Usecase: CrawlerFetch Sitemap / Product Feed
+	GetArtifact(ctx context.Context, name string) (model.GeneratedArtifact, error)
+
+	// RegenerateArtifacts dipanggil job berkala. Hanya file yang data sumbernya berubah yang
+	// ditulis ulang; mengembalikan jumlah file yang ditulis.
+	RegenerateArtifacts(ctx context.Context) (int, error)
+}
+
+// =================================================================================
+// KONTRAK UNTUK REPOSITORY (Akses Database) 🚚
+// =================================================================================
+// Repository mendefinisikan semua interaksi ke database yang dibutuhkan oleh Service.
+type Repository interface {
+	// --- Artifact ---
+	FindArtifact(ctx context.Context, name string) (model.GeneratedArtifact, error)
+	// FindFingerprints mengembalikan fingerprint semua file yang tersimpan (name -> fingerprint).
+	FindFingerprints(ctx context.Context) (map[string]string, error)
+	SaveArtifact(ctx context.Context, artifact model.GeneratedArtifact) error
+	DeleteArtifacts(ctx context.Context, names []string) error
+
+	// --- Sitemap ---
+	// FindSitemapPages membagi sumber (products / shops / categories) menjadi halaman berisi
+	// pageSize URL dan menghitung fingerprint tiap halaman.
+	FindSitemapPages(ctx context.Context, source string, pageSize int) ([]SitemapPage, error)
+	FindSitemapEntries(ctx context.Context, source string, page, pageSize int) ([]SitemapEntry, error)
+
+	// --- Product Feed ---
+	FindProductFeedFingerprint(ctx context.Context) (string, error)
+	FindProductFeedItems(ctx context.Context) ([]FeedItem, error)
+}
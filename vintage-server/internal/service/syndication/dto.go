@@ -0,0 +1,39 @@
+package syndication
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SiteConfig menentukan URL absolut yang ditulis ke sitemap dan product feed.
+type SiteConfig struct {
+	// SiteBaseURL adalah alamat storefront, misal "https://vintage.example"
+	SiteBaseURL string
+	// AssetBaseURL dipakai untuk URL gambar yang disimpan relatif (misal "/uploads/...")
+	AssetBaseURL string
+}
+
+type SitemapPage struct {
+	Page         int       `db:"page"`
+	LastModified time.Time `db:"last_modified"`
+	Fingerprint  string    `db:"fingerprint"`
+}
+
+// SitemapEntry adalah satu URL; Key berupa slug produk / shop / kategori.
+type SitemapEntry struct {
+	Key          string    `db:"key"`
+	LastModified time.Time `db:"last_modified"`
+}
+
+type FeedItem struct {
+	ID              uuid.UUID `db:"id"`
+	Slug            string    `db:"slug"`
+	Name            string    `db:"name"`
+	Description     string    `db:"description"`
+	Price           int64     `db:"price"`
+	Status          string    `db:"status"`
+	Brand           *string   `db:"brand"`
+	GoogleCondition string    `db:"google_condition"`
+	ImageURL        *string   `db:"image_url"`
+}
@@ -0,0 +1,47 @@
+package syndication
+
+import (
+	"net/http"
+	"strings"
+	"time"
+	"vintage-server/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler adalah struct yang memegang dependency ke Service
+type Handler struct {
+	svc Service
+}
+
+// NewHandler adalah constructor untuk handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// ServeArtifact menyajikan sitemap / product feed sesuai path request (misal "/sitemaps/products-1.xml").
+// File dibangkitkan oleh job, jadi cukup dibaca dari database dan divalidasi lewat ETag.
+func (h *Handler) ServeArtifact(c *gin.Context) {
+	name := strings.TrimPrefix(c.Request.URL.Path, "/")
+
+	artifact, err := h.svc.GetArtifact(c.Request.Context(), name)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	etag := `"` + artifact.ETag + `"`
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", artifact.GeneratedAt.UTC().Format(http.TimeFormat))
+	c.Header("Cache-Control", "public, max-age=3600")
+	if match := c.GetHeader("If-None-Match"); match != "" && strings.Contains(match, etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	if since, err := time.Parse(http.TimeFormat, c.GetHeader("If-Modified-Since")); err == nil && c.GetHeader("If-None-Match") == "" &&
+		!artifact.GeneratedAt.Truncate(time.Second).After(since) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, artifact.ContentType, artifact.Content)
+}
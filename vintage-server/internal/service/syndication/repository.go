@@ -0,0 +1,150 @@
+package syndication
+
+import (
+	"context"
+	"fmt"
+	"vintage-server/internal/model"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// sitemapSources berisi query (key, last_modified, sort_at) untuk tiap jenis sitemap.
+var sitemapSources = map[string]string{
+	"products": `
+		SELECT slug AS key, COALESCE(updated_at, created_at) AS last_modified, created_at AS sort_at
+		FROM products WHERE status IN ('published', 'sold') AND deleted_at IS NULL AND shop_id IN (SELECT id FROM shop WHERE active)`,
+	"shops": `
+		SELECT slug AS key, COALESCE(updated_at, created_at) AS last_modified, created_at AS sort_at
+		FROM shop WHERE active AND deleted_at IS NULL`,
+	"categories": `
+		SELECT slug AS key, COALESCE(updated_at, created_at) AS last_modified, created_at AS sort_at
+		FROM product_categories WHERE deleted_at IS NULL`,
+}
+
+// feedFrom adalah sumber product feed: listing tayang / terjual yang sudah punya harga.
+const feedFrom = `
+	FROM products p
+	LEFT JOIN brands b ON b.id = p.brand_id
+	LEFT JOIN product_conditions pc ON pc.id = p.condition_id
+	LEFT JOIN product_images pi ON pi.product_id = p.id AND pi.image_index = 0
+	WHERE p.status IN ('published', 'sold') AND p.deleted_at IS NULL AND p.price IS NOT NULL
+	  AND p.shop_id IN (SELECT id FROM shop WHERE active)`
+
+// repository adalah struct yang mengimplementasikan kontrak Repository dari domain.go
+type repository struct {
+	db *sqlx.DB
+}
+
+// NewRepository adalah constructor untuk implementasi repository
+func NewRepository(db *sqlx.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// --- Artifact ---
+
+func (r *repository) FindArtifact(ctx context.Context, name string) (model.GeneratedArtifact, error) {
+	var artifact model.GeneratedArtifact
+	err := r.db.GetContext(ctx, &artifact, "SELECT * FROM generated_artifacts WHERE name = $1", name)
+	return artifact, err
+}
+
+func (r *repository) FindFingerprints(ctx context.Context) (map[string]string, error) {
+	var rows []struct {
+		Name        string `db:"name"`
+		Fingerprint string `db:"fingerprint"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, "SELECT name, fingerprint FROM generated_artifacts"); err != nil {
+		return nil, err
+	}
+	fingerprints := make(map[string]string, len(rows))
+	for _, row := range rows {
+		fingerprints[row.Name] = row.Fingerprint
+	}
+	return fingerprints, nil
+}
+
+func (r *repository) SaveArtifact(ctx context.Context, artifact model.GeneratedArtifact) error {
+	query := `
+		INSERT INTO generated_artifacts (name, content_type, content, etag, fingerprint, generated_at)
+		VALUES (:name, :content_type, :content, :etag, :fingerprint, :generated_at)
+		ON CONFLICT (name) DO UPDATE SET
+			content_type = EXCLUDED.content_type,
+			content = EXCLUDED.content,
+			etag = EXCLUDED.etag,
+			fingerprint = EXCLUDED.fingerprint,
+			generated_at = EXCLUDED.generated_at`
+	_, err := r.db.NamedExecContext(ctx, query, artifact)
+	return err
+}
+
+func (r *repository) DeleteArtifacts(ctx context.Context, names []string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM generated_artifacts WHERE name = ANY($1)", pq.Array(names))
+	return err
+}
+
+// --- Sitemap ---
+
+func (r *repository) FindSitemapPages(ctx context.Context, source string, pageSize int) ([]SitemapPage, error) {
+	from, ok := sitemapSources[source]
+	if !ok {
+		return nil, fmt.Errorf("unknown sitemap source %q", source)
+	}
+
+	pages := []SitemapPage{}
+	// Fingerprint memuat key + waktu ubah setiap URL, jadi perubahan / pergeseran isi halaman ikut terdeteksi
+	query := fmt.Sprintf(`
+		SELECT page, MAX(last_modified) AS last_modified,
+			COUNT(*) || ':' || md5(string_agg(key || '@' || EXTRACT(EPOCH FROM last_modified)::text, ',' ORDER BY rn)) AS fingerprint
+		FROM (
+			SELECT key, last_modified,
+				ROW_NUMBER() OVER (ORDER BY sort_at, key) AS rn,
+				(ROW_NUMBER() OVER (ORDER BY sort_at, key) - 1) / $1 + 1 AS page
+			FROM (%s) src
+		) numbered
+		GROUP BY page
+		ORDER BY page`, from)
+	err := r.db.SelectContext(ctx, &pages, query, pageSize)
+	return pages, err
+}
+
+func (r *repository) FindSitemapEntries(ctx context.Context, source string, page, pageSize int) ([]SitemapEntry, error) {
+	from, ok := sitemapSources[source]
+	if !ok {
+		return nil, fmt.Errorf("unknown sitemap source %q", source)
+	}
+
+	entries := []SitemapEntry{}
+	query := fmt.Sprintf(`
+		SELECT key, last_modified FROM (%s) src
+		ORDER BY sort_at, key
+		LIMIT $1 OFFSET $2`, from)
+	err := r.db.SelectContext(ctx, &entries, query, pageSize, (page-1)*pageSize)
+	return entries, err
+}
+
+// --- Product Feed ---
+
+func (r *repository) FindProductFeedFingerprint(ctx context.Context) (string, error) {
+	var fingerprint string
+	query := `
+		SELECT COUNT(*) || ':' || COALESCE(md5(string_agg(
+			p.slug || '@' || p.status || '@' ||
+			EXTRACT(EPOCH FROM GREATEST(p.updated_at, b.updated_at, pc.updated_at, pi.created_at))::text,
+			',' ORDER BY p.created_at, p.id)), '')` + feedFrom
+	err := r.db.GetContext(ctx, &fingerprint, query)
+	return fingerprint, err
+}
+
+func (r *repository) FindProductFeedItems(ctx context.Context) ([]FeedItem, error) {
+	items := []FeedItem{}
+	query := `
+		SELECT
+			p.id, p.slug, p.name, COALESCE(p.description, p.summary, p.name) AS description, p.price, p.status,
+			b.name AS brand, COALESCE(pc.google_condition, 'used') AS google_condition, pi.url AS image_url` + feedFrom + `
+		ORDER BY p.created_at, p.id`
+	err := r.db.SelectContext(ctx, &items, query)
+	return items, err
+}
@@ -0,0 +1,267 @@
+package syndication
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/pkg/apperror"
+)
+
+const (
+	// sitemapPageSize di bawah batas 50.000 URL per file dari protokol sitemap
+	sitemapPageSize = 10000
+
+	sitemapIndexName = "sitemap.xml"
+	sitemapPrefix    = "sitemaps/"
+	productFeedName  = "feeds/products.xml"
+
+	contentTypeXML = "application/xml; charset=utf-8"
+)
+
+// sitemapSourcePaths memetakan sumber sitemap ke path halaman storefront.
+var sitemapSourcePaths = []struct {
+	Source string
+	Path   string
+}{
+	{Source: "products", Path: "/products/"},
+	{Source: "shops", Path: "/shops/"},
+	{Source: "categories", Path: "/categories/"},
+}
+
+// service adalah struct yang akan mengimplementasikan interface Service dari domain.go
+type service struct {
+	repo Repository
+	site SiteConfig
+}
+
+// NewService adalah constructor untuk service
+func NewService(repo Repository, site SiteConfig) Service {
+	site.SiteBaseURL = strings.TrimRight(site.SiteBaseURL, "/")
+	site.AssetBaseURL = strings.TrimRight(site.AssetBaseURL, "/")
+	return &service{
+		repo: repo,
+		site: site,
+	}
+}
+
+func (s *service) GetArtifact(ctx context.Context, name string) (model.GeneratedArtifact, error) {
+	artifact, err := s.repo.FindArtifact(ctx, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.GeneratedArtifact{}, apperror.New(apperror.ErrCodeNotFound, "file not found")
+		}
+		log.Printf("Error finding generated artifact %s: %v", name, err)
+		return model.GeneratedArtifact{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return artifact, nil
+}
+
+func (s *service) RegenerateArtifacts(ctx context.Context) (int, error) {
+	stored, err := s.repo.FindFingerprints(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("find fingerprints: %w", err)
+	}
+
+	written := 0
+	now := time.Now()
+	index := sitemapIndex{Xmlns: sitemapNamespace}
+	current := map[string]bool{}
+
+	// 1. Halaman sitemap per sumber, hanya yang fingerprint-nya berubah yang dibangun ulang
+	for _, src := range sitemapSourcePaths {
+		pages, err := s.repo.FindSitemapPages(ctx, src.Source, sitemapPageSize)
+		if err != nil {
+			return written, fmt.Errorf("find %s sitemap pages: %w", src.Source, err)
+		}
+
+		for _, page := range pages {
+			name := fmt.Sprintf("%s%s-%d.xml", sitemapPrefix, src.Source, page.Page)
+			current[name] = true
+			index.Sitemaps = append(index.Sitemaps, sitemapRef{
+				Loc:     s.site.SiteBaseURL + "/" + name,
+				LastMod: page.LastModified.UTC().Format(time.RFC3339),
+			})
+			if stored[name] == page.Fingerprint {
+				continue
+			}
+
+			entries, err := s.repo.FindSitemapEntries(ctx, src.Source, page.Page, sitemapPageSize)
+			if err != nil {
+				return written, fmt.Errorf("find %s sitemap entries: %w", name, err)
+			}
+			urlSet := urlSet{Xmlns: sitemapNamespace}
+			for _, entry := range entries {
+				urlSet.URLs = append(urlSet.URLs, sitemapURL{
+					Loc:     s.site.SiteBaseURL + src.Path + entry.Key,
+					LastMod: entry.LastModified.UTC().Format(time.RFC3339),
+				})
+			}
+			if err := s.save(ctx, name, page.Fingerprint, urlSet, now); err != nil {
+				return written, err
+			}
+			written++
+		}
+	}
+
+	// 2. Halaman yang sudah tidak ada (misal produk berkurang) dihapus
+	var stale []string
+	for name := range stored {
+		if strings.HasPrefix(name, sitemapPrefix) && !current[name] {
+			stale = append(stale, name)
+		}
+	}
+	if len(stale) > 0 {
+		if err := s.repo.DeleteArtifacts(ctx, stale); err != nil {
+			return written, fmt.Errorf("delete stale sitemaps: %w", err)
+		}
+	}
+
+	// 3. Sitemap index; fingerprint-nya adalah isi index itu sendiri
+	indexContent, err := marshalXML(index)
+	if err != nil {
+		return written, fmt.Errorf("marshal sitemap index: %w", err)
+	}
+	if fingerprint := hashHex(indexContent); stored[sitemapIndexName] != fingerprint {
+		if err := s.saveContent(ctx, sitemapIndexName, fingerprint, indexContent, now); err != nil {
+			return written, err
+		}
+		written++
+	}
+
+	// 4. Product feed
+	fingerprint, err := s.repo.FindProductFeedFingerprint(ctx)
+	if err != nil {
+		return written, fmt.Errorf("find product feed fingerprint: %w", err)
+	}
+	if stored[productFeedName] != fingerprint {
+		items, err := s.repo.FindProductFeedItems(ctx)
+		if err != nil {
+			return written, fmt.Errorf("find product feed items: %w", err)
+		}
+		if err := s.save(ctx, productFeedName, fingerprint, s.buildProductFeed(items), now); err != nil {
+			return written, err
+		}
+		written++
+	}
+
+	return written, nil
+}
+
+func (s *service) buildProductFeed(items []FeedItem) productFeed {
+	feed := productFeed{
+		Version: "2.0",
+		XmlnsG:  googleNamespace,
+		Channel: feedChannel{
+			Title:       "Vintage Online Shop",
+			Link:        s.site.SiteBaseURL,
+			Description: "Product feed",
+		},
+	}
+
+	for _, item := range items {
+		entry := feedEntry{
+			ID:               item.ID.String(),
+			Title:            item.Name,
+			Description:      item.Description,
+			Link:             s.site.SiteBaseURL + "/products/" + item.Slug,
+			Price:            fmt.Sprintf("%d IDR", item.Price),
+			Availability:     "in stock",
+			Condition:        item.GoogleCondition,
+			IdentifierExists: "no",
+		}
+		if item.Status == model.ListingStatusSold {
+			entry.Availability = "out of stock"
+		}
+		if item.Brand != nil {
+			entry.Brand = *item.Brand
+		}
+		if item.ImageURL != nil {
+			entry.ImageLink = s.absoluteAssetURL(*item.ImageURL)
+		}
+		feed.Channel.Items = append(feed.Channel.Items, entry)
+	}
+	return feed
+}
+
+// absoluteAssetURL melengkapi URL gambar relatif dari local storage ("/uploads/...").
+func (s *service) absoluteAssetURL(url string) string {
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		return url
+	}
+	return s.site.AssetBaseURL + "/" + strings.TrimLeft(url, "/")
+}
+
+func (s *service) save(ctx context.Context, name, fingerprint string, doc any, now time.Time) error {
+	content, err := marshalXML(doc)
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", name, err)
+	}
+	return s.saveContent(ctx, name, fingerprint, content, now)
+}
+
+func (s *service) saveContent(ctx context.Context, name, fingerprint string, content []byte, now time.Time) error {
+	artifact := model.GeneratedArtifact{
+		Name:        name,
+		ContentType: contentTypeXML,
+		Content:     content,
+		ETag:        hashHex(content),
+		Fingerprint: fingerprint,
+		GeneratedAt: now,
+	}
+	if err := s.repo.SaveArtifact(ctx, artifact); err != nil {
+		return fmt.Errorf("save %s: %w", name, err)
+	}
+	return nil
+}
+
+func marshalXML(doc any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func hashHex(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// StartArtifactJob menjalankan RegenerateArtifacts sekali saat start, lalu berkala sampai ctx dibatalkan.
+func StartArtifactJob(ctx context.Context, svc Service, interval time.Duration) {
+	regenerate := func() {
+		written, err := svc.RegenerateArtifacts(ctx)
+		if err != nil {
+			log.Printf("Error regenerating sitemap and product feed: %v", err)
+		}
+		if written > 0 {
+			log.Printf("Regenerated %d sitemap/feed files", written)
+		}
+	}
+
+	regenerate()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			regenerate()
+		}
+	}
+}
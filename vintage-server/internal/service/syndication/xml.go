@@ -0,0 +1,61 @@
+package syndication
+
+import "encoding/xml"
+
+const (
+	sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	googleNamespace  = "http://base.google.com/ns/1.0"
+)
+
+// --- Sitemap (sitemaps.org) ---
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	Xmlns    string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapRef `xml:"sitemap"`
+}
+
+type sitemapRef struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+type urlSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// --- Product Feed (RSS 2.0 + namespace g: Google Merchant Center) ---
+
+type productFeed struct {
+	XMLName xml.Name    `xml:"rss"`
+	Version string      `xml:"version,attr"`
+	XmlnsG  string      `xml:"xmlns:g,attr"`
+	Channel feedChannel `xml:"channel"`
+}
+
+type feedChannel struct {
+	Title       string      `xml:"title"`
+	Link        string      `xml:"link"`
+	Description string      `xml:"description"`
+	Items       []feedEntry `xml:"item"`
+}
+
+type feedEntry struct {
+	ID               string `xml:"g:id"`
+	Title            string `xml:"g:title"`
+	Description      string `xml:"g:description"`
+	Link             string `xml:"g:link"`
+	ImageLink        string `xml:"g:image_link,omitempty"`
+	Price            string `xml:"g:price"`
+	Availability     string `xml:"g:availability"`
+	Condition        string `xml:"g:condition"`
+	Brand            string `xml:"g:brand,omitempty"`
+	IdentifierExists string `xml:"g:identifier_exists"`
+}
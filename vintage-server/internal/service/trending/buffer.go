@@ -0,0 +1,76 @@
+package trending
+
+import (
+	"sync"
+	"time"
+	"vintage-server/internal/model"
+
+	"github.com/google/uuid"
+)
+
+type bucketKey struct {
+	productID uuid.UUID
+	hour      time.Time
+}
+
+// viewBuffer menampung view di memori supaya tiap view tidak menjadi satu write ke database.
+// Deduplikasi berlaku per instance service: pengunjung yang sama terhitung sekali per
+// produk dalam satu window.
+type viewBuffer struct {
+	mu     sync.Mutex
+	window time.Duration
+	// seen menyimpan waktu view terakhir yang dihitung per sessionKey|productID
+	seen   map[string]time.Time
+	counts map[bucketKey]int
+}
+
+func newViewBuffer(window time.Duration) *viewBuffer {
+	return &viewBuffer{
+		window: window,
+		seen:   make(map[string]time.Time),
+		counts: make(map[bucketKey]int),
+	}
+}
+
+// add mencatat satu view; false jika masih duplikat dalam window.
+func (b *viewBuffer) add(sessionKey string, productID uuid.UUID, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := sessionKey + "|" + productID.String()
+	if last, ok := b.seen[key]; ok && now.Sub(last) < b.window {
+		return false
+	}
+	b.seen[key] = now
+	b.counts[bucketKey{productID: productID, hour: now.UTC().Truncate(time.Hour)}]++
+	return true
+}
+
+// drain mengambil seluruh counter yang terkumpul dan membuang entri dedup yang sudah lewat window.
+func (b *viewBuffer) drain(now time.Time) []model.ProductViewHourly {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for key, last := range b.seen {
+		if now.Sub(last) >= b.window {
+			delete(b.seen, key)
+		}
+	}
+
+	counts := make([]model.ProductViewHourly, 0, len(b.counts))
+	for key, views := range b.counts {
+		counts = append(counts, model.ProductViewHourly{ProductID: key.productID, Hour: key.hour, Views: views})
+	}
+	b.counts = make(map[bucketKey]int)
+	return counts
+}
+
+// restore mengembalikan counter yang gagal ditulis supaya ikut di flush berikutnya.
+func (b *viewBuffer) restore(counts []model.ProductViewHourly) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, count := range counts {
+		b.counts[bucketKey{productID: count.ProductID, hour: count.Hour}] += count.Views
+	}
+}
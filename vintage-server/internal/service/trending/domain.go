@@ -0,0 +1,53 @@
+package trending
+
+// File: internal/service/trending/domain.go
+
+import (
+	"context"
+	"time"
+	"vintage-server/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// =================================================================================
+// KONTRAK UNTUK SERVICE (Logika Bisnis) 🧠
+// =================================================================================
+type Service interface {
+	// Usecase: Storefront RecordView
+	// RecordView hanya menambah counter di memori; mengembalikan false jika view diabaikan
+	// (bot atau duplikat dalam satu jendela sesi).
+	RecordView(ctx context.Context, event ViewEvent) (bool, error)
+
+	// Usecase: Storefront Trending ("Trending in Jackets")
+	GetTrending(ctx context.Context, filter TrendingFilter) (TrendingList, error)
+
+	// Usecase: Seller View Stats
+	GetListingViewStats(ctx context.Context, sellerID uuid.UUID, filter ViewStatsFilter) (ListingViewStatsPage, error)
+
+	// FlushViews menulis buffer view ke counter per jam dalam satu batch; dipanggil job berkala.
+	FlushViews(ctx context.Context) (int, error)
+	// RebuildTrending menghitung ulang skor trending; dipanggil job berkala.
+	RebuildTrending(ctx context.Context) error
+}
+
+// =================================================================================
+// KONTRAK UNTUK REPOSITORY (Akses Database) 🚚
+// =================================================================================
+// Repository mendefinisikan semua interaksi ke database yang dibutuhkan oleh Service.
+type Repository interface {
+	// --- View Counter ---
+	// SaveViewCounts menambahkan counts ke product_view_hourly; view untuk produk yang
+	// tidak ada / tidak tayang dibuang. Mengembalikan jumlah baris yang ditulis.
+	SaveViewCounts(ctx context.Context, counts []model.ProductViewHourly) (int, error)
+
+	// --- Trending ---
+	TransactionRebuildTrending(ctx context.Context, weights ScoreWeights, since, computedAt time.Time) error
+	FindTrending(ctx context.Context, categoryID *int, limit int) ([]TrendingProduct, error)
+	FindCategory(ctx context.Context, categoryID int) (TrendingCategory, error)
+
+	// --- Seller Stats ---
+	// FindShopMembership mengambil shop tempat akun menjadi anggota beserta role-nya.
+	FindShopMembership(ctx context.Context, accountID uuid.UUID) (model.ShopMember, error)
+	FindListingViewStats(ctx context.Context, shopID uuid.UUID, now time.Time, limit, offset int) ([]ListingViewStats, int64, error)
+}
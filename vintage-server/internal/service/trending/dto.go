@@ -0,0 +1,77 @@
+package trending
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ViewEvent adalah satu view halaman produk dari storefront.
+type ViewEvent struct {
+	ProductID uuid.UUID
+	// SessionKey mengidentifikasi pengunjung untuk deduplikasi (account, session id, atau IP + user agent)
+	SessionKey string
+	UserAgent  string
+}
+
+// ScoreWeights adalah bobot tiap sinyal pada skor trending. Semua sinyal meluruh
+// setengahnya setiap HalfLife.
+type ScoreWeights struct {
+	View     float64
+	Wishlist float64
+	Sale     float64
+	HalfLife time.Duration
+	// PaidStatuses adalah status order yang dihitung sebagai penjualan
+	PaidStatuses []int
+}
+
+type TrendingFilter struct {
+	// CategoryID membatasi ke kategori beserta turunannya, misal "Trending in Jackets"
+	CategoryID *int `form:"category_id"`
+	Limit      int  `form:"limit"`
+}
+
+type TrendingCategory struct {
+	ID   int    `json:"id" db:"id"`
+	Name string `json:"name" db:"name"`
+	Slug string `json:"slug" db:"slug"`
+}
+
+type TrendingProduct struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	ShopID     uuid.UUID `json:"shop_id" db:"shop_id"`
+	ShopName   string    `json:"shop_name" db:"shop_name"`
+	CategoryID int       `json:"category_id" db:"category_id"`
+	Name       string    `json:"name" db:"name"`
+	Price      int64     `json:"price" db:"price"`
+	ImageURL   *string   `json:"image_url" db:"image_url"`
+	Score      float64   `json:"score" db:"score"`
+}
+
+type TrendingList struct {
+	Category *TrendingCategory `json:"category,omitempty"`
+	Items    []TrendingProduct `json:"items"`
+}
+
+type ViewStatsFilter struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+// ListingViewStats adalah jumlah view satu listing milik seller.
+type ListingViewStats struct {
+	ProductID uuid.UUID `json:"product_id" db:"product_id"`
+	Name      string    `json:"name" db:"name"`
+	Status    string    `json:"status" db:"status"`
+	Views24h  int64     `json:"views_24h" db:"views_24h"`
+	Views7d   int64     `json:"views_7d" db:"views_7d"`
+	Views30d  int64     `json:"views_30d" db:"views_30d"`
+	ViewsAll  int64     `json:"views_total" db:"views_total"`
+}
+
+type ListingViewStatsPage struct {
+	Items []ListingViewStats `json:"items"`
+	Total int64              `json:"total"`
+	Page  int                `json:"page"`
+	Limit int                `json:"limit"`
+}
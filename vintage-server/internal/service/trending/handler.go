@@ -0,0 +1,94 @@
+package trending
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"vintage-server/pkg/middleware"
+	"vintage-server/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// sessionHeader adalah id sesi anonim yang dikirim storefront untuk deduplikasi view
+const sessionHeader = "X-Session-ID"
+
+// Handler adalah struct yang memegang dependency ke Service
+type Handler struct {
+	svc Service
+}
+
+// NewHandler adalah constructor untuk handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// RecordView mencatat satu view halaman produk. Selalu 204 agar bot / duplikat tidak bisa
+// membedakan view yang dihitung dan yang diabaikan.
+func (h *Handler) RecordView(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid product id")
+		return
+	}
+
+	event := ViewEvent{
+		ProductID:  productID,
+		SessionKey: sessionKey(c),
+		UserAgent:  c.Request.UserAgent(),
+	}
+	if _, err := h.svc.RecordView(c.Request.Context(), event); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetTrending mengembalikan listing trending, opsional per kategori (misal "Trending in Jackets")
+func (h *Handler) GetTrending(c *gin.Context) {
+	var filter TrendingFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.svc.GetTrending(c.Request.Context(), filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=300")
+	response.Success(c, http.StatusOK, result)
+}
+
+// GetListingViewStats mengembalikan jumlah view per listing milik seller yang login
+func (h *Handler) GetListingViewStats(c *gin.Context) {
+	sellerID, _ := middleware.GetAccountID(c)
+
+	var filter ViewStatsFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	result, err := h.svc.GetListingViewStats(c.Request.Context(), sellerID, filter)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, result)
+}
+
+// sessionKey mengidentifikasi pengunjung: account jika login, lalu X-Session-ID,
+// terakhir hash IP + user agent.
+func sessionKey(c *gin.Context) string {
+	if accountID, ok := middleware.GetAccountID(c); ok {
+		return "a:" + accountID.String()
+	}
+	if session := c.GetHeader(sessionHeader); session != "" && len(session) <= 128 {
+		return "s:" + session
+	}
+	sum := sha256.Sum256([]byte(c.ClientIP() + "|" + c.Request.UserAgent()))
+	return "c:" + hex.EncodeToString(sum[:16])
+}
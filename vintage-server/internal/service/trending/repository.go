@@ -0,0 +1,168 @@
+package trending
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/staff"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// decayed menghasilkan bobot peluruhan untuk kolom waktu %s: 0.5 ^ (umur / half-life).
+// $4 = half-life (detik), $6 = waktu perhitungan.
+const decayed = `exp(-ln(2) * EXTRACT(EPOCH FROM ($6::timestamptz - %s))::float8 / $4)`
+
+// repository adalah struct yang mengimplementasikan kontrak Repository dari domain.go
+type repository struct {
+	db *sqlx.DB
+}
+
+// NewRepository adalah constructor untuk implementasi repository
+func NewRepository(db *sqlx.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// --- View Counter ---
+
+func (r *repository) SaveViewCounts(ctx context.Context, counts []model.ProductViewHourly) (int, error) {
+	productIDs := make([]string, len(counts))
+	hours := make([]string, len(counts))
+	views := make([]int64, len(counts))
+	for i, count := range counts {
+		productIDs[i] = count.ProductID.String()
+		hours[i] = count.Hour.UTC().Format(time.RFC3339)
+		views[i] = int64(count.Views)
+	}
+
+	// Satu statement untuk seluruh batch; JOIN products membuang id yang tidak valid
+	query := `
+		INSERT INTO product_view_hourly (product_id, hour, views)
+		SELECT v.product_id, v.hour, v.views
+		FROM UNNEST($1::uuid[], $2::timestamptz[], $3::int[]) AS v(product_id, hour, views)
+		JOIN products p ON p.id = v.product_id AND p.status IN ('published', 'sold') AND p.deleted_at IS NULL
+		ON CONFLICT (product_id, hour) DO UPDATE SET views = product_view_hourly.views + EXCLUDED.views`
+	result, err := r.db.ExecContext(ctx, query, pq.Array(productIDs), pq.Array(hours), pq.Array(views))
+	if err != nil {
+		return 0, err
+	}
+	written, err := result.RowsAffected()
+	return int(written), err
+}
+
+// --- Trending ---
+
+func (r *repository) TransactionRebuildTrending(ctx context.Context, weights ScoreWeights, since, computedAt time.Time) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Query 1: Kosongkan hasil sebelumnya
+	if _, err := tx.ExecContext(ctx, "DELETE FROM product_trending"); err != nil {
+		return err
+	}
+
+	// Query 2: Gabungkan view, wishlist, dan penjualan sejak $5 dengan peluruhan waktu.
+	// Hanya listing published; listing yang habis terjual otomatis keluar dari trending.
+	query := `
+		INSERT INTO product_trending (product_id, category_id, score, views, wishlist_adds, sales, computed_at)
+		SELECT
+			p.id, p.category_id,
+			COALESCE(v.score, 0) * $1 + COALESCE(w.score, 0) * $2 + COALESCE(s.score, 0) * $3,
+			COALESCE(v.total, 0), COALESCE(w.total, 0), COALESCE(s.total, 0), $6
+		FROM products p
+		LEFT JOIN (
+			SELECT product_id, SUM(views) AS total, SUM(views * ` + fmt.Sprintf(decayed, "hour") + `) AS score
+			FROM product_view_hourly WHERE hour >= $5
+			GROUP BY product_id
+		) v ON v.product_id = p.id
+		LEFT JOIN (
+			SELECT product_id, COUNT(*) AS total, SUM(` + fmt.Sprintf(decayed, "created_at") + `) AS score
+			FROM wishlist WHERE created_at >= $5
+			GROUP BY product_id
+		) w ON w.product_id = p.id
+		LEFT JOIN (
+			SELECT oi.product_id, SUM(oi.quantity) AS total, SUM(oi.quantity * ` + fmt.Sprintf(decayed, "oi.created_at") + `) AS score
+			FROM order_items oi
+			JOIN orders o ON o.id = oi.order_id
+			WHERE oi.created_at >= $5 AND o.status = ANY($7)
+			GROUP BY oi.product_id
+		) s ON s.product_id = p.id
+		WHERE p.status = 'published' AND p.deleted_at IS NULL
+		  AND p.shop_id IN (SELECT id FROM shop WHERE active)
+		  AND (v.product_id IS NOT NULL OR w.product_id IS NOT NULL OR s.product_id IS NOT NULL)`
+	_, err = tx.ExecContext(ctx, query,
+		weights.View, weights.Wishlist, weights.Sale, weights.HalfLife.Seconds(),
+		since, computedAt, pq.Array(weights.PaidStatuses))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *repository) FindTrending(ctx context.Context, categoryID *int, limit int) ([]TrendingProduct, error) {
+	products := []TrendingProduct{}
+	query := `
+		WITH RECURSIVE sub AS (
+			SELECT id FROM product_categories WHERE id = $1
+			UNION ALL
+			SELECT c.id FROM product_categories c JOIN sub ON c.parent_id = sub.id
+			WHERE c.deleted_at IS NULL
+		)
+		SELECT p.id, p.shop_id, s.name AS shop_name, p.category_id, p.name, p.price, pi.url AS image_url, t.score
+		FROM product_trending t
+		JOIN products p ON p.id = t.product_id
+		JOIN shop s ON s.id = p.shop_id
+		LEFT JOIN product_images pi ON pi.product_id = p.id AND pi.image_index = 0
+		WHERE p.status = 'published' AND p.deleted_at IS NULL AND s.active
+		  AND ($1::int IS NULL OR t.category_id IN (SELECT id FROM sub))
+		ORDER BY t.score DESC, p.id
+		LIMIT $2`
+	err := r.db.SelectContext(ctx, &products, query, categoryID, limit)
+	return products, err
+}
+
+func (r *repository) FindCategory(ctx context.Context, categoryID int) (TrendingCategory, error) {
+	var category TrendingCategory
+	query := "SELECT id, name, slug FROM product_categories WHERE id = $1 AND deleted_at IS NULL"
+	err := r.db.GetContext(ctx, &category, query, categoryID)
+	return category, err
+}
+
+// --- Seller Stats ---
+
+func (r *repository) FindShopMembership(ctx context.Context, accountID uuid.UUID) (model.ShopMember, error) {
+	return staff.FindMembership(ctx, r.db, accountID)
+}
+
+func (r *repository) FindListingViewStats(ctx context.Context, shopID uuid.UUID, now time.Time, limit, offset int) ([]ListingViewStats, int64, error) {
+	var total int64
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM products WHERE shop_id = $1 AND deleted_at IS NULL", shopID); err != nil {
+		return nil, 0, err
+	}
+
+	stats := []ListingViewStats{}
+	query := `
+		SELECT
+			p.id AS product_id, p.name, p.status,
+			COALESCE(SUM(v.views) FILTER (WHERE v.hour >= $2::timestamptz - INTERVAL '24 hours'), 0) AS views_24h,
+			COALESCE(SUM(v.views) FILTER (WHERE v.hour >= $2::timestamptz - INTERVAL '7 days'), 0) AS views_7d,
+			COALESCE(SUM(v.views) FILTER (WHERE v.hour >= $2::timestamptz - INTERVAL '30 days'), 0) AS views_30d,
+			COALESCE(SUM(v.views), 0) AS views_total
+		FROM products p
+		LEFT JOIN product_view_hourly v ON v.product_id = p.id
+		WHERE p.shop_id = $1 AND p.deleted_at IS NULL
+		GROUP BY p.id
+		ORDER BY views_30d DESC, p.created_at DESC, p.id
+		LIMIT $3 OFFSET $4`
+	err := r.db.SelectContext(ctx, &stats, query, shopID, now, limit, offset)
+	return stats, total, err
+}
@@ -0,0 +1,214 @@
+package trending
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"vintage-server/internal/model"
+	"vintage-server/internal/service/staff"
+	"vintage-server/pkg/apperror"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// viewDedupWindow: pengunjung yang sama hanya dihitung sekali per produk dalam window ini
+	viewDedupWindow = 30 * time.Minute
+
+	// trendingLookback adalah rentang sinyal yang dihitung; sinyal lebih lama sudah meluruh hampir nol
+	trendingLookback = 7 * 24 * time.Hour
+
+	defaultTrendingLimit = 20
+	maxTrendingLimit     = 50
+
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// defaultWeights: satu wishlist setara 3 view, satu penjualan setara 10 view, meluruh setengahnya per 24 jam.
+var defaultWeights = ScoreWeights{
+	View:         1,
+	Wishlist:     3,
+	Sale:         10,
+	HalfLife:     24 * time.Hour,
+	PaidStatuses: []int{model.OrderStatusPaid, model.OrderStatusShipped, model.OrderStatusCompleted},
+}
+
+// botMarkers adalah potongan user agent crawler / preview link / HTTP client yang tidak dihitung sebagai view.
+var botMarkers = []string{
+	"bot", "crawler", "spider", "slurp", "crawl", "preview", "facebookexternalhit",
+	"headless", "lighthouse", "curl", "wget", "python-requests", "go-http-client", "okhttp",
+}
+
+// service adalah struct yang akan mengimplementasikan interface Service dari domain.go
+type service struct {
+	repo   Repository
+	buffer *viewBuffer
+}
+
+// NewService adalah constructor untuk service
+func NewService(repo Repository) Service {
+	return &service{
+		repo:   repo,
+		buffer: newViewBuffer(viewDedupWindow),
+	}
+}
+
+func (s *service) RecordView(ctx context.Context, event ViewEvent) (bool, error) {
+	if isBot(event.UserAgent) {
+		return false, nil
+	}
+	if event.SessionKey == "" {
+		return false, apperror.New(apperror.ErrCodeValidation, "session is required")
+	}
+	return s.buffer.add(event.SessionKey, event.ProductID, time.Now()), nil
+}
+
+func (s *service) FlushViews(ctx context.Context) (int, error) {
+	counts := s.buffer.drain(time.Now())
+	if len(counts) == 0 {
+		return 0, nil
+	}
+
+	written, err := s.repo.SaveViewCounts(ctx, counts)
+	if err != nil {
+		s.buffer.restore(counts)
+		return 0, fmt.Errorf("save view counts: %w", err)
+	}
+	return written, nil
+}
+
+func (s *service) RebuildTrending(ctx context.Context) error {
+	now := time.Now()
+	if err := s.repo.TransactionRebuildTrending(ctx, defaultWeights, now.Add(-trendingLookback), now); err != nil {
+		return fmt.Errorf("rebuild trending: %w", err)
+	}
+	return nil
+}
+
+func (s *service) GetTrending(ctx context.Context, filter TrendingFilter) (TrendingList, error) {
+	limit := filter.Limit
+	if limit < 1 {
+		limit = defaultTrendingLimit
+	}
+	if limit > maxTrendingLimit {
+		limit = maxTrendingLimit
+	}
+
+	var result TrendingList
+	if filter.CategoryID != nil {
+		category, err := s.repo.FindCategory(ctx, *filter.CategoryID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return TrendingList{}, apperror.New(apperror.ErrCodeNotFound, "category not found")
+			}
+			log.Printf("Error finding category: %v", err)
+			return TrendingList{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+		}
+		result.Category = &category
+	}
+
+	items, err := s.repo.FindTrending(ctx, filter.CategoryID, limit)
+	if err != nil {
+		log.Printf("Error finding trending products: %v", err)
+		return TrendingList{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	result.Items = items
+	return result, nil
+}
+
+func (s *service) GetListingViewStats(ctx context.Context, sellerID uuid.UUID, filter ViewStatsFilter) (ListingViewStatsPage, error) {
+	member, err := s.repo.FindShopMembership(ctx, sellerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ListingViewStatsPage{}, apperror.New(apperror.ErrCodeForbidden, "open a shop before listing products")
+		}
+		log.Printf("Error finding seller shop: %v", err)
+		return ListingViewStatsPage{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	if !staff.Can(member.Role, staff.PermissionListings) {
+		return ListingViewStatsPage{}, apperror.New(apperror.ErrCodeForbidden, "your shop role does not allow managing listings")
+	}
+	shopID := member.ShopID
+
+	page, limit := normalizePage(filter.Page, filter.Limit)
+	items, total, err := s.repo.FindListingViewStats(ctx, shopID, time.Now(), limit, (page-1)*limit)
+	if err != nil {
+		log.Printf("Error finding listing view stats: %v", err)
+		return ListingViewStatsPage{}, apperror.New(apperror.ErrCodeInternal, "an internal error occurred")
+	}
+	return ListingViewStatsPage{Items: items, Total: total, Page: page, Limit: limit}, nil
+}
+
+// isBot mengenali crawler dari user agent; user agent kosong juga dianggap bukan browser.
+func isBot(userAgent string) bool {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if ua == "" {
+		return true
+	}
+	for _, marker := range botMarkers {
+		if strings.Contains(ua, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	return page, limit
+}
+
+// StartViewFlusher menulis buffer view ke database secara berkala. Saat ctx dibatalkan,
+// sisa buffer di-flush sekali lagi supaya view tidak hilang ketika service dimatikan.
+func StartViewFlusher(ctx context.Context, svc Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if _, err := svc.FlushViews(context.Background()); err != nil {
+				log.Printf("Error flushing product views: %v", err)
+			}
+			return
+		case <-ticker.C:
+			if _, err := svc.FlushViews(ctx); err != nil {
+				log.Printf("Error flushing product views: %v", err)
+			}
+		}
+	}
+}
+
+// StartTrendingJob menjalankan RebuildTrending sekali saat start, lalu berkala sampai ctx dibatalkan.
+func StartTrendingJob(ctx context.Context, svc Service, interval time.Duration) {
+	if err := svc.RebuildTrending(ctx); err != nil {
+		log.Printf("Error rebuilding trending products: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := svc.RebuildTrending(ctx); err != nil {
+				log.Printf("Error rebuilding trending products: %v", err)
+			}
+		}
+	}
+}
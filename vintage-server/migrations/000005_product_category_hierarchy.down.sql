@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_product_categories_parent_id;
+DROP INDEX IF EXISTS idx_product_categories_slug;
+
+ALTER TABLE product_categories
+    DROP CONSTRAINT IF EXISTS chk_product_categories_parent,
+    DROP COLUMN slug,
+    DROP COLUMN parent_id;
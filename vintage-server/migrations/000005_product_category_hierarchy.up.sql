@@ -0,0 +1,15 @@
+-- 000005 hierarchical product categories (parent_id + slug)
+ALTER TABLE product_categories
+    ADD COLUMN parent_id INT REFERENCES product_categories(id),
+    ADD COLUMN slug VARCHAR(160);
+
+-- Backfill slug untuk data lama, id ditambahkan supaya dijamin unik
+UPDATE product_categories
+SET slug = trim(both '-' from lower(regexp_replace(name, '[^a-zA-Z0-9]+', '-', 'g'))) || '-' || id;
+
+ALTER TABLE product_categories
+    ALTER COLUMN slug SET NOT NULL,
+    ADD CONSTRAINT chk_product_categories_parent CHECK (parent_id IS NULL OR parent_id <> id);
+
+CREATE UNIQUE INDEX idx_product_categories_slug ON product_categories (slug);
+CREATE INDEX idx_product_categories_parent_id ON product_categories (parent_id);
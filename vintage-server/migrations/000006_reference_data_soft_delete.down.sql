@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS idx_products_condition_id;
+DROP INDEX IF EXISTS idx_product_size_name_active;
+DROP INDEX IF EXISTS idx_product_conditions_name_active;
+DROP INDEX IF EXISTS idx_brands_name_active;
+
+ALTER TABLE product_size ADD CONSTRAINT product_size_size_name_key UNIQUE (size_name);
+ALTER TABLE brands ADD CONSTRAINT brands_name_key UNIQUE (name);
+
+ALTER TABLE product_size
+    DROP COLUMN deleted_at,
+    DROP COLUMN updated_at,
+    DROP COLUMN created_at;
+ALTER TABLE product_categories DROP COLUMN deleted_at;
+ALTER TABLE product_conditions DROP COLUMN deleted_at;
+ALTER TABLE brands DROP COLUMN deleted_at;
@@ -0,0 +1,52 @@
+-- 000006 soft delete untuk data referensi produk (brands, conditions, sizes, categories)
+ALTER TABLE brands ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE product_conditions ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE product_categories ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE product_size
+    ADD COLUMN created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Nama unik hanya berlaku untuk data yang belum dihapus, supaya nama bisa dipakai ulang
+ALTER TABLE brands DROP CONSTRAINT brands_name_key;
+ALTER TABLE product_size DROP CONSTRAINT product_size_size_name_key;
+
+-- Sebelumnya brands dan product_size unik secara case-sensitive, dan product_conditions
+-- tidak punya constraint unik sama sekali, jadi data lama bisa memuat "Levi's" dan "LEVI'S"
+-- atau nama kondisi ganda. Duplikat digabung ke baris dengan id terkecil: produk dipindah
+-- ke baris tersebut lalu duplikatnya di-soft delete, supaya index unik di bawah bisa dibuat.
+WITH ranked AS (
+    SELECT id, MIN(id) OVER (PARTITION BY lower(name)) AS keep_id FROM brands
+)
+UPDATE products p SET brand_id = r.keep_id
+FROM ranked r
+WHERE p.brand_id = r.id AND r.id <> r.keep_id;
+
+UPDATE brands b SET deleted_at = CURRENT_TIMESTAMP
+WHERE EXISTS (SELECT 1 FROM brands k WHERE lower(k.name) = lower(b.name) AND k.id < b.id);
+
+WITH ranked AS (
+    SELECT id, MIN(id) OVER (PARTITION BY lower(name)) AS keep_id FROM product_conditions
+)
+UPDATE products p SET condition_id = r.keep_id
+FROM ranked r
+WHERE p.condition_id = r.id AND r.id <> r.keep_id;
+
+UPDATE product_conditions c SET deleted_at = CURRENT_TIMESTAMP
+WHERE EXISTS (SELECT 1 FROM product_conditions k WHERE lower(k.name) = lower(c.name) AND k.id < c.id);
+
+WITH ranked AS (
+    SELECT id, MIN(id) OVER (PARTITION BY lower(size_name)) AS keep_id FROM product_size
+)
+UPDATE products p SET size_id = r.keep_id
+FROM ranked r
+WHERE p.size_id = r.id AND r.id <> r.keep_id;
+
+UPDATE product_size s SET deleted_at = CURRENT_TIMESTAMP
+WHERE EXISTS (SELECT 1 FROM product_size k WHERE lower(k.size_name) = lower(s.size_name) AND k.id < s.id);
+
+CREATE UNIQUE INDEX idx_brands_name_active ON brands (lower(name)) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_product_conditions_name_active ON product_conditions (lower(name)) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_product_size_name_active ON product_size (lower(size_name)) WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_products_condition_id ON products (condition_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_cart_items_cart_product;
+DROP TABLE IF EXISTS inventory_holds;
@@ -0,0 +1,22 @@
+-- 000007 inventory holds: stok yang sedang di-checkout dikunci per pembeli dengan TTL
+-- Stok tersedia = products.stock - SUM(quantity hold 'active' yang belum kedaluwarsa)
+CREATE TABLE inventory_holds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    order_id UUID REFERENCES orders(id) ON DELETE SET NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    status VARCHAR(16) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'released', 'expired', 'converted')),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Partial index: hanya hold aktif yang ikut dihitung & disapu sweeper
+CREATE INDEX idx_inventory_holds_product_active ON inventory_holds (product_id) WHERE status = 'active';
+CREATE INDEX idx_inventory_holds_expires_active ON inventory_holds (expires_at) WHERE status = 'active';
+CREATE INDEX idx_inventory_holds_order_id ON inventory_holds (order_id);
+
+-- Satu produk hanya boleh muncul sekali di keranjang yang sama
+CREATE UNIQUE INDEX idx_cart_items_cart_product ON cart_items (cart_id, product_id);
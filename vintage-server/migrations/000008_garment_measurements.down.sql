@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS account_measurements;
+DROP TABLE IF EXISTS product_measurements;
+DROP TABLE IF EXISTS category_measurement_fields;
+DROP TABLE IF EXISTS measurement_fields;
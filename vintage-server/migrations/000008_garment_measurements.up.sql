@@ -0,0 +1,51 @@
+-- 000008 ukuran garmen terstruktur (dalam cm) untuk pencarian berdasarkan fit
+CREATE TABLE measurement_fields (
+    key VARCHAR(32) PRIMARY KEY,
+    label VARCHAR(64) NOT NULL,
+    min_cm NUMERIC(5,1) NOT NULL,
+    max_cm NUMERIC(5,1) NOT NULL,
+    -- selisih maksimal (cm) antara ukuran produk dan ukuran pembeli agar dianggap "fits you"
+    fit_tolerance_cm NUMERIC(4,1) NOT NULL DEFAULT 2.0,
+    CHECK (min_cm >= 0 AND max_cm > min_cm)
+);
+
+INSERT INTO measurement_fields (key, label, min_cm, max_cm, fit_tolerance_cm)
+VALUES
+  ('chest', 'Chest (pit to pit)', 20, 100, 2.0),
+  ('length', 'Length', 20, 150, 3.0),
+  ('shoulder', 'Shoulder', 20, 80, 2.0),
+  ('sleeve', 'Sleeve', 5, 100, 3.0),
+  ('waist', 'Waist (flat)', 20, 80, 1.5),
+  ('hip', 'Hip (flat)', 25, 90, 2.0),
+  ('inseam', 'Inseam', 20, 110, 3.0),
+  ('rise', 'Front rise', 10, 50, 2.0),
+  ('thigh', 'Thigh', 15, 50, 2.0),
+  ('leg_opening', 'Leg opening', 8, 40, 2.0);
+
+-- Skema ukuran per kategori. Sub-kategori tanpa skema sendiri mewarisi skema leluhur terdekat.
+CREATE TABLE category_measurement_fields (
+    category_id INT NOT NULL REFERENCES product_categories(id) ON DELETE CASCADE,
+    field_key VARCHAR(32) NOT NULL REFERENCES measurement_fields(key),
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    position SMALLINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (category_id, field_key)
+);
+
+CREATE TABLE product_measurements (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    field_key VARCHAR(32) NOT NULL REFERENCES measurement_fields(key),
+    value_cm NUMERIC(5,1) NOT NULL CHECK (value_cm > 0),
+    PRIMARY KEY (product_id, field_key)
+);
+
+-- Ukuran garmen yang pas di badan pembeli, dipakai untuk flag "likely fits you"
+CREATE TABLE account_measurements (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    field_key VARCHAR(32) NOT NULL REFERENCES measurement_fields(key),
+    value_cm NUMERIC(5,1) NOT NULL CHECK (value_cm > 0),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account_id, field_key)
+);
+
+-- Untuk filter "chest antara 52 dan 56 cm"
+CREATE INDEX idx_product_measurements_field_value ON product_measurements (field_key, value_cm);
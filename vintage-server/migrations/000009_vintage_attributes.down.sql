@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS product_tags;
+DROP TABLE IF EXISTS tag_synonyms;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS product_materials;
+
+DROP INDEX IF EXISTS idx_products_label_type_id;
+DROP INDEX IF EXISTS idx_products_country_of_origin;
+DROP INDEX IF EXISTS idx_products_era_decade;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS label_type_id,
+    DROP COLUMN IF EXISTS country_of_origin,
+    DROP COLUMN IF EXISTS era_decade;
+
+DROP TABLE IF EXISTS materials;
+DROP TABLE IF EXISTS label_types;
@@ -0,0 +1,67 @@
+-- 000009 atribut vintage terstruktur (era, negara pembuat, bahan, jenis label) dan tag bebas
+CREATE TABLE label_types (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO label_types (name)
+VALUES ('Woven'), ('Printed'), ('Screen printed'), ('Tagless'), ('Cut / removed'), ('No label');
+
+CREATE TABLE materials (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO materials (name)
+VALUES ('Cotton'), ('Polyester'), ('Wool'), ('Silk'), ('Linen'), ('Denim'), ('Leather'),
+       ('Suede'), ('Nylon'), ('Rayon'), ('Acrylic'), ('Cashmere'), ('Spandex'), ('Corduroy');
+
+-- era_decade disimpan sebagai tahun awal dekade (1980 = 80-an), country_of_origin kode ISO 3166-1 alpha-2
+ALTER TABLE products
+    ADD COLUMN era_decade SMALLINT CHECK (era_decade % 10 = 0 AND era_decade BETWEEN 1900 AND 2090),
+    ADD COLUMN country_of_origin CHAR(2) CHECK (country_of_origin ~ '^[A-Z]{2}$'),
+    ADD COLUMN label_type_id INT REFERENCES label_types(id);
+
+CREATE INDEX idx_products_era_decade ON products (era_decade);
+CREATE INDEX idx_products_country_of_origin ON products (country_of_origin);
+CREATE INDEX idx_products_label_type_id ON products (label_type_id);
+
+-- Komposisi bahan, misal 50% cotton / 50% polyester
+CREATE TABLE product_materials (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    material_id INT NOT NULL REFERENCES materials(id),
+    percentage SMALLINT CHECK (percentage BETWEEN 1 AND 100),
+    PRIMARY KEY (product_id, material_id)
+);
+
+CREATE INDEX idx_product_materials_material_id ON product_materials (material_id);
+
+-- Nama tag selalu disimpan dalam bentuk ternormalisasi (huruf kecil, spasi tunggal)
+CREATE TABLE tags (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Alias yang otomatis diarahkan ke tag kanonik, misal 'single stitched' -> 'single stitch'
+CREATE TABLE tag_synonyms (
+    alias VARCHAR(64) PRIMARY KEY,
+    tag_id INT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_tag_synonyms_tag_id ON tag_synonyms (tag_id);
+
+-- Untuk browse/autocomplete tag berdasarkan prefix (LIKE 'den%')
+CREATE INDEX idx_tags_name_pattern ON tags (name varchar_pattern_ops);
+CREATE INDEX idx_tag_synonyms_alias_pattern ON tag_synonyms (alias varchar_pattern_ops);
+
+CREATE TABLE product_tags (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    tag_id INT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (product_id, tag_id)
+);
+
+CREATE INDEX idx_product_tags_tag_id ON product_tags (tag_id);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS job_checkpoints;
+DROP TABLE IF EXISTS pending_price_alerts;
+DROP TABLE IF EXISTS notifications;
+
+DROP TRIGGER IF EXISTS trg_products_price_history ON products;
+DROP FUNCTION IF EXISTS record_product_price_change();
+DROP TABLE IF EXISTS product_price_history;
@@ -0,0 +1,69 @@
+-- 000010 riwayat harga produk, notifikasi, dan antrian alert penurunan harga
+CREATE TABLE product_price_history (
+    id BIGSERIAL PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    -- NULL untuk harga awal saat produk dibuat
+    old_price BIGINT,
+    new_price BIGINT NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_product_price_history_product ON product_price_history (product_id, changed_at);
+
+-- Dicatat lewat trigger supaya semua jalur perubahan harga (edit seller, import, dll.) ikut terekam
+CREATE OR REPLACE FUNCTION record_product_price_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO product_price_history (product_id, old_price, new_price)
+        VALUES (NEW.id, NULL, NEW.price);
+    ELSIF NEW.price IS DISTINCT FROM OLD.price THEN
+        INSERT INTO product_price_history (product_id, old_price, new_price)
+        VALUES (NEW.id, OLD.price, NEW.price);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_products_price_history
+    AFTER INSERT OR UPDATE OF price ON products
+    FOR EACH ROW EXECUTE FUNCTION record_product_price_change();
+
+-- Harga saat ini sebagai titik awal riwayat produk yang sudah ada
+INSERT INTO product_price_history (product_id, old_price, new_price, changed_at)
+SELECT id, NULL, price, COALESCE(created_at, CURRENT_TIMESTAMP) FROM products;
+
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    type VARCHAR(32) NOT NULL,
+    title VARCHAR(128) NOT NULL,
+    body TEXT NOT NULL,
+    data JSONB,
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_notifications_account_created ON notifications (account_id, created_at DESC);
+CREATE INDEX idx_notifications_account_type_created ON notifications (account_id, type, created_at DESC);
+
+-- Penurunan harga yang belum dikirim. Satu baris per (akun, produk); old_price adalah harga
+-- sebelum penurunan pertama sehingga beberapa kali repricing tetap jadi satu alert.
+CREATE TABLE pending_price_alerts (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    old_price BIGINT NOT NULL,
+    new_price BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account_id, product_id)
+);
+
+-- Posisi terakhir background job yang membaca tabel append-only (misal product_price_history)
+CREATE TABLE job_checkpoints (
+    name VARCHAR(64) PRIMARY KEY,
+    last_id BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Backfill di atas tidak boleh memicu alert
+INSERT INTO job_checkpoints (name, last_id)
+SELECT 'price_drop_alerts', COALESCE(MAX(id), 0) FROM product_price_history;
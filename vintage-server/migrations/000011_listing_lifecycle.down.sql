@@ -0,0 +1,37 @@
+CREATE OR REPLACE FUNCTION record_product_price_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO product_price_history (product_id, old_price, new_price)
+        VALUES (NEW.id, NULL, NEW.price);
+    ELSIF NEW.price IS DISTINCT FROM OLD.price THEN
+        INSERT INTO product_price_history (product_id, old_price, new_price)
+        VALUES (NEW.id, OLD.price, NEW.price);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TABLE IF EXISTS product_status_logs;
+
+DROP INDEX IF EXISTS idx_products_pending_review;
+DROP INDEX IF EXISTS idx_products_shop_status;
+DROP INDEX IF EXISTS idx_products_status_created;
+
+-- Draft yang belum lengkap tidak bisa dikembalikan ke skema lama
+DELETE FROM products
+WHERE condition_id IS NULL OR category_id IS NULL OR size_id IS NULL OR price IS NULL;
+
+ALTER TABLE products
+    DROP CONSTRAINT IF EXISTS products_complete_unless_draft,
+    ALTER COLUMN stock DROP DEFAULT,
+    ALTER COLUMN price SET NOT NULL,
+    ALTER COLUMN size_id SET NOT NULL,
+    ALTER COLUMN category_id SET NOT NULL,
+    ALTER COLUMN condition_id SET NOT NULL;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS sold_at,
+    DROP COLUMN IF EXISTS published_at,
+    DROP COLUMN IF EXISTS submitted_at,
+    DROP COLUMN IF EXISTS moderation_note,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,61 @@
+-- 000011 siklus hidup listing: draft -> pending_review -> published -> sold / archived
+ALTER TABLE products
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'pending_review', 'published', 'sold', 'archived')),
+    -- alasan penolakan terakhir dari moderator, dikosongkan saat disetujui
+    ADD COLUMN moderation_note TEXT,
+    ADD COLUMN submitted_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN published_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN sold_at TIMESTAMP WITH TIME ZONE;
+
+-- Produk lama dianggap sudah tayang (atau terjual jika stok habis)
+UPDATE products SET status = 'published', published_at = created_at WHERE stock > 0;
+UPDATE products SET status = 'sold', published_at = created_at, sold_at = updated_at WHERE stock = 0;
+
+-- Draft boleh belum lengkap, validasi ketat hanya berlaku setelah disubmit
+ALTER TABLE products
+    ALTER COLUMN condition_id DROP NOT NULL,
+    ALTER COLUMN category_id DROP NOT NULL,
+    ALTER COLUMN size_id DROP NOT NULL,
+    ALTER COLUMN price DROP NOT NULL,
+    ALTER COLUMN stock SET DEFAULT 1,
+    ADD CONSTRAINT products_complete_unless_draft CHECK (
+        status = 'draft' OR (
+            condition_id IS NOT NULL AND category_id IS NOT NULL
+            AND size_id IS NOT NULL AND price IS NOT NULL
+        )
+    );
+
+CREATE INDEX idx_products_status_created ON products (status, created_at DESC);
+CREATE INDEX idx_products_shop_status ON products (shop_id, status);
+CREATE INDEX idx_products_pending_review ON products (submitted_at) WHERE status = 'pending_review';
+
+CREATE TABLE product_status_logs (
+    id BIGSERIAL PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    from_status VARCHAR(16),
+    to_status VARCHAR(16) NOT NULL,
+    reason TEXT,
+    -- NULL jika perubahan dilakukan sistem (misal otomatis 'sold' saat stok habis)
+    created_by UUID REFERENCES accounts(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_product_status_logs_product ON product_status_logs (product_id, created_at);
+
+-- Draft boleh belum punya harga: riwayat harga baru dimulai saat harga pertama kali diisi
+CREATE OR REPLACE FUNCTION record_product_price_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.price IS NULL THEN
+        RETURN NEW;
+    END IF;
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO product_price_history (product_id, old_price, new_price)
+        VALUES (NEW.id, NULL, NEW.price);
+    ELSIF NEW.price IS DISTINCT FROM OLD.price THEN
+        INSERT INTO product_price_history (product_id, old_price, new_price)
+        VALUES (NEW.id, OLD.price, NEW.price);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS collection_items;
+DROP TABLE IF EXISTS collections;
+DROP TABLE IF EXISTS shop_followers;
+DROP INDEX IF EXISTS idx_products_shop_published_at;
+DROP INDEX IF EXISTS idx_products_published_at;
@@ -0,0 +1,39 @@
+-- 000012 feed beranda: new arrivals, shop yang diikuti, dan koleksi editorial
+CREATE INDEX idx_products_published_at ON products (published_at DESC) WHERE status = 'published';
+CREATE INDEX idx_products_shop_published_at ON products (shop_id, published_at DESC) WHERE status = 'published';
+
+CREATE TABLE shop_followers (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    shop_id UUID NOT NULL REFERENCES shop(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account_id, shop_id)
+);
+
+CREATE INDEX idx_shop_followers_shop ON shop_followers (shop_id);
+
+-- Koleksi kurasi admin, misal "90s Denim Edit". Tampil hanya di dalam jendela starts_at..ends_at
+-- (NULL berarti tanpa batas) dan diurutkan berdasarkan position.
+CREATE TABLE collections (
+    id SERIAL PRIMARY KEY,
+    slug VARCHAR(96) NOT NULL UNIQUE,
+    title VARCHAR(96) NOT NULL,
+    description TEXT,
+    cover_image_url VARCHAR(255),
+    position INT NOT NULL DEFAULT 0,
+    starts_at TIMESTAMP WITH TIME ZONE,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT collections_window CHECK (starts_at IS NULL OR ends_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX idx_collections_position ON collections (position, id);
+
+CREATE TABLE collection_items (
+    collection_id INT NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    PRIMARY KEY (collection_id, product_id)
+);
+
+CREATE INDEX idx_collection_items_position ON collection_items (collection_id, position);
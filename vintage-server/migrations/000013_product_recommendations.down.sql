@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_wishlist_product_id;
+DROP TABLE IF EXISTS product_neighbors;
@@ -0,0 +1,16 @@
+-- 000013 rekomendasi produk yang dihitung ulang secara berkala oleh job
+CREATE TABLE product_neighbors (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    -- similar, also_wishlisted, bought_together
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('similar', 'also_wishlisted', 'bought_together')),
+    neighbor_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    score REAL NOT NULL,
+    rank SMALLINT NOT NULL,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (product_id, kind, neighbor_id)
+);
+
+CREATE INDEX idx_product_neighbors_rank ON product_neighbors (product_id, kind, rank);
+
+-- Co-occurrence wishlist dihitung dari sisi produk
+CREATE INDEX IF NOT EXISTS idx_wishlist_product_id ON wishlist (product_id);
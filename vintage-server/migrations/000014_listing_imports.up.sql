@@ -0,0 +1,30 @@
+-- 000014 import listing massal (csv / xlsx) yang diproses job background secara bertahap
+CREATE TABLE listing_imports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    shop_id UUID NOT NULL REFERENCES shop(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    format VARCHAR(8) NOT NULL CHECK (format IN ('csv', 'xlsx')),
+    file_name VARCHAR(255) NOT NULL,
+    -- File asli disimpan supaya job bisa dilanjutkan setelah restart
+    source BYTEA NOT NULL,
+    images BYTEA,
+    -- header kolom -> field produk, hasil gabungan alias bawaan dan mapping dari seller
+    mapping JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(16) NOT NULL DEFAULT 'queued'
+        CHECK (status IN ('queued', 'running', 'completed', 'failed')),
+    total_rows INT NOT NULL DEFAULT 0,
+    -- next_row adalah indeks baris data berikutnya yang akan diproses (0-based, tanpa header)
+    next_row INT NOT NULL DEFAULT 0,
+    created_count INT NOT NULL DEFAULT 0,
+    failed_count INT NOT NULL DEFAULT 0,
+    errors JSONB NOT NULL DEFAULT '[]',
+    last_error TEXT,
+    attempts INT NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_listing_imports_shop ON listing_imports (shop_id, created_at DESC);
+CREATE INDEX idx_listing_imports_pending ON listing_imports (created_at) WHERE status IN ('queued', 'running');
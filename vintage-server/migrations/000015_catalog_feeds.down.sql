@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS generated_artifacts;
+ALTER TABLE product_conditions DROP COLUMN IF EXISTS google_condition;
@@ -0,0 +1,19 @@
+-- 000015 sitemap & product feed yang dibangkitkan job dan disajikan dari database
+ALTER TABLE product_conditions
+    ADD COLUMN google_condition VARCHAR(16) NOT NULL DEFAULT 'used'
+        CHECK (google_condition IN ('new', 'refurbished', 'used'));
+
+-- Tebakan awal, admin bisa mengubahnya lewat endpoint kondisi
+UPDATE product_conditions SET google_condition = 'new'
+WHERE name ILIKE '%new%' OR name ILIKE '%baru%';
+
+-- Satu baris per file (sitemap.xml, sitemaps/products-1.xml, feeds/products.xml, ...).
+-- fingerprint merangkum data sumber sehingga job hanya menulis ulang file yang berubah.
+CREATE TABLE generated_artifacts (
+    name VARCHAR(128) PRIMARY KEY,
+    content_type VARCHAR(64) NOT NULL,
+    content BYTEA NOT NULL,
+    etag VARCHAR(64) NOT NULL,
+    fingerprint VARCHAR(128) NOT NULL,
+    generated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
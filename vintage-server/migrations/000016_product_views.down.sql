@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_order_items_created_at;
+DROP INDEX IF EXISTS idx_wishlist_created_at;
+DROP TABLE IF EXISTS product_trending;
+DROP TABLE IF EXISTS product_view_hourly;
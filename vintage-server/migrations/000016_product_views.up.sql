@@ -0,0 +1,26 @@
+-- 000016 view tracking (counter per jam) dan peringkat trending yang dihitung job
+CREATE TABLE product_view_hourly (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    hour TIMESTAMP WITH TIME ZONE NOT NULL,
+    views INT NOT NULL CHECK (views >= 0),
+    PRIMARY KEY (product_id, hour)
+);
+
+CREATE INDEX idx_product_view_hourly_hour ON product_view_hourly (hour);
+
+-- Hasil job trending; diganti penuh setiap kali job berjalan
+CREATE TABLE product_trending (
+    product_id UUID PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+    category_id INT NOT NULL REFERENCES product_categories(id),
+    score REAL NOT NULL,
+    views INT NOT NULL DEFAULT 0,
+    wishlist_adds INT NOT NULL DEFAULT 0,
+    sales INT NOT NULL DEFAULT 0,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_product_trending_score ON product_trending (score DESC);
+CREATE INDEX idx_product_trending_category_score ON product_trending (category_id, score DESC);
+
+CREATE INDEX idx_wishlist_created_at ON wishlist (created_at);
+CREATE INDEX idx_order_items_created_at ON order_items (created_at);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS shop_slug_history;
+DROP TABLE IF EXISTS product_slug_history;
+
+DROP INDEX IF EXISTS idx_shop_slug;
+DROP INDEX IF EXISTS idx_products_slug;
+
+ALTER TABLE shop DROP COLUMN IF EXISTS slug;
+ALTER TABLE products DROP COLUMN IF EXISTS slug;
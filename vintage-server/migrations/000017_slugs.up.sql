@@ -0,0 +1,52 @@
+-- 000017 slug produk & shop, beserta slug lama untuk redirect setelah nama berubah
+ALTER TABLE products ADD COLUMN slug VARCHAR(160);
+ALTER TABLE shop ADD COLUMN slug VARCHAR(80);
+
+-- Backfill data lama tanpa transliterasi (slug baru dibuat aplikasi lewat pkg/slug).
+-- Nama yang bentrok diberi suffix potongan id supaya dijamin unik.
+WITH base AS (
+    SELECT id, created_at,
+        COALESCE(NULLIF(trim(both '-' from left(trim(both '-' from lower(regexp_replace(name, '[^a-zA-Z0-9]+', '-', 'g'))), 140)), ''), 'product') AS slug
+    FROM products
+), ranked AS (
+    SELECT id, slug, ROW_NUMBER() OVER (PARTITION BY slug ORDER BY created_at, id) AS rn FROM base
+)
+UPDATE products p
+SET slug = CASE WHEN r.rn = 1 THEN r.slug ELSE r.slug || '-' || left(p.id::text, 8) END
+FROM ranked r
+WHERE r.id = p.id;
+
+WITH base AS (
+    SELECT id, created_at,
+        COALESCE(NULLIF(trim(both '-' from left(trim(both '-' from lower(regexp_replace(name, '[^a-zA-Z0-9]+', '-', 'g'))), 64)), ''), 'shop') AS slug
+    FROM shop
+), ranked AS (
+    SELECT id, slug, ROW_NUMBER() OVER (PARTITION BY slug ORDER BY created_at, id) AS rn FROM base
+)
+UPDATE shop s
+SET slug = CASE WHEN r.rn = 1 THEN r.slug ELSE r.slug || '-' || left(s.id::text, 8) END
+FROM ranked r
+WHERE r.id = s.id;
+
+ALTER TABLE products ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE shop ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX idx_products_slug ON products (slug);
+CREATE UNIQUE INDEX idx_shop_slug ON shop (slug);
+
+-- Slug lama tidak boleh dipakai entitas lain supaya redirect tetap menuju pemilik aslinya
+CREATE TABLE product_slug_history (
+    slug VARCHAR(160) PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_product_slug_history_product_id ON product_slug_history (product_id);
+
+CREATE TABLE shop_slug_history (
+    slug VARCHAR(80) PRIMARY KEY,
+    shop_id UUID NOT NULL REFERENCES shop(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_shop_slug_history_shop_id ON shop_slug_history (shop_id);
@@ -0,0 +1,35 @@
+ALTER TABLE product_status_logs
+    DROP CONSTRAINT product_status_logs_created_by_fkey,
+    ADD CONSTRAINT product_status_logs_created_by_fkey FOREIGN KEY (created_by) REFERENCES accounts(id);
+
+ALTER TABLE inventory_holds
+    DROP CONSTRAINT inventory_holds_account_id_fkey,
+    ADD CONSTRAINT inventory_holds_account_id_fkey FOREIGN KEY (account_id) REFERENCES accounts(id),
+    DROP CONSTRAINT inventory_holds_product_id_fkey,
+    ADD CONSTRAINT inventory_holds_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id);
+
+ALTER TABLE addresses
+    DROP CONSTRAINT addresses_account_id_fkey,
+    ADD CONSTRAINT addresses_account_id_fkey FOREIGN KEY (account_id) REFERENCES accounts(id);
+
+ALTER TABLE cart
+    DROP CONSTRAINT cart_account_id_fkey,
+    ADD CONSTRAINT cart_account_id_fkey FOREIGN KEY (account_id) REFERENCES accounts(id);
+
+ALTER TABLE cart_items
+    DROP CONSTRAINT cart_items_product_id_fkey,
+    ADD CONSTRAINT cart_items_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id);
+
+ALTER TABLE wishlist
+    DROP CONSTRAINT wishlist_account_id_fkey,
+    ADD CONSTRAINT wishlist_account_id_fkey FOREIGN KEY (account_id) REFERENCES accounts(id),
+    DROP CONSTRAINT wishlist_product_id_fkey,
+    ADD CONSTRAINT wishlist_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id);
+
+DROP INDEX IF EXISTS idx_accounts_deleted_at;
+DROP INDEX IF EXISTS idx_shop_deleted_at;
+DROP INDEX IF EXISTS idx_products_deleted_at;
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE shop DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE products DROP COLUMN IF EXISTS deleted_at;
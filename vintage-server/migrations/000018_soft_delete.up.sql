@@ -0,0 +1,43 @@
+-- 000018 soft delete untuk products, shop dan accounts.
+-- Baris yang dihapus hanya diberi deleted_at; job retensi menghapus permanen setelah masa retensi lewat.
+ALTER TABLE products ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE shop ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE accounts ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Index parsial untuk daftar admin dan job retensi (baris terhapus selalu sedikit)
+CREATE INDEX idx_products_deleted_at ON products (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_shop_deleted_at ON shop (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_accounts_deleted_at ON accounts (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Data turunan yang tidak punya nilai historis ikut terhapus saat purge.
+-- order_items, reviews, orders dan shop.account_id tetap RESTRICT: baris yang masih
+-- dirujuk riwayat transaksi tidak pernah di-purge.
+ALTER TABLE wishlist
+    DROP CONSTRAINT wishlist_product_id_fkey,
+    ADD CONSTRAINT wishlist_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    DROP CONSTRAINT wishlist_account_id_fkey,
+    ADD CONSTRAINT wishlist_account_id_fkey FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE;
+
+ALTER TABLE cart_items
+    DROP CONSTRAINT cart_items_product_id_fkey,
+    ADD CONSTRAINT cart_items_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
+
+ALTER TABLE cart
+    DROP CONSTRAINT cart_account_id_fkey,
+    ADD CONSTRAINT cart_account_id_fkey FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE;
+
+ALTER TABLE addresses
+    DROP CONSTRAINT addresses_account_id_fkey,
+    ADD CONSTRAINT addresses_account_id_fkey FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE;
+
+-- Hold yang terikat order tetap terlindungi oleh order_items; sisanya hanya catatan checkout
+ALTER TABLE inventory_holds
+    DROP CONSTRAINT inventory_holds_product_id_fkey,
+    ADD CONSTRAINT inventory_holds_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    DROP CONSTRAINT inventory_holds_account_id_fkey,
+    ADD CONSTRAINT inventory_holds_account_id_fkey FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE;
+
+-- Riwayat status listing tetap disimpan walaupun akun pelakunya sudah di-purge
+ALTER TABLE product_status_logs
+    DROP CONSTRAINT product_status_logs_created_by_fkey,
+    ADD CONSTRAINT product_status_logs_created_by_fkey FOREIGN KEY (created_by) REFERENCES accounts(id) ON DELETE SET NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE inventory_holds DROP COLUMN IF EXISTS unit_price;
+
+DROP TABLE IF EXISTS offer_events;
+DROP TABLE IF EXISTS offers;
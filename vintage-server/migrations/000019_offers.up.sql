@@ -0,0 +1,39 @@
+-- 000019 make-an-offer: negosiasi harga antara pembeli dan seller
+CREATE TABLE offers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    shop_id UUID NOT NULL REFERENCES shop(id) ON DELETE CASCADE,
+    buyer_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    -- pending: menunggu seller, countered: menunggu pembeli
+    status VARCHAR(16) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    quantity INT NOT NULL DEFAULT 1 CHECK (quantity > 0),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    -- Hold harga khusus pembeli yang dibuat saat offer diterima
+    hold_id UUID REFERENCES inventory_holds(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Satu negosiasi berjalan per pembeli per produk
+CREATE UNIQUE INDEX idx_offers_open_per_buyer ON offers (product_id, buyer_id)
+    WHERE status IN ('pending', 'countered', 'accepted');
+CREATE INDEX idx_offers_buyer_created ON offers (buyer_id, created_at DESC);
+CREATE INDEX idx_offers_shop_created ON offers (shop_id, created_at DESC);
+CREATE INDEX idx_offers_expiry ON offers (expires_at) WHERE status IN ('pending', 'countered', 'accepted');
+
+-- Riwayat lengkap negosiasi (append-only)
+CREATE TABLE offer_events (
+    id BIGSERIAL PRIMARY KEY,
+    offer_id UUID NOT NULL REFERENCES offers(id) ON DELETE CASCADE,
+    action VARCHAR(16) NOT NULL,
+    amount BIGINT,
+    actor_id UUID REFERENCES accounts(id) ON DELETE SET NULL,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_offer_events_offer ON offer_events (offer_id, id);
+
+-- Harga per unit yang disepakati lewat offer; NULL berarti harga listing
+ALTER TABLE inventory_holds ADD COLUMN unit_price BIGINT CHECK (unit_price >= 0);
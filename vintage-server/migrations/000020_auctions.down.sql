@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS auction_bids;
+DROP TABLE IF EXISTS auctions;
+
+ALTER TABLE products DROP COLUMN IF EXISTS listing_mode;
@@ -0,0 +1,47 @@
+-- 000020 lelang berwaktu: produk bisa dijual lewat lelang selain harga tetap
+ALTER TABLE products
+    ADD COLUMN listing_mode VARCHAR(16) NOT NULL DEFAULT 'fixed'
+        CHECK (listing_mode IN ('fixed', 'auction'));
+
+CREATE TABLE auctions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    shop_id UUID NOT NULL REFERENCES shop(id) ON DELETE CASCADE,
+    -- active -> sold (order pemenang dibuat) / unsold (tanpa bid, reserve tidak tercapai) / cancelled
+    status VARCHAR(16) NOT NULL DEFAULT 'active',
+    start_price BIGINT NOT NULL CHECK (start_price > 0),
+    reserve_price BIGINT CHECK (reserve_price >= start_price),
+    bid_increment BIGINT NOT NULL CHECK (bid_increment > 0),
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    -- ends_at bergeser maju saat bid masuk di menit-menit terakhir (anti-sniping)
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL CHECK (ends_at > starts_at),
+    original_ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    extension_count INT NOT NULL DEFAULT 0,
+    -- Harga yang terlihat saat ini dan pemimpin lelang; leader_max adalah bid maksimum (proxy) yang dirahasiakan
+    current_price BIGINT,
+    leader_id UUID REFERENCES accounts(id) ON DELETE SET NULL,
+    leader_max BIGINT,
+    bid_count INT NOT NULL DEFAULT 0,
+    order_id UUID REFERENCES orders(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Satu lelang berjalan per produk
+CREATE UNIQUE INDEX idx_auctions_active_product ON auctions (product_id) WHERE status = 'active';
+CREATE INDEX idx_auctions_ending ON auctions (ends_at) WHERE status = 'active';
+CREATE INDEX idx_auctions_shop_created ON auctions (shop_id, created_at DESC);
+
+-- Riwayat bid lengkap (append-only). is_auto menandai bid yang dipasang sistem atas nama proxy bidder.
+CREATE TABLE auction_bids (
+    id BIGSERIAL PRIMARY KEY,
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    bidder_id UUID REFERENCES accounts(id) ON DELETE SET NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    max_amount BIGINT NOT NULL,
+    is_auto BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_auction_bids_auction ON auction_bids (auction_id, id);
+CREATE INDEX idx_auction_bids_bidder ON auction_bids (bidder_id, created_at DESC);
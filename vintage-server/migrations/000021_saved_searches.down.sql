@@ -0,0 +1,4 @@
+DELETE FROM job_checkpoints WHERE name = 'saved_search_alerts';
+
+DROP TABLE IF EXISTS saved_search_matches;
+DROP TABLE IF EXISTS saved_searches;
@@ -0,0 +1,35 @@
+-- 000021 saved search: pembeli menyimpan filter katalog dan diberi tahu saat listing baru cocok
+CREATE TABLE saved_searches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    -- Filter yang sama dengan query pencarian katalog (ProductFilter tanpa pagination)
+    filter JSONB NOT NULL,
+    alert VARCHAR(16) NOT NULL DEFAULT 'instant' CHECK (alert IN ('instant', 'daily', 'off')),
+    -- Salinan brand & size dari filter untuk menyaring saved search yang perlu dicek per batch produk baru
+    brand_id INT,
+    size_id INT,
+    last_alerted_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_saved_searches_account ON saved_searches (account_id, created_at DESC);
+CREATE INDEX idx_saved_searches_brand ON saved_searches (brand_id) WHERE alert <> 'off';
+CREATE INDEX idx_saved_searches_size ON saved_searches (size_id) WHERE alert <> 'off';
+
+-- Listing baru yang cocok dengan saved search; alerted_at kosong berarti belum dikirim (antrian instant / digest)
+CREATE TABLE saved_search_matches (
+    saved_search_id UUID NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    matched_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    alerted_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (saved_search_id, product_id)
+);
+
+CREATE INDEX idx_saved_search_matches_pending ON saved_search_matches (saved_search_id, matched_at) WHERE alerted_at IS NULL;
+
+-- Pencocokan membaca product_status_logs (perpindahan ke 'published') secara bertahap;
+-- listing yang sudah tayang sebelum fitur ini ada tidak memicu alert
+INSERT INTO job_checkpoints (name, last_id)
+SELECT 'saved_search_alerts', COALESCE(MAX(id), 0) FROM product_status_logs;
@@ -0,0 +1,12 @@
+-- 000022 recently viewed: riwayat listing yang dilihat, per akun ("a:<uuid>") atau sesi anonim ("s:<session id>").
+-- Dibatasi jumlahnya per owner oleh aplikasi; dipakai jika RECENTLY_VIEWED_STORE=postgres (default).
+CREATE TABLE recently_viewed (
+    owner_key VARCHAR(160) NOT NULL,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    viewed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (owner_key, product_id)
+);
+
+CREATE INDEX idx_recently_viewed_owner ON recently_viewed (owner_key, viewed_at DESC);
+-- Untuk job yang membuang riwayat sesi anonim yang sudah lama
+CREATE INDEX idx_recently_viewed_sessions ON recently_viewed (viewed_at) WHERE owner_key LIKE 's:%';
@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS shop_status_logs;
+
+DROP INDEX IF EXISTS idx_shop_status;
+ALTER TABLE shop DROP CONSTRAINT IF EXISTS shop_active_matches_status;
+ALTER TABLE shop ALTER COLUMN active DROP NOT NULL;
+
+ALTER TABLE shop
+    DROP COLUMN IF EXISTS payment_policy,
+    DROP COLUMN IF EXISTS return_policy,
+    DROP COLUMN IF EXISTS shipping_policy,
+    DROP COLUMN IF EXISTS banner_url,
+    DROP COLUMN IF EXISTS logo_url,
+    DROP COLUMN IF EXISTS status_reason,
+    DROP COLUMN IF EXISTS status;
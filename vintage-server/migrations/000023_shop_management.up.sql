@@ -0,0 +1,33 @@
+-- 000023 pengelolaan shop: profil lengkap seller dan siklus review / suspend oleh admin.
+-- Kolom active tetap dipertahankan (dipakai query katalog) dan selalu sama dengan status = 'active'.
+ALTER TABLE shop
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'active', 'suspended')),
+    -- Alasan keputusan admin terakhir, ditampilkan ke seller
+    ADD COLUMN status_reason TEXT,
+    ADD COLUMN logo_url TEXT,
+    ADD COLUMN banner_url TEXT,
+    ADD COLUMN shipping_policy TEXT,
+    ADD COLUMN return_policy TEXT,
+    ADD COLUMN payment_policy TEXT;
+
+UPDATE shop SET status = 'active' WHERE active;
+UPDATE shop SET active = FALSE WHERE active IS NULL;
+
+ALTER TABLE shop ALTER COLUMN active SET NOT NULL;
+ALTER TABLE shop ADD CONSTRAINT shop_active_matches_status CHECK (active = (status = 'active'));
+
+CREATE INDEX idx_shop_status ON shop (status, created_at) WHERE deleted_at IS NULL;
+
+-- Riwayat keputusan admin atas sebuah shop (append-only)
+CREATE TABLE shop_status_logs (
+    id BIGSERIAL PRIMARY KEY,
+    shop_id UUID NOT NULL REFERENCES shop(id) ON DELETE CASCADE,
+    from_status VARCHAR(16) NOT NULL,
+    to_status VARCHAR(16) NOT NULL,
+    reason TEXT,
+    created_by UUID REFERENCES accounts(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_shop_status_logs_shop ON shop_status_logs (shop_id, created_at);
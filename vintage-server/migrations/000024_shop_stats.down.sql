@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_order_status_logs_order_status;
+DROP TABLE IF EXISTS shop_stats;
@@ -0,0 +1,22 @@
+-- 000024 statistik publik shop: dihitung ulang berkala oleh job di shop-service
+-- agar profil shop cukup membaca satu baris.
+CREATE TABLE shop_stats (
+    shop_id UUID PRIMARY KEY REFERENCES shop(id) ON DELETE CASCADE,
+    active_listings INT NOT NULL DEFAULT 0,
+    sold_listings INT NOT NULL DEFAULT 0,
+    rating_count INT NOT NULL DEFAULT 0,
+    -- NULL jika belum ada review
+    rating_average NUMERIC(3, 2),
+    rating_1 INT NOT NULL DEFAULT 0,
+    rating_2 INT NOT NULL DEFAULT 0,
+    rating_3 INT NOT NULL DEFAULT 0,
+    rating_4 INT NOT NULL DEFAULT 0,
+    rating_5 INT NOT NULL DEFAULT 0,
+    -- Porsi offer yang dijawab seller (0..1) dalam jendela statistik; NULL jika belum ada offer
+    response_rate NUMERIC(5, 4),
+    -- Median jam dari order dibayar sampai dikirim dalam jendela statistik; NULL jika belum ada pengiriman
+    median_ship_hours NUMERIC(8, 2),
+    refreshed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_order_status_logs_order_status ON order_status_logs (order_id, new_status);
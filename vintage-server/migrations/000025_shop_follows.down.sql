@@ -0,0 +1,6 @@
+DELETE FROM job_checkpoints WHERE name = 'shop_drop_alerts';
+DROP TABLE IF EXISTS shop_drops;
+DROP INDEX IF EXISTS idx_shop_followers_notify;
+DROP INDEX IF EXISTS idx_shop_followers_account_created;
+ALTER TABLE shop_followers DROP COLUMN IF EXISTS notify;
+ALTER TABLE shop DROP COLUMN IF EXISTS follower_count;
@@ -0,0 +1,31 @@
+-- 000025 follow shop: jumlah follower, feed kronologis, dan notifikasi "drop" listing baru.
+-- follower_count dijaga di transaksi follow / unfollow supaya profil shop tidak perlu COUNT(*).
+ALTER TABLE shop ADD COLUMN follower_count INT NOT NULL DEFAULT 0 CHECK (follower_count >= 0);
+
+UPDATE shop s SET follower_count = (SELECT COUNT(*) FROM shop_followers f WHERE f.shop_id = s.id);
+
+-- notify: follower ingin dikabari saat shop menayangkan listing baru (opt-in)
+ALTER TABLE shop_followers ADD COLUMN notify BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_shop_followers_account_created ON shop_followers (account_id, created_at DESC);
+-- Fan-out notifikasi berjalan per shop dengan keyset account_id
+CREATE INDEX idx_shop_followers_notify ON shop_followers (shop_id, account_id) WHERE notify;
+
+-- Satu "drop" adalah kumpulan listing baru satu shop yang dikabarkan ke follower dalam satu notifikasi.
+-- Fan-out dicicil per potongan follower; last_account_id adalah posisi terakhir yang sudah dikirimi.
+CREATE TABLE shop_drops (
+    id BIGSERIAL PRIMARY KEY,
+    shop_id UUID NOT NULL REFERENCES shop(id) ON DELETE CASCADE,
+    listing_count INT NOT NULL CHECK (listing_count > 0),
+    product_ids UUID[] NOT NULL,
+    last_account_id UUID,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_shop_drops_pending ON shop_drops (id) WHERE completed_at IS NULL;
+
+-- Drop dibentuk dari product_status_logs (perpindahan ke 'published') secara bertahap;
+-- listing yang sudah tayang sebelum fitur ini ada tidak memicu notifikasi
+INSERT INTO job_checkpoints (name, last_id)
+SELECT 'shop_drop_alerts', COALESCE(MAX(id), 0) FROM product_status_logs;
@@ -0,0 +1,12 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS ship_by;
+
+DROP INDEX IF EXISTS idx_shop_vacation_end;
+DROP INDEX IF EXISTS idx_shop_vacation_start;
+ALTER TABLE shop
+    DROP CONSTRAINT IF EXISTS shop_vacation_window,
+    DROP COLUMN IF EXISTS vacation_blocked_at,
+    DROP COLUMN IF EXISTS vacation_pending_orders,
+    DROP COLUMN IF EXISTS vacation_ends_at,
+    DROP COLUMN IF EXISTS vacation_starts_at,
+    DROP COLUMN IF EXISTS vacation_message,
+    DROP COLUMN IF EXISTS vacation_active;
@@ -0,0 +1,25 @@
+-- 000026 mode libur shop: listing tetap bisa dilihat tapi tidak bisa di-checkout / ditawar.
+-- Libur bisa langsung aktif atau dijadwalkan (vacation_starts_at); job shop-service menyalakan
+-- dan mematikannya sesuai jadwal.
+ALTER TABLE shop
+    ADD COLUMN vacation_active BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN vacation_message TEXT,
+    ADD COLUMN vacation_starts_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN vacation_ends_at TIMESTAMP WITH TIME ZONE,
+    -- Perlakuan order yang belum dikirim saat libur dimulai: block (libur ditolak) atau
+    -- extend (batas kirim order digeser sepanjang libur)
+    ADD COLUMN vacation_pending_orders VARCHAR(8) NOT NULL DEFAULT 'block'
+        CHECK (vacation_pending_orders IN ('block', 'extend')),
+    -- Diisi saat libur terjadwal tertahan karena masih ada order (seller sudah dinotifikasi)
+    ADD COLUMN vacation_blocked_at TIMESTAMP WITH TIME ZONE,
+    ADD CONSTRAINT shop_vacation_window CHECK (
+        vacation_starts_at IS NULL OR vacation_ends_at IS NULL OR vacation_ends_at > vacation_starts_at
+    );
+
+CREATE INDEX idx_shop_vacation_start ON shop (vacation_starts_at) WHERE NOT vacation_active AND vacation_starts_at IS NOT NULL;
+CREATE INDEX idx_shop_vacation_end ON shop (vacation_ends_at) WHERE vacation_ends_at IS NOT NULL;
+
+-- Batas waktu seller mengirim order yang sudah dibayar
+ALTER TABLE orders ADD COLUMN ship_by TIMESTAMP WITH TIME ZONE;
+
+UPDATE orders SET ship_by = updated_at + INTERVAL '3 days' WHERE status = 2;
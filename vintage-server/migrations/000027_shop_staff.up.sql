@@ -0,0 +1,38 @@
+-- 000027 staff shop: satu shop bisa dijalankan beberapa akun dengan role berbeda.
+-- shop.account_id tetap menunjuk owner (penerima notifikasi seller); akses fitur seller
+-- dibaca dari shop_members. Satu akun hanya bisa menjadi anggota satu shop.
+CREATE TABLE shop_members (
+    shop_id UUID NOT NULL REFERENCES shop(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    role VARCHAR(16) NOT NULL CHECK (role IN ('owner', 'manager', 'lister', 'fulfillment')),
+    invited_by UUID REFERENCES accounts(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (shop_id, account_id),
+    CONSTRAINT shop_members_account_key UNIQUE (account_id)
+);
+
+CREATE UNIQUE INDEX idx_shop_members_owner ON shop_members (shop_id) WHERE role = 'owner';
+
+INSERT INTO shop_members (shop_id, account_id, role, created_at)
+SELECT id, account_id, 'owner', created_at FROM shop;
+
+-- Undangan ditujukan ke alamat email; diterima oleh akun yang login dengan email tersebut.
+CREATE TABLE shop_invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    shop_id UUID NOT NULL REFERENCES shop(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(16) NOT NULL CHECK (role IN ('manager', 'lister', 'fulfillment')),
+    invited_by UUID REFERENCES accounts(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    declined_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Satu undangan terbuka per email per shop; mengundang ulang memperbarui undangan yang ada
+CREATE UNIQUE INDEX idx_shop_invitations_open ON shop_invitations (shop_id, lower(email))
+    WHERE accepted_at IS NULL AND declined_at IS NULL AND revoked_at IS NULL;
+CREATE INDEX idx_shop_invitations_email ON shop_invitations (lower(email), created_at DESC);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS kyc_submissions;
+
+ALTER TABLE shop
+    DROP COLUMN IF EXISTS kyc_verified_at,
+    DROP COLUMN IF EXISTS kyc_status;
@@ -0,0 +1,34 @@
+-- 000028 verifikasi identitas (KYC) seller. Status terakhir disalin ke shop.kyc_status supaya
+-- fitur yang butuh shop terverifikasi (saat ini listing bernilai tinggi) cukup membaca shop.
+ALTER TABLE shop
+    ADD COLUMN kyc_status VARCHAR(16) NOT NULL DEFAULT 'unverified'
+        CHECK (kyc_status IN ('unverified', 'pending', 'needs_info', 'verified', 'rejected')),
+    ADD COLUMN kyc_verified_at TIMESTAMP WITH TIME ZONE;
+
+-- Nomor KTP disimpan terenkripsi (AES-GCM); ktp_number_hash adalah blind index (HMAC) untuk
+-- mendeteksi KTP yang sama dipakai shop lain. Foto KTP & selfie berada di private storage.
+CREATE TABLE kyc_submissions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    shop_id UUID NOT NULL REFERENCES shop(id) ON DELETE CASCADE,
+    submitted_by UUID REFERENCES accounts(id) ON DELETE SET NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'needs_info', 'approved', 'rejected')),
+    ktp_number_encrypted BYTEA NOT NULL,
+    ktp_number_hash CHAR(64) NOT NULL,
+    ktp_last4 CHAR(4) NOT NULL,
+    ktp_photo_key TEXT NOT NULL,
+    selfie_key TEXT NOT NULL,
+    bank_account_holder VARCHAR(100) NOT NULL,
+    review_note TEXT,
+    reviewed_by UUID REFERENCES accounts(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    submitted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Satu pengajuan terbuka per shop; satu KTP hanya bisa memverifikasi satu shop
+CREATE UNIQUE INDEX idx_kyc_submissions_open ON kyc_submissions (shop_id) WHERE status IN ('pending', 'needs_info');
+CREATE UNIQUE INDEX idx_kyc_submissions_ktp ON kyc_submissions (ktp_number_hash) WHERE status = 'approved';
+CREATE INDEX idx_kyc_submissions_queue ON kyc_submissions (status, submitted_at);
+CREATE INDEX idx_kyc_submissions_shop ON kyc_submissions (shop_id, created_at DESC);
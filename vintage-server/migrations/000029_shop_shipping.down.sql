@@ -0,0 +1,26 @@
+DROP INDEX IF EXISTS idx_shipments_shop_id;
+
+-- Kebalikan dari backfill up: sebelum constraint lama dipasang kembali, shipment yang tidak bisa
+-- memenuhinya dihapus, yaitu ambil sendiri (tanpa alamat) dan shipment shop kedua dst. per order.
+DELETE FROM shipments WHERE address_id IS NULL;
+DELETE FROM shipments sh USING shipments other
+WHERE sh.order_id = other.order_id
+  AND (COALESCE(sh.created_at, 'epoch'), sh.id) > (COALESCE(other.created_at, 'epoch'), other.id);
+ALTER TABLE shipments
+    DROP CONSTRAINT IF EXISTS shipments_order_shop_key,
+    ADD CONSTRAINT shipments_order_id_key UNIQUE (order_id),
+    ALTER COLUMN address_id SET NOT NULL,
+    DROP COLUMN IF EXISTS shop_id;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS shipping_cost;
+
+DROP TABLE IF EXISTS shop_couriers;
+
+ALTER TABLE shop
+    DROP COLUMN IF EXISTS self_pickup,
+    DROP COLUMN IF EXISTS handling_days,
+    DROP COLUMN IF EXISTS origin_postal_code,
+    DROP COLUMN IF EXISTS origin_street,
+    DROP COLUMN IF EXISTS origin_district_id,
+    DROP COLUMN IF EXISTS origin_regency_id,
+    DROP COLUMN IF EXISTS origin_province_id;
@@ -0,0 +1,42 @@
+-- 000029 pengaturan pengiriman shop: alamat asal (untuk ongkir), kurir & layanan yang aktif,
+-- lama proses kirim dan opsi ambil sendiri. Checkout membuat satu shipment per shop di order.
+ALTER TABLE shop
+    ADD COLUMN origin_province_id VARCHAR(10) REFERENCES provinces(id),
+    ADD COLUMN origin_regency_id VARCHAR(10) REFERENCES regencies(id),
+    ADD COLUMN origin_district_id VARCHAR(10) REFERENCES districts(id),
+    ADD COLUMN origin_street TEXT,
+    ADD COLUMN origin_postal_code VARCHAR(10),
+    -- NULL berarti memakai batas kirim default platform (ORDER_SHIP_WINDOW)
+    ADD COLUMN handling_days SMALLINT CHECK (handling_days BETWEEN 1 AND 14),
+    ADD COLUMN self_pickup BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Kode kurir & layanan mengikuti katalog di package shipment
+CREATE TABLE shop_couriers (
+    shop_id UUID NOT NULL REFERENCES shop(id) ON DELETE CASCADE,
+    courier VARCHAR(10) NOT NULL,
+    service VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (shop_id, courier, service)
+);
+
+-- Order bisa memuat produk dari beberapa shop: satu shipment per shop. Shipment ambil sendiri
+-- tidak punya alamat tujuan.
+ALTER TABLE orders ADD COLUMN shipping_cost BIGINT NOT NULL DEFAULT 0 CHECK (shipping_cost >= 0);
+
+ALTER TABLE shipments ADD COLUMN shop_id UUID REFERENCES shop(id);
+UPDATE shipments sh SET shop_id = (
+    SELECT p.shop_id FROM order_items oi JOIN products p ON p.id = oi.product_id
+    WHERE oi.order_id = sh.order_id
+    ORDER BY oi.id
+    LIMIT 1
+);
+-- Shipment yang order-nya tidak punya item tidak bisa dikaitkan ke shop mana pun dan tidak
+-- ada barang yang dikirim; dihapus supaya shop_id bisa NOT NULL.
+DELETE FROM shipments WHERE shop_id IS NULL;
+ALTER TABLE shipments
+    ALTER COLUMN shop_id SET NOT NULL,
+    ALTER COLUMN address_id DROP NOT NULL,
+    DROP CONSTRAINT shipments_order_id_key,
+    ADD CONSTRAINT shipments_order_shop_key UNIQUE (order_id, shop_id);
+
+CREATE INDEX idx_shipments_shop_id ON shipments (shop_id, created_at DESC);
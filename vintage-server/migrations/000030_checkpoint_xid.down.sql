@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_product_price_history_xid;
+
+ALTER TABLE product_price_history DROP COLUMN IF EXISTS created_xid;
+ALTER TABLE job_checkpoints DROP COLUMN IF EXISTS last_xid;
@@ -0,0 +1,14 @@
+-- 000030 checkpoint job dicatat per transaksi, bukan hanya per id.
+-- id BIGSERIAL diambil saat INSERT, sedangkan barisnya baru terlihat saat transaksi commit.
+-- Transaksi lambat (misal import massal yang mengubah harga) bisa commit id yang lebih kecil
+-- setelah job melewatinya, sehingga baris itu tidak pernah diproses. Karena itu setiap baris
+-- menyimpan xid transaksi pembuatnya, job hanya membaca baris dari transaksi yang sudah selesai
+-- (xid < xmin snapshot saat ini) dan posisi checkpoint disimpan sebagai (last_xid, last_id).
+ALTER TABLE job_checkpoints ADD COLUMN last_xid xid8 NOT NULL DEFAULT '0';
+
+-- Baris lama mendapat xid 0 supaya posisi checkpoint (0, last_id) tetap berlaku untuknya;
+-- baris baru memakai xid transaksi yang meng-insert-nya.
+ALTER TABLE product_price_history ADD COLUMN created_xid xid8 NOT NULL DEFAULT '0';
+ALTER TABLE product_price_history ALTER COLUMN created_xid SET DEFAULT pg_current_xact_id();
+
+CREATE INDEX idx_product_price_history_xid ON product_price_history (created_xid, id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_product_status_logs_xid;
+
+ALTER TABLE product_status_logs DROP COLUMN IF EXISTS created_xid;
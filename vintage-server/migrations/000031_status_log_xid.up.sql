@@ -0,0 +1,6 @@
+-- 000031 product_status_logs ikut mencatat xid transaksi pembuatnya (lihat 000030), supaya job
+-- saved search dan drop shop tidak melewatkan publish yang commit dengan id lebih kecil.
+ALTER TABLE product_status_logs ADD COLUMN created_xid xid8 NOT NULL DEFAULT '0';
+ALTER TABLE product_status_logs ALTER COLUMN created_xid SET DEFAULT pg_current_xact_id();
+
+CREATE INDEX idx_product_status_logs_xid ON product_status_logs (created_xid, id);
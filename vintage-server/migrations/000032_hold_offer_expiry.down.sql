@@ -0,0 +1 @@
+ALTER TABLE inventory_holds DROP COLUMN IF EXISTS offer_expires_at;
@@ -0,0 +1,3 @@
+-- 000032 Hold offer yang dipakai checkout menyimpan masa berlaku aslinya, supaya saat order
+-- batal / kedaluwarsa hold dikembalikan ke pembeli (order_id NULL) dengan expires_at semula.
+ALTER TABLE inventory_holds ADD COLUMN offer_expires_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1 @@
+-- Perbaikan data: mode lelang lama tidak dikembalikan.
@@ -0,0 +1,5 @@
+-- 000033 Lelang yang terjual dulu membiarkan listing di mode 'auction'. Listing tanpa lelang
+-- aktif dikembalikan ke harga tetap; unit milik pemenang tetap terlindungi hold order-nya.
+UPDATE products p SET listing_mode = 'fixed', updated_at = CURRENT_TIMESTAMP
+WHERE p.listing_mode = 'auction'
+  AND NOT EXISTS (SELECT 1 FROM auctions a WHERE a.product_id = p.id AND a.status = 'active');
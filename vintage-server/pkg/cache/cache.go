@@ -0,0 +1,63 @@
+// File: pkg/cache/cache.go
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry struct {
+	value     any
+	expiresAt time.Time
+}
+
+// Cache adalah cache in-memory sederhana dengan TTL per entry.
+// Cocok untuk data kecil yang jarang berubah (misal data referensi untuk form).
+type Cache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]entry
+}
+
+// New adalah constructor untuk Cache dengan TTL default.
+func New(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:     ttl,
+		entries: make(map[string]entry),
+	}
+}
+
+// Get mengambil value dari cache. ok bernilai false jika tidak ada atau sudah kedaluwarsa.
+func (c *Cache) Get(key string) (any, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	e, ok := c.entries[key]
+	if !ok || time.Now().After(e.expiresAt) {
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set menyimpan value ke cache dengan TTL default.
+func (c *Cache) Set(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = entry{value: value, expiresAt: time.Now().Add(c.ttl)}
+}
+
+// Delete menghapus satu atau beberapa key dari cache.
+func (c *Cache) Delete(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+}
+
+// TTL mengembalikan TTL default cache, berguna untuk header Cache-Control.
+func (c *Cache) TTL() time.Duration {
+	return c.ttl
+}
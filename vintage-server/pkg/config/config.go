@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
@@ -19,6 +20,84 @@ type Config struct {
 	DBSSLMode       string `mapstructure:"DB_SSLMODE"`
 	UserServicePort int    `mapstructure:"USER_SERVICE_PORT"`
 	JWTSecretKey    string `mapstructure:"JWT_SECRET_KEY"`
+	StorageDir      string `mapstructure:"STORAGE_DIR"`
+	StorageBaseURL  string `mapstructure:"STORAGE_BASE_URL"`
+
+	// CheckoutHoldTTL adalah lama stok di-hold sejak checkout, misal "15m".
+	CheckoutHoldTTL   time.Duration `mapstructure:"CHECKOUT_HOLD_TTL"`
+	MidtransServerKey string        `mapstructure:"MIDTRANS_SERVER_KEY"`
+	// OrderShipWindow adalah batas seller mengirim order sejak dibayar, misal "72h".
+	OrderShipWindow time.Duration `mapstructure:"ORDER_SHIP_WINDOW"`
+
+	// Alert penurunan harga untuk produk di wishlist
+	PriceDropThresholdPercent int           `mapstructure:"PRICE_DROP_THRESHOLD_PERCENT"`
+	PriceAlertInterval        time.Duration `mapstructure:"PRICE_ALERT_INTERVAL"`
+	PriceAlertCooldown        time.Duration `mapstructure:"PRICE_ALERT_COOLDOWN"`
+
+	// Review admin sebelum listing tayang. Seller dengan jumlah listing tayang
+	// >= ListingReviewTrustedAfter tidak perlu direview lagi.
+	ListingReviewEnabled      bool `mapstructure:"LISTING_REVIEW_ENABLED"`
+	ListingReviewTrustedAfter int  `mapstructure:"LISTING_REVIEW_TRUSTED_AFTER"`
+
+	// RecommendationInterval adalah jeda job penghitung ulang product_neighbors, misal "6h".
+	RecommendationInterval time.Duration `mapstructure:"RECOMMENDATION_INTERVAL"`
+	// TrendingInterval adalah jeda job penghitung ulang skor trending, misal "15m".
+	TrendingInterval time.Duration `mapstructure:"TRENDING_INTERVAL"`
+
+	// Sitemap & product feed. SiteBaseURL adalah alamat storefront yang ditulis ke <loc>,
+	// AssetBaseURL dipakai untuk melengkapi URL gambar relatif di product feed.
+	SiteBaseURL         string        `mapstructure:"SITE_BASE_URL"`
+	AssetBaseURL        string        `mapstructure:"ASSET_BASE_URL"`
+	SyndicationInterval time.Duration `mapstructure:"SYNDICATION_INTERVAL"`
+
+	// Data yang di-soft-delete dihapus permanen setelah RetentionPeriod (misal "720h"),
+	// diperiksa oleh job retensi setiap RetentionInterval.
+	RetentionPeriod   time.Duration `mapstructure:"RETENTION_PERIOD"`
+	RetentionInterval time.Duration `mapstructure:"RETENTION_INTERVAL"`
+
+	// Offer: OfferTTL adalah lama offer / counter menunggu jawaban, OfferAcceptedTTL adalah lama
+	// harga khusus di-hold setelah diterima, OfferMaxPerProduct adalah batas offer per pembeli per produk.
+	OfferTTL           time.Duration `mapstructure:"OFFER_TTL"`
+	OfferAcceptedTTL   time.Duration `mapstructure:"OFFER_ACCEPTED_TTL"`
+	OfferMaxPerProduct int           `mapstructure:"OFFER_MAX_PER_PRODUCT"`
+
+	// Lelang: bid yang masuk kurang dari AuctionSnipeWindow sebelum berakhir memperpanjang lelang,
+	// pemenang diberi waktu AuctionPaymentTTL untuk membayar order-nya.
+	AuctionSnipeWindow time.Duration `mapstructure:"AUCTION_SNIPE_WINDOW"`
+	AuctionPaymentTTL  time.Duration `mapstructure:"AUCTION_PAYMENT_TTL"`
+
+	// Listing baru dicocokkan ke saved search dan alert-nya dikirim setiap SavedSearchAlertInterval.
+	SavedSearchAlertInterval time.Duration `mapstructure:"SAVED_SEARCH_ALERT_INTERVAL"`
+
+	// Riwayat recently viewed disimpan di Postgres ("postgres") atau server yang kompatibel
+	// dengan Redis ("redis", memakai RedisAddr / RedisPassword / RedisDB).
+	RecentlyViewedStore string `mapstructure:"RECENTLY_VIEWED_STORE"`
+	RedisAddr           string `mapstructure:"REDIS_ADDR"`
+	RedisPassword       string `mapstructure:"REDIS_PASSWORD"`
+	RedisDB             int    `mapstructure:"REDIS_DB"`
+
+	// ShopStatsInterval adalah jeda job penghitung ulang statistik publik shop, misal "15m".
+	ShopStatsInterval time.Duration `mapstructure:"SHOP_STATS_INTERVAL"`
+	// ShopDropAlertInterval adalah jeda job notifikasi listing baru ke follower shop; listing yang
+	// tayang dalam satu jeda digabung menjadi satu notifikasi per shop.
+	ShopDropAlertInterval time.Duration `mapstructure:"SHOP_DROP_ALERT_INTERVAL"`
+	// ShopVacationInterval adalah jeda job yang menyalakan / mematikan mode libur terjadwal.
+	ShopVacationInterval time.Duration `mapstructure:"SHOP_VACATION_INTERVAL"`
+
+	// KYC seller: dokumen disimpan di PrivateStorageDir (tidak dilayani router.Static), nomor KTP
+	// dienkripsi dengan KYCEncryptionKey (base64, 32 byte). Listing dengan harga >=
+	// KYCHighValueListingPrice hanya bisa tayang dari shop yang sudah terverifikasi (0 = tanpa batas).
+	PrivateStorageDir        string `mapstructure:"PRIVATE_STORAGE_DIR"`
+	KYCEncryptionKey         string `mapstructure:"KYC_ENCRYPTION_KEY"`
+	KYCHighValueListingPrice int64  `mapstructure:"KYC_HIGH_VALUE_LISTING_PRICE"`
+
+	// Ongkir per paket per zona tujuan dari alamat asal shop: satu kota / kabupaten, satu provinsi,
+	// dan antarprovinsi. ShippingServiceRates mengganti tarif tersebut untuk layanan tertentu,
+	// format "jne/REG=10000:18000:30000,jne/YES=18000:28000:45000" (lihat shipment.ParseServiceRates).
+	ShippingRateSameRegency   int64  `mapstructure:"SHIPPING_RATE_SAME_REGENCY"`
+	ShippingRateSameProvince  int64  `mapstructure:"SHIPPING_RATE_SAME_PROVINCE"`
+	ShippingRateOtherProvince int64  `mapstructure:"SHIPPING_RATE_OTHER_PROVINCE"`
+	ShippingServiceRates      string `mapstructure:"SHIPPING_SERVICE_RATES"`
 }
 
 // DSN (Data Source Name) mengembalikan connection string untuk database.
@@ -48,6 +127,78 @@ func LoadConfig() (config Config, err error) {
 	viper.BindEnv("DB_SSLMODE")
 	viper.BindEnv("USER_SERVICE_PORT")
 	viper.BindEnv("JWT_SECRET_KEY")
+	viper.BindEnv("STORAGE_DIR")
+	viper.BindEnv("STORAGE_BASE_URL")
+	viper.BindEnv("CHECKOUT_HOLD_TTL")
+	viper.BindEnv("MIDTRANS_SERVER_KEY")
+	viper.BindEnv("ORDER_SHIP_WINDOW")
+	viper.BindEnv("PRICE_DROP_THRESHOLD_PERCENT")
+	viper.BindEnv("PRICE_ALERT_INTERVAL")
+	viper.BindEnv("PRICE_ALERT_COOLDOWN")
+	viper.BindEnv("LISTING_REVIEW_ENABLED")
+	viper.BindEnv("LISTING_REVIEW_TRUSTED_AFTER")
+	viper.BindEnv("RECOMMENDATION_INTERVAL")
+	viper.BindEnv("TRENDING_INTERVAL")
+	viper.BindEnv("SITE_BASE_URL")
+	viper.BindEnv("ASSET_BASE_URL")
+	viper.BindEnv("SYNDICATION_INTERVAL")
+	viper.BindEnv("RETENTION_PERIOD")
+	viper.BindEnv("RETENTION_INTERVAL")
+	viper.BindEnv("OFFER_TTL")
+	viper.BindEnv("OFFER_ACCEPTED_TTL")
+	viper.BindEnv("OFFER_MAX_PER_PRODUCT")
+	viper.BindEnv("AUCTION_SNIPE_WINDOW")
+	viper.BindEnv("AUCTION_PAYMENT_TTL")
+	viper.BindEnv("SAVED_SEARCH_ALERT_INTERVAL")
+	viper.BindEnv("RECENTLY_VIEWED_STORE")
+	viper.BindEnv("REDIS_ADDR")
+	viper.BindEnv("REDIS_PASSWORD")
+	viper.BindEnv("REDIS_DB")
+	viper.BindEnv("SHOP_STATS_INTERVAL")
+	viper.BindEnv("SHOP_DROP_ALERT_INTERVAL")
+	viper.BindEnv("SHOP_VACATION_INTERVAL")
+	viper.BindEnv("PRIVATE_STORAGE_DIR")
+	viper.BindEnv("KYC_ENCRYPTION_KEY")
+	viper.BindEnv("KYC_HIGH_VALUE_LISTING_PRICE")
+	viper.BindEnv("SHIPPING_RATE_SAME_REGENCY")
+	viper.BindEnv("SHIPPING_RATE_SAME_PROVINCE")
+	viper.BindEnv("SHIPPING_RATE_OTHER_PROVINCE")
+	viper.BindEnv("SHIPPING_SERVICE_RATES")
+
+	// Nilai default untuk konfigurasi opsional
+	viper.SetDefault("STORAGE_DIR", "./uploads")
+	viper.SetDefault("STORAGE_BASE_URL", "/uploads")
+	viper.SetDefault("CHECKOUT_HOLD_TTL", "15m")
+	viper.SetDefault("ORDER_SHIP_WINDOW", "72h")
+	viper.SetDefault("PRICE_DROP_THRESHOLD_PERCENT", 10)
+	viper.SetDefault("PRICE_ALERT_INTERVAL", "15m")
+	viper.SetDefault("PRICE_ALERT_COOLDOWN", "24h")
+	viper.SetDefault("LISTING_REVIEW_ENABLED", false)
+	viper.SetDefault("LISTING_REVIEW_TRUSTED_AFTER", 3)
+	viper.SetDefault("RECOMMENDATION_INTERVAL", "6h")
+	viper.SetDefault("TRENDING_INTERVAL", "15m")
+	viper.SetDefault("SITE_BASE_URL", "http://localhost:3000")
+	viper.SetDefault("ASSET_BASE_URL", "http://localhost:8082")
+	viper.SetDefault("SYNDICATION_INTERVAL", "1h")
+	viper.SetDefault("RETENTION_PERIOD", "720h")
+	viper.SetDefault("RETENTION_INTERVAL", "24h")
+	viper.SetDefault("OFFER_TTL", "48h")
+	viper.SetDefault("OFFER_ACCEPTED_TTL", "24h")
+	viper.SetDefault("OFFER_MAX_PER_PRODUCT", 3)
+	viper.SetDefault("AUCTION_SNIPE_WINDOW", "2m")
+	viper.SetDefault("AUCTION_PAYMENT_TTL", "48h")
+	viper.SetDefault("SAVED_SEARCH_ALERT_INTERVAL", "5m")
+	viper.SetDefault("RECENTLY_VIEWED_STORE", "postgres")
+	viper.SetDefault("REDIS_ADDR", "localhost:6379")
+	viper.SetDefault("REDIS_DB", 0)
+	viper.SetDefault("SHOP_STATS_INTERVAL", "15m")
+	viper.SetDefault("SHOP_DROP_ALERT_INTERVAL", "15m")
+	viper.SetDefault("SHOP_VACATION_INTERVAL", "1m")
+	viper.SetDefault("PRIVATE_STORAGE_DIR", "./private")
+	viper.SetDefault("KYC_HIGH_VALUE_LISTING_PRICE", 5000000)
+	viper.SetDefault("SHIPPING_RATE_SAME_REGENCY", 10000)
+	viper.SetDefault("SHIPPING_RATE_SAME_PROVINCE", 18000)
+	viper.SetDefault("SHIPPING_RATE_OTHER_PROVINCE", 30000)
 
 	// Unmarshal semua konfigurasi yang ditemukan ke dalam struct Config
 	err = viper.Unmarshal(&config)
@@ -0,0 +1,102 @@
+// File: pkg/middleware/auth.go
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"vintage-server/pkg/auth"
+	"vintage-server/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// ContextAccountID adalah key di gin.Context untuk menyimpan ID akun dari token.
+	ContextAccountID = "account_id"
+	// ContextRoles adalah key di gin.Context untuk menyimpan daftar role dari token.
+	ContextRoles = "roles"
+)
+
+// RequireAuth memvalidasi access token dari header Authorization (Bearer)
+// atau dari cookie 'access_token', lalu menyimpan claims-nya ke context.
+func RequireAuth(jwt *auth.JWTService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := parseToken(c, jwt)
+		if !ok {
+			response.Error(c, http.StatusUnauthorized, "Unauthorized")
+			c.Abort()
+			return
+		}
+		c.Set(ContextAccountID, claims.AccountID)
+		c.Set(ContextRoles, claims.Roles)
+		c.Next()
+	}
+}
+
+// OptionalAuth sama seperti RequireAuth, tapi request tanpa token tetap diteruskan.
+// Berguna untuk endpoint publik yang responsenya sedikit berbeda kalau user login.
+func OptionalAuth(jwt *auth.JWTService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims, ok := parseToken(c, jwt); ok {
+			c.Set(ContextAccountID, claims.AccountID)
+			c.Set(ContextRoles, claims.Roles)
+		}
+		c.Next()
+	}
+}
+
+// RequireRole memastikan user yang sudah terautentikasi punya salah satu role yang diminta.
+// Harus dipasang setelah RequireAuth.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, owned := range GetRoles(c) {
+			for _, wanted := range roles {
+				if owned == wanted {
+					c.Next()
+					return
+				}
+			}
+		}
+		response.Error(c, http.StatusForbidden, "Forbidden")
+		c.Abort()
+	}
+}
+
+// GetAccountID mengambil ID akun yang disimpan oleh RequireAuth / OptionalAuth.
+func GetAccountID(c *gin.Context) (uuid.UUID, bool) {
+	value, exists := c.Get(ContextAccountID)
+	if !exists {
+		return uuid.Nil, false
+	}
+	id, ok := value.(uuid.UUID)
+	return id, ok
+}
+
+// GetRoles mengambil daftar role yang disimpan oleh RequireAuth / OptionalAuth.
+func GetRoles(c *gin.Context) []string {
+	value, exists := c.Get(ContextRoles)
+	if !exists {
+		return nil
+	}
+	roles, _ := value.([]string)
+	return roles
+}
+
+func parseToken(c *gin.Context, jwt *auth.JWTService) (*auth.Claims, bool) {
+	token := ""
+	if header := c.GetHeader("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		token = strings.TrimPrefix(header, "Bearer ")
+	} else if cookie, err := c.Cookie("access_token"); err == nil {
+		token = cookie
+	}
+	if token == "" {
+		return nil, false
+	}
+
+	claims, err := jwt.ValidateToken(token)
+	if err != nil {
+		return nil, false
+	}
+	return claims, true
+}
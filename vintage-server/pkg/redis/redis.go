@@ -0,0 +1,238 @@
+// File: pkg/redis/redis.go
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// defaultTimeout dipakai untuk dial dan satu round-trip jika ctx tidak punya deadline.
+const defaultTimeout = 3 * time.Second
+
+// maxIdleConns adalah jumlah koneksi idle yang disimpan untuk dipakai ulang.
+const maxIdleConns = 16
+
+// Error adalah balasan error dari server (misal "WRONGTYPE ...").
+type Error string
+
+func (e Error) Error() string { return "redis: " + string(e) }
+
+// Client adalah klien minimal untuk server yang kompatibel dengan protokol Redis (RESP2):
+// Redis, Valkey, KeyDB, dll. Hanya mendukung perintah request/response biasa dan pipeline,
+// tanpa pub/sub atau cluster.
+type Client struct {
+	addr     string
+	password string
+	db       int
+	idle     chan *conn
+}
+
+type conn struct {
+	nc net.Conn
+	r  *bufio.Reader
+}
+
+// New adalah constructor untuk Client. Koneksi dibuka saat perintah pertama dijalankan.
+func New(addr, password string, db int) *Client {
+	return &Client{
+		addr:     addr,
+		password: password,
+		db:       db,
+		idle:     make(chan *conn, maxIdleConns),
+	}
+}
+
+// Do menjalankan satu perintah. Balasan berupa string, int64, nil, atau []any.
+func (c *Client) Do(ctx context.Context, args ...any) (any, error) {
+	replies, err := c.Pipeline(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return replies[0], nil
+}
+
+// Pipeline mengirim beberapa perintah sekaligus dan membaca semua balasannya dalam satu round-trip.
+// Jika salah satu balasan adalah error dari server, error pertama dikembalikan.
+func (c *Client) Pipeline(ctx context.Context, cmds ...[]any) ([]any, error) {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultTimeout)
+	}
+	cn.nc.SetDeadline(deadline)
+
+	replies, err := cn.roundTrip(cmds)
+	if err != nil {
+		cn.nc.Close()
+		return nil, err
+	}
+	c.put(cn)
+
+	for _, reply := range replies {
+		if replyErr, ok := reply.(Error); ok {
+			return replies, replyErr
+		}
+	}
+	return replies, nil
+}
+
+// Close menutup semua koneksi idle.
+func (c *Client) Close() error {
+	for {
+		select {
+		case cn := <-c.idle:
+			cn.nc.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	select {
+	case cn := <-c.idle:
+		return cn, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: defaultTimeout}
+	nc, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, err
+	}
+	cn := &conn{nc: nc, r: bufio.NewReader(nc)}
+	nc.SetDeadline(time.Now().Add(defaultTimeout))
+
+	// AUTH & SELECT dijalankan sekali per koneksi baru
+	var setup [][]any
+	if c.password != "" {
+		setup = append(setup, []any{"AUTH", c.password})
+	}
+	if c.db != 0 {
+		setup = append(setup, []any{"SELECT", c.db})
+	}
+	if len(setup) > 0 {
+		replies, err := cn.roundTrip(setup)
+		if err == nil {
+			for _, reply := range replies {
+				if replyErr, ok := reply.(Error); ok {
+					err = replyErr
+				}
+			}
+		}
+		if err != nil {
+			nc.Close()
+			return nil, err
+		}
+	}
+	return cn, nil
+}
+
+func (c *Client) put(cn *conn) {
+	select {
+	case c.idle <- cn:
+	default:
+		cn.nc.Close()
+	}
+}
+
+func (cn *conn) roundTrip(cmds [][]any) ([]any, error) {
+	w := bufio.NewWriter(cn.nc)
+	for _, args := range cmds {
+		fmt.Fprintf(w, "*%d\r\n", len(args))
+		for _, arg := range args {
+			value := formatArg(arg)
+			fmt.Fprintf(w, "$%d\r\n%s\r\n", len(value), value)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+
+	replies := make([]any, len(cmds))
+	for i := range cmds {
+		reply, err := readReply(cn.r)
+		if err != nil {
+			return nil, err
+		}
+		replies[i] = reply
+	}
+	return replies, nil
+}
+
+func formatArg(arg any) string {
+	switch v := arg.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errors.New("redis: malformed reply")
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return Error(body), nil
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, err
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		items := make([]any, count)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unknown reply type %q", kind)
+	}
+}
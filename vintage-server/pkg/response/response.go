@@ -1,6 +1,10 @@
 package response
 
 import (
+	"errors"
+	"net/http"
+	"vintage-server/pkg/apperror"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -22,4 +26,15 @@ func Success[T any](c *gin.Context, statusCode int, data T) {
 // Error mengirimkan response error dengan pesan detail.
 func Error(c *gin.Context, statusCode int, message string) {
 	c.JSON(statusCode, APIResponse[any]{Detail: &message})
-}
\ No newline at end of file
+}
+
+// FromError mengirimkan response error berdasarkan error dari service.
+// *apperror.AppError dipetakan ke kode & pesannya, selain itu dianggap 500.
+func FromError(c *gin.Context, err error) {
+	var appErr *apperror.AppError
+	if errors.As(err, &appErr) {
+		Error(c, appErr.Code, appErr.Message)
+		return
+	}
+	Error(c, http.StatusInternalServerError, "An unexpected error occurred")
+}
@@ -0,0 +1,87 @@
+// File: pkg/secret/secret.go
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// KeySize adalah panjang master key (AES-256).
+const KeySize = 32
+
+// ErrMalformed dikembalikan Decrypt jika data terlalu pendek atau gagal diautentikasi
+// (key salah / data rusak).
+var ErrMalformed = errors.New("secret: malformed ciphertext")
+
+// Cipher mengenkripsi data sensitif (misal nomor KTP) dengan AES-256-GCM sebelum disimpan ke DB.
+// Nilai terenkripsi tidak bisa dicari, jadi BlindIndex disediakan untuk pencocokan nilai yang sama.
+type Cipher struct {
+	aead     cipher.AEAD
+	indexKey []byte
+}
+
+// NewCipher membuat Cipher dari master key berformat base64 (32 byte setelah di-decode),
+// misal hasil `openssl rand -base64 32`.
+func NewCipher(encodedKey string) (*Cipher, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("secret: key is not valid base64: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("secret: key must be %d bytes, got %d", KeySize, len(key))
+	}
+
+	// Key enkripsi dan key blind index diturunkan terpisah dari master key
+	block, err := aes.NewCipher(derive(key, "encryption"))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead, indexKey: derive(key, "blind-index")}, nil
+}
+
+// Encrypt mengembalikan nonce || ciphertext; nonce acak sehingga nilai yang sama menghasilkan
+// ciphertext berbeda.
+func (c *Cipher) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (c *Cipher) Decrypt(data []byte) ([]byte, error) {
+	size := c.aead.NonceSize()
+	if len(data) < size {
+		return nil, ErrMalformed
+	}
+	plaintext, err := c.aead.Open(nil, data[:size], data[size:], nil)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	return plaintext, nil
+}
+
+// BlindIndex mengembalikan HMAC-SHA256 (hex) dari value: deterministik, sehingga bisa di-index
+// untuk mendeteksi nilai kembar tanpa menyimpan plaintext-nya.
+func (c *Cipher) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func derive(key []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,120 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func newTestCipher(t *testing.T, seed byte) *Cipher {
+	t.Helper()
+	c, err := NewCipher(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{seed}, KeySize)))
+	if err != nil {
+		t.Fatalf("NewCipher: %v", err)
+	}
+	return c
+}
+
+func TestNewCipherRejectsBadKeys(t *testing.T) {
+	tests := map[string]string{
+		"not base64": "not-base64!",
+		"too short":  base64.StdEncoding.EncodeToString(make([]byte, 16)),
+		"too long":   base64.StdEncoding.EncodeToString(make([]byte, 64)),
+	}
+	for name, key := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewCipher(key); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	c := newTestCipher(t, 1)
+	for _, plaintext := range []string{"3171234567890001", "", "ktp dengan spasi"} {
+		sealed, err := c.Encrypt([]byte(plaintext))
+		if err != nil {
+			t.Fatalf("Encrypt(%q): %v", plaintext, err)
+		}
+		if plaintext != "" && bytes.Contains(sealed, []byte(plaintext)) {
+			t.Errorf("ciphertext of %q contains the plaintext", plaintext)
+		}
+		opened, err := c.Decrypt(sealed)
+		if err != nil {
+			t.Fatalf("Decrypt(%q): %v", plaintext, err)
+		}
+		if string(opened) != plaintext {
+			t.Errorf("round trip = %q, want %q", opened, plaintext)
+		}
+	}
+}
+
+func TestEncryptUsesFreshNonce(t *testing.T) {
+	c := newTestCipher(t, 1)
+	a, err := c.Encrypt([]byte("3171234567890001"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := c.Encrypt([]byte("3171234567890001"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(a, b) {
+		t.Error("encrypting the same value twice produced identical ciphertext")
+	}
+}
+
+func TestDecryptDetectsTampering(t *testing.T) {
+	c := newTestCipher(t, 1)
+	sealed, err := c.Encrypt([]byte("3171234567890001"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flipped := func(i int) []byte {
+		data := bytes.Clone(sealed)
+		data[i] ^= 0x01
+		return data
+	}
+	tests := map[string][]byte{
+		"flipped nonce":      flipped(0),
+		"flipped ciphertext": flipped(len(sealed) / 2),
+		"flipped tag":        flipped(len(sealed) - 1),
+		"truncated":          sealed[:len(sealed)-1],
+		"shorter than nonce": sealed[:4],
+		"empty":              nil,
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := c.Decrypt(data); !errors.Is(err, ErrMalformed) {
+				t.Errorf("err = %v, want ErrMalformed", err)
+			}
+		})
+	}
+
+	t.Run("wrong key", func(t *testing.T) {
+		if _, err := newTestCipher(t, 2).Decrypt(sealed); !errors.Is(err, ErrMalformed) {
+			t.Errorf("err = %v, want ErrMalformed", err)
+		}
+	})
+}
+
+func TestBlindIndex(t *testing.T) {
+	c := newTestCipher(t, 1)
+	first := c.BlindIndex("3171234567890001")
+
+	if again := newTestCipher(t, 1).BlindIndex("3171234567890001"); again != first {
+		t.Errorf("same key and value gave %s and %s", first, again)
+	}
+	if other := c.BlindIndex("3171234567890002"); other == first {
+		t.Error("different values share a blind index")
+	}
+	if otherKey := newTestCipher(t, 2).BlindIndex("3171234567890001"); otherKey == first {
+		t.Error("different keys share a blind index")
+	}
+	if len(first) != 64 {
+		t.Errorf("blind index length = %d, want 64 hex characters", len(first))
+	}
+}
@@ -0,0 +1,69 @@
+// File: pkg/slug/slug.go
+package slug
+
+import (
+	"fmt"
+	"strings"
+)
+
+// transliterations memetakan huruf Latin beraksen / ligatur ke padanan ASCII,
+// misal "Café Crème" -> "cafe-creme" dan "Straße" -> "strasse".
+var transliterations = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ĉ': "c", 'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ę': "e", 'ě': "e", 'ğ': "g",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'ı': "i", 'ľ': "l", 'ł': "l",
+	'ñ': "n", 'ń': "n", 'ň': "n", 'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o",
+	'ō': "o", 'ő': "o", 'œ': "oe", 'ř': "r", 'ś': "s", 'š': "s", 'ş': "s", 'ß': "ss",
+	'ť': "t", 'ţ': "t", 'þ': "th", 'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u",
+	'ű': "u", 'ý': "y", 'ÿ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// Make mengubah teks bebas menjadi slug URL, misal "Denim Jackets" -> "denim-jackets".
+// Huruf beraksen ditransliterasi ke ASCII, karakter lain selain huruf a-z dan angka 0-9
+// (termasuk angka non-ASCII seperti angka Arab atau fullwidth) diganti dengan tanda '-',
+// dan tanda '-' berulang digabung.
+func Make(text string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		if ascii, ok := transliterations[r]; ok {
+			b.WriteString(ascii)
+			dash = false
+			continue
+		}
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// Truncate memotong slug menjadi maksimal max byte tanpa menyisakan '-' di akhir.
+func Truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return strings.TrimRight(s[:max], "-")
+}
+
+// Unique mengembalikan base jika belum dipakai, jika sudah maka base-2, base-3, dst.
+// taken berisi slug yang sudah dipakai (boleh memuat slug lain yang tidak berawalan base).
+func Unique(base string, taken []string) string {
+	used := make(map[string]bool, len(taken))
+	for _, s := range taken {
+		used[s] = true
+	}
+
+	candidate := base
+	for i := 2; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+	return candidate
+}
@@ -0,0 +1,102 @@
+// File: pkg/spreadsheet/spreadsheet.go
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format file yang didukung
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// ErrUnsupportedFormat dikembalikan jika format bukan csv / xlsx.
+var ErrUnsupportedFormat = errors.New("spreadsheet: unsupported format")
+
+// Read membaca seluruh baris dari file csv / xlsx. Baris pertama adalah header.
+// Untuk xlsx, yang dibaca hanya sheet pertama.
+func Read(format string, data []byte) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		return ReadCSV(data)
+	case FormatXLSX:
+		return ReadXLSX(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// Write menulis baris (header di baris pertama) dalam format csv / xlsx.
+func Write(format string, w io.Writer, rows [][]string) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, rows)
+	case FormatXLSX:
+		return WriteXLSX(w, rows)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
+
+// ReadCSV membaca csv dengan pemisah ',' atau ';' (Excel berlocale Indonesia memakai ';').
+// BOM UTF-8 di awal file diabaikan dan baris kosong dilewati.
+func ReadCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var rows [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("spreadsheet: %w", err)
+		}
+		if isBlank(record) {
+			continue
+		}
+		rows = append(rows, record)
+	}
+	return rows, nil
+}
+
+// WriteCSV menulis baris sebagai csv dengan pemisah ','.
+func WriteCSV(w io.Writer, rows [][]string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// detectDelimiter memilih ',' atau ';' berdasarkan mana yang lebih banyak muncul di baris header.
+func detectDelimiter(data []byte) rune {
+	header := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		header = data[:i]
+	}
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+func isBlank(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,279 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// maxXLSXPartSize membatasi ukuran satu file xml di dalam xlsx setelah didekompresi (zip bomb).
+const maxXLSXPartSize = 64 << 20
+
+// ErrInvalidXLSX dikembalikan jika file bukan workbook xlsx yang valid.
+var ErrInvalidXLSX = errors.New("spreadsheet: invalid xlsx file")
+
+// ReadXLSX membaca sheet pertama workbook xlsx. Sel kosong di tengah baris diisi "".
+func ReadXLSX(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, ErrInvalidXLSX
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := readSharedStrings(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var sheet struct {
+		Rows []struct {
+			Cells []struct {
+				Ref    string `xml:"r,attr"`
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline struct {
+					Text string `xml:"t"`
+					Runs []struct {
+						Text string `xml:"t"`
+					} `xml:"r"`
+				} `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodePart(files, sheetPath, &sheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, row := range sheet.Rows {
+		var record []string
+		for i, cell := range row.Cells {
+			col := i
+			if cell.Ref != "" {
+				if parsed, ok := columnIndex(cell.Ref); ok {
+					col = parsed
+				}
+			}
+			for len(record) <= col {
+				record = append(record, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(strings.TrimSpace(cell.Value))
+				if err != nil || index < 0 || index >= len(shared) {
+					return nil, ErrInvalidXLSX
+				}
+				record[col] = shared[index]
+			case "inlineStr":
+				text := cell.Inline.Text
+				for _, run := range cell.Inline.Runs {
+					text += run.Text
+				}
+				record[col] = text
+			default:
+				record[col] = cell.Value
+			}
+		}
+		if isBlank(record) {
+			continue
+		}
+		rows = append(rows, record)
+	}
+	return rows, nil
+}
+
+// firstSheetPath mencari path sheet pertama lewat workbook.xml dan relasinya.
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	var workbook struct {
+		Sheets []struct {
+			RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodePart(files, "xl/workbook.xml", &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", ErrInvalidXLSX
+	}
+
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodePart(files, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].RelID {
+			continue
+		}
+		// Target bisa relatif terhadap xl/ atau absolut dari root paket
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return "", ErrInvalidXLSX
+}
+
+// readSharedStrings membaca xl/sharedStrings.xml (boleh tidak ada jika semua sel inline / angka).
+func readSharedStrings(files map[string]*zip.File) ([]string, error) {
+	if _, ok := files["xl/sharedStrings.xml"]; !ok {
+		return nil, nil
+	}
+
+	var sst struct {
+		Items []struct {
+			Text string `xml:"t"`
+			Runs []struct {
+				Text string `xml:"t"`
+			} `xml:"r"`
+		} `xml:"si"`
+	}
+	if err := decodePart(files, "xl/sharedStrings.xml", &sst); err != nil {
+		return nil, err
+	}
+
+	strs := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		text := item.Text
+		for _, run := range item.Runs {
+			text += run.Text
+		}
+		strs[i] = text
+	}
+	return strs, nil
+}
+
+func decodePart(files map[string]*zip.File, name string, dest any) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("%w: missing %s", ErrInvalidXLSX, name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return ErrInvalidXLSX
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(io.LimitReader(rc, maxXLSXPartSize)).Decode(dest); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidXLSX, name)
+	}
+	return nil
+}
+
+// columnIndex mengubah referensi sel seperti "C12" menjadi indeks kolom 0-based (2).
+func columnIndex(ref string) (int, bool) {
+	col := 0
+	letters := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		letters++
+	}
+	if letters == 0 {
+		return 0, false
+	}
+	return col - 1, true
+}
+
+// columnName adalah kebalikan columnIndex: 0 -> "A", 27 -> "AB".
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// WriteXLSX menulis workbook minimal berisi satu sheet dengan sel teks inline.
+func WriteXLSX(w io.Writer, rows [][]string) error {
+	archive := zip.NewWriter(w)
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+	for _, part := range parts {
+		f, err := archive.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	if err := writeSheet(sheet, rows); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+func writeSheet(w io.Writer, rows [][]string) error {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for r, row := range rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for c, value := range row {
+			fmt.Fprintf(&b, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, columnName(c), r+1)
+			if err := xml.EscapeText(&b, []byte(value)); err != nil {
+				return err
+			}
+			b.WriteString(`</t></is></c>`)
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+const xlsxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`</Types>`
+
+const xlsxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+	`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="Listings" sheetId="1" r:id="rId1"/></sheets>` +
+	`</workbook>`
+
+const xlsxWorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`</Relationships>`
@@ -0,0 +1,136 @@
+// File: pkg/storage/storage.go
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Storage adalah kontrak penyimpanan file (logo, gambar produk, dll).
+// Implementasi lain (S3, GCS) cukup memenuhi interface ini.
+type Storage interface {
+	// Save menyimpan isi reader dengan key tertentu dan mengembalikan URL publiknya.
+	Save(ctx context.Context, key string, r io.Reader) (string, error)
+	// Delete menghapus file berdasarkan key. Tidak error jika file sudah tidak ada.
+	Delete(ctx context.Context, key string) error
+}
+
+// LocalStorage menyimpan file di disk lokal, dilayani lewat router.Static(baseURL, baseDir).
+type LocalStorage struct {
+	baseDir string
+	baseURL string
+}
+
+// NewLocalStorage adalah constructor untuk LocalStorage.
+func NewLocalStorage(baseDir, baseURL string) *LocalStorage {
+	return &LocalStorage{
+		baseDir: baseDir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (s *LocalStorage) Save(ctx context.Context, key string, r io.Reader) (string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := writeFile(path, r); err != nil {
+		return "", err
+	}
+	return s.baseURL + "/" + filepath.ToSlash(filepath.Clean(key)), nil
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	return removeFile(path)
+}
+
+func (s *LocalStorage) path(key string) (string, error) {
+	return safePath(s.baseDir, key)
+}
+
+// PrivateStorage adalah kontrak penyimpanan dokumen yang tidak boleh diakses publik (misal dokumen KYC).
+// Tidak ada URL publik; isi file hanya bisa dibaca lewat Open oleh service yang berwenang.
+type PrivateStorage interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Open mengembalikan isi file; os.ErrNotExist (lewat errors.Is) jika key tidak ada.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete menghapus file berdasarkan key. Tidak error jika file sudah tidak ada.
+	Delete(ctx context.Context, key string) error
+}
+
+// LocalPrivateStorage menyimpan file di disk lokal. baseDir tidak boleh sama dengan / berada di
+// dalam direktori yang dilayani router.Static.
+type LocalPrivateStorage struct {
+	baseDir string
+}
+
+// NewLocalPrivateStorage adalah constructor untuk LocalPrivateStorage.
+func NewLocalPrivateStorage(baseDir string) *LocalPrivateStorage {
+	return &LocalPrivateStorage{baseDir: baseDir}
+}
+
+func (s *LocalPrivateStorage) Put(ctx context.Context, key string, r io.Reader) error {
+	path, err := safePath(s.baseDir, key)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, r)
+}
+
+func (s *LocalPrivateStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := safePath(s.baseDir, key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (s *LocalPrivateStorage) Delete(ctx context.Context, key string) error {
+	path, err := safePath(s.baseDir, key)
+	if err != nil {
+		return err
+	}
+	return removeFile(path)
+}
+
+// safePath memastikan key tidak keluar dari baseDir (misal "../../etc/passwd").
+func safePath(baseDir, key string) (string, error) {
+	if strings.TrimSpace(key) == "" {
+		return "", errors.New("storage: empty key")
+	}
+	return filepath.Join(baseDir, filepath.Clean("/"+key)), nil
+}
+
+// writeFile menulis isi reader ke path; file yang gagal ditulis sampai selesai dihapus.
+func writeFile(path string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, r); err != nil {
+		os.Remove(path)
+		return err
+	}
+	return nil
+}
+
+func removeFile(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}